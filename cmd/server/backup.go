@@ -0,0 +1,203 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/backup"
+	configs "github.com/haakaashs/todos-backend/internal/configs"
+	"github.com/haakaashs/todos-backend/internal/db"
+	"github.com/haakaashs/todos-backend/internal/repository"
+)
+
+// backupPattern matches the files written by backup into a directory.
+const backupPattern = "todos-*.backup.gz"
+
+// runBackup implements "server backup".
+func runBackup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := flags.String("o", "-", `file to write, a directory to write "todos-<time>.backup.gz" into, or - for stdout`)
+	keep := flags.Int("keep", 0, "when writing into a directory, delete all but this many newest backups; 0 keeps all")
+	flags.Parse(args)
+
+	store, err := backupStore()
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "todos-"+time.Now().UTC().Format("20060102T150405Z")+".backup.gz")
+	}
+	manifest, err := writeBackup(store, path)
+	if err != nil {
+		return err
+	}
+	for _, table := range manifest.Tables {
+		fmt.Fprintf(os.Stderr, "%-20s %d rows\n", table.Name, table.Rows)
+	}
+	if path != "-" {
+		fmt.Fprintln(os.Stderr, "Wrote", path)
+	}
+
+	if *keep > 0 && path != *output {
+		return prune(*output, *keep)
+	}
+	return nil
+}
+
+// writeBackup writes a backup to path through a temporary file, so that a
+// failed backup never leaves a partial file under its name.
+func writeBackup(store backup.Store, path string) (backup.Manifest, error) {
+	ctx := context.Background()
+	if path == "-" {
+		return backup.Backup(ctx, store, os.Stdout)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".backup-*")
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	defer os.Remove(f.Name())
+	manifest, err := backup.Backup(ctx, store, f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	return manifest, os.Rename(f.Name(), path)
+}
+
+// prune deletes all but the keep newest backups in dir. Their names sort by
+// time.
+func prune(dir string, keep int) error {
+	paths, err := filepath.Glob(filepath.Join(dir, backupPattern))
+	if err != nil {
+		return err
+	}
+	slices.Sort(paths)
+	for len(paths) > keep {
+		if err := os.Remove(paths[0]); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Deleted", paths[0])
+		paths = paths[1:]
+	}
+	return nil
+}
+
+// runRestore implements "server restore".
+func runRestore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	merge := flags.Bool("merge", false, "restore into a database that already has rows; otherwise every table must be empty")
+	onConflict := flags.String("on-conflict", "fail", "with -merge, what to do with rows that already exist: fail, skip or overwrite")
+	verifyOnly := flags.Bool("verify", false, "only check the backup, without connecting to the database")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server restore [flags] <file or ->")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("restore takes one backup file")
+	}
+	policy, err := backup.ParseConflictPolicy(*onConflict)
+	if err != nil {
+		return err
+	}
+	path := flags.Arg(0)
+
+	// check a file before touching the database; stdin can only be read
+	// once, and is checked as it is restored
+	if path != "-" {
+		manifest, err := verifyFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Backup of %s is intact\n", manifest.CreatedAt.Format(time.RFC3339))
+	}
+	if *verifyOnly {
+		return nil
+	}
+
+	store, err := backupStore()
+	if err != nil {
+		return err
+	}
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	manifest, err := backup.Restore(context.Background(), store, in, backup.RestoreOptions{Merge: *merge, OnConflict: policy})
+	if err != nil {
+		return err
+	}
+	for _, table := range manifest.Tables {
+		fmt.Fprintf(os.Stderr, "%-20s %d of %d rows restored\n", table.Name, table.Restored, table.Rows)
+	}
+	return nil
+}
+
+func verifyFile(path string) (backup.Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	defer f.Close()
+	return backup.Verify(f)
+}
+
+// backupStore connects to the database the server prepared, with a plain
+// pool and only the statements prepared: unlike the server, it neither
+// bootstraps the database nor watches replicas.
+func backupStore() (backup.Store, error) {
+	config := configs.LoadConfig()
+	ctx, cancel := context.WithTimeout(context.Background(), cmp.Or(config.DB.ConnectTimeout, time.Minute))
+	defer cancel()
+
+	conn, err := db.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := repository.NewRepository(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+// runCommand runs the subcommand in args, if any, and reports whether there
+// was one.
+func runCommand(args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return false, nil
+	}
+	switch args[0] {
+	case "serve":
+		return false, nil
+	case "backup":
+		return true, runBackup(args[1:])
+	case "restore":
+		return true, runRestore(args[1:])
+	}
+	return true, fmt.Errorf("unknown command %q, expected serve, backup or restore", args[0])
+}
@@ -1,52 +1,173 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"expvar"
 	"log"
 	"net/http"
+	"os"
+	"time"
+	_ "time/tzdata" // recurrence rules need IANA zones even in minimal images
 
 	"connectrpc.com/connect"
 	"connectrpc.com/validate"
+	"github.com/haakaashs/todos-backend/gen/openapi"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
 	gen "github.com/haakaashs/todos-backend/gen/protos/todos/v1/todosv1connect"
+	"github.com/haakaashs/todos-backend/internal/api/v1/gateway"
 	handler "github.com/haakaashs/todos-backend/internal/api/v1/handler"
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/blob"
+	"github.com/haakaashs/todos-backend/internal/cache"
+	configs "github.com/haakaashs/todos-backend/internal/configs"
 	"github.com/haakaashs/todos-backend/internal/db"
+	"github.com/haakaashs/todos-backend/internal/outbox"
+	"github.com/haakaashs/todos-backend/internal/ratelimit"
 	"github.com/haakaashs/todos-backend/internal/repository"
 	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/haakaashs/todos-backend/internal/webhook"
 	"github.com/rs/cors"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 )
 
 func main() {
+	if ran, err := runCommand(os.Args[1:]); ran {
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	config := configs.LoadConfig()
+
 	// Initialize DB
-	repo, err := repository.NewRepository(db.InitializeDB())
+	repo, err := newRepository(config.DB)
 	if err != nil {
 		log.Fatal("Failed to initialize repository:", err)
 	}
 
+	// Serve reads from the cache, if configured
+	cached, err := newCache(repo, config.Cache)
+	if err != nil {
+		log.Fatal("Invalid cache configuration:", err)
+	}
+
+	// Store attachments, if configured
+	opts := []service.Option{service.WithMaxTodosPerUser(config.MaxTodosPerUser)}
+	blobs, err := newBlobStore(config.Attachments)
+	if err != nil {
+		log.Fatal("Invalid attachment configuration:", err)
+	}
+	if blobs != nil {
+		opts = append(opts, service.WithAttachments(blobs, service.AttachmentLimits{
+			MaxBytes:        config.Attachments.MaxBytes,
+			MaxBytesPerUser: config.Attachments.MaxBytesPerUser,
+			Retention:       config.Attachments.Retention,
+		}))
+	}
+
 	// Create service handler
-	todosHandler := handler.NewTodosServiceHandler(service.NewTodosService(repo))
+	todosService := service.NewTodosService(cached, opts...)
+	todosHandler := handler.NewTodosServiceHandler(todosService)
+
+	limiter, err := newRateLimiter(config.RateLimit)
+	if err != nil {
+		log.Fatal("Invalid rate limit configuration:", err)
+	}
+
+	authenticator := auth.NewInterceptor(auth.Config{
+		Keys:         todosService,
+		Scopes:       handler.ProcedureScopes,
+		AdminToken:   config.AdminToken,
+		UserHeader:   config.UserHeader,
+		EmailHeader:  config.EmailHeader,
+		TenantHeader: config.TenantHeader,
+	})
 
-	// Get Connect handler
-	path, h := gen.NewTodosServiceHandler(todosHandler, connect.WithInterceptors(validate.NewInterceptor()))
+	// Get Connect handlers
+	lastWrite := handler.NewLastWriteInterceptor()
+	interceptors := connect.WithInterceptors(authenticator, limiter, lastWrite, validate.NewInterceptor())
+	path, h := gen.NewTodosServiceHandler(todosHandler, interceptors)
+	webhooksPath, webhooksHandler := gen.NewWebhooksServiceHandler(handler.NewWebhooksServiceHandler(todosService), interceptors)
+
+	// REST mappings from the google.api.http annotations
+	restHandler, err := gateway.NewHandler(v1.File_protos_todos_v1_todos_proto.Services().ByName("TodosService"), h)
+	if err != nil {
+		log.Fatal("Failed to build REST gateway:", err)
+	}
+	webhooksRestHandler, err := gateway.NewHandler(v1.File_protos_todos_v1_webhooks_proto.Services().ByName("WebhooksService"), webhooksHandler)
+	if err != nil {
+		log.Fatal("Failed to build REST gateway:", err)
+	}
+	openAPIHandler, err := gateway.OpenAPIHandler(openapi.Spec)
+	if err != nil {
+		log.Fatal("Failed to load OpenAPI document:", err)
+	}
 
 	// Register HTTP handlers
 	mux := http.NewServeMux()
 	mux.Handle(path, h)
+	mux.Handle("/v1/", restHandler)
+	mux.Handle(webhooksPath, webhooksHandler)
+	mux.Handle("/v1/webhooks", webhooksRestHandler)
+	mux.Handle("/v1/webhooks/", webhooksRestHandler)
+	mux.Handle("GET /openapi.json", openAPIHandler)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	mux.Handle(handler.CalendarFeedPattern, handler.NewCalendarFeedHandler(todosService))
+	attachments := handler.NewAttachmentHandler(todosService)
+	mux.Handle(handler.AttachmentUploadPattern, authenticator.Handler(gen.TodosServiceUploadAttachmentProcedure,
+		limiter.Handler(gen.TodosServiceUploadAttachmentProcedure, lastWrite.Handler(http.HandlerFunc(attachments.Upload)))))
+	mux.Handle(handler.AttachmentContentPattern, authenticator.Handler(gen.TodosServiceDownloadAttachmentProcedure,
+		limiter.Handler(gen.TodosServiceDownloadAttachmentProcedure, lastWrite.Handler(http.HandlerFunc(attachments.Content)))))
+
+	// Send webhook deliveries in the background
+	dispatcher := webhook.NewDispatcher(webhook.Config{
+		Store:                repo,
+		MaxAttempts:          config.Webhooks.MaxAttempts,
+		AllowPrivateNetworks: config.Webhooks.AllowPrivateNetworks,
+	})
+	go dispatcher.Run(context.Background())
+
+	// Publish the events recorded with every change; webhooks consume them
+	// from the in-process bus
+	bus := outbox.NewBus()
+	bus.Subscribe(todosService.QueueDeliveries)
+	publisher, err := newPublisher(bus, config.Outbox)
+	if err != nil {
+		log.Fatal("Invalid outbox configuration:", err)
+	}
+	go outbox.NewRelay(outbox.Config{Store: repo, Publisher: publisher}).Run(context.Background())
+
+	// Remove the content of deleted attachments
+	if blobs != nil {
+		go todosService.RunAttachmentPurger(context.Background(), time.Hour)
+	}
 
 	// Specialized CORS Configuration
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"http://todos.localhost", "http://localhost:3000"},
-		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders: []string{
 			"Connect-Protocol-Version",
 			"Content-Type",
 			"Connect-Timeout-Ms",
 			"Authorization",
+			"If-None-Match",
+			"If-Modified-Since",
+			handler.LastWriteHeader,
 		},
 		ExposedHeaders: []string{
 			"Grpc-Status",
 			"Grpc-Message",
 			"Grpc-Status-Details-Bin",
+			"ETag",
+			"Last-Modified",
+			"Retry-After",
+			"WWW-Authenticate",
+			"Content-Disposition",
+			handler.LastWriteHeader,
 		},
 		// Prevents the 404 by returning 200 to OPTIONS requests
 		OptionsPassthrough: false,
@@ -67,3 +188,129 @@ func main() {
 	log.Println("Backend listening on :8080 with HTTP/2 (h2c) and specialized CORS support")
 	log.Fatal(server.ListenAndServe())
 }
+
+func newRateLimiter(config configs.RateLimitConfig) (*ratelimit.Interceptor, error) {
+	procedures, err := ratelimit.ParseProcedureLimits(config.Procedures)
+	if err != nil {
+		return nil, err
+	}
+	proxies, err := ratelimit.ParsePrefixes(config.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewInterceptor(ratelimit.Config{
+		Default:        ratelimit.Limit{Rate: config.RPS, Burst: config.Burst},
+		Procedures:     procedures,
+		TrustedProxies: proxies,
+	}), nil
+}
+
+// newBlobStore creates the attachment store of config, or returns nil if
+// attachments are disabled.
+func newBlobStore(config configs.AttachmentConfig) (service.BlobStore, error) {
+	switch config.Store {
+	case "fs":
+		log.Printf("Storing attachments in %s", cmp.Or(config.Dir, "attachments"))
+		return blob.NewFS(cmp.Or(config.Dir, "attachments"))
+	case "s3":
+		log.Printf("Storing attachments in the S3 bucket %s", config.S3Bucket)
+		return blob.NewS3(blob.S3Config{
+			Endpoint:        config.S3Endpoint,
+			Bucket:          config.S3Bucket,
+			Region:          config.S3Region,
+			AccessKeyID:     config.S3AccessKeyID,
+			SecretAccessKey: config.S3SecretAccessKey,
+			PathStyle:       config.S3PathStyle,
+		})
+	}
+	return nil, nil
+}
+
+func newPublisher(bus *outbox.Bus, config configs.OutboxConfig) (outbox.Publisher, error) {
+	publishers := outbox.Publishers{bus}
+	if config.NATSURL != "" {
+		nats, err := outbox.NewNATSPublisher(outbox.NATSConfig{
+			URL:       config.NATSURL,
+			Subject:   config.NATSSubject,
+			JetStream: config.NATSJetStream,
+		})
+		if err != nil {
+			return nil, err
+		}
+		publishers = append(publishers, nats)
+	}
+	if config.File != "" {
+		file, err := outbox.NewFileSink(config.File)
+		if err != nil {
+			return nil, err
+		}
+		publishers = append(publishers, file)
+	}
+	return publishers, nil
+}
+
+// newCache wraps repo in the cache of config, publishing its statistics as
+// the "cache" expvar, or returns repo as is if no cache is configured.
+func newCache(repo service.Repository, config configs.CacheConfig) (service.Repository, error) {
+	var c *cache.Cache
+	switch config.Backend {
+	case "memory":
+		notifier, err := cache.NewPostgresNotifier(db.DSN())
+		if err != nil {
+			return nil, err
+		}
+		c = cache.New(cache.Config{Backend: cache.NewMemory(config.MaxEntries), TTL: config.TTL, Notifier: notifier})
+		go notifier.Run(context.Background(), c)
+	case "redis":
+		backend, err := cache.NewRedis(cache.RedisConfig{URL: config.RedisURL})
+		if err != nil {
+			return nil, err
+		}
+		c = cache.New(cache.Config{Backend: backend, TTL: config.TTL})
+	default:
+		return repo, nil
+	}
+	expvar.Publish("cache", expvar.Func(func() any { return c.Stats() }))
+	log.Printf("Caching reads in %s", config.Backend)
+	return c.Wrap(repo), nil
+}
+
+// todosRepository is what the server needs from a repository.
+type todosRepository interface {
+	service.Repository
+	webhook.Store
+	outbox.Store
+}
+
+// newRepository connects to the database and its read replicas with the
+// driver of config.
+func newRepository(config configs.DBConfig) (todosRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmp.Or(config.ConnectTimeout, time.Minute))
+	defer cancel()
+
+	replicaConfig := repository.ReplicaConfig{
+		MaxLag:       config.ReplicaMaxLag,
+		StickyWindow: config.ReplicaStickyWindow,
+	}
+	if config.Driver == "pgx" {
+		pool, err := db.InitializePool(ctx)
+		if err != nil {
+			return nil, err
+		}
+		replicas, err := db.InitializeReplicas()
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewPgxRepository(pool, repository.WithReplicas(replicas, replicaConfig))
+	}
+
+	primary, err := db.InitializeDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := db.InitializeReplicas()
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewRepository(primary, repository.WithReplicas(replicas, replicaConfig))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"connectrpc.com/connect"
+	gen "github.com/haakaashs/todos-backend/gen/protos/todos/v1/todosv1connect"
+	"golang.org/x/net/http2"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultAddr = "http://localhost:8080"
+
+// options holds the global flags shared by every subcommand.
+type options struct {
+	configPath string
+	addr       string
+	protocol   string
+	output     string
+}
+
+// config is the todosctl config file. Flags override environment variables,
+// which override the file.
+type config struct {
+	Addr     string `yaml:"addr"`
+	Protocol string `yaml:"protocol"`
+	Token    string `yaml:"token"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "todosctl", "config.yaml")
+}
+
+// loadConfig reads the config file and applies environment variables and
+// flags on top of it. A missing default config file is not an error.
+func (o *options) loadConfig() (config, error) {
+	var cfg config
+
+	path := o.configPath
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return config{}, fmt.Errorf("error parsing config %s: %w", path, err)
+			}
+		case errors.Is(err, os.ErrNotExist) && o.configPath == "":
+		default:
+			return config{}, fmt.Errorf("error reading config: %w", err)
+		}
+	}
+
+	override(&cfg.Addr, os.Getenv("TODOS_ADDR"), o.addr)
+	override(&cfg.Protocol, os.Getenv("TODOS_PROTOCOL"), o.protocol)
+	override(&cfg.Token, os.Getenv("TODOS_TOKEN"))
+	if cfg.Addr == "" {
+		cfg.Addr = defaultAddr
+	}
+	return cfg, nil
+}
+
+func override(dst *string, values ...string) {
+	for _, v := range values {
+		if v != "" {
+			*dst = v
+		}
+	}
+}
+
+// client builds a TodosServiceClient for the configured server and protocol.
+func (o *options) client() (gen.TodosServiceClient, error) {
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var clientOpts []connect.ClientOption
+	switch strings.ToLower(cfg.Protocol) {
+	case "", "connect":
+		// side-effect free calls such as Get and List go out as cacheable GETs
+		clientOpts = append(clientOpts, connect.WithHTTPGet())
+	case "grpc":
+		clientOpts = append(clientOpts, connect.WithGRPC())
+	case "grpcweb", "grpc-web":
+		clientOpts = append(clientOpts, connect.WithGRPCWeb())
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", cfg.Protocol)
+	}
+
+	httpClient := &http.Client{Transport: newTransport(cfg.Addr)}
+	if cfg.Token != "" {
+		httpClient.Transport = bearerTransport{token: cfg.Token, next: httpClient.Transport}
+	}
+
+	return gen.NewTodosServiceClient(httpClient, strings.TrimSuffix(cfg.Addr, "/"), clientOpts...), nil
+}
+
+// newTransport returns an HTTP/2 transport, speaking h2c to plain http://
+// servers, since gRPC and client streaming need HTTP/2.
+func newTransport(addr string) http.RoundTripper {
+	if strings.HasPrefix(addr, "https://") {
+		return &http2.Transport{}
+	}
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// bearerTransport sends the auth token with every request.
+type bearerTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.next.RoundTrip(req)
+}
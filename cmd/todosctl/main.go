@@ -0,0 +1,53 @@
+// Command todosctl is a command-line client for the TodosService.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:           "todosctl",
+		Short:         "Manage todos from the command line",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configPath, "config", "", "config file (default $XDG_CONFIG_HOME/todosctl/config.yaml)")
+	flags.StringVar(&opts.addr, "addr", "", "server base URL (env TODOS_ADDR)")
+	flags.StringVar(&opts.protocol, "protocol", "", "wire protocol: connect, grpc or grpcweb (env TODOS_PROTOCOL)")
+	flags.StringVarP(&opts.output, "output", "o", "table", "output format: table, json or yaml")
+	_ = root.RegisterFlagCompletionFunc("protocol", fixedCompletions("connect", "grpc", "grpcweb"))
+	_ = root.RegisterFlagCompletionFunc("output", fixedCompletions("table", "json", "yaml"))
+
+	root.AddCommand(
+		newCreateCommand(opts),
+		newGetCommand(opts),
+		newListCommand(opts),
+		newUpdateCommand(opts),
+		newCompleteCommand(opts),
+		newDeleteCommand(opts),
+		newImportCommand(opts),
+		newExportCommand(opts),
+	)
+	return root
+}
+
+func fixedCompletions(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/yaml.v3"
+)
+
+// printTodo writes the todo a command acted on in the format selected with
+// --output, as a single object in JSON and YAML.
+func (o *options) printTodo(w io.Writer, todo *v1.Todo) error {
+	return o.print(w, []*v1.Todo{todo}, true)
+}
+
+// printTodos writes todos in the format selected with --output, as an array
+// in JSON and YAML however many there are, so scripts can rely on the shape.
+func (o *options) printTodos(w io.Writer, todos []*v1.Todo) error {
+	return o.print(w, todos, false)
+}
+
+// print writes todos as a table, or as JSON or YAML using the proto JSON
+// field names so they match what the API accepts.
+func (o *options) print(w io.Writer, todos []*v1.Todo, single bool) error {
+	if o.output == "" || o.output == "table" {
+		return printTable(w, todos)
+	}
+	if o.output != "json" && o.output != "yaml" {
+		return fmt.Errorf("unknown output format %q", o.output)
+	}
+
+	docs, err := todoDocuments(todos)
+	if err != nil {
+		return err
+	}
+	var doc any = docs
+	if single {
+		doc = docs[0]
+	}
+	if o.output == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	defer enc.Close()
+	return enc.Encode(doc)
+}
+
+// todoDocuments converts todos to generic values via protojson.
+func todoDocuments(todos []*v1.Todo) ([]map[string]any, error) {
+	marshal := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+	docs := make([]map[string]any, 0, len(todos))
+	for _, t := range todos {
+		data, err := marshal.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func printTable(w io.Writer, todos []*v1.Todo) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tDONE\tPRIORITY\tDUE\tTITLE")
+	for _, t := range todos {
+		done := ""
+		if t.Completed {
+			done = "x"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			t.Id, done, enumName("PRIORITY", t.Priority.String()), t.DueAt, t.Title)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/spf13/cobra"
+)
+
+// parseEnum accepts the short lower-case name of a proto enum value, e.g.
+// "high" for PRIORITY_HIGH. "none" and "" select the zero value.
+func parseEnum(prefix, s string, values map[string]int32) (int32, error) {
+	if s == "" || s == "none" {
+		return 0, nil
+	}
+	name := prefix + "_" + strings.ToUpper(strings.ReplaceAll(s, "-", "_"))
+	v, ok := values[name]
+	if !ok {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// enumName is the inverse of parseEnum.
+func enumName(prefix, name string) string {
+	name = strings.TrimPrefix(name, prefix+"_")
+	if name == "UNSPECIFIED" {
+		return "none"
+	}
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+func parsePriority(s string) (v1.Priority, error) {
+	v, err := parseEnum("PRIORITY", s, v1.Priority_value)
+	if err != nil {
+		return 0, fmt.Errorf("priority: %w", err)
+	}
+	return v1.Priority(v), nil
+}
+
+func parseRecurrenceMode(s string) (v1.RecurrenceMode, error) {
+	v, err := parseEnum("RECURRENCE_MODE", s, v1.RecurrenceMode_value)
+	if err != nil {
+		return 0, fmt.Errorf("recurrence mode: %w", err)
+	}
+	return v1.RecurrenceMode(v), nil
+}
+
+var todoIDCompletion = cobra.NoFileCompletions
+
+func newCreateCommand(opts *options) *cobra.Command {
+	var priority, dueAt, rrule, timezone, mode, description string
+
+	cmd := &cobra.Command{
+		Use:   "create TITLE",
+		Short: "Create a todo",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := parsePriority(priority)
+			if err != nil {
+				return err
+			}
+			m, err := parseRecurrenceMode(mode)
+			if err != nil {
+				return err
+			}
+			d, err := readDescription(cmd, description)
+			if err != nil {
+				return err
+			}
+
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			res, err := client.Create(cmd.Context(), connect.NewRequest(&v1.CreateRequest{
+				Title:          args[0],
+				Priority:       p,
+				DueAt:          dueAt,
+				Rrule:          rrule,
+				Timezone:       timezone,
+				RecurrenceMode: m,
+				Description:    d,
+			}))
+			if err != nil {
+				return err
+			}
+			return opts.printTodo(cmd.OutOrStdout(), res.Msg.Todo)
+		},
+	}
+
+	addTodoFlags(cmd, &priority, &dueAt, &rrule, &timezone, &mode, &description)
+	return cmd
+}
+
+func addTodoFlags(cmd *cobra.Command, priority, dueAt, rrule, timezone, mode, description *string) {
+	flags := cmd.Flags()
+	flags.StringVarP(priority, "priority", "p", "", "none, low, medium, high or urgent")
+	flags.StringVar(dueAt, "due", "", "due date as RFC 3339, e.g. 2024-05-01T09:00:00Z")
+	flags.StringVar(rrule, "rrule", "", "iCalendar recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO")
+	flags.StringVar(timezone, "timezone", "", "IANA time zone the rule is evaluated in")
+	flags.StringVar(mode, "recurrence-mode", "", "fixed-schedule or after-completion")
+	flags.StringVarP(description, "description", "d", "", "Markdown description, - to read it from stdin")
+	_ = cmd.RegisterFlagCompletionFunc("priority", fixedCompletions("none", "low", "medium", "high", "urgent"))
+	_ = cmd.RegisterFlagCompletionFunc("recurrence-mode", fixedCompletions("fixed-schedule", "after-completion"))
+}
+
+// readDescription returns the value of the description flag, read from
+// stdin if it is "-".
+func readDescription(cmd *cobra.Command, value string) (string, error) {
+	if value != "-" {
+		return value, nil
+	}
+	data, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", fmt.Errorf("description: %w", err)
+	}
+	return string(data), nil
+}
+
+func newGetCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "get ID",
+		Short:             "Show a todo",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: todoIDCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			res, err := client.Get(cmd.Context(), connect.NewRequest(&v1.GetRequest{Id: args[0]}))
+			if err != nil {
+				return err
+			}
+			return opts.printTodo(cmd.OutOrStdout(), res.Msg.Todo)
+		},
+	}
+}
+
+func newListCommand(opts *options) *cobra.Command {
+	var order, query string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List todos",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := parseEnum("LIST_ORDER", order, v1.ListOrder_value)
+			if err != nil {
+				return fmt.Errorf("order: %w", err)
+			}
+
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			res, err := client.List(cmd.Context(), connect.NewRequest(&v1.ListRequest{Order: v1.ListOrder(o), Query: query}))
+			if err != nil {
+				return err
+			}
+			return opts.printTodos(cmd.OutOrStdout(), res.Msg.Todos)
+		},
+	}
+
+	cmd.Flags().StringVar(&order, "order", "", "priority or position (default newest first)")
+	cmd.Flags().StringVarP(&query, "query", "q", "", `only todos whose title or description match, e.g. "release notes" -draft`)
+	_ = cmd.RegisterFlagCompletionFunc("order", fixedCompletions("priority", "position"))
+	return cmd
+}
+
+func newUpdateCommand(opts *options) *cobra.Command {
+	var title, priority, dueAt, rrule, timezone, mode, description string
+	var completed bool
+
+	cmd := &cobra.Command{
+		Use:               "update ID",
+		Short:             "Change fields of a todo",
+		Long:              "Change fields of a todo. Fields without a flag keep their current value.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: todoIDCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.updateTodo(cmd, args[0], func(req *v1.UpdateRequest) error {
+				flags := cmd.Flags()
+				if flags.Changed("title") {
+					req.Title = title
+				}
+				if flags.Changed("completed") {
+					req.Completed = completed
+				}
+				if flags.Changed("priority") {
+					p, err := parsePriority(priority)
+					if err != nil {
+						return err
+					}
+					req.Priority = p
+				}
+				if flags.Changed("due") {
+					req.DueAt = dueAt
+				}
+				if flags.Changed("rrule") {
+					req.Rrule = rrule
+				}
+				if flags.Changed("timezone") {
+					req.Timezone = timezone
+				}
+				if flags.Changed("recurrence-mode") {
+					m, err := parseRecurrenceMode(mode)
+					if err != nil {
+						return err
+					}
+					req.RecurrenceMode = m
+				}
+				if flags.Changed("description") {
+					d, err := readDescription(cmd, description)
+					if err != nil {
+						return err
+					}
+					req.Description = d
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&title, "title", "t", "", "new title")
+	cmd.Flags().BoolVar(&completed, "completed", false, "mark as completed, --completed=false to reopen")
+	addTodoFlags(cmd, &priority, &dueAt, &rrule, &timezone, &mode, &description)
+	return cmd
+}
+
+func newCompleteCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "complete ID",
+		Short:             "Mark a todo as completed",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: todoIDCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.updateTodo(cmd, args[0], func(req *v1.UpdateRequest) error {
+				req.Completed = true
+				return nil
+			})
+		},
+	}
+}
+
+// updateTodo fetches a todo, lets change modify it and writes it back, as
+// Update replaces every field.
+func (o *options) updateTodo(cmd *cobra.Command, id string, change func(*v1.UpdateRequest) error) error {
+	client, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	current, err := client.Get(cmd.Context(), connect.NewRequest(&v1.GetRequest{Id: id}))
+	if err != nil {
+		return err
+	}
+	t := current.Msg.Todo
+	req := &v1.UpdateRequest{
+		Id:             t.Id,
+		Title:          t.Title,
+		Completed:      t.Completed,
+		Priority:       t.Priority,
+		DueAt:          t.DueAt,
+		Rrule:          t.Rrule,
+		Timezone:       t.Timezone,
+		RecurrenceMode: t.RecurrenceMode,
+		Description:    t.Description,
+	}
+	if err := change(req); err != nil {
+		return err
+	}
+
+	res, err := client.Update(cmd.Context(), connect.NewRequest(req))
+	if err != nil {
+		return err
+	}
+	return o.printTodo(cmd.OutOrStdout(), res.Msg.Todo)
+}
+
+func newDeleteCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete ID...",
+		Short:             "Delete todos",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: todoIDCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			for _, id := range args {
+				if _, err := client.Delete(cmd.Context(), connect.NewRequest(&v1.DeleteRequest{Id: id})); err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	gen "github.com/haakaashs/todos-backend/gen/protos/todos/v1/todosv1connect"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("addr: http://file:8080\nprotocol: grpc\ntoken: from-file\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TODOS_ADDR", "http://env:8080")
+	t.Setenv("TODOS_PROTOCOL", "")
+	t.Setenv("TODOS_TOKEN", "from-env")
+
+	opts := &options{configPath: path, protocol: "grpcweb"}
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+
+	if cfg.Addr != "http://env:8080" {
+		t.Errorf("Expected addr from env, got %s", cfg.Addr)
+	}
+	if cfg.Protocol != "grpcweb" {
+		t.Errorf("Expected protocol from flag, got %s", cfg.Protocol)
+	}
+	if cfg.Token != "from-env" {
+		t.Errorf("Expected token from env, got %s", cfg.Token)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TODOS_ADDR", "")
+
+	cfg, err := (&options{}).loadConfig()
+	if err != nil {
+		t.Fatalf("Expected a missing default config to be fine, got %v", err)
+	}
+	if cfg.Addr != defaultAddr {
+		t.Errorf("Expected default addr, got %s", cfg.Addr)
+	}
+
+	_, err = (&options{configPath: filepath.Join(t.TempDir(), "missing.yaml")}).loadConfig()
+	if err == nil {
+		t.Errorf("Expected an error for an explicit missing config")
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	tests := []struct {
+		flag, path string
+		want       v1.DataFormat
+	}{
+		{"", "todos.csv", v1.DataFormat_DATA_FORMAT_CSV},
+		{"", "backlog.md", v1.DataFormat_DATA_FORMAT_MARKDOWN},
+		{"", "work.ics", v1.DataFormat_DATA_FORMAT_ICALENDAR},
+		{"todotxt", "todo", v1.DataFormat_DATA_FORMAT_TODO_TXT},
+		{"NDJSON", "todos.csv", v1.DataFormat_DATA_FORMAT_NDJSON},
+	}
+	for _, tt := range tests {
+		got, err := resolveFormat(tt.flag, tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveFormat(%q, %q): Expected %v, got %v (%v)", tt.flag, tt.path, tt.want, got, err)
+		}
+	}
+
+	if _, err := resolveFormat("", "-"); err == nil {
+		t.Errorf("Expected an error without format or extension")
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	p, err := parsePriority("urgent")
+	if err != nil || p != v1.Priority_PRIORITY_URGENT {
+		t.Errorf("Expected PRIORITY_URGENT, got %v (%v)", p, err)
+	}
+	if _, err := parsePriority("critical"); err == nil {
+		t.Errorf("Expected an error for an unknown priority")
+	}
+	if name := enumName("PRIORITY", v1.Priority_PRIORITY_UNSPECIFIED.String()); name != "none" {
+		t.Errorf("Expected none, got %s", name)
+	}
+}
+
+func TestPrintTodos(t *testing.T) {
+	todos := []*v1.Todo{
+		{Id: "1", Title: "Write docs", Priority: v1.Priority_PRIORITY_HIGH},
+		{Id: "2", Title: "Ship", Completed: true},
+	}
+
+	var buf bytes.Buffer
+	if err := (&options{output: "table"}).printTodos(&buf, todos); err != nil {
+		t.Fatalf("printTodos failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "high") || !strings.Contains(buf.String(), "Ship") {
+		t.Errorf("Expected both todos in the table, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&options{output: "yaml"}).printTodo(&buf, todos[0]); err != nil {
+		t.Fatalf("printTodo failed: %v", err)
+	}
+	if strings.HasPrefix(buf.String(), "- ") || !strings.Contains(buf.String(), "title: Write docs") || !strings.Contains(buf.String(), "priority: PRIORITY_HIGH") {
+		t.Errorf("Expected a YAML document, got %q", buf.String())
+	}
+
+	// a list of one is still a list
+	for _, output := range []string{"json", "yaml"} {
+		buf.Reset()
+		if err := (&options{output: output}).printTodos(&buf, todos[:1]); err != nil {
+			t.Fatalf("printTodos failed: %v", err)
+		}
+		if !strings.HasPrefix(buf.String(), "[") && !strings.HasPrefix(buf.String(), "- ") {
+			t.Errorf("Expected a %s array, got %q", output, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := (&options{output: "json"}).printTodos(&buf, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected an empty JSON array, got %q %v", buf.String(), err)
+	}
+
+	if err := (&options{output: "xml"}).printTodos(&buf, todos); err == nil {
+		t.Errorf("Expected an error for an unknown output format")
+	}
+}
+
+// fakeTodos answers Get and Update for TestProtocols.
+type fakeTodos struct {
+	gen.UnimplementedTodosServiceHandler
+	auth string
+}
+
+func (f *fakeTodos) Get(_ context.Context, req *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error) {
+	f.auth = req.Header().Get("Authorization")
+	return connect.NewResponse(&v1.GetResponse{Todo: &v1.Todo{Id: req.Msg.Id, Title: "Water plants"}}), nil
+}
+
+func (f *fakeTodos) Update(_ context.Context, req *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error) {
+	return connect.NewResponse(&v1.UpdateResponse{Todo: &v1.Todo{
+		Id: req.Msg.Id, Title: req.Msg.Title, Completed: req.Msg.Completed,
+	}}), nil
+}
+
+func TestProtocols(t *testing.T) {
+	fake := &fakeTodos{}
+	mux := http.NewServeMux()
+	mux.Handle(gen.NewTodosServiceHandler(fake))
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TODOS_TOKEN", "secret")
+	for _, protocol := range []string{"connect", "grpc", "grpcweb"} {
+		var out bytes.Buffer
+		cmd := newRootCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"complete", "42", "--addr", server.URL, "--protocol", protocol, "-o", "json"})
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s: complete failed: %v", protocol, err)
+		}
+		if !strings.Contains(out.String(), `"completed": true`) {
+			t.Errorf("%s: Expected a completed todo, got %q", protocol, out.String())
+		}
+		if fake.auth != "Bearer secret" {
+			t.Errorf("%s: Expected the bearer token, got %q", protocol, fake.auth)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/spf13/cobra"
+)
+
+// chunkSize is the size of each ImportRequest message.
+const chunkSize = 256 * 1024
+
+// formats maps --format values and file extensions to data formats.
+var formats = map[string]v1.DataFormat{
+	"ndjson":   v1.DataFormat_DATA_FORMAT_NDJSON,
+	"jsonl":    v1.DataFormat_DATA_FORMAT_NDJSON,
+	"csv":      v1.DataFormat_DATA_FORMAT_CSV,
+	"markdown": v1.DataFormat_DATA_FORMAT_MARKDOWN,
+	"md":       v1.DataFormat_DATA_FORMAT_MARKDOWN,
+	"todotxt":  v1.DataFormat_DATA_FORMAT_TODO_TXT,
+	"txt":      v1.DataFormat_DATA_FORMAT_TODO_TXT,
+	"ics":      v1.DataFormat_DATA_FORMAT_ICALENDAR,
+}
+
+var formatCompletions = fixedCompletions("ndjson", "csv", "markdown", "todotxt", "ics")
+
+// resolveFormat returns the format named by flag or, if that is empty, the
+// one implied by the extension of path.
+func resolveFormat(flag, path string) (v1.DataFormat, error) {
+	name := strings.ToLower(flag)
+	if name == "" {
+		name = strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	}
+	if f, ok := formats[name]; ok {
+		return f, nil
+	}
+	if flag == "" {
+		return 0, errors.New("cannot tell the format from the file name, use --format")
+	}
+	return 0, fmt.Errorf("unknown format %q", flag)
+}
+
+func newImportCommand(opts *options) *cobra.Command {
+	var format string
+	var dryRun, allowDuplicates bool
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Create todos from a file, - for stdin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := resolveFormat(format, args[0])
+			if err != nil {
+				return err
+			}
+
+			in := io.Reader(cmd.InOrStdin())
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				in = file
+			}
+
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+
+			stream := client.Import(cmd.Context())
+			req := &v1.ImportRequest{Format: f, DryRun: dryRun, AllowDuplicates: allowDuplicates}
+			buf := make([]byte, chunkSize)
+			for {
+				n, err := in.Read(buf)
+				if n > 0 {
+					req.Chunk = buf[:n]
+					if err := stream.Send(req); err != nil {
+						break // the real error is returned by CloseAndReceive
+					}
+					req = &v1.ImportRequest{}
+				}
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+			res, err := stream.CloseAndReceive()
+			if err != nil {
+				return err
+			}
+
+			for _, title := range res.Msg.Duplicates {
+				fmt.Fprintln(cmd.ErrOrStderr(), "skipped duplicate:", title)
+			}
+			if res.Msg.DryRun {
+				fmt.Fprintln(cmd.ErrOrStderr(), "dry run, nothing was created")
+			}
+			return opts.printTodos(cmd.OutOrStdout(), res.Msg.Todos)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&format, "format", "f", "", "ndjson, csv, markdown, todotxt or ics (default from the file extension)")
+	flags.BoolVar(&dryRun, "dry-run", false, "report what would be created without creating it")
+	flags.BoolVar(&allowDuplicates, "allow-duplicates", false, "create todos whose title already exists")
+	_ = cmd.RegisterFlagCompletionFunc("format", formatCompletions)
+	return cmd
+}
+
+func newExportCommand(opts *options) *cobra.Command {
+	var format, outPath string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write all todos to a file or stdout",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" && outPath == "" {
+				format = "ndjson"
+			}
+			f, err := resolveFormat(format, outPath)
+			if err != nil {
+				return err
+			}
+
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			stream, err := client.Export(cmd.Context(), connect.NewRequest(&v1.ExportRequest{Format: f}))
+			if err != nil {
+				return err
+			}
+			defer stream.Close()
+
+			out := cmd.OutOrStdout()
+			if outPath != "" && outPath != "-" {
+				file, err := os.Create(outPath)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				out = file
+			}
+
+			for stream.Receive() {
+				if _, err := out.Write(stream.Msg().Chunk); err != nil {
+					return err
+				}
+			}
+			return stream.Err()
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&format, "format", "f", "", "ndjson, csv, markdown, todotxt or ics (default from --file, else ndjson)")
+	flags.StringVar(&outPath, "file", "", "write to this file instead of stdout")
+	_ = cmd.RegisterFlagCompletionFunc("format", formatCompletions)
+	return cmd
+}
@@ -0,0 +1,10 @@
+// Package openapi embeds the OpenAPI document that buf generate writes for
+// the REST mappings of the API.
+package openapi
+
+import _ "embed"
+
+// Spec is the generated OpenAPI 3 document in YAML.
+//
+//go:embed openapi.yaml
+var Spec []byte
@@ -8,8 +8,10 @@ package todosv1
 
 import (
 	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,30 +24,4996 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Role int32
+
+const (
+	Role_ROLE_UNSPECIFIED Role = 0
+	// may read the todos of a list
+	Role_ROLE_VIEWER Role = 1
+	// may also create, change and delete them
+	Role_ROLE_EDITOR Role = 2
+	// may also manage members
+	Role_ROLE_OWNER Role = 3
+)
+
+// Enum value maps for Role.
+var (
+	Role_name = map[int32]string{
+		0: "ROLE_UNSPECIFIED",
+		1: "ROLE_VIEWER",
+		2: "ROLE_EDITOR",
+		3: "ROLE_OWNER",
+	}
+	Role_value = map[string]int32{
+		"ROLE_UNSPECIFIED": 0,
+		"ROLE_VIEWER":      1,
+		"ROLE_EDITOR":      2,
+		"ROLE_OWNER":       3,
+	}
+)
+
+func (x Role) Enum() *Role {
+	p := new(Role)
+	*p = x
+	return p
+}
+
+func (x Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[0].Descriptor()
+}
+
+func (Role) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[0]
+}
+
+func (x Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Role.Descriptor instead.
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{0}
+}
+
+type InvitationStatus int32
+
+const (
+	InvitationStatus_INVITATION_STATUS_UNSPECIFIED InvitationStatus = 0
+	InvitationStatus_INVITATION_STATUS_PENDING     InvitationStatus = 1
+	InvitationStatus_INVITATION_STATUS_ACCEPTED    InvitationStatus = 2
+	InvitationStatus_INVITATION_STATUS_DECLINED    InvitationStatus = 3
+)
+
+// Enum value maps for InvitationStatus.
+var (
+	InvitationStatus_name = map[int32]string{
+		0: "INVITATION_STATUS_UNSPECIFIED",
+		1: "INVITATION_STATUS_PENDING",
+		2: "INVITATION_STATUS_ACCEPTED",
+		3: "INVITATION_STATUS_DECLINED",
+	}
+	InvitationStatus_value = map[string]int32{
+		"INVITATION_STATUS_UNSPECIFIED": 0,
+		"INVITATION_STATUS_PENDING":     1,
+		"INVITATION_STATUS_ACCEPTED":    2,
+		"INVITATION_STATUS_DECLINED":    3,
+	}
+)
+
+func (x InvitationStatus) Enum() *InvitationStatus {
+	p := new(InvitationStatus)
+	*p = x
+	return p
+}
+
+func (x InvitationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InvitationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[1].Descriptor()
+}
+
+func (InvitationStatus) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[1]
+}
+
+func (x InvitationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InvitationStatus.Descriptor instead.
+func (InvitationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{1}
+}
+
+type Priority int32
+
+const (
+	// no priority set
+	Priority_PRIORITY_UNSPECIFIED Priority = 0
+	Priority_PRIORITY_LOW         Priority = 1
+	Priority_PRIORITY_MEDIUM      Priority = 2
+	Priority_PRIORITY_HIGH        Priority = 3
+	Priority_PRIORITY_URGENT      Priority = 4
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_LOW",
+		2: "PRIORITY_MEDIUM",
+		3: "PRIORITY_HIGH",
+		4: "PRIORITY_URGENT",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_LOW":         1,
+		"PRIORITY_MEDIUM":      2,
+		"PRIORITY_HIGH":        3,
+		"PRIORITY_URGENT":      4,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[2].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[2]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{2}
+}
+
+type RecurrenceMode int32
+
+const (
+	// same as RECURRENCE_MODE_FIXED_SCHEDULE
+	RecurrenceMode_RECURRENCE_MODE_UNSPECIFIED RecurrenceMode = 0
+	// the next occurrence follows the rule from the previous due date
+	RecurrenceMode_RECURRENCE_MODE_FIXED_SCHEDULE RecurrenceMode = 1
+	// the next occurrence follows the rule from the completion date
+	RecurrenceMode_RECURRENCE_MODE_AFTER_COMPLETION RecurrenceMode = 2
+)
+
+// Enum value maps for RecurrenceMode.
+var (
+	RecurrenceMode_name = map[int32]string{
+		0: "RECURRENCE_MODE_UNSPECIFIED",
+		1: "RECURRENCE_MODE_FIXED_SCHEDULE",
+		2: "RECURRENCE_MODE_AFTER_COMPLETION",
+	}
+	RecurrenceMode_value = map[string]int32{
+		"RECURRENCE_MODE_UNSPECIFIED":      0,
+		"RECURRENCE_MODE_FIXED_SCHEDULE":   1,
+		"RECURRENCE_MODE_AFTER_COMPLETION": 2,
+	}
+)
+
+func (x RecurrenceMode) Enum() *RecurrenceMode {
+	p := new(RecurrenceMode)
+	*p = x
+	return p
+}
+
+func (x RecurrenceMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RecurrenceMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[3].Descriptor()
+}
+
+func (RecurrenceMode) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[3]
+}
+
+func (x RecurrenceMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RecurrenceMode.Descriptor instead.
+func (RecurrenceMode) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{3}
+}
+
+type DataFormat int32
+
+const (
+	// same as DATA_FORMAT_NDJSON
+	DataFormat_DATA_FORMAT_UNSPECIFIED DataFormat = 0
+	// one JSON todo per line
+	DataFormat_DATA_FORMAT_NDJSON DataFormat = 1
+	// title, completed, priority, due_at, rrule and timezone columns with a header row
+	DataFormat_DATA_FORMAT_CSV DataFormat = 2
+	// "- [ ] title" and "- [x] title" checklist items
+	DataFormat_DATA_FORMAT_MARKDOWN DataFormat = 3
+	// http://todotxt.org
+	DataFormat_DATA_FORMAT_TODO_TXT DataFormat = 4
+	// RFC 5545 VTODO components
+	DataFormat_DATA_FORMAT_ICALENDAR DataFormat = 5
+)
+
+// Enum value maps for DataFormat.
+var (
+	DataFormat_name = map[int32]string{
+		0: "DATA_FORMAT_UNSPECIFIED",
+		1: "DATA_FORMAT_NDJSON",
+		2: "DATA_FORMAT_CSV",
+		3: "DATA_FORMAT_MARKDOWN",
+		4: "DATA_FORMAT_TODO_TXT",
+		5: "DATA_FORMAT_ICALENDAR",
+	}
+	DataFormat_value = map[string]int32{
+		"DATA_FORMAT_UNSPECIFIED": 0,
+		"DATA_FORMAT_NDJSON":      1,
+		"DATA_FORMAT_CSV":         2,
+		"DATA_FORMAT_MARKDOWN":    3,
+		"DATA_FORMAT_TODO_TXT":    4,
+		"DATA_FORMAT_ICALENDAR":   5,
+	}
+)
+
+func (x DataFormat) Enum() *DataFormat {
+	p := new(DataFormat)
+	*p = x
+	return p
+}
+
+func (x DataFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[4].Descriptor()
+}
+
+func (DataFormat) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[4]
+}
+
+func (x DataFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataFormat.Descriptor instead.
+func (DataFormat) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{4}
+}
+
+type ListOrder int32
+
+const (
+	// newest first
+	ListOrder_LIST_ORDER_UNSPECIFIED ListOrder = 0
+	// highest priority first, then manual order
+	ListOrder_LIST_ORDER_PRIORITY ListOrder = 1
+	// manual drag-and-drop order
+	ListOrder_LIST_ORDER_POSITION ListOrder = 2
+)
+
+// Enum value maps for ListOrder.
+var (
+	ListOrder_name = map[int32]string{
+		0: "LIST_ORDER_UNSPECIFIED",
+		1: "LIST_ORDER_PRIORITY",
+		2: "LIST_ORDER_POSITION",
+	}
+	ListOrder_value = map[string]int32{
+		"LIST_ORDER_UNSPECIFIED": 0,
+		"LIST_ORDER_PRIORITY":    1,
+		"LIST_ORDER_POSITION":    2,
+	}
+)
+
+func (x ListOrder) Enum() *ListOrder {
+	p := new(ListOrder)
+	*p = x
+	return p
+}
+
+func (x ListOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[5].Descriptor()
+}
+
+func (ListOrder) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[5]
+}
+
+func (x ListOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListOrder.Descriptor instead.
+func (ListOrder) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{5}
+}
+
+type MutationOp int32
+
+const (
+	MutationOp_MUTATION_OP_UNSPECIFIED MutationOp = 0
+	MutationOp_MUTATION_OP_CREATE      MutationOp = 1
+	MutationOp_MUTATION_OP_UPDATE      MutationOp = 2
+	MutationOp_MUTATION_OP_DELETE      MutationOp = 3
+)
+
+// Enum value maps for MutationOp.
+var (
+	MutationOp_name = map[int32]string{
+		0: "MUTATION_OP_UNSPECIFIED",
+		1: "MUTATION_OP_CREATE",
+		2: "MUTATION_OP_UPDATE",
+		3: "MUTATION_OP_DELETE",
+	}
+	MutationOp_value = map[string]int32{
+		"MUTATION_OP_UNSPECIFIED": 0,
+		"MUTATION_OP_CREATE":      1,
+		"MUTATION_OP_UPDATE":      2,
+		"MUTATION_OP_DELETE":      3,
+	}
+)
+
+func (x MutationOp) Enum() *MutationOp {
+	p := new(MutationOp)
+	*p = x
+	return p
+}
+
+func (x MutationOp) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MutationOp) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[6].Descriptor()
+}
+
+func (MutationOp) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[6]
+}
+
+func (x MutationOp) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MutationOp.Descriptor instead.
+func (MutationOp) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{6}
+}
+
+type MutationStatus int32
+
+const (
+	MutationStatus_MUTATION_STATUS_UNSPECIFIED MutationStatus = 0
+	MutationStatus_MUTATION_STATUS_APPLIED     MutationStatus = 1
+	MutationStatus_MUTATION_STATUS_CONFLICT    MutationStatus = 2
+	MutationStatus_MUTATION_STATUS_NOT_FOUND   MutationStatus = 3
+	MutationStatus_MUTATION_STATUS_REJECTED    MutationStatus = 4
+)
+
+// Enum value maps for MutationStatus.
+var (
+	MutationStatus_name = map[int32]string{
+		0: "MUTATION_STATUS_UNSPECIFIED",
+		1: "MUTATION_STATUS_APPLIED",
+		2: "MUTATION_STATUS_CONFLICT",
+		3: "MUTATION_STATUS_NOT_FOUND",
+		4: "MUTATION_STATUS_REJECTED",
+	}
+	MutationStatus_value = map[string]int32{
+		"MUTATION_STATUS_UNSPECIFIED": 0,
+		"MUTATION_STATUS_APPLIED":     1,
+		"MUTATION_STATUS_CONFLICT":    2,
+		"MUTATION_STATUS_NOT_FOUND":   3,
+		"MUTATION_STATUS_REJECTED":    4,
+	}
+)
+
+func (x MutationStatus) Enum() *MutationStatus {
+	p := new(MutationStatus)
+	*p = x
+	return p
+}
+
+func (x MutationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MutationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_todos_v1_todos_proto_enumTypes[7].Descriptor()
+}
+
+func (MutationStatus) Type() protoreflect.EnumType {
+	return &file_protos_todos_v1_todos_proto_enumTypes[7]
+}
+
+func (x MutationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MutationStatus.Descriptor instead.
+func (MutationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{7}
+}
+
 type Todo struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title     string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Completed bool                   `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+	// version is the change sequence of the last write to this todo.
+	Version  int64    `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Priority Priority `protobuf:"varint,5,opt,name=priority,proto3,enum=todos.v1.Priority" json:"priority,omitempty"`
+	// position is the fractional index of the todo in the manual order.
+	Position string `protobuf:"bytes,6,opt,name=position,proto3" json:"position,omitempty"`
+	// due_at is an RFC 3339 timestamp.
+	DueAt string `protobuf:"bytes,7,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	// rrule is an iCalendar RRULE such as "FREQ=WEEKLY;BYDAY=MO".
+	Rrule string `protobuf:"bytes,8,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	// timezone is the IANA zone recurrences are computed in, UTC if empty.
+	Timezone       string         `protobuf:"bytes,9,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	RecurrenceMode RecurrenceMode `protobuf:"varint,10,opt,name=recurrence_mode,json=recurrenceMode,proto3,enum=todos.v1.RecurrenceMode" json:"recurrence_mode,omitempty"`
+	// updated_at is the RFC 3339 time of the last write to this todo.
+	UpdatedAt string `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// list_id is the shared list the todo belongs to, empty for none.
+	ListId string `protobuf:"bytes,12,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// description is Markdown.
+	Description string `protobuf:"bytes,13,opt,name=description,proto3" json:"description,omitempty"`
+	// description_html is the description rendered to sanitized HTML, unset
+	// when there is no description.
+	DescriptionHtml *string `protobuf:"bytes,14,opt,name=description_html,json=descriptionHtml,proto3,oneof" json:"description_html,omitempty"`
+	// links are the http, https and mailto links of the description.
+	Links []*Link `protobuf:"bytes,15,rep,name=links,proto3" json:"links,omitempty"`
+	// checklist holds the "- [ ]" and "- [x]" items of the description.
+	Checklist []*ChecklistItem `protobuf:"bytes,16,rep,name=checklist,proto3" json:"checklist,omitempty"`
+	// comment_count is the number of comments on the todo, set by Get and
+	// List.
+	CommentCount int32 `protobuf:"varint,17,opt,name=comment_count,json=commentCount,proto3" json:"comment_count,omitempty"`
+	// blocked is set when a blocker of the todo is still open, by Get, List
+	// and the dependency methods. A blocked todo cannot be completed.
+	Blocked       bool `protobuf:"varint,18,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Todo) Reset() {
+	*x = Todo{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Todo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Todo) ProtoMessage() {}
+
+func (x *Todo) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Todo.ProtoReflect.Descriptor instead.
+func (*Todo) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Todo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Todo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Todo) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *Todo) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Todo) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *Todo) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Todo) GetDueAt() string {
+	if x != nil {
+		return x.DueAt
+	}
+	return ""
+}
+
+func (x *Todo) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *Todo) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Todo) GetRecurrenceMode() RecurrenceMode {
+	if x != nil {
+		return x.RecurrenceMode
+	}
+	return RecurrenceMode_RECURRENCE_MODE_UNSPECIFIED
+}
+
+func (x *Todo) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Todo) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *Todo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Todo) GetDescriptionHtml() string {
+	if x != nil && x.DescriptionHtml != nil {
+		return *x.DescriptionHtml
+	}
+	return ""
+}
+
+func (x *Todo) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *Todo) GetChecklist() []*ChecklistItem {
+	if x != nil {
+		return x.Checklist
+	}
+	return nil
+}
+
+func (x *Todo) GetCommentCount() int32 {
+	if x != nil {
+		return x.CommentCount
+	}
+	return 0
+}
+
+func (x *Todo) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+type Link struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Link) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Link) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ChecklistItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Checked       bool                   `protobuf:"varint,2,opt,name=checked,proto3" json:"checked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecklistItem) Reset() {
+	*x = ChecklistItem{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecklistItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecklistItem) ProtoMessage() {}
+
+func (x *ChecklistItem) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistItem.ProtoReflect.Descriptor instead.
+func (*ChecklistItem) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChecklistItem) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetChecked() bool {
+	if x != nil {
+		return x.Checked
+	}
+	return false
+}
+
+type CreateRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Title          string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Priority       Priority               `protobuf:"varint,2,opt,name=priority,proto3,enum=todos.v1.Priority" json:"priority,omitempty"`
+	DueAt          string                 `protobuf:"bytes,3,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	Rrule          string                 `protobuf:"bytes,4,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	Timezone       string                 `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	RecurrenceMode RecurrenceMode         `protobuf:"varint,6,opt,name=recurrence_mode,json=recurrenceMode,proto3,enum=todos.v1.RecurrenceMode" json:"recurrence_mode,omitempty"`
+	ListId         string                 `protobuf:"bytes,7,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// description is Markdown.
+	Description   string `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *CreateRequest) GetDueAt() string {
+	if x != nil {
+		return x.DueAt
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetRecurrenceMode() RecurrenceMode {
+	if x != nil {
+		return x.RecurrenceMode
+	}
+	return RecurrenceMode_RECURRENCE_MODE_UNSPECIFIED
+}
+
+func (x *CreateRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type CreateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type ListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Order ListOrder              `protobuf:"varint,1,opt,name=order,proto3,enum=todos.v1.ListOrder" json:"order,omitempty"`
+	// list_id limits the result to one shared list.
+	ListId string `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// query limits the result to todos whose title or description contain
+	// its words. Quoted phrases, "or" and "-word" work as in web search.
+	Query         string `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListRequest) GetOrder() ListOrder {
+	if x != nil {
+		return x.Order
+	}
+	return ListOrder_LIST_ORDER_UNSPECIFIED
+}
+
+func (x *ListRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *ListRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{10}
+}
+
+type UpdateRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Completed      bool                   `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+	Priority       Priority               `protobuf:"varint,4,opt,name=priority,proto3,enum=todos.v1.Priority" json:"priority,omitempty"`
+	DueAt          string                 `protobuf:"bytes,5,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	Rrule          string                 `protobuf:"bytes,6,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	Timezone       string                 `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	RecurrenceMode RecurrenceMode         `protobuf:"varint,8,opt,name=recurrence_mode,json=recurrenceMode,proto3,enum=todos.v1.RecurrenceMode" json:"recurrence_mode,omitempty"`
+	// update_mask limits the update to the listed fields when set.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,9,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// description is Markdown.
+	Description   string `protobuf:"bytes,10,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *UpdateRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *UpdateRequest) GetDueAt() string {
+	if x != nil {
+		return x.DueAt
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetRecurrenceMode() RecurrenceMode {
+	if x != nil {
+		return x.RecurrenceMode
+	}
+	return RecurrenceMode_RECURRENCE_MODE_UNSPECIFIED
+}
+
+func (x *UpdateRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type UpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type SyncRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sync_token is the token returned by the previous Sync call, empty for a full sync.
+	SyncToken     string `protobuf:"bytes,1,opt,name=sync_token,json=syncToken,proto3" json:"sync_token,omitempty"`
+	PageSize      int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncRequest) Reset() {
+	*x = SyncRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncRequest) ProtoMessage() {}
+
+func (x *SyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncRequest.ProtoReflect.Descriptor instead.
+func (*SyncRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SyncRequest) GetSyncToken() string {
+	if x != nil {
+		return x.SyncToken
+	}
+	return ""
+}
+
+func (x *SyncRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type SyncResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Todos      []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	DeletedIds []string               `protobuf:"bytes,2,rep,name=deleted_ids,json=deletedIds,proto3" json:"deleted_ids,omitempty"`
+	SyncToken  string                 `protobuf:"bytes,3,opt,name=sync_token,json=syncToken,proto3" json:"sync_token,omitempty"`
+	// has_more is set when more changes are pending and Sync should be called again.
+	HasMore       bool `protobuf:"varint,4,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncResponse) Reset() {
+	*x = SyncResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncResponse) ProtoMessage() {}
+
+func (x *SyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncResponse.ProtoReflect.Descriptor instead.
+func (*SyncResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SyncResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+func (x *SyncResponse) GetDeletedIds() []string {
+	if x != nil {
+		return x.DeletedIds
+	}
+	return nil
+}
+
+func (x *SyncResponse) GetSyncToken() string {
+	if x != nil {
+		return x.SyncToken
+	}
+	return ""
+}
+
+func (x *SyncResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type Mutation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Op        MutationOp             `protobuf:"varint,1,opt,name=op,proto3,enum=todos.v1.MutationOp" json:"op,omitempty"`
+	Id        string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Title     string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Completed bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	// base_version is the version the client last saw; ignored for creates.
+	BaseVersion   int64    `protobuf:"varint,5,opt,name=base_version,json=baseVersion,proto3" json:"base_version,omitempty"`
+	Priority      Priority `protobuf:"varint,6,opt,name=priority,proto3,enum=todos.v1.Priority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mutation) Reset() {
+	*x = Mutation{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mutation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mutation) ProtoMessage() {}
+
+func (x *Mutation) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mutation.ProtoReflect.Descriptor instead.
+func (*Mutation) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Mutation) GetOp() MutationOp {
+	if x != nil {
+		return x.Op
+	}
+	return MutationOp_MUTATION_OP_UNSPECIFIED
+}
+
+func (x *Mutation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Mutation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Mutation) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *Mutation) GetBaseVersion() int64 {
+	if x != nil {
+		return x.BaseVersion
+	}
+	return 0
+}
+
+func (x *Mutation) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+type PushRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mutations     []*Mutation            `protobuf:"bytes,1,rep,name=mutations,proto3" json:"mutations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushRequest) Reset() {
+	*x = PushRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushRequest) ProtoMessage() {}
+
+func (x *PushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushRequest.ProtoReflect.Descriptor instead.
+func (*PushRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PushRequest) GetMutations() []*Mutation {
+	if x != nil {
+		return x.Mutations
+	}
+	return nil
+}
+
+type MutationResult struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status MutationStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=todos.v1.MutationStatus" json:"status,omitempty"`
+	// todo is the server state after the mutation, or the winning state on conflict.
+	Todo *Todo `protobuf:"bytes,3,opt,name=todo,proto3" json:"todo,omitempty"`
+	// deleted is set when the server state is a tombstone.
+	Deleted       bool   `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MutationResult) Reset() {
+	*x = MutationResult{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MutationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MutationResult) ProtoMessage() {}
+
+func (x *MutationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MutationResult.ProtoReflect.Descriptor instead.
+func (*MutationResult) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MutationResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MutationResult) GetStatus() MutationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return MutationStatus_MUTATION_STATUS_UNSPECIFIED
+}
+
+func (x *MutationResult) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+func (x *MutationResult) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *MutationResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PushResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*MutationResult      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushResponse) Reset() {
+	*x = PushResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushResponse) ProtoMessage() {}
+
+func (x *PushResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushResponse.ProtoReflect.Descriptor instead.
+func (*PushResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PushResponse) GetResults() []*MutationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type MoveRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*MoveRequest_BeforeId
+	//	*MoveRequest_AfterId
+	Target        isMoveRequest_Target `protobuf_oneof:"target"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveRequest) Reset() {
+	*x = MoveRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRequest) ProtoMessage() {}
+
+func (x *MoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRequest.ProtoReflect.Descriptor instead.
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MoveRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveRequest) GetTarget() isMoveRequest_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *MoveRequest) GetBeforeId() string {
+	if x != nil {
+		if x, ok := x.Target.(*MoveRequest_BeforeId); ok {
+			return x.BeforeId
+		}
+	}
+	return ""
+}
+
+func (x *MoveRequest) GetAfterId() string {
+	if x != nil {
+		if x, ok := x.Target.(*MoveRequest_AfterId); ok {
+			return x.AfterId
+		}
+	}
+	return ""
+}
+
+type isMoveRequest_Target interface {
+	isMoveRequest_Target()
+}
+
+type MoveRequest_BeforeId struct {
+	// before_id places the todo directly before this sibling.
+	BeforeId string `protobuf:"bytes,2,opt,name=before_id,json=beforeId,proto3,oneof"`
+}
+
+type MoveRequest_AfterId struct {
+	// after_id places the todo directly after this sibling.
+	AfterId string `protobuf:"bytes,3,opt,name=after_id,json=afterId,proto3,oneof"`
+}
+
+func (*MoveRequest_BeforeId) isMoveRequest_Target() {}
+
+func (*MoveRequest_AfterId) isMoveRequest_Target() {}
+
+type MoveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveResponse) Reset() {
+	*x = MoveResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveResponse) ProtoMessage() {}
+
+func (x *MoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveResponse.ProtoReflect.Descriptor instead.
+func (*MoveResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MoveResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+type PreviewOccurrencesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rrule string                 `protobuf:"bytes,1,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	// start_at is the RFC 3339 due date of the first occurrence.
+	StartAt       string `protobuf:"bytes,2,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	Timezone      string `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Count         int32  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewOccurrencesRequest) Reset() {
+	*x = PreviewOccurrencesRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewOccurrencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewOccurrencesRequest) ProtoMessage() {}
+
+func (x *PreviewOccurrencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewOccurrencesRequest.ProtoReflect.Descriptor instead.
+func (*PreviewOccurrencesRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PreviewOccurrencesRequest) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *PreviewOccurrencesRequest) GetStartAt() string {
+	if x != nil {
+		return x.StartAt
+	}
+	return ""
+}
+
+func (x *PreviewOccurrencesRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *PreviewOccurrencesRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PreviewOccurrencesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// occurrences are RFC 3339 timestamps in the requested time zone.
+	Occurrences   []string `protobuf:"bytes,1,rep,name=occurrences,proto3" json:"occurrences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewOccurrencesResponse) Reset() {
+	*x = PreviewOccurrencesResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewOccurrencesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewOccurrencesResponse) ProtoMessage() {}
+
+func (x *PreviewOccurrencesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewOccurrencesResponse.ProtoReflect.Descriptor instead.
+func (*PreviewOccurrencesResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PreviewOccurrencesResponse) GetOccurrences() []string {
+	if x != nil {
+		return x.Occurrences
+	}
+	return nil
+}
+
+type ExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        DataFormat             `protobuf:"varint,1,opt,name=format,proto3,enum=todos.v1.DataFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExportRequest) GetFormat() DataFormat {
+	if x != nil {
+		return x.Format
+	}
+	return DataFormat_DATA_FORMAT_UNSPECIFIED
+}
+
+type ExportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunk         []byte                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportResponse) Reset() {
+	*x = ExportResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportResponse) ProtoMessage() {}
+
+func (x *ExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportResponse.ProtoReflect.Descriptor instead.
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExportResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type ImportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// format, dry_run and allow_duplicates are read from the first message only.
+	Format DataFormat `protobuf:"varint,1,opt,name=format,proto3,enum=todos.v1.DataFormat" json:"format,omitempty"`
+	// dry_run reports what would be created without writing anything.
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// allow_duplicates imports todos whose title already exists.
+	AllowDuplicates bool   `protobuf:"varint,3,opt,name=allow_duplicates,json=allowDuplicates,proto3" json:"allow_duplicates,omitempty"`
+	Chunk           []byte `protobuf:"bytes,4,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ImportRequest) Reset() {
+	*x = ImportRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRequest) ProtoMessage() {}
+
+func (x *ImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRequest.ProtoReflect.Descriptor instead.
+func (*ImportRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ImportRequest) GetFormat() DataFormat {
+	if x != nil {
+		return x.Format
+	}
+	return DataFormat_DATA_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportRequest) GetAllowDuplicates() bool {
+	if x != nil {
+		return x.AllowDuplicates
+	}
+	return false
+}
+
+func (x *ImportRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type ImportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todos are the created todos, or the todos that would be created on a dry run.
+	Todos []*Todo `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	// duplicates are the titles skipped because a todo with that title exists.
+	Duplicates    []string `protobuf:"bytes,2,rep,name=duplicates,proto3" json:"duplicates,omitempty"`
+	DryRun        bool     `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportResponse) Reset() {
+	*x = ImportResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportResponse) ProtoMessage() {}
+
+func (x *ImportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportResponse.ProtoReflect.Descriptor instead.
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ImportResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+func (x *ImportResponse) GetDuplicates() []string {
+	if x != nil {
+		return x.Duplicates
+	}
+	return nil
+}
+
+func (x *ImportResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type CalendarFeed struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// created_at is an RFC 3339 timestamp.
+	CreatedAt     string `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarFeed) Reset() {
+	*x = CalendarFeed{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarFeed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarFeed) ProtoMessage() {}
+
+func (x *CalendarFeed) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarFeed.ProtoReflect.Descriptor instead.
+func (*CalendarFeed) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CalendarFeed) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CalendarFeed) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CalendarFeed) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateCalendarFeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCalendarFeedRequest) Reset() {
+	*x = CreateCalendarFeedRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCalendarFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCalendarFeedRequest) ProtoMessage() {}
+
+func (x *CreateCalendarFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCalendarFeedRequest.ProtoReflect.Descriptor instead.
+func (*CreateCalendarFeedRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CreateCalendarFeedRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateCalendarFeedResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Feed  *CalendarFeed          `protobuf:"bytes,1,opt,name=feed,proto3" json:"feed,omitempty"`
+	// token is shown only once; subscribe to /v1/calendar/{token}.ics.
+	Token         string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCalendarFeedResponse) Reset() {
+	*x = CreateCalendarFeedResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCalendarFeedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCalendarFeedResponse) ProtoMessage() {}
+
+func (x *CreateCalendarFeedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCalendarFeedResponse.ProtoReflect.Descriptor instead.
+func (*CreateCalendarFeedResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CreateCalendarFeedResponse) GetFeed() *CalendarFeed {
+	if x != nil {
+		return x.Feed
+	}
+	return nil
+}
+
+func (x *CreateCalendarFeedResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ListCalendarFeedsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCalendarFeedsRequest) Reset() {
+	*x = ListCalendarFeedsRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCalendarFeedsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCalendarFeedsRequest) ProtoMessage() {}
+
+func (x *ListCalendarFeedsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCalendarFeedsRequest.ProtoReflect.Descriptor instead.
+func (*ListCalendarFeedsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{30}
+}
+
+type ListCalendarFeedsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Feeds         []*CalendarFeed        `protobuf:"bytes,1,rep,name=feeds,proto3" json:"feeds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCalendarFeedsResponse) Reset() {
+	*x = ListCalendarFeedsResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCalendarFeedsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCalendarFeedsResponse) ProtoMessage() {}
+
+func (x *ListCalendarFeedsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCalendarFeedsResponse.ProtoReflect.Descriptor instead.
+func (*ListCalendarFeedsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListCalendarFeedsResponse) GetFeeds() []*CalendarFeed {
+	if x != nil {
+		return x.Feeds
+	}
+	return nil
+}
+
+type RevokeCalendarFeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCalendarFeedRequest) Reset() {
+	*x = RevokeCalendarFeedRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCalendarFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCalendarFeedRequest) ProtoMessage() {}
+
+func (x *RevokeCalendarFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCalendarFeedRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCalendarFeedRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RevokeCalendarFeedRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeCalendarFeedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCalendarFeedResponse) Reset() {
+	*x = RevokeCalendarFeedResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCalendarFeedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCalendarFeedResponse) ProtoMessage() {}
+
+func (x *RevokeCalendarFeedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCalendarFeedResponse.ProtoReflect.Descriptor instead.
+func (*RevokeCalendarFeedResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{33}
+}
+
+type ApiKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// prefix is the public part of the token, e.g. "todos_1a2b3c4d5e6f".
+	Prefix string   `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Scopes []string `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// expires_at is an RFC 3339 timestamp, empty if the key never expires.
+	ExpiresAt string `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt string `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// last_used_at is accurate to about a minute.
+	LastUsedAt    string `protobuf:"bytes,7,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKey) Reset() {
+	*x = ApiKey{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKey) ProtoMessage() {}
+
+func (x *ApiKey) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKey.ProtoReflect.Descriptor instead.
+func (*ApiKey) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ApiKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApiKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApiKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ApiKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ApiKey) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *ApiKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ApiKey) GetLastUsedAt() string {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return ""
+}
+
+type CreateApiKeyRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// expires_at is an optional RFC 3339 timestamp.
+	ExpiresAt     string `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateApiKeyRequest) Reset() {
+	*x = CreateApiKeyRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyRequest) ProtoMessage() {}
+
+func (x *CreateApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CreateApiKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateApiKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateApiKeyRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type CreateApiKeyResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey *ApiKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// token is shown only once; send it as "Authorization: Bearer <token>".
+	Token         string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateApiKeyResponse) Reset() {
+	*x = CreateApiKeyResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyResponse) ProtoMessage() {}
+
+func (x *CreateApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CreateApiKeyResponse) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateApiKeyResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ListApiKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysRequest) Reset() {
+	*x = ListApiKeysRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysRequest) ProtoMessage() {}
+
+func (x *ListApiKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListApiKeysRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{37}
+}
+
+type ListApiKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*ApiKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysResponse) Reset() {
+	*x = ListApiKeysResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysResponse) ProtoMessage() {}
+
+func (x *ListApiKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListApiKeysResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListApiKeysResponse) GetApiKeys() []*ApiKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RotateApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateApiKeyRequest) Reset() {
+	*x = RotateApiKeyRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateApiKeyRequest) ProtoMessage() {}
+
+func (x *RotateApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RotateApiKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RotateApiKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *ApiKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateApiKeyResponse) Reset() {
+	*x = RotateApiKeyResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateApiKeyResponse) ProtoMessage() {}
+
+func (x *RotateApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RotateApiKeyResponse) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *RotateApiKeyResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type RevokeApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeApiKeyRequest) Reset() {
+	*x = RevokeApiKeyRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeApiKeyRequest) ProtoMessage() {}
+
+func (x *RevokeApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RevokeApiKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeApiKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeApiKeyResponse) Reset() {
+	*x = RevokeApiKeyResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeApiKeyResponse) ProtoMessage() {}
+
+func (x *RevokeApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{42}
+}
+
+type TodoList struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// role is the caller's role in the list.
+	Role          Role `protobuf:"varint,4,opt,name=role,proto3,enum=todos.v1.Role" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoList) Reset() {
+	*x = TodoList{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoList) ProtoMessage() {}
+
+func (x *TodoList) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoList.ProtoReflect.Descriptor instead.
+func (*TodoList) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *TodoList) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TodoList) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TodoList) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *TodoList) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+type Member struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          Role                   `protobuf:"varint,3,opt,name=role,proto3,enum=todos.v1.Role" json:"role,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Member) Reset() {
+	*x = Member{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Member) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Member) ProtoMessage() {}
+
+func (x *Member) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Member.ProtoReflect.Descriptor instead.
+func (*Member) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *Member) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *Member) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Member) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *Member) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type Invitation struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ListId   string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ListName string                 `protobuf:"bytes,3,opt,name=list_name,json=listName,proto3" json:"list_name,omitempty"`
+	// user_id or email identifies the invitee.
+	UserId        string           `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email         string           `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Role          Role             `protobuf:"varint,6,opt,name=role,proto3,enum=todos.v1.Role" json:"role,omitempty"`
+	InvitedBy     string           `protobuf:"bytes,7,opt,name=invited_by,json=invitedBy,proto3" json:"invited_by,omitempty"`
+	Status        InvitationStatus `protobuf:"varint,8,opt,name=status,proto3,enum=todos.v1.InvitationStatus" json:"status,omitempty"`
+	CreatedAt     string           `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Invitation) Reset() {
+	*x = Invitation{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Invitation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Invitation) ProtoMessage() {}
+
+func (x *Invitation) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Invitation.ProtoReflect.Descriptor instead.
+func (*Invitation) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *Invitation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Invitation) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *Invitation) GetListName() string {
+	if x != nil {
+		return x.ListName
+	}
+	return ""
+}
+
+func (x *Invitation) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Invitation) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Invitation) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *Invitation) GetInvitedBy() string {
+	if x != nil {
+		return x.InvitedBy
+	}
+	return ""
+}
+
+func (x *Invitation) GetStatus() InvitationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return InvitationStatus_INVITATION_STATUS_UNSPECIFIED
+}
+
+func (x *Invitation) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateListRequest) Reset() {
+	*x = CreateListRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateListRequest) ProtoMessage() {}
+
+func (x *CreateListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateListRequest.ProtoReflect.Descriptor instead.
+func (*CreateListRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreateListRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	List          *TodoList              `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateListResponse) Reset() {
+	*x = CreateListResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateListResponse) ProtoMessage() {}
+
+func (x *CreateListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateListResponse.ProtoReflect.Descriptor instead.
+func (*CreateListResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CreateListResponse) GetList() *TodoList {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type ListListsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListListsRequest) Reset() {
+	*x = ListListsRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListListsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListListsRequest) ProtoMessage() {}
+
+func (x *ListListsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListListsRequest.ProtoReflect.Descriptor instead.
+func (*ListListsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{48}
+}
+
+type ListListsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lists         []*TodoList            `protobuf:"bytes,1,rep,name=lists,proto3" json:"lists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListListsResponse) Reset() {
+	*x = ListListsResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListListsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListListsResponse) ProtoMessage() {}
+
+func (x *ListListsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListListsResponse.ProtoReflect.Descriptor instead.
+func (*ListListsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListListsResponse) GetLists() []*TodoList {
+	if x != nil {
+		return x.Lists
+	}
+	return nil
+}
+
+type InviteMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Role          Role                   `protobuf:"varint,4,opt,name=role,proto3,enum=todos.v1.Role" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteMemberRequest) Reset() {
+	*x = InviteMemberRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteMemberRequest) ProtoMessage() {}
+
+func (x *InviteMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteMemberRequest.ProtoReflect.Descriptor instead.
+func (*InviteMemberRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *InviteMemberRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *InviteMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *InviteMemberRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *InviteMemberRequest) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+type InviteMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Invitation    *Invitation            `protobuf:"bytes,1,opt,name=invitation,proto3" json:"invitation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteMemberResponse) Reset() {
+	*x = InviteMemberResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteMemberResponse) ProtoMessage() {}
+
+func (x *InviteMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteMemberResponse.ProtoReflect.Descriptor instead.
+func (*InviteMemberResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *InviteMemberResponse) GetInvitation() *Invitation {
+	if x != nil {
+		return x.Invitation
+	}
+	return nil
+}
+
+type ListInvitationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvitationsRequest) Reset() {
+	*x = ListInvitationsRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvitationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvitationsRequest) ProtoMessage() {}
+
+func (x *ListInvitationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvitationsRequest.ProtoReflect.Descriptor instead.
+func (*ListInvitationsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{52}
+}
+
+type ListInvitationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Invitations   []*Invitation          `protobuf:"bytes,1,rep,name=invitations,proto3" json:"invitations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvitationsResponse) Reset() {
+	*x = ListInvitationsResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvitationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvitationsResponse) ProtoMessage() {}
+
+func (x *ListInvitationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvitationsResponse.ProtoReflect.Descriptor instead.
+func (*ListInvitationsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListInvitationsResponse) GetInvitations() []*Invitation {
+	if x != nil {
+		return x.Invitations
+	}
+	return nil
+}
+
+type AcceptInvitationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptInvitationRequest) Reset() {
+	*x = AcceptInvitationRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptInvitationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptInvitationRequest) ProtoMessage() {}
+
+func (x *AcceptInvitationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptInvitationRequest.ProtoReflect.Descriptor instead.
+func (*AcceptInvitationRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *AcceptInvitationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type AcceptInvitationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        *Member                `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptInvitationResponse) Reset() {
+	*x = AcceptInvitationResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptInvitationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptInvitationResponse) ProtoMessage() {}
+
+func (x *AcceptInvitationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptInvitationResponse.ProtoReflect.Descriptor instead.
+func (*AcceptInvitationResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *AcceptInvitationResponse) GetMember() *Member {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+type DeclineInvitationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeclineInvitationRequest) Reset() {
+	*x = DeclineInvitationRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclineInvitationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclineInvitationRequest) ProtoMessage() {}
+
+func (x *DeclineInvitationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclineInvitationRequest.ProtoReflect.Descriptor instead.
+func (*DeclineInvitationRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *DeclineInvitationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeclineInvitationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeclineInvitationResponse) Reset() {
+	*x = DeclineInvitationResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclineInvitationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclineInvitationResponse) ProtoMessage() {}
+
+func (x *DeclineInvitationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclineInvitationResponse.ProtoReflect.Descriptor instead.
+func (*DeclineInvitationResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{57}
+}
+
+type ListMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembersRequest) Reset() {
+	*x = ListMembersRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembersRequest) ProtoMessage() {}
+
+func (x *ListMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListMembersRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListMembersRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+type ListMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*Member              `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembersResponse) Reset() {
+	*x = ListMembersResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembersResponse) ProtoMessage() {}
+
+func (x *ListMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListMembersResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListMembersResponse) GetMembers() []*Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type UpdateMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          Role                   `protobuf:"varint,3,opt,name=role,proto3,enum=todos.v1.Role" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMemberRequest) Reset() {
+	*x = UpdateMemberRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMemberRequest) ProtoMessage() {}
+
+func (x *UpdateMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMemberRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMemberRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *UpdateMemberRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *UpdateMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateMemberRequest) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+type UpdateMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        *Member                `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMemberResponse) Reset() {
+	*x = UpdateMemberResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMemberResponse) ProtoMessage() {}
+
+func (x *UpdateMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMemberResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMemberResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *UpdateMemberResponse) GetMember() *Member {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+type RemoveMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberRequest) Reset() {
+	*x = RemoveMemberRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberRequest) ProtoMessage() {}
+
+func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *RemoveMemberRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *RemoveMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RemoveMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberResponse) Reset() {
+	*x = RemoveMemberResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberResponse) ProtoMessage() {}
+
+func (x *RemoveMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberResponse.ProtoReflect.Descriptor instead.
+func (*RemoveMemberResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{63}
+}
+
+type Attachment struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TodoId      string                 `protobuf:"bytes,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	Filename    string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// size is the length of the content in bytes.
+	Size int64 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	// sha256 is the hex SHA-256 digest of the content.
+	Sha256 string `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// created_at is an RFC 3339 timestamp.
+	CreatedAt     string `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *Attachment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Attachment) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *Attachment) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Attachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Attachment) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Attachment) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Attachment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type UploadAttachmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// todo_id, filename and content_type are read from the first message only.
+	TodoId   string `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	Filename string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	// content_type is detected from the content if empty.
+	ContentType   string `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Chunk         []byte `protobuf:"bytes,4,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentRequest) Reset() {
+	*x = UploadAttachmentRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentRequest) ProtoMessage() {}
+
+func (x *UploadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *UploadAttachmentRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *UploadAttachmentRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UploadAttachmentRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadAttachmentRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type UploadAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachment    *Attachment            `protobuf:"bytes,1,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentResponse) Reset() {
+	*x = UploadAttachmentResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentResponse) ProtoMessage() {}
+
+func (x *UploadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *UploadAttachmentResponse) GetAttachment() *Attachment {
+	if x != nil {
+		return x.Attachment
+	}
+	return nil
+}
+
+type ListAttachmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TodoId        string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAttachmentsRequest) Reset() {
+	*x = ListAttachmentsRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAttachmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAttachmentsRequest) ProtoMessage() {}
+
+func (x *ListAttachmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAttachmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAttachmentsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListAttachmentsRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+type ListAttachmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachments   []*Attachment          `protobuf:"bytes,1,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAttachmentsResponse) Reset() {
+	*x = ListAttachmentsResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAttachmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAttachmentsResponse) ProtoMessage() {}
+
+func (x *ListAttachmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAttachmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAttachmentsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ListAttachmentsResponse) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+type DownloadAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentRequest) Reset() {
+	*x = DownloadAttachmentRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentRequest) ProtoMessage() {}
+
+func (x *DownloadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *DownloadAttachmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DownloadAttachmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// attachment is set in the first message only.
+	Attachment    *Attachment `protobuf:"bytes,1,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	Chunk         []byte      `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentResponse) Reset() {
+	*x = DownloadAttachmentResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentResponse) ProtoMessage() {}
+
+func (x *DownloadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *DownloadAttachmentResponse) GetAttachment() *Attachment {
+	if x != nil {
+		return x.Attachment
+	}
+	return nil
+}
+
+func (x *DownloadAttachmentResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type DeleteAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAttachmentRequest) Reset() {
+	*x = DeleteAttachmentRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAttachmentRequest) ProtoMessage() {}
+
+func (x *DeleteAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *DeleteAttachmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAttachmentResponse) Reset() {
+	*x = DeleteAttachmentResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAttachmentResponse) ProtoMessage() {}
+
+func (x *DeleteAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{72}
+}
+
+type Comment struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TodoId string                 `protobuf:"bytes,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	// author is "user:<user id>" for users and "key:<API key id>" for API
+	// keys.
+	Author string `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Body   string `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	// mentions are the ids of the users mentioned as "@<user id>" in the body.
+	Mentions []string `protobuf:"bytes,5,rep,name=mentions,proto3" json:"mentions,omitempty"`
+	// created_at is an RFC 3339 timestamp.
+	CreatedAt string `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// edited_at is the RFC 3339 time of the last edit, empty if there was
+	// none.
+	EditedAt      string `protobuf:"bytes,7,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *Comment) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Comment) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Comment) GetMentions() []string {
+	if x != nil {
+		return x.Mentions
+	}
+	return nil
+}
+
+func (x *Comment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Comment) GetEditedAt() string {
+	if x != nil {
+		return x.EditedAt
+	}
+	return ""
+}
+
+type CreateCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TodoId        string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentRequest) Reset() {
+	*x = CreateCommentRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCommentRequest) ProtoMessage() {}
+
+func (x *CreateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCommentRequest.ProtoReflect.Descriptor instead.
+func (*CreateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *CreateCommentRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *CreateCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type CreateCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentResponse) Reset() {
+	*x = CreateCommentResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCommentResponse) ProtoMessage() {}
+
+func (x *CreateCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCommentResponse.ProtoReflect.Descriptor instead.
+func (*CreateCommentResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *CreateCommentResponse) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type ListCommentsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TodoId string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	// page_size defaults to 50.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous page.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsRequest) Reset() {
+	*x = ListCommentsRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsRequest) ProtoMessage() {}
+
+func (x *ListCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ListCommentsRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *ListCommentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCommentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListCommentsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Comments []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsResponse) Reset() {
+	*x = ListCommentsResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsResponse) ProtoMessage() {}
+
+func (x *ListCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ListCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *ListCommentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentRequest) Reset() {
+	*x = GetCommentRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentRequest) ProtoMessage() {}
+
+func (x *GetCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *GetCommentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentResponse) Reset() {
+	*x = GetCommentResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentResponse) ProtoMessage() {}
+
+func (x *GetCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *GetCommentResponse) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type UpdateCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCommentRequest) Reset() {
+	*x = UpdateCommentRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommentRequest) ProtoMessage() {}
+
+func (x *UpdateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *UpdateCommentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type UpdateCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCommentResponse) Reset() {
+	*x = UpdateCommentResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommentResponse) ProtoMessage() {}
+
+func (x *UpdateCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommentResponse.ProtoReflect.Descriptor instead.
+func (*UpdateCommentResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *UpdateCommentResponse) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type DeleteCommentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Completed     bool                   `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Todo) Reset() {
-	*x = Todo{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[0]
+func (x *DeleteCommentRequest) Reset() {
+	*x = DeleteCommentRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Todo) String() string {
+func (x *DeleteCommentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Todo) ProtoMessage() {}
+func (*DeleteCommentRequest) ProtoMessage() {}
 
-func (x *Todo) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[0]
+func (x *DeleteCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,54 +5024,39 @@ func (x *Todo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Todo.ProtoReflect.Descriptor instead.
-func (*Todo) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use DeleteCommentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCommentRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *Todo) GetId() string {
+func (x *DeleteCommentRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *Todo) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
-}
-
-func (x *Todo) GetCompleted() bool {
-	if x != nil {
-		return x.Completed
-	}
-	return false
-}
-
-type CreateRequest struct {
+type DeleteCommentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateRequest) Reset() {
-	*x = CreateRequest{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[1]
+func (x *DeleteCommentResponse) Reset() {
+	*x = DeleteCommentResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateRequest) String() string {
+func (x *DeleteCommentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateRequest) ProtoMessage() {}
+func (*DeleteCommentResponse) ProtoMessage() {}
 
-func (x *CreateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[1]
+func (x *DeleteCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -114,40 +5067,38 @@ func (x *CreateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
-func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *CreateRequest) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
+// Deprecated: Use DeleteCommentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCommentResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{83}
 }
 
-type CreateResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+// Dependency records that the todo todo_id is blocked by the todo
+// blocked_by_id.
+type Dependency struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TodoId      string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	BlockedById string                 `protobuf:"bytes,2,opt,name=blocked_by_id,json=blockedById,proto3" json:"blocked_by_id,omitempty"`
+	// created_at is an RFC 3339 timestamp.
+	CreatedAt     string `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateResponse) Reset() {
-	*x = CreateResponse{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[2]
+func (x *Dependency) Reset() {
+	*x = Dependency{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateResponse) String() string {
+func (x *Dependency) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateResponse) ProtoMessage() {}
+func (*Dependency) ProtoMessage() {}
 
-func (x *CreateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[2]
+func (x *Dependency) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -158,40 +5109,55 @@ func (x *CreateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
-func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Dependency.ProtoReflect.Descriptor instead.
+func (*Dependency) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *CreateResponse) GetTodo() *Todo {
+func (x *Dependency) GetTodoId() string {
 	if x != nil {
-		return x.Todo
+		return x.TodoId
 	}
-	return nil
+	return ""
 }
 
-type GetRequest struct {
+func (x *Dependency) GetBlockedById() string {
+	if x != nil {
+		return x.BlockedById
+	}
+	return ""
+}
+
+func (x *Dependency) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type AddDependencyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TodoId        string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	BlockedById   string                 `protobuf:"bytes,2,opt,name=blocked_by_id,json=blockedById,proto3" json:"blocked_by_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRequest) Reset() {
-	*x = GetRequest{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[3]
+func (x *AddDependencyRequest) Reset() {
+	*x = AddDependencyRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRequest) String() string {
+func (x *AddDependencyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRequest) ProtoMessage() {}
+func (*AddDependencyRequest) ProtoMessage() {}
 
-func (x *GetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[3]
+func (x *AddDependencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -202,40 +5168,47 @@ func (x *GetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
-func (*GetRequest) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use AddDependencyRequest.ProtoReflect.Descriptor instead.
+func (*AddDependencyRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *GetRequest) GetId() string {
+func (x *AddDependencyRequest) GetTodoId() string {
 	if x != nil {
-		return x.Id
+		return x.TodoId
 	}
 	return ""
 }
 
-type GetResponse struct {
+func (x *AddDependencyRequest) GetBlockedById() string {
+	if x != nil {
+		return x.BlockedById
+	}
+	return ""
+}
+
+type AddDependencyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	Dependency    *Dependency            `protobuf:"bytes,1,opt,name=dependency,proto3" json:"dependency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetResponse) Reset() {
-	*x = GetResponse{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[4]
+func (x *AddDependencyResponse) Reset() {
+	*x = AddDependencyResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetResponse) String() string {
+func (x *AddDependencyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResponse) ProtoMessage() {}
+func (*AddDependencyResponse) ProtoMessage() {}
 
-func (x *GetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[4]
+func (x *AddDependencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -246,39 +5219,41 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
-func (*GetResponse) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use AddDependencyResponse.ProtoReflect.Descriptor instead.
+func (*AddDependencyResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{86}
 }
 
-func (x *GetResponse) GetTodo() *Todo {
+func (x *AddDependencyResponse) GetDependency() *Dependency {
 	if x != nil {
-		return x.Todo
+		return x.Dependency
 	}
 	return nil
 }
 
-type ListRequest struct {
+type RemoveDependencyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	TodoId        string                 `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	BlockedById   string                 `protobuf:"bytes,2,opt,name=blocked_by_id,json=blockedById,proto3" json:"blocked_by_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRequest) Reset() {
-	*x = ListRequest{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[5]
+func (x *RemoveDependencyRequest) Reset() {
+	*x = RemoveDependencyRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRequest) String() string {
+func (x *RemoveDependencyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRequest) ProtoMessage() {}
+func (*RemoveDependencyRequest) ProtoMessage() {}
 
-func (x *ListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[5]
+func (x *RemoveDependencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -289,33 +5264,46 @@ func (x *ListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
-func (*ListRequest) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use RemoveDependencyRequest.ProtoReflect.Descriptor instead.
+func (*RemoveDependencyRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{87}
 }
 
-type ListResponse struct {
+func (x *RemoveDependencyRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *RemoveDependencyRequest) GetBlockedById() string {
+	if x != nil {
+		return x.BlockedById
+	}
+	return ""
+}
+
+type RemoveDependencyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListResponse) Reset() {
-	*x = ListResponse{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[6]
+func (x *RemoveDependencyResponse) Reset() {
+	*x = RemoveDependencyResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListResponse) String() string {
+func (x *RemoveDependencyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListResponse) ProtoMessage() {}
+func (*RemoveDependencyResponse) ProtoMessage() {}
 
-func (x *ListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[6]
+func (x *RemoveDependencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -326,40 +5314,36 @@ func (x *ListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
-func (*ListResponse) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *ListResponse) GetTodos() []*Todo {
-	if x != nil {
-		return x.Todos
-	}
-	return nil
+// Deprecated: Use RemoveDependencyResponse.ProtoReflect.Descriptor instead.
+func (*RemoveDependencyResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{88}
 }
 
-type DeleteRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+type GetDependencyGraphRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// list_id limits the graph to one shared list.
+	ListId string `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// todo_id limits the graph to the todos connected to this one.
+	TodoId        string `protobuf:"bytes,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[7]
+func (x *GetDependencyGraphRequest) Reset() {
+	*x = GetDependencyGraphRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRequest) String() string {
+func (x *GetDependencyGraphRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*GetDependencyGraphRequest) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[7]
+func (x *GetDependencyGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -370,39 +5354,48 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use GetDependencyGraphRequest.ProtoReflect.Descriptor instead.
+func (*GetDependencyGraphRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{89}
 }
 
-func (x *DeleteRequest) GetId() string {
+func (x *GetDependencyGraphRequest) GetListId() string {
 	if x != nil {
-		return x.Id
+		return x.ListId
 	}
 	return ""
 }
 
-type DeleteResponse struct {
+func (x *GetDependencyGraphRequest) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+type GetDependencyGraphResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*Todo                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges         []*Dependency          `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[8]
+func (x *GetDependencyGraphResponse) Reset() {
+	*x = GetDependencyGraphResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteResponse) String() string {
+func (x *GetDependencyGraphResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*GetDependencyGraphResponse) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[8]
+func (x *GetDependencyGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -413,35 +5406,50 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetDependencyGraphResponse.ProtoReflect.Descriptor instead.
+func (*GetDependencyGraphResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{90}
 }
 
-type UpdateRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Completed     bool                   `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+func (x *GetDependencyGraphResponse) GetNodes() []*Todo {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *GetDependencyGraphResponse) GetEdges() []*Dependency {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+type ListNextRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// list_id limits the result to one shared list.
+	ListId string `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// ready_only leaves out the todos that are still blocked.
+	ReadyOnly     bool `protobuf:"varint,2,opt,name=ready_only,json=readyOnly,proto3" json:"ready_only,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateRequest) Reset() {
-	*x = UpdateRequest{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[9]
+func (x *ListNextRequest) Reset() {
+	*x = ListNextRequest{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateRequest) String() string {
+func (x *ListNextRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateRequest) ProtoMessage() {}
+func (*ListNextRequest) ProtoMessage() {}
 
-func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[9]
+func (x *ListNextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -452,54 +5460,47 @@ func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
-func (*UpdateRequest) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *UpdateRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+// Deprecated: Use ListNextRequest.ProtoReflect.Descriptor instead.
+func (*ListNextRequest) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *UpdateRequest) GetTitle() string {
+func (x *ListNextRequest) GetListId() string {
 	if x != nil {
-		return x.Title
+		return x.ListId
 	}
 	return ""
 }
 
-func (x *UpdateRequest) GetCompleted() bool {
+func (x *ListNextRequest) GetReadyOnly() bool {
 	if x != nil {
-		return x.Completed
+		return x.ReadyOnly
 	}
 	return false
 }
 
-type UpdateResponse struct {
+type ListNextResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Todo          *Todo                  `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateResponse) Reset() {
-	*x = UpdateResponse{}
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[10]
+func (x *ListNextResponse) Reset() {
+	*x = ListNextResponse{}
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateResponse) String() string {
+func (x *ListNextResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateResponse) ProtoMessage() {}
+func (*ListNextResponse) ProtoMessage() {}
 
-func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_todos_v1_todos_proto_msgTypes[10]
+func (x *ListNextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_todos_v1_todos_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -510,14 +5511,14 @@ func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
-func (*UpdateResponse) Descriptor() ([]byte, []int) {
-	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ListNextResponse.ProtoReflect.Descriptor instead.
+func (*ListNextResponse) Descriptor() ([]byte, []int) {
+	return file_protos_todos_v1_todos_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *UpdateResponse) GetTodo() *Todo {
+func (x *ListNextResponse) GetTodos() []*Todo {
 	if x != nil {
-		return x.Todo
+		return x.Todos
 	}
 	return nil
 }
@@ -526,40 +5527,444 @@ var File_protos_todos_v1_todos_proto protoreflect.FileDescriptor
 
 const file_protos_todos_v1_todos_proto_rawDesc = "" +
 	"\n" +
-	"\x1bprotos/todos/v1/todos.proto\x12\btodos.v1\x1a\x1bbuf/validate/validate.proto\"J\n" +
+	"\x1bprotos/todos/v1/todos.proto\x12\btodos.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a google/protobuf/field_mask.proto\"\xf7\x04\n" +
 	"\x04Todo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1c\n" +
-	"\tcompleted\x18\x03 \x01(\bR\tcompleted\"1\n" +
+	"\tcompleted\x18\x03 \x01(\bR\tcompleted\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\x12.\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x12.todos.v1.PriorityR\bpriority\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\tR\bposition\x12\x15\n" +
+	"\x06due_at\x18\a \x01(\tR\x05dueAt\x12\x14\n" +
+	"\x05rrule\x18\b \x01(\tR\x05rrule\x12\x1a\n" +
+	"\btimezone\x18\t \x01(\tR\btimezone\x12A\n" +
+	"\x0frecurrence_mode\x18\n" +
+	" \x01(\x0e2\x18.todos.v1.RecurrenceModeR\x0erecurrenceMode\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\x12\x17\n" +
+	"\alist_id\x18\f \x01(\tR\x06listId\x12 \n" +
+	"\vdescription\x18\r \x01(\tR\vdescription\x12.\n" +
+	"\x10description_html\x18\x0e \x01(\tH\x00R\x0fdescriptionHtml\x88\x01\x01\x12$\n" +
+	"\x05links\x18\x0f \x03(\v2\x0e.todos.v1.LinkR\x05links\x125\n" +
+	"\tchecklist\x18\x10 \x03(\v2\x17.todos.v1.ChecklistItemR\tchecklist\x12#\n" +
+	"\rcomment_count\x18\x11 \x01(\x05R\fcommentCount\x12\x18\n" +
+	"\ablocked\x18\x12 \x01(\bR\ablockedB\x13\n" +
+	"\x11_description_html\",\n" +
+	"\x04Link\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"=\n" +
+	"\rChecklistItem\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x18\n" +
+	"\achecked\x18\x02 \x01(\bR\achecked\"\xf0\x02\n" +
 	"\rCreateRequest\x12 \n" +
 	"\x05title\x18\x01 \x01(\tB\n" +
-	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x05title\"4\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x05title\x128\n" +
+	"\bpriority\x18\x02 \x01(\x0e2\x12.todos.v1.PriorityB\b\xbaH\x05\x82\x01\x02\x10\x01R\bpriority\x12\x1e\n" +
+	"\x06due_at\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x18@R\x05dueAt\x12\x1e\n" +
+	"\x05rrule\x18\x04 \x01(\tB\b\xbaH\x05r\x03\x18\x80\bR\x05rrule\x12#\n" +
+	"\btimezone\x18\x05 \x01(\tB\a\xbaH\x04r\x02\x18@R\btimezone\x12K\n" +
+	"\x0frecurrence_mode\x18\x06 \x01(\x0e2\x18.todos.v1.RecurrenceModeB\b\xbaH\x05\x82\x01\x02\x10\x01R\x0erecurrenceMode\x12$\n" +
+	"\alist_id\x18\a \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\x06listId\x12+\n" +
+	"\vdescription\x18\b \x01(\tB\t\xbaH\x06r\x04(\x80\x80\x01R\vdescription\"4\n" +
 	"\x0eCreateResponse\x12\"\n" +
 	"\x04todo\x18\x01 \x01(\v2\x0e.todos.v1.TodoR\x04todo\"&\n" +
 	"\n" +
 	"GetRequest\x12\x18\n" +
 	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"1\n" +
 	"\vGetResponse\x12\"\n" +
-	"\x04todo\x18\x01 \x01(\v2\x0e.todos.v1.TodoR\x04todo\"\r\n" +
-	"\vListRequest\"4\n" +
+	"\x04todo\x18\x01 \x01(\v2\x0e.todos.v1.TodoR\x04todo\"\x88\x01\n" +
+	"\vListRequest\x123\n" +
+	"\x05order\x18\x01 \x01(\x0e2\x13.todos.v1.ListOrderB\b\xbaH\x05\x82\x01\x02\x10\x01R\x05order\x12$\n" +
+	"\alist_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\x06listId\x12\x1e\n" +
+	"\x05query\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x02R\x05query\"4\n" +
 	"\fListResponse\x12$\n" +
 	"\x05todos\x18\x01 \x03(\v2\x0e.todos.v1.TodoR\x05todos\")\n" +
 	"\rDeleteRequest\x12\x18\n" +
 	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x10\n" +
-	"\x0eDeleteResponse\"i\n" +
+	"\x0eDeleteResponse\"\xc9\x04\n" +
 	"\rUpdateRequest\x12\x18\n" +
-	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12 \n" +
-	"\x05title\x18\x02 \x01(\tB\n" +
-	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x05title\x12\x1c\n" +
-	"\tcompleted\x18\x03 \x01(\bR\tcompleted\"4\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x1e\n" +
+	"\x05title\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x05title\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\bR\tcompleted\x128\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x12.todos.v1.PriorityB\b\xbaH\x05\x82\x01\x02\x10\x01R\bpriority\x12\x1e\n" +
+	"\x06due_at\x18\x05 \x01(\tB\a\xbaH\x04r\x02\x18@R\x05dueAt\x12\x1e\n" +
+	"\x05rrule\x18\x06 \x01(\tB\b\xbaH\x05r\x03\x18\x80\bR\x05rrule\x12#\n" +
+	"\btimezone\x18\a \x01(\tB\a\xbaH\x04r\x02\x18@R\btimezone\x12K\n" +
+	"\x0frecurrence_mode\x18\b \x01(\x0e2\x18.todos.v1.RecurrenceModeB\b\xbaH\x05\x82\x01\x02\x10\x01R\x0erecurrenceMode\x12;\n" +
+	"\vupdate_mask\x18\t \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12+\n" +
+	"\vdescription\x18\n" +
+	" \x01(\tB\t\xbaH\x06r\x04(\x80\x80\x01R\vdescription:\x89\x01\xbaH\x85\x01\x1a\x82\x01\n" +
+	"\x0etitle_required\x12\x17title must not be empty\x1aWsize(this.title) > 0 || (has(this.update_mask) && !('title' in this.update_mask.paths))\"4\n" +
 	"\x0eUpdateResponse\x12\"\n" +
-	"\x04todo\x18\x01 \x01(\v2\x0e.todos.v1.TodoR\x04todo2\xb0\x02\n" +
-	"\fTodosService\x12;\n" +
-	"\x06Create\x12\x17.todos.v1.CreateRequest\x1a\x18.todos.v1.CreateResponse\x122\n" +
-	"\x03Get\x12\x14.todos.v1.GetRequest\x1a\x15.todos.v1.GetResponse\x12;\n" +
-	"\x06Update\x12\x17.todos.v1.UpdateRequest\x1a\x18.todos.v1.UpdateResponse\x12;\n" +
-	"\x06Delete\x12\x17.todos.v1.DeleteRequest\x1a\x18.todos.v1.DeleteResponse\x125\n" +
-	"\x04List\x12\x15.todos.v1.ListRequest\x1a\x16.todos.v1.ListResponseB\x9b\x01\n" +
+	"\x04todo\x18\x01 \x01(\v2\x0e.todos.v1.TodoR\x04todo\"U\n" +
+	"\vSyncRequest\x12\x1d\n" +
+	"\n" +
+	"sync_token\x18\x01 \x01(\tR\tsyncToken\x12'\n" +
+	"\tpage_size\x18\x02 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xe8\a(\x00R\bpageSize\"\x8f\x01\n" +
+	"\fSyncResponse\x12$\n" +
+	"\x05todos\x18\x01 \x03(\v2\x0e.todos.v1.TodoR\x05todos\x12\x1f\n" +
+	"\vdeleted_ids\x18\x02 \x03(\tR\n" +
+	"deletedIds\x12\x1d\n" +
+	"\n" +
+	"sync_token\x18\x03 \x01(\tR\tsyncToken\x12\x19\n" +
+	"\bhas_more\x18\x04 \x01(\bR\ahasMore\"\xf1\x01\n" +
+	"\bMutation\x120\n" +
+	"\x02op\x18\x01 \x01(\x0e2\x14.todos.v1.MutationOpB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\x02op\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x1e\n" +
+	"\x05title\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x05title\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\bR\tcompleted\x12!\n" +
+	"\fbase_version\x18\x05 \x01(\x03R\vbaseVersion\x128\n" +
+	"\bpriority\x18\x06 \x01(\x0e2\x12.todos.v1.PriorityB\b\xbaH\x05\x82\x01\x02\x10\x01R\bpriority\"L\n" +
+	"\vPushRequest\x12=\n" +
+	"\tmutations\x18\x01 \x03(\v2\x12.todos.v1.MutationB\v\xbaH\b\x92\x01\x05\b\x01\x10\xf4\x03R\tmutations\"\xa6\x01\n" +
+	"\x0eMutationResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.todos.v1.MutationStatusR\x06status\x12\"\n" +
+	"\x04todo\x18\x03 \x01(\v2\x0e.todos.v1.TodoR\x04todo\x12\x18\n" +
+	"\adeleted\x18\x04 \x01(\bR\adeleted\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"B\n" +
+	"\fPushResponse\x122\n" +
+	"\aresults\x18\x01 \x03(\v2\x18.todos.v1.MutationResultR\aresults\"\x88\x01\n" +
+	"\vMoveRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12'\n" +
+	"\tbefore_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01H\x00R\bbeforeId\x12%\n" +
+	"\bafter_id\x18\x03 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01H\x00R\aafterIdB\x0f\n" +
+	"\x06target\x12\x05\xbaH\x02\b\x01\"2\n" +
+	"\fMoveResponse\x12\"\n" +
+	"\x04todo\x18\x01 \x01(\v2\x0e.todos.v1.TodoR\x04todo\"\xa7\x01\n" +
+	"\x19PreviewOccurrencesRequest\x12 \n" +
+	"\x05rrule\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\bR\x05rrule\x12\"\n" +
+	"\bstart_at\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\astartAt\x12#\n" +
+	"\btimezone\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x18@R\btimezone\x12\x1f\n" +
+	"\x05count\x18\x04 \x01(\x05B\t\xbaH\x06\x1a\x04\x18d(\x01R\x05count\">\n" +
+	"\x1aPreviewOccurrencesResponse\x12 \n" +
+	"\voccurrences\x18\x01 \x03(\tR\voccurrences\"G\n" +
+	"\rExportRequest\x126\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x14.todos.v1.DataFormatB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06format\"&\n" +
+	"\x0eExportResponse\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\fR\x05chunk\"\xac\x01\n" +
+	"\rImportRequest\x126\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x14.todos.v1.DataFormatB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06format\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12)\n" +
+	"\x10allow_duplicates\x18\x03 \x01(\bR\x0fallowDuplicates\x12\x1f\n" +
+	"\x05chunk\x18\x04 \x01(\fB\t\xbaH\x06z\x04\x18\x80\x80@R\x05chunk\"o\n" +
+	"\x0eImportResponse\x12$\n" +
+	"\x05todos\x18\x01 \x03(\v2\x0e.todos.v1.TodoR\x05todos\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x02 \x03(\tR\n" +
+	"duplicates\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"Q\n" +
+	"\fCalendarFeed\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\";\n" +
+	"\x19CreateCalendarFeedRequest\x12\x1e\n" +
+	"\x04name\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x04name\"^\n" +
+	"\x1aCreateCalendarFeedResponse\x12*\n" +
+	"\x04feed\x18\x01 \x01(\v2\x16.todos.v1.CalendarFeedR\x04feed\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\x1a\n" +
+	"\x18ListCalendarFeedsRequest\"I\n" +
+	"\x19ListCalendarFeedsResponse\x12,\n" +
+	"\x05feeds\x18\x01 \x03(\v2\x16.todos.v1.CalendarFeedR\x05feeds\"5\n" +
+	"\x19RevokeCalendarFeedRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x1c\n" +
+	"\x1aRevokeCalendarFeedResponse\"\xbc\x01\n" +
+	"\x06ApiKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_used_at\x18\a \x01(\tR\n" +
+	"lastUsedAt\"\xaa\x01\n" +
+	"\x13CreateApiKeyRequest\x12\x1e\n" +
+	"\x04name\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x04name\x12K\n" +
+	"\x06scopes\x18\x02 \x03(\tB3\xbaH0\x92\x01-\b\x01\x18\x01\"'r%R\n" +
+	"todos:readR\vtodos:writeR\n" +
+	"keys:adminR\x06scopes\x12&\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x18@R\texpiresAt\"W\n" +
+	"\x14CreateApiKeyResponse\x12)\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x10.todos.v1.ApiKeyR\x06apiKey\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\x14\n" +
+	"\x12ListApiKeysRequest\"B\n" +
+	"\x13ListApiKeysResponse\x12+\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\x10.todos.v1.ApiKeyR\aapiKeys\"/\n" +
+	"\x13RotateApiKeyRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"W\n" +
+	"\x14RotateApiKeyResponse\x12)\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x10.todos.v1.ApiKeyR\x06apiKey\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"/\n" +
+	"\x13RevokeApiKeyRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x16\n" +
+	"\x14RevokeApiKeyResponse\"q\n" +
+	"\bTodoList\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12\"\n" +
+	"\x04role\x18\x04 \x01(\x0e2\x0e.todos.v1.RoleR\x04role\"}\n" +
+	"\x06Member\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\"\n" +
+	"\x04role\x18\x03 \x01(\x0e2\x0e.todos.v1.RoleR\x04role\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"\x97\x02\n" +
+	"\n" +
+	"Invitation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\alist_id\x18\x02 \x01(\tR\x06listId\x12\x1b\n" +
+	"\tlist_name\x18\x03 \x01(\tR\blistName\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\"\n" +
+	"\x04role\x18\x06 \x01(\x0e2\x0e.todos.v1.RoleR\x04role\x12\x1d\n" +
+	"\n" +
+	"invited_by\x18\a \x01(\tR\tinvitedBy\x122\n" +
+	"\x06status\x18\b \x01(\x0e2\x1a.todos.v1.InvitationStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"3\n" +
+	"\x11CreateListRequest\x12\x1e\n" +
+	"\x04name\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x04name\"<\n" +
+	"\x12CreateListResponse\x12&\n" +
+	"\x04list\x18\x01 \x01(\v2\x12.todos.v1.TodoListR\x04list\"\x12\n" +
+	"\x10ListListsRequest\"=\n" +
+	"\x11ListListsResponse\x12(\n" +
+	"\x05lists\x18\x01 \x03(\v2\x12.todos.v1.TodoListR\x05lists\"\xc3\x01\n" +
+	"\x13InviteMemberRequest\x12!\n" +
+	"\alist_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06listId\x12!\n" +
+	"\auser_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x06userId\x12\x1d\n" +
+	"\x05email\x18\x03 \x01(\tB\a\xbaH\x04r\x02`\x01R\x05email\x12.\n" +
+	"\x04role\x18\x04 \x01(\x0e2\x0e.todos.v1.RoleB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\x04role:\x17\xbaH\x14\"\x12\n" +
+	"\auser_id\n" +
+	"\x05email\x10\x01\"L\n" +
+	"\x14InviteMemberResponse\x124\n" +
+	"\n" +
+	"invitation\x18\x01 \x01(\v2\x14.todos.v1.InvitationR\n" +
+	"invitation\"\x18\n" +
+	"\x16ListInvitationsRequest\"Q\n" +
+	"\x17ListInvitationsResponse\x126\n" +
+	"\vinvitations\x18\x01 \x03(\v2\x14.todos.v1.InvitationR\vinvitations\"3\n" +
+	"\x17AcceptInvitationRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"D\n" +
+	"\x18AcceptInvitationResponse\x12(\n" +
+	"\x06member\x18\x01 \x01(\v2\x10.todos.v1.MemberR\x06member\"4\n" +
+	"\x18DeclineInvitationRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x1b\n" +
+	"\x19DeclineInvitationResponse\"7\n" +
+	"\x12ListMembersRequest\x12!\n" +
+	"\alist_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06listId\"A\n" +
+	"\x13ListMembersResponse\x12*\n" +
+	"\amembers\x18\x01 \x03(\v2\x10.todos.v1.MemberR\amembers\"\x8a\x01\n" +
+	"\x13UpdateMemberRequest\x12!\n" +
+	"\alist_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06listId\x12 \n" +
+	"\auser_id\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x06userId\x12.\n" +
+	"\x04role\x18\x03 \x01(\x0e2\x0e.todos.v1.RoleB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\x04role\"@\n" +
+	"\x14UpdateMemberResponse\x12(\n" +
+	"\x06member\x18\x01 \x01(\v2\x10.todos.v1.MemberR\x06member\"Z\n" +
+	"\x13RemoveMemberRequest\x12!\n" +
+	"\alist_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06listId\x12 \n" +
+	"\auser_id\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x06userId\"\x16\n" +
+	"\x14RemoveMemberResponse\"\xbf\x01\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\tR\x06todoId\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"\xb3\x01\n" +
+	"\x17UploadAttachmentRequest\x12$\n" +
+	"\atodo_id\x18\x01 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\x06todoId\x12$\n" +
+	"\bfilename\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\bfilename\x12+\n" +
+	"\fcontent_type\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\vcontentType\x12\x1f\n" +
+	"\x05chunk\x18\x04 \x01(\fB\t\xbaH\x06z\x04\x18\x80\x80@R\x05chunk\"P\n" +
+	"\x18UploadAttachmentResponse\x124\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x14.todos.v1.AttachmentR\n" +
+	"attachment\";\n" +
+	"\x16ListAttachmentsRequest\x12!\n" +
+	"\atodo_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06todoId\"Q\n" +
+	"\x17ListAttachmentsResponse\x126\n" +
+	"\vattachments\x18\x01 \x03(\v2\x14.todos.v1.AttachmentR\vattachments\"5\n" +
+	"\x19DownloadAttachmentRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"h\n" +
+	"\x1aDownloadAttachmentResponse\x124\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x14.todos.v1.AttachmentR\n" +
+	"attachment\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\"3\n" +
+	"\x17DeleteAttachmentRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x1a\n" +
+	"\x18DeleteAttachmentResponse\"\xb6\x01\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\tR\x06todoId\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12\x1a\n" +
+	"\bmentions\x18\x05 \x03(\tR\bmentions\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1b\n" +
+	"\tedited_at\x18\a \x01(\tR\beditedAt\"Y\n" +
+	"\x14CreateCommentRequest\x12!\n" +
+	"\atodo_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06todoId\x12\x1e\n" +
+	"\x04body\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01(\x80@R\x04body\"D\n" +
+	"\x15CreateCommentResponse\x12+\n" +
+	"\acomment\x18\x01 \x01(\v2\x11.todos.v1.CommentR\acomment\"\x8a\x01\n" +
+	"\x13ListCommentsRequest\x12!\n" +
+	"\atodo_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06todoId\x12'\n" +
+	"\tpage_size\x18\x02 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xc8\x01(\x00R\bpageSize\x12'\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x02R\tpageToken\"m\n" +
+	"\x14ListCommentsResponse\x12-\n" +
+	"\bcomments\x18\x01 \x03(\v2\x11.todos.v1.CommentR\bcomments\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"-\n" +
+	"\x11GetCommentRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"A\n" +
+	"\x12GetCommentResponse\x12+\n" +
+	"\acomment\x18\x01 \x01(\v2\x11.todos.v1.CommentR\acomment\"P\n" +
+	"\x14UpdateCommentRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x1e\n" +
+	"\x04body\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01(\x80@R\x04body\"D\n" +
+	"\x15UpdateCommentResponse\x12+\n" +
+	"\acomment\x18\x01 \x01(\v2\x11.todos.v1.CommentR\acomment\"0\n" +
+	"\x14DeleteCommentRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x17\n" +
+	"\x15DeleteCommentResponse\"h\n" +
+	"\n" +
+	"Dependency\x12\x17\n" +
+	"\atodo_id\x18\x01 \x01(\tR\x06todoId\x12\"\n" +
+	"\rblocked_by_id\x18\x02 \x01(\tR\vblockedById\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\"g\n" +
+	"\x14AddDependencyRequest\x12!\n" +
+	"\atodo_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06todoId\x12,\n" +
+	"\rblocked_by_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\vblockedById\"M\n" +
+	"\x15AddDependencyResponse\x124\n" +
+	"\n" +
+	"dependency\x18\x01 \x01(\v2\x14.todos.v1.DependencyR\n" +
+	"dependency\"j\n" +
+	"\x17RemoveDependencyRequest\x12!\n" +
+	"\atodo_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06todoId\x12,\n" +
+	"\rblocked_by_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\vblockedById\"\x1a\n" +
+	"\x18RemoveDependencyResponse\"g\n" +
+	"\x19GetDependencyGraphRequest\x12$\n" +
+	"\alist_id\x18\x01 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\x06listId\x12$\n" +
+	"\atodo_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\x06todoId\"n\n" +
+	"\x1aGetDependencyGraphResponse\x12$\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x0e.todos.v1.TodoR\x05nodes\x12*\n" +
+	"\x05edges\x18\x02 \x03(\v2\x14.todos.v1.DependencyR\x05edges\"V\n" +
+	"\x0fListNextRequest\x12$\n" +
+	"\alist_id\x18\x01 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\x06listId\x12\x1d\n" +
+	"\n" +
+	"ready_only\x18\x02 \x01(\bR\treadyOnly\"8\n" +
+	"\x10ListNextResponse\x12$\n" +
+	"\x05todos\x18\x01 \x03(\v2\x0e.todos.v1.TodoR\x05todos*N\n" +
+	"\x04Role\x12\x14\n" +
+	"\x10ROLE_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vROLE_VIEWER\x10\x01\x12\x0f\n" +
+	"\vROLE_EDITOR\x10\x02\x12\x0e\n" +
+	"\n" +
+	"ROLE_OWNER\x10\x03*\x94\x01\n" +
+	"\x10InvitationStatus\x12!\n" +
+	"\x1dINVITATION_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19INVITATION_STATUS_PENDING\x10\x01\x12\x1e\n" +
+	"\x1aINVITATION_STATUS_ACCEPTED\x10\x02\x12\x1e\n" +
+	"\x1aINVITATION_STATUS_DECLINED\x10\x03*s\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x01\x12\x13\n" +
+	"\x0fPRIORITY_MEDIUM\x10\x02\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x03\x12\x13\n" +
+	"\x0fPRIORITY_URGENT\x10\x04*{\n" +
+	"\x0eRecurrenceMode\x12\x1f\n" +
+	"\x1bRECURRENCE_MODE_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eRECURRENCE_MODE_FIXED_SCHEDULE\x10\x01\x12$\n" +
+	" RECURRENCE_MODE_AFTER_COMPLETION\x10\x02*\xa5\x01\n" +
+	"\n" +
+	"DataFormat\x12\x1b\n" +
+	"\x17DATA_FORMAT_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12DATA_FORMAT_NDJSON\x10\x01\x12\x13\n" +
+	"\x0fDATA_FORMAT_CSV\x10\x02\x12\x18\n" +
+	"\x14DATA_FORMAT_MARKDOWN\x10\x03\x12\x18\n" +
+	"\x14DATA_FORMAT_TODO_TXT\x10\x04\x12\x19\n" +
+	"\x15DATA_FORMAT_ICALENDAR\x10\x05*Y\n" +
+	"\tListOrder\x12\x1a\n" +
+	"\x16LIST_ORDER_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13LIST_ORDER_PRIORITY\x10\x01\x12\x17\n" +
+	"\x13LIST_ORDER_POSITION\x10\x02*q\n" +
+	"\n" +
+	"MutationOp\x12\x1b\n" +
+	"\x17MUTATION_OP_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12MUTATION_OP_CREATE\x10\x01\x12\x16\n" +
+	"\x12MUTATION_OP_UPDATE\x10\x02\x12\x16\n" +
+	"\x12MUTATION_OP_DELETE\x10\x03*\xa9\x01\n" +
+	"\x0eMutationStatus\x12\x1f\n" +
+	"\x1bMUTATION_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17MUTATION_STATUS_APPLIED\x10\x01\x12\x1c\n" +
+	"\x18MUTATION_STATUS_CONFLICT\x10\x02\x12\x1d\n" +
+	"\x19MUTATION_STATUS_NOT_FOUND\x10\x03\x12\x1c\n" +
+	"\x18MUTATION_STATUS_REJECTED\x10\x042\xec!\n" +
+	"\fTodosService\x12Q\n" +
+	"\x06Create\x12\x17.todos.v1.CreateRequest\x1a\x18.todos.v1.CreateResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/todos\x12M\n" +
+	"\x03Get\x12\x14.todos.v1.GetRequest\x1a\x15.todos.v1.GetResponse\"\x19\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/todos/{id}\x90\x02\x01\x12k\n" +
+	"\x06Update\x12\x17.todos.v1.UpdateRequest\x1a\x18.todos.v1.UpdateResponse\".\x82\xd3\xe4\x93\x02(:\x01*Z\x13:\x01*\x1a\x0e/v1/todos/{id}2\x0e/v1/todos/{id}\x12S\n" +
+	"\x06Delete\x12\x17.todos.v1.DeleteRequest\x1a\x18.todos.v1.DeleteResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/todos/{id}\x12K\n" +
+	"\x04List\x12\x15.todos.v1.ListRequest\x1a\x16.todos.v1.ListResponse\"\x14\x82\xd3\xe4\x93\x02\v\x12\t/v1/todos\x90\x02\x01\x12P\n" +
+	"\x04Sync\x12\x15.todos.v1.SyncRequest\x1a\x16.todos.v1.SyncResponse\"\x19\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/todos:sync\x90\x02\x01\x12P\n" +
+	"\x04Push\x12\x15.todos.v1.PushRequest\x1a\x16.todos.v1.PushResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/todos:push\x12U\n" +
+	"\x04Move\x12\x15.todos.v1.MoveRequest\x1a\x16.todos.v1.MoveResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/todos/{id}:move\x12{\n" +
+	"\x12PreviewOccurrences\x12#.todos.v1.PreviewOccurrencesRequest\x1a$.todos.v1.PreviewOccurrencesResponse\"\x1a\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/occurrences\x90\x02\x01\x12=\n" +
+	"\x06Export\x12\x17.todos.v1.ExportRequest\x1a\x18.todos.v1.ExportResponse0\x01\x12=\n" +
+	"\x06Import\x12\x17.todos.v1.ImportRequest\x1a\x18.todos.v1.ImportResponse(\x01\x12~\n" +
+	"\x12CreateCalendarFeed\x12#.todos.v1.CreateCalendarFeedRequest\x1a$.todos.v1.CreateCalendarFeedResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/calendar-feeds\x12{\n" +
+	"\x11ListCalendarFeeds\x12\".todos.v1.ListCalendarFeedsRequest\x1a#.todos.v1.ListCalendarFeedsResponse\"\x1d\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/calendar-feeds\x90\x02\x01\x12\x80\x01\n" +
+	"\x12RevokeCalendarFeed\x12#.todos.v1.RevokeCalendarFeedRequest\x1a$.todos.v1.RevokeCalendarFeedResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/v1/calendar-feeds/{id}\x12f\n" +
+	"\fCreateApiKey\x12\x1d.todos.v1.CreateApiKeyRequest\x1a\x1e.todos.v1.CreateApiKeyResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/api-keys\x12c\n" +
+	"\vListApiKeys\x12\x1c.todos.v1.ListApiKeysRequest\x1a\x1d.todos.v1.ListApiKeysResponse\"\x17\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/api-keys\x90\x02\x01\x12r\n" +
+	"\fRotateApiKey\x12\x1d.todos.v1.RotateApiKeyRequest\x1a\x1e.todos.v1.RotateApiKeyResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/api-keys/{id}:rotate\x12h\n" +
+	"\fRevokeApiKey\x12\x1d.todos.v1.RevokeApiKeyRequest\x1a\x1e.todos.v1.RevokeApiKeyResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/api-keys/{id}\x12]\n" +
+	"\n" +
+	"CreateList\x12\x1b.todos.v1.CreateListRequest\x1a\x1c.todos.v1.CreateListResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/lists\x12Z\n" +
+	"\tListLists\x12\x1a.todos.v1.ListListsRequest\x1a\x1b.todos.v1.ListListsResponse\"\x14\x82\xd3\xe4\x93\x02\v\x12\t/v1/lists\x90\x02\x01\x12y\n" +
+	"\fInviteMember\x12\x1d.todos.v1.InviteMemberRequest\x1a\x1e.todos.v1.InviteMemberResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/lists/{list_id}/invitations\x12r\n" +
+	"\x0fListInvitations\x12 .todos.v1.ListInvitationsRequest\x1a!.todos.v1.ListInvitationsResponse\"\x1a\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/invitations\x90\x02\x01\x12\x81\x01\n" +
+	"\x10AcceptInvitation\x12!.todos.v1.AcceptInvitationRequest\x1a\".todos.v1.AcceptInvitationResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/invitations/{id}:accept\x12\x85\x01\n" +
+	"\x11DeclineInvitation\x12\".todos.v1.DeclineInvitationRequest\x1a#.todos.v1.DeclineInvitationResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/invitations/{id}:decline\x12r\n" +
+	"\vListMembers\x12\x1c.todos.v1.ListMembersRequest\x1a\x1d.todos.v1.ListMembersResponse\"&\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/lists/{list_id}/members\x90\x02\x01\x12\x7f\n" +
+	"\fUpdateMember\x12\x1d.todos.v1.UpdateMemberRequest\x1a\x1e.todos.v1.UpdateMemberResponse\"0\x82\xd3\xe4\x93\x02*:\x01*2%/v1/lists/{list_id}/members/{user_id}\x12|\n" +
+	"\fRemoveMember\x12\x1d.todos.v1.RemoveMemberRequest\x1a\x1e.todos.v1.RemoveMemberResponse\"-\x82\xd3\xe4\x93\x02'*%/v1/lists/{list_id}/members/{user_id}\x12[\n" +
+	"\x10UploadAttachment\x12!.todos.v1.UploadAttachmentRequest\x1a\".todos.v1.UploadAttachmentResponse(\x01\x12\x82\x01\n" +
+	"\x0fListAttachments\x12 .todos.v1.ListAttachmentsRequest\x1a!.todos.v1.ListAttachmentsResponse\"*\x82\xd3\xe4\x93\x02!\x12\x1f/v1/todos/{todo_id}/attachments\x90\x02\x01\x12a\n" +
+	"\x12DownloadAttachment\x12#.todos.v1.DownloadAttachmentRequest\x1a$.todos.v1.DownloadAttachmentResponse0\x01\x12w\n" +
+	"\x10DeleteAttachment\x12!.todos.v1.DeleteAttachmentRequest\x1a\".todos.v1.DeleteAttachmentResponse\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/v1/attachments/{id}\x12y\n" +
+	"\rCreateComment\x12\x1e.todos.v1.CreateCommentRequest\x1a\x1f.todos.v1.CreateCommentResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/todos/{todo_id}/comments\x12v\n" +
+	"\fListComments\x12\x1d.todos.v1.ListCommentsRequest\x1a\x1e.todos.v1.ListCommentsResponse\"'\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/todos/{todo_id}/comments\x90\x02\x01\x12e\n" +
+	"\n" +
+	"GetComment\x12\x1b.todos.v1.GetCommentRequest\x1a\x1c.todos.v1.GetCommentResponse\"\x1c\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/comments/{id}\x90\x02\x01\x12n\n" +
+	"\rUpdateComment\x12\x1e.todos.v1.UpdateCommentRequest\x1a\x1f.todos.v1.UpdateCommentResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*2\x11/v1/comments/{id}\x12k\n" +
+	"\rDeleteComment\x12\x1e.todos.v1.DeleteCommentRequest\x1a\x1f.todos.v1.DeleteCommentResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/comments/{id}\x12y\n" +
+	"\rAddDependency\x12\x1e.todos.v1.AddDependencyRequest\x1a\x1f.todos.v1.AddDependencyResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/todos/{todo_id}/blockers\x12\x8f\x01\n" +
+	"\x10RemoveDependency\x12!.todos.v1.RemoveDependencyRequest\x1a\".todos.v1.RemoveDependencyResponse\"4\x82\xd3\xe4\x93\x02.*,/v1/todos/{todo_id}/blockers/{blocked_by_id}\x12\x80\x01\n" +
+	"\x12GetDependencyGraph\x12#.todos.v1.GetDependencyGraphRequest\x1a$.todos.v1.GetDependencyGraphResponse\"\x1f\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/dependency-graph\x90\x02\x01\x12\\\n" +
+	"\bListNext\x12\x19.todos.v1.ListNextRequest\x1a\x1a.todos.v1.ListNextResponse\"\x19\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/todos:next\x90\x02\x01B\x9b\x01\n" +
 	"\fcom.todos.v1B\n" +
 	"TodosProtoP\x01Z>github.com/haakaashs/todos-backend/gen/protos/todos/v1;todosv1\xa2\x02\x03TXX\xaa\x02\bTodos.V1\xca\x02\bTodos\\V1\xe2\x02\x14Todos\\V1\\GPBMetadata\xea\x02\tTodos::V1b\x06proto3"
 
@@ -575,40 +5980,252 @@ func file_protos_todos_v1_todos_proto_rawDescGZIP() []byte {
 	return file_protos_todos_v1_todos_proto_rawDescData
 }
 
-var file_protos_todos_v1_todos_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_protos_todos_v1_todos_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_protos_todos_v1_todos_proto_msgTypes = make([]protoimpl.MessageInfo, 93)
 var file_protos_todos_v1_todos_proto_goTypes = []any{
-	(*Todo)(nil),           // 0: todos.v1.Todo
-	(*CreateRequest)(nil),  // 1: todos.v1.CreateRequest
-	(*CreateResponse)(nil), // 2: todos.v1.CreateResponse
-	(*GetRequest)(nil),     // 3: todos.v1.GetRequest
-	(*GetResponse)(nil),    // 4: todos.v1.GetResponse
-	(*ListRequest)(nil),    // 5: todos.v1.ListRequest
-	(*ListResponse)(nil),   // 6: todos.v1.ListResponse
-	(*DeleteRequest)(nil),  // 7: todos.v1.DeleteRequest
-	(*DeleteResponse)(nil), // 8: todos.v1.DeleteResponse
-	(*UpdateRequest)(nil),  // 9: todos.v1.UpdateRequest
-	(*UpdateResponse)(nil), // 10: todos.v1.UpdateResponse
+	(Role)(0),                          // 0: todos.v1.Role
+	(InvitationStatus)(0),              // 1: todos.v1.InvitationStatus
+	(Priority)(0),                      // 2: todos.v1.Priority
+	(RecurrenceMode)(0),                // 3: todos.v1.RecurrenceMode
+	(DataFormat)(0),                    // 4: todos.v1.DataFormat
+	(ListOrder)(0),                     // 5: todos.v1.ListOrder
+	(MutationOp)(0),                    // 6: todos.v1.MutationOp
+	(MutationStatus)(0),                // 7: todos.v1.MutationStatus
+	(*Todo)(nil),                       // 8: todos.v1.Todo
+	(*Link)(nil),                       // 9: todos.v1.Link
+	(*ChecklistItem)(nil),              // 10: todos.v1.ChecklistItem
+	(*CreateRequest)(nil),              // 11: todos.v1.CreateRequest
+	(*CreateResponse)(nil),             // 12: todos.v1.CreateResponse
+	(*GetRequest)(nil),                 // 13: todos.v1.GetRequest
+	(*GetResponse)(nil),                // 14: todos.v1.GetResponse
+	(*ListRequest)(nil),                // 15: todos.v1.ListRequest
+	(*ListResponse)(nil),               // 16: todos.v1.ListResponse
+	(*DeleteRequest)(nil),              // 17: todos.v1.DeleteRequest
+	(*DeleteResponse)(nil),             // 18: todos.v1.DeleteResponse
+	(*UpdateRequest)(nil),              // 19: todos.v1.UpdateRequest
+	(*UpdateResponse)(nil),             // 20: todos.v1.UpdateResponse
+	(*SyncRequest)(nil),                // 21: todos.v1.SyncRequest
+	(*SyncResponse)(nil),               // 22: todos.v1.SyncResponse
+	(*Mutation)(nil),                   // 23: todos.v1.Mutation
+	(*PushRequest)(nil),                // 24: todos.v1.PushRequest
+	(*MutationResult)(nil),             // 25: todos.v1.MutationResult
+	(*PushResponse)(nil),               // 26: todos.v1.PushResponse
+	(*MoveRequest)(nil),                // 27: todos.v1.MoveRequest
+	(*MoveResponse)(nil),               // 28: todos.v1.MoveResponse
+	(*PreviewOccurrencesRequest)(nil),  // 29: todos.v1.PreviewOccurrencesRequest
+	(*PreviewOccurrencesResponse)(nil), // 30: todos.v1.PreviewOccurrencesResponse
+	(*ExportRequest)(nil),              // 31: todos.v1.ExportRequest
+	(*ExportResponse)(nil),             // 32: todos.v1.ExportResponse
+	(*ImportRequest)(nil),              // 33: todos.v1.ImportRequest
+	(*ImportResponse)(nil),             // 34: todos.v1.ImportResponse
+	(*CalendarFeed)(nil),               // 35: todos.v1.CalendarFeed
+	(*CreateCalendarFeedRequest)(nil),  // 36: todos.v1.CreateCalendarFeedRequest
+	(*CreateCalendarFeedResponse)(nil), // 37: todos.v1.CreateCalendarFeedResponse
+	(*ListCalendarFeedsRequest)(nil),   // 38: todos.v1.ListCalendarFeedsRequest
+	(*ListCalendarFeedsResponse)(nil),  // 39: todos.v1.ListCalendarFeedsResponse
+	(*RevokeCalendarFeedRequest)(nil),  // 40: todos.v1.RevokeCalendarFeedRequest
+	(*RevokeCalendarFeedResponse)(nil), // 41: todos.v1.RevokeCalendarFeedResponse
+	(*ApiKey)(nil),                     // 42: todos.v1.ApiKey
+	(*CreateApiKeyRequest)(nil),        // 43: todos.v1.CreateApiKeyRequest
+	(*CreateApiKeyResponse)(nil),       // 44: todos.v1.CreateApiKeyResponse
+	(*ListApiKeysRequest)(nil),         // 45: todos.v1.ListApiKeysRequest
+	(*ListApiKeysResponse)(nil),        // 46: todos.v1.ListApiKeysResponse
+	(*RotateApiKeyRequest)(nil),        // 47: todos.v1.RotateApiKeyRequest
+	(*RotateApiKeyResponse)(nil),       // 48: todos.v1.RotateApiKeyResponse
+	(*RevokeApiKeyRequest)(nil),        // 49: todos.v1.RevokeApiKeyRequest
+	(*RevokeApiKeyResponse)(nil),       // 50: todos.v1.RevokeApiKeyResponse
+	(*TodoList)(nil),                   // 51: todos.v1.TodoList
+	(*Member)(nil),                     // 52: todos.v1.Member
+	(*Invitation)(nil),                 // 53: todos.v1.Invitation
+	(*CreateListRequest)(nil),          // 54: todos.v1.CreateListRequest
+	(*CreateListResponse)(nil),         // 55: todos.v1.CreateListResponse
+	(*ListListsRequest)(nil),           // 56: todos.v1.ListListsRequest
+	(*ListListsResponse)(nil),          // 57: todos.v1.ListListsResponse
+	(*InviteMemberRequest)(nil),        // 58: todos.v1.InviteMemberRequest
+	(*InviteMemberResponse)(nil),       // 59: todos.v1.InviteMemberResponse
+	(*ListInvitationsRequest)(nil),     // 60: todos.v1.ListInvitationsRequest
+	(*ListInvitationsResponse)(nil),    // 61: todos.v1.ListInvitationsResponse
+	(*AcceptInvitationRequest)(nil),    // 62: todos.v1.AcceptInvitationRequest
+	(*AcceptInvitationResponse)(nil),   // 63: todos.v1.AcceptInvitationResponse
+	(*DeclineInvitationRequest)(nil),   // 64: todos.v1.DeclineInvitationRequest
+	(*DeclineInvitationResponse)(nil),  // 65: todos.v1.DeclineInvitationResponse
+	(*ListMembersRequest)(nil),         // 66: todos.v1.ListMembersRequest
+	(*ListMembersResponse)(nil),        // 67: todos.v1.ListMembersResponse
+	(*UpdateMemberRequest)(nil),        // 68: todos.v1.UpdateMemberRequest
+	(*UpdateMemberResponse)(nil),       // 69: todos.v1.UpdateMemberResponse
+	(*RemoveMemberRequest)(nil),        // 70: todos.v1.RemoveMemberRequest
+	(*RemoveMemberResponse)(nil),       // 71: todos.v1.RemoveMemberResponse
+	(*Attachment)(nil),                 // 72: todos.v1.Attachment
+	(*UploadAttachmentRequest)(nil),    // 73: todos.v1.UploadAttachmentRequest
+	(*UploadAttachmentResponse)(nil),   // 74: todos.v1.UploadAttachmentResponse
+	(*ListAttachmentsRequest)(nil),     // 75: todos.v1.ListAttachmentsRequest
+	(*ListAttachmentsResponse)(nil),    // 76: todos.v1.ListAttachmentsResponse
+	(*DownloadAttachmentRequest)(nil),  // 77: todos.v1.DownloadAttachmentRequest
+	(*DownloadAttachmentResponse)(nil), // 78: todos.v1.DownloadAttachmentResponse
+	(*DeleteAttachmentRequest)(nil),    // 79: todos.v1.DeleteAttachmentRequest
+	(*DeleteAttachmentResponse)(nil),   // 80: todos.v1.DeleteAttachmentResponse
+	(*Comment)(nil),                    // 81: todos.v1.Comment
+	(*CreateCommentRequest)(nil),       // 82: todos.v1.CreateCommentRequest
+	(*CreateCommentResponse)(nil),      // 83: todos.v1.CreateCommentResponse
+	(*ListCommentsRequest)(nil),        // 84: todos.v1.ListCommentsRequest
+	(*ListCommentsResponse)(nil),       // 85: todos.v1.ListCommentsResponse
+	(*GetCommentRequest)(nil),          // 86: todos.v1.GetCommentRequest
+	(*GetCommentResponse)(nil),         // 87: todos.v1.GetCommentResponse
+	(*UpdateCommentRequest)(nil),       // 88: todos.v1.UpdateCommentRequest
+	(*UpdateCommentResponse)(nil),      // 89: todos.v1.UpdateCommentResponse
+	(*DeleteCommentRequest)(nil),       // 90: todos.v1.DeleteCommentRequest
+	(*DeleteCommentResponse)(nil),      // 91: todos.v1.DeleteCommentResponse
+	(*Dependency)(nil),                 // 92: todos.v1.Dependency
+	(*AddDependencyRequest)(nil),       // 93: todos.v1.AddDependencyRequest
+	(*AddDependencyResponse)(nil),      // 94: todos.v1.AddDependencyResponse
+	(*RemoveDependencyRequest)(nil),    // 95: todos.v1.RemoveDependencyRequest
+	(*RemoveDependencyResponse)(nil),   // 96: todos.v1.RemoveDependencyResponse
+	(*GetDependencyGraphRequest)(nil),  // 97: todos.v1.GetDependencyGraphRequest
+	(*GetDependencyGraphResponse)(nil), // 98: todos.v1.GetDependencyGraphResponse
+	(*ListNextRequest)(nil),            // 99: todos.v1.ListNextRequest
+	(*ListNextResponse)(nil),           // 100: todos.v1.ListNextResponse
+	(*fieldmaskpb.FieldMask)(nil),      // 101: google.protobuf.FieldMask
 }
 var file_protos_todos_v1_todos_proto_depIdxs = []int32{
-	0,  // 0: todos.v1.CreateResponse.todo:type_name -> todos.v1.Todo
-	0,  // 1: todos.v1.GetResponse.todo:type_name -> todos.v1.Todo
-	0,  // 2: todos.v1.ListResponse.todos:type_name -> todos.v1.Todo
-	0,  // 3: todos.v1.UpdateResponse.todo:type_name -> todos.v1.Todo
-	1,  // 4: todos.v1.TodosService.Create:input_type -> todos.v1.CreateRequest
-	3,  // 5: todos.v1.TodosService.Get:input_type -> todos.v1.GetRequest
-	9,  // 6: todos.v1.TodosService.Update:input_type -> todos.v1.UpdateRequest
-	7,  // 7: todos.v1.TodosService.Delete:input_type -> todos.v1.DeleteRequest
-	5,  // 8: todos.v1.TodosService.List:input_type -> todos.v1.ListRequest
-	2,  // 9: todos.v1.TodosService.Create:output_type -> todos.v1.CreateResponse
-	4,  // 10: todos.v1.TodosService.Get:output_type -> todos.v1.GetResponse
-	10, // 11: todos.v1.TodosService.Update:output_type -> todos.v1.UpdateResponse
-	8,  // 12: todos.v1.TodosService.Delete:output_type -> todos.v1.DeleteResponse
-	6,  // 13: todos.v1.TodosService.List:output_type -> todos.v1.ListResponse
-	9,  // [9:14] is the sub-list for method output_type
-	4,  // [4:9] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	2,   // 0: todos.v1.Todo.priority:type_name -> todos.v1.Priority
+	3,   // 1: todos.v1.Todo.recurrence_mode:type_name -> todos.v1.RecurrenceMode
+	9,   // 2: todos.v1.Todo.links:type_name -> todos.v1.Link
+	10,  // 3: todos.v1.Todo.checklist:type_name -> todos.v1.ChecklistItem
+	2,   // 4: todos.v1.CreateRequest.priority:type_name -> todos.v1.Priority
+	3,   // 5: todos.v1.CreateRequest.recurrence_mode:type_name -> todos.v1.RecurrenceMode
+	8,   // 6: todos.v1.CreateResponse.todo:type_name -> todos.v1.Todo
+	8,   // 7: todos.v1.GetResponse.todo:type_name -> todos.v1.Todo
+	5,   // 8: todos.v1.ListRequest.order:type_name -> todos.v1.ListOrder
+	8,   // 9: todos.v1.ListResponse.todos:type_name -> todos.v1.Todo
+	2,   // 10: todos.v1.UpdateRequest.priority:type_name -> todos.v1.Priority
+	3,   // 11: todos.v1.UpdateRequest.recurrence_mode:type_name -> todos.v1.RecurrenceMode
+	101, // 12: todos.v1.UpdateRequest.update_mask:type_name -> google.protobuf.FieldMask
+	8,   // 13: todos.v1.UpdateResponse.todo:type_name -> todos.v1.Todo
+	8,   // 14: todos.v1.SyncResponse.todos:type_name -> todos.v1.Todo
+	6,   // 15: todos.v1.Mutation.op:type_name -> todos.v1.MutationOp
+	2,   // 16: todos.v1.Mutation.priority:type_name -> todos.v1.Priority
+	23,  // 17: todos.v1.PushRequest.mutations:type_name -> todos.v1.Mutation
+	7,   // 18: todos.v1.MutationResult.status:type_name -> todos.v1.MutationStatus
+	8,   // 19: todos.v1.MutationResult.todo:type_name -> todos.v1.Todo
+	25,  // 20: todos.v1.PushResponse.results:type_name -> todos.v1.MutationResult
+	8,   // 21: todos.v1.MoveResponse.todo:type_name -> todos.v1.Todo
+	4,   // 22: todos.v1.ExportRequest.format:type_name -> todos.v1.DataFormat
+	4,   // 23: todos.v1.ImportRequest.format:type_name -> todos.v1.DataFormat
+	8,   // 24: todos.v1.ImportResponse.todos:type_name -> todos.v1.Todo
+	35,  // 25: todos.v1.CreateCalendarFeedResponse.feed:type_name -> todos.v1.CalendarFeed
+	35,  // 26: todos.v1.ListCalendarFeedsResponse.feeds:type_name -> todos.v1.CalendarFeed
+	42,  // 27: todos.v1.CreateApiKeyResponse.api_key:type_name -> todos.v1.ApiKey
+	42,  // 28: todos.v1.ListApiKeysResponse.api_keys:type_name -> todos.v1.ApiKey
+	42,  // 29: todos.v1.RotateApiKeyResponse.api_key:type_name -> todos.v1.ApiKey
+	0,   // 30: todos.v1.TodoList.role:type_name -> todos.v1.Role
+	0,   // 31: todos.v1.Member.role:type_name -> todos.v1.Role
+	0,   // 32: todos.v1.Invitation.role:type_name -> todos.v1.Role
+	1,   // 33: todos.v1.Invitation.status:type_name -> todos.v1.InvitationStatus
+	51,  // 34: todos.v1.CreateListResponse.list:type_name -> todos.v1.TodoList
+	51,  // 35: todos.v1.ListListsResponse.lists:type_name -> todos.v1.TodoList
+	0,   // 36: todos.v1.InviteMemberRequest.role:type_name -> todos.v1.Role
+	53,  // 37: todos.v1.InviteMemberResponse.invitation:type_name -> todos.v1.Invitation
+	53,  // 38: todos.v1.ListInvitationsResponse.invitations:type_name -> todos.v1.Invitation
+	52,  // 39: todos.v1.AcceptInvitationResponse.member:type_name -> todos.v1.Member
+	52,  // 40: todos.v1.ListMembersResponse.members:type_name -> todos.v1.Member
+	0,   // 41: todos.v1.UpdateMemberRequest.role:type_name -> todos.v1.Role
+	52,  // 42: todos.v1.UpdateMemberResponse.member:type_name -> todos.v1.Member
+	72,  // 43: todos.v1.UploadAttachmentResponse.attachment:type_name -> todos.v1.Attachment
+	72,  // 44: todos.v1.ListAttachmentsResponse.attachments:type_name -> todos.v1.Attachment
+	72,  // 45: todos.v1.DownloadAttachmentResponse.attachment:type_name -> todos.v1.Attachment
+	81,  // 46: todos.v1.CreateCommentResponse.comment:type_name -> todos.v1.Comment
+	81,  // 47: todos.v1.ListCommentsResponse.comments:type_name -> todos.v1.Comment
+	81,  // 48: todos.v1.GetCommentResponse.comment:type_name -> todos.v1.Comment
+	81,  // 49: todos.v1.UpdateCommentResponse.comment:type_name -> todos.v1.Comment
+	92,  // 50: todos.v1.AddDependencyResponse.dependency:type_name -> todos.v1.Dependency
+	8,   // 51: todos.v1.GetDependencyGraphResponse.nodes:type_name -> todos.v1.Todo
+	92,  // 52: todos.v1.GetDependencyGraphResponse.edges:type_name -> todos.v1.Dependency
+	8,   // 53: todos.v1.ListNextResponse.todos:type_name -> todos.v1.Todo
+	11,  // 54: todos.v1.TodosService.Create:input_type -> todos.v1.CreateRequest
+	13,  // 55: todos.v1.TodosService.Get:input_type -> todos.v1.GetRequest
+	19,  // 56: todos.v1.TodosService.Update:input_type -> todos.v1.UpdateRequest
+	17,  // 57: todos.v1.TodosService.Delete:input_type -> todos.v1.DeleteRequest
+	15,  // 58: todos.v1.TodosService.List:input_type -> todos.v1.ListRequest
+	21,  // 59: todos.v1.TodosService.Sync:input_type -> todos.v1.SyncRequest
+	24,  // 60: todos.v1.TodosService.Push:input_type -> todos.v1.PushRequest
+	27,  // 61: todos.v1.TodosService.Move:input_type -> todos.v1.MoveRequest
+	29,  // 62: todos.v1.TodosService.PreviewOccurrences:input_type -> todos.v1.PreviewOccurrencesRequest
+	31,  // 63: todos.v1.TodosService.Export:input_type -> todos.v1.ExportRequest
+	33,  // 64: todos.v1.TodosService.Import:input_type -> todos.v1.ImportRequest
+	36,  // 65: todos.v1.TodosService.CreateCalendarFeed:input_type -> todos.v1.CreateCalendarFeedRequest
+	38,  // 66: todos.v1.TodosService.ListCalendarFeeds:input_type -> todos.v1.ListCalendarFeedsRequest
+	40,  // 67: todos.v1.TodosService.RevokeCalendarFeed:input_type -> todos.v1.RevokeCalendarFeedRequest
+	43,  // 68: todos.v1.TodosService.CreateApiKey:input_type -> todos.v1.CreateApiKeyRequest
+	45,  // 69: todos.v1.TodosService.ListApiKeys:input_type -> todos.v1.ListApiKeysRequest
+	47,  // 70: todos.v1.TodosService.RotateApiKey:input_type -> todos.v1.RotateApiKeyRequest
+	49,  // 71: todos.v1.TodosService.RevokeApiKey:input_type -> todos.v1.RevokeApiKeyRequest
+	54,  // 72: todos.v1.TodosService.CreateList:input_type -> todos.v1.CreateListRequest
+	56,  // 73: todos.v1.TodosService.ListLists:input_type -> todos.v1.ListListsRequest
+	58,  // 74: todos.v1.TodosService.InviteMember:input_type -> todos.v1.InviteMemberRequest
+	60,  // 75: todos.v1.TodosService.ListInvitations:input_type -> todos.v1.ListInvitationsRequest
+	62,  // 76: todos.v1.TodosService.AcceptInvitation:input_type -> todos.v1.AcceptInvitationRequest
+	64,  // 77: todos.v1.TodosService.DeclineInvitation:input_type -> todos.v1.DeclineInvitationRequest
+	66,  // 78: todos.v1.TodosService.ListMembers:input_type -> todos.v1.ListMembersRequest
+	68,  // 79: todos.v1.TodosService.UpdateMember:input_type -> todos.v1.UpdateMemberRequest
+	70,  // 80: todos.v1.TodosService.RemoveMember:input_type -> todos.v1.RemoveMemberRequest
+	73,  // 81: todos.v1.TodosService.UploadAttachment:input_type -> todos.v1.UploadAttachmentRequest
+	75,  // 82: todos.v1.TodosService.ListAttachments:input_type -> todos.v1.ListAttachmentsRequest
+	77,  // 83: todos.v1.TodosService.DownloadAttachment:input_type -> todos.v1.DownloadAttachmentRequest
+	79,  // 84: todos.v1.TodosService.DeleteAttachment:input_type -> todos.v1.DeleteAttachmentRequest
+	82,  // 85: todos.v1.TodosService.CreateComment:input_type -> todos.v1.CreateCommentRequest
+	84,  // 86: todos.v1.TodosService.ListComments:input_type -> todos.v1.ListCommentsRequest
+	86,  // 87: todos.v1.TodosService.GetComment:input_type -> todos.v1.GetCommentRequest
+	88,  // 88: todos.v1.TodosService.UpdateComment:input_type -> todos.v1.UpdateCommentRequest
+	90,  // 89: todos.v1.TodosService.DeleteComment:input_type -> todos.v1.DeleteCommentRequest
+	93,  // 90: todos.v1.TodosService.AddDependency:input_type -> todos.v1.AddDependencyRequest
+	95,  // 91: todos.v1.TodosService.RemoveDependency:input_type -> todos.v1.RemoveDependencyRequest
+	97,  // 92: todos.v1.TodosService.GetDependencyGraph:input_type -> todos.v1.GetDependencyGraphRequest
+	99,  // 93: todos.v1.TodosService.ListNext:input_type -> todos.v1.ListNextRequest
+	12,  // 94: todos.v1.TodosService.Create:output_type -> todos.v1.CreateResponse
+	14,  // 95: todos.v1.TodosService.Get:output_type -> todos.v1.GetResponse
+	20,  // 96: todos.v1.TodosService.Update:output_type -> todos.v1.UpdateResponse
+	18,  // 97: todos.v1.TodosService.Delete:output_type -> todos.v1.DeleteResponse
+	16,  // 98: todos.v1.TodosService.List:output_type -> todos.v1.ListResponse
+	22,  // 99: todos.v1.TodosService.Sync:output_type -> todos.v1.SyncResponse
+	26,  // 100: todos.v1.TodosService.Push:output_type -> todos.v1.PushResponse
+	28,  // 101: todos.v1.TodosService.Move:output_type -> todos.v1.MoveResponse
+	30,  // 102: todos.v1.TodosService.PreviewOccurrences:output_type -> todos.v1.PreviewOccurrencesResponse
+	32,  // 103: todos.v1.TodosService.Export:output_type -> todos.v1.ExportResponse
+	34,  // 104: todos.v1.TodosService.Import:output_type -> todos.v1.ImportResponse
+	37,  // 105: todos.v1.TodosService.CreateCalendarFeed:output_type -> todos.v1.CreateCalendarFeedResponse
+	39,  // 106: todos.v1.TodosService.ListCalendarFeeds:output_type -> todos.v1.ListCalendarFeedsResponse
+	41,  // 107: todos.v1.TodosService.RevokeCalendarFeed:output_type -> todos.v1.RevokeCalendarFeedResponse
+	44,  // 108: todos.v1.TodosService.CreateApiKey:output_type -> todos.v1.CreateApiKeyResponse
+	46,  // 109: todos.v1.TodosService.ListApiKeys:output_type -> todos.v1.ListApiKeysResponse
+	48,  // 110: todos.v1.TodosService.RotateApiKey:output_type -> todos.v1.RotateApiKeyResponse
+	50,  // 111: todos.v1.TodosService.RevokeApiKey:output_type -> todos.v1.RevokeApiKeyResponse
+	55,  // 112: todos.v1.TodosService.CreateList:output_type -> todos.v1.CreateListResponse
+	57,  // 113: todos.v1.TodosService.ListLists:output_type -> todos.v1.ListListsResponse
+	59,  // 114: todos.v1.TodosService.InviteMember:output_type -> todos.v1.InviteMemberResponse
+	61,  // 115: todos.v1.TodosService.ListInvitations:output_type -> todos.v1.ListInvitationsResponse
+	63,  // 116: todos.v1.TodosService.AcceptInvitation:output_type -> todos.v1.AcceptInvitationResponse
+	65,  // 117: todos.v1.TodosService.DeclineInvitation:output_type -> todos.v1.DeclineInvitationResponse
+	67,  // 118: todos.v1.TodosService.ListMembers:output_type -> todos.v1.ListMembersResponse
+	69,  // 119: todos.v1.TodosService.UpdateMember:output_type -> todos.v1.UpdateMemberResponse
+	71,  // 120: todos.v1.TodosService.RemoveMember:output_type -> todos.v1.RemoveMemberResponse
+	74,  // 121: todos.v1.TodosService.UploadAttachment:output_type -> todos.v1.UploadAttachmentResponse
+	76,  // 122: todos.v1.TodosService.ListAttachments:output_type -> todos.v1.ListAttachmentsResponse
+	78,  // 123: todos.v1.TodosService.DownloadAttachment:output_type -> todos.v1.DownloadAttachmentResponse
+	80,  // 124: todos.v1.TodosService.DeleteAttachment:output_type -> todos.v1.DeleteAttachmentResponse
+	83,  // 125: todos.v1.TodosService.CreateComment:output_type -> todos.v1.CreateCommentResponse
+	85,  // 126: todos.v1.TodosService.ListComments:output_type -> todos.v1.ListCommentsResponse
+	87,  // 127: todos.v1.TodosService.GetComment:output_type -> todos.v1.GetCommentResponse
+	89,  // 128: todos.v1.TodosService.UpdateComment:output_type -> todos.v1.UpdateCommentResponse
+	91,  // 129: todos.v1.TodosService.DeleteComment:output_type -> todos.v1.DeleteCommentResponse
+	94,  // 130: todos.v1.TodosService.AddDependency:output_type -> todos.v1.AddDependencyResponse
+	96,  // 131: todos.v1.TodosService.RemoveDependency:output_type -> todos.v1.RemoveDependencyResponse
+	98,  // 132: todos.v1.TodosService.GetDependencyGraph:output_type -> todos.v1.GetDependencyGraphResponse
+	100, // 133: todos.v1.TodosService.ListNext:output_type -> todos.v1.ListNextResponse
+	94,  // [94:134] is the sub-list for method output_type
+	54,  // [54:94] is the sub-list for method input_type
+	54,  // [54:54] is the sub-list for extension type_name
+	54,  // [54:54] is the sub-list for extension extendee
+	0,   // [0:54] is the sub-list for field type_name
 }
 
 func init() { file_protos_todos_v1_todos_proto_init() }
@@ -616,18 +6233,24 @@ func file_protos_todos_v1_todos_proto_init() {
 	if File_protos_todos_v1_todos_proto != nil {
 		return
 	}
+	file_protos_todos_v1_todos_proto_msgTypes[0].OneofWrappers = []any{}
+	file_protos_todos_v1_todos_proto_msgTypes[19].OneofWrappers = []any{
+		(*MoveRequest_BeforeId)(nil),
+		(*MoveRequest_AfterId)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_todos_v1_todos_proto_rawDesc), len(file_protos_todos_v1_todos_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      8,
+			NumMessages:   93,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_protos_todos_v1_todos_proto_goTypes,
 		DependencyIndexes: file_protos_todos_v1_todos_proto_depIdxs,
+		EnumInfos:         file_protos_todos_v1_todos_proto_enumTypes,
 		MessageInfos:      file_protos_todos_v1_todos_proto_msgTypes,
 	}.Build()
 	File_protos_todos_v1_todos_proto = out.File
@@ -43,15 +43,191 @@ const (
 	TodosServiceDeleteProcedure = "/todos.v1.TodosService/Delete"
 	// TodosServiceListProcedure is the fully-qualified name of the TodosService's List RPC.
 	TodosServiceListProcedure = "/todos.v1.TodosService/List"
+	// TodosServiceSyncProcedure is the fully-qualified name of the TodosService's Sync RPC.
+	TodosServiceSyncProcedure = "/todos.v1.TodosService/Sync"
+	// TodosServicePushProcedure is the fully-qualified name of the TodosService's Push RPC.
+	TodosServicePushProcedure = "/todos.v1.TodosService/Push"
+	// TodosServiceMoveProcedure is the fully-qualified name of the TodosService's Move RPC.
+	TodosServiceMoveProcedure = "/todos.v1.TodosService/Move"
+	// TodosServicePreviewOccurrencesProcedure is the fully-qualified name of the TodosService's
+	// PreviewOccurrences RPC.
+	TodosServicePreviewOccurrencesProcedure = "/todos.v1.TodosService/PreviewOccurrences"
+	// TodosServiceExportProcedure is the fully-qualified name of the TodosService's Export RPC.
+	TodosServiceExportProcedure = "/todos.v1.TodosService/Export"
+	// TodosServiceImportProcedure is the fully-qualified name of the TodosService's Import RPC.
+	TodosServiceImportProcedure = "/todos.v1.TodosService/Import"
+	// TodosServiceCreateCalendarFeedProcedure is the fully-qualified name of the TodosService's
+	// CreateCalendarFeed RPC.
+	TodosServiceCreateCalendarFeedProcedure = "/todos.v1.TodosService/CreateCalendarFeed"
+	// TodosServiceListCalendarFeedsProcedure is the fully-qualified name of the TodosService's
+	// ListCalendarFeeds RPC.
+	TodosServiceListCalendarFeedsProcedure = "/todos.v1.TodosService/ListCalendarFeeds"
+	// TodosServiceRevokeCalendarFeedProcedure is the fully-qualified name of the TodosService's
+	// RevokeCalendarFeed RPC.
+	TodosServiceRevokeCalendarFeedProcedure = "/todos.v1.TodosService/RevokeCalendarFeed"
+	// TodosServiceCreateApiKeyProcedure is the fully-qualified name of the TodosService's CreateApiKey
+	// RPC.
+	TodosServiceCreateApiKeyProcedure = "/todos.v1.TodosService/CreateApiKey"
+	// TodosServiceListApiKeysProcedure is the fully-qualified name of the TodosService's ListApiKeys
+	// RPC.
+	TodosServiceListApiKeysProcedure = "/todos.v1.TodosService/ListApiKeys"
+	// TodosServiceRotateApiKeyProcedure is the fully-qualified name of the TodosService's RotateApiKey
+	// RPC.
+	TodosServiceRotateApiKeyProcedure = "/todos.v1.TodosService/RotateApiKey"
+	// TodosServiceRevokeApiKeyProcedure is the fully-qualified name of the TodosService's RevokeApiKey
+	// RPC.
+	TodosServiceRevokeApiKeyProcedure = "/todos.v1.TodosService/RevokeApiKey"
+	// TodosServiceCreateListProcedure is the fully-qualified name of the TodosService's CreateList RPC.
+	TodosServiceCreateListProcedure = "/todos.v1.TodosService/CreateList"
+	// TodosServiceListListsProcedure is the fully-qualified name of the TodosService's ListLists RPC.
+	TodosServiceListListsProcedure = "/todos.v1.TodosService/ListLists"
+	// TodosServiceInviteMemberProcedure is the fully-qualified name of the TodosService's InviteMember
+	// RPC.
+	TodosServiceInviteMemberProcedure = "/todos.v1.TodosService/InviteMember"
+	// TodosServiceListInvitationsProcedure is the fully-qualified name of the TodosService's
+	// ListInvitations RPC.
+	TodosServiceListInvitationsProcedure = "/todos.v1.TodosService/ListInvitations"
+	// TodosServiceAcceptInvitationProcedure is the fully-qualified name of the TodosService's
+	// AcceptInvitation RPC.
+	TodosServiceAcceptInvitationProcedure = "/todos.v1.TodosService/AcceptInvitation"
+	// TodosServiceDeclineInvitationProcedure is the fully-qualified name of the TodosService's
+	// DeclineInvitation RPC.
+	TodosServiceDeclineInvitationProcedure = "/todos.v1.TodosService/DeclineInvitation"
+	// TodosServiceListMembersProcedure is the fully-qualified name of the TodosService's ListMembers
+	// RPC.
+	TodosServiceListMembersProcedure = "/todos.v1.TodosService/ListMembers"
+	// TodosServiceUpdateMemberProcedure is the fully-qualified name of the TodosService's UpdateMember
+	// RPC.
+	TodosServiceUpdateMemberProcedure = "/todos.v1.TodosService/UpdateMember"
+	// TodosServiceRemoveMemberProcedure is the fully-qualified name of the TodosService's RemoveMember
+	// RPC.
+	TodosServiceRemoveMemberProcedure = "/todos.v1.TodosService/RemoveMember"
+	// TodosServiceUploadAttachmentProcedure is the fully-qualified name of the TodosService's
+	// UploadAttachment RPC.
+	TodosServiceUploadAttachmentProcedure = "/todos.v1.TodosService/UploadAttachment"
+	// TodosServiceListAttachmentsProcedure is the fully-qualified name of the TodosService's
+	// ListAttachments RPC.
+	TodosServiceListAttachmentsProcedure = "/todos.v1.TodosService/ListAttachments"
+	// TodosServiceDownloadAttachmentProcedure is the fully-qualified name of the TodosService's
+	// DownloadAttachment RPC.
+	TodosServiceDownloadAttachmentProcedure = "/todos.v1.TodosService/DownloadAttachment"
+	// TodosServiceDeleteAttachmentProcedure is the fully-qualified name of the TodosService's
+	// DeleteAttachment RPC.
+	TodosServiceDeleteAttachmentProcedure = "/todos.v1.TodosService/DeleteAttachment"
+	// TodosServiceCreateCommentProcedure is the fully-qualified name of the TodosService's
+	// CreateComment RPC.
+	TodosServiceCreateCommentProcedure = "/todos.v1.TodosService/CreateComment"
+	// TodosServiceListCommentsProcedure is the fully-qualified name of the TodosService's ListComments
+	// RPC.
+	TodosServiceListCommentsProcedure = "/todos.v1.TodosService/ListComments"
+	// TodosServiceGetCommentProcedure is the fully-qualified name of the TodosService's GetComment RPC.
+	TodosServiceGetCommentProcedure = "/todos.v1.TodosService/GetComment"
+	// TodosServiceUpdateCommentProcedure is the fully-qualified name of the TodosService's
+	// UpdateComment RPC.
+	TodosServiceUpdateCommentProcedure = "/todos.v1.TodosService/UpdateComment"
+	// TodosServiceDeleteCommentProcedure is the fully-qualified name of the TodosService's
+	// DeleteComment RPC.
+	TodosServiceDeleteCommentProcedure = "/todos.v1.TodosService/DeleteComment"
+	// TodosServiceAddDependencyProcedure is the fully-qualified name of the TodosService's
+	// AddDependency RPC.
+	TodosServiceAddDependencyProcedure = "/todos.v1.TodosService/AddDependency"
+	// TodosServiceRemoveDependencyProcedure is the fully-qualified name of the TodosService's
+	// RemoveDependency RPC.
+	TodosServiceRemoveDependencyProcedure = "/todos.v1.TodosService/RemoveDependency"
+	// TodosServiceGetDependencyGraphProcedure is the fully-qualified name of the TodosService's
+	// GetDependencyGraph RPC.
+	TodosServiceGetDependencyGraphProcedure = "/todos.v1.TodosService/GetDependencyGraph"
+	// TodosServiceListNextProcedure is the fully-qualified name of the TodosService's ListNext RPC.
+	TodosServiceListNextProcedure = "/todos.v1.TodosService/ListNext"
 )
 
 // TodosServiceClient is a client for the todos.v1.TodosService service.
 type TodosServiceClient interface {
 	Create(context.Context, *connect.Request[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error)
+	// Get and List can be sent as HTTP GET and answer conditional requests
+	// with 304 Not Modified.
 	Get(context.Context, *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error)
+	// Update replaces every field of a todo, or only the fields in update_mask.
+	// PATCH requests over REST set update_mask to the fields in the body.
 	Update(context.Context, *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error)
 	Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error)
 	List(context.Context, *connect.Request[v1.ListRequest]) (*connect.Response[v1.ListResponse], error)
+	// Sync returns every todo changed or deleted since the given sync token.
+	Sync(context.Context, *connect.Request[v1.SyncRequest]) (*connect.Response[v1.SyncResponse], error)
+	// Push applies a batch of offline client mutations and reports a result per item.
+	Push(context.Context, *connect.Request[v1.PushRequest]) (*connect.Response[v1.PushResponse], error)
+	// Move places a todo directly before or after a sibling in the manual order.
+	Move(context.Context, *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error)
+	// PreviewOccurrences returns the next dates a recurrence rule produces.
+	PreviewOccurrences(context.Context, *connect.Request[v1.PreviewOccurrencesRequest]) (*connect.Response[v1.PreviewOccurrencesResponse], error)
+	// Export streams all todos encoded in the requested format.
+	Export(context.Context, *connect.Request[v1.ExportRequest]) (*connect.ServerStreamForClient[v1.ExportResponse], error)
+	// Import reads a file streamed in chunks and creates its todos in one transaction.
+	Import(context.Context) *connect.ClientStreamForClient[v1.ImportRequest, v1.ImportResponse]
+	// CreateCalendarFeed creates a secret token for subscribing to the iCalendar feed.
+	CreateCalendarFeed(context.Context, *connect.Request[v1.CreateCalendarFeedRequest]) (*connect.Response[v1.CreateCalendarFeedResponse], error)
+	ListCalendarFeeds(context.Context, *connect.Request[v1.ListCalendarFeedsRequest]) (*connect.Response[v1.ListCalendarFeedsResponse], error)
+	RevokeCalendarFeed(context.Context, *connect.Request[v1.RevokeCalendarFeedRequest]) (*connect.Response[v1.RevokeCalendarFeedResponse], error)
+	// CreateApiKey creates a key for callers that cannot log in interactively.
+	// The API key methods require the keys:admin scope.
+	CreateApiKey(context.Context, *connect.Request[v1.CreateApiKeyRequest]) (*connect.Response[v1.CreateApiKeyResponse], error)
+	ListApiKeys(context.Context, *connect.Request[v1.ListApiKeysRequest]) (*connect.Response[v1.ListApiKeysResponse], error)
+	// RotateApiKey replaces the secret of a key; the old token stops working at once.
+	RotateApiKey(context.Context, *connect.Request[v1.RotateApiKeyRequest]) (*connect.Response[v1.RotateApiKeyResponse], error)
+	RevokeApiKey(context.Context, *connect.Request[v1.RevokeApiKeyRequest]) (*connect.Response[v1.RevokeApiKeyResponse], error)
+	// CreateList creates a shared list owned by the caller. Todos in a list are
+	// only visible to its members, and only editors and owners may change them.
+	CreateList(context.Context, *connect.Request[v1.CreateListRequest]) (*connect.Response[v1.CreateListResponse], error)
+	// ListLists returns the lists the caller is a member of.
+	ListLists(context.Context, *connect.Request[v1.ListListsRequest]) (*connect.Response[v1.ListListsResponse], error)
+	// InviteMember invites a user, by user ID or email, to a list. Owners only.
+	InviteMember(context.Context, *connect.Request[v1.InviteMemberRequest]) (*connect.Response[v1.InviteMemberResponse], error)
+	// ListInvitations returns the pending invitations addressed to the caller.
+	ListInvitations(context.Context, *connect.Request[v1.ListInvitationsRequest]) (*connect.Response[v1.ListInvitationsResponse], error)
+	// AcceptInvitation makes the caller a member. The next Sync returns the
+	// todos the list already holds.
+	AcceptInvitation(context.Context, *connect.Request[v1.AcceptInvitationRequest]) (*connect.Response[v1.AcceptInvitationResponse], error)
+	DeclineInvitation(context.Context, *connect.Request[v1.DeclineInvitationRequest]) (*connect.Response[v1.DeclineInvitationResponse], error)
+	ListMembers(context.Context, *connect.Request[v1.ListMembersRequest]) (*connect.Response[v1.ListMembersResponse], error)
+	// UpdateMember changes the role of a member. Owners only; a list always
+	// keeps at least one owner.
+	UpdateMember(context.Context, *connect.Request[v1.UpdateMemberRequest]) (*connect.Response[v1.UpdateMemberResponse], error)
+	// RemoveMember removes a member. Owners may remove anyone, members themselves.
+	RemoveMember(context.Context, *connect.Request[v1.RemoveMemberRequest]) (*connect.Response[v1.RemoveMemberResponse], error)
+	// UploadAttachment attaches a file streamed in chunks to a todo. Over
+	// plain HTTP, files are uploaded as multipart/form-data with a "file"
+	// part to POST /v1/todos/{todo_id}/attachments.
+	UploadAttachment(context.Context) *connect.ClientStreamForClient[v1.UploadAttachmentRequest, v1.UploadAttachmentResponse]
+	ListAttachments(context.Context, *connect.Request[v1.ListAttachmentsRequest]) (*connect.Response[v1.ListAttachmentsResponse], error)
+	// DownloadAttachment streams the metadata of an attachment followed by its
+	// content. Over plain HTTP, GET /v1/attachments/{id}/content returns the
+	// content as is.
+	DownloadAttachment(context.Context, *connect.Request[v1.DownloadAttachmentRequest]) (*connect.ServerStreamForClient[v1.DownloadAttachmentResponse], error)
+	// DeleteAttachment removes an attachment; its content is deleted in the
+	// background.
+	DeleteAttachment(context.Context, *connect.Request[v1.DeleteAttachmentRequest]) (*connect.Response[v1.DeleteAttachmentResponse], error)
+	// CreateComment adds a comment to a todo. "@user" in the body mentions
+	// that user.
+	CreateComment(context.Context, *connect.Request[v1.CreateCommentRequest]) (*connect.Response[v1.CreateCommentResponse], error)
+	// ListComments returns the comments of a todo, oldest first.
+	ListComments(context.Context, *connect.Request[v1.ListCommentsRequest]) (*connect.Response[v1.ListCommentsResponse], error)
+	GetComment(context.Context, *connect.Request[v1.GetCommentRequest]) (*connect.Response[v1.GetCommentResponse], error)
+	// UpdateComment changes the body of a comment. Authors only.
+	UpdateComment(context.Context, *connect.Request[v1.UpdateCommentRequest]) (*connect.Response[v1.UpdateCommentResponse], error)
+	// DeleteComment removes a comment. Its author and the owners of the list
+	// of the todo may delete it.
+	DeleteComment(context.Context, *connect.Request[v1.DeleteCommentRequest]) (*connect.Response[v1.DeleteCommentResponse], error)
+	// AddDependency records that a todo cannot be completed before another
+	// one, its blocker. Both must be in the same list, and a todo cannot end
+	// up blocking itself, directly or through others.
+	AddDependency(context.Context, *connect.Request[v1.AddDependencyRequest]) (*connect.Response[v1.AddDependencyResponse], error)
+	RemoveDependency(context.Context, *connect.Request[v1.RemoveDependencyRequest]) (*connect.Response[v1.RemoveDependencyResponse], error)
+	// GetDependencyGraph returns the todos that block or are blocked by
+	// others, and the dependencies between them.
+	GetDependencyGraph(context.Context, *connect.Request[v1.GetDependencyGraphRequest]) (*connect.Response[v1.GetDependencyGraphResponse], error)
+	// ListNext returns the open todos in an order they can be done in: every
+	// todo comes after its blockers.
+	ListNext(context.Context, *connect.Request[v1.ListNextRequest]) (*connect.Response[v1.ListNextResponse], error)
 }
 
 // NewTodosServiceClient constructs a client for the todos.v1.TodosService service. By default, it
@@ -75,6 +251,7 @@ func NewTodosServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			httpClient,
 			baseURL+TodosServiceGetProcedure,
 			connect.WithSchema(todosServiceMethods.ByName("Get")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		update: connect.NewClient[v1.UpdateRequest, v1.UpdateResponse](
@@ -93,6 +270,229 @@ func NewTodosServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			httpClient,
 			baseURL+TodosServiceListProcedure,
 			connect.WithSchema(todosServiceMethods.ByName("List")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		sync: connect.NewClient[v1.SyncRequest, v1.SyncResponse](
+			httpClient,
+			baseURL+TodosServiceSyncProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("Sync")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		push: connect.NewClient[v1.PushRequest, v1.PushResponse](
+			httpClient,
+			baseURL+TodosServicePushProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("Push")),
+			connect.WithClientOptions(opts...),
+		),
+		move: connect.NewClient[v1.MoveRequest, v1.MoveResponse](
+			httpClient,
+			baseURL+TodosServiceMoveProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("Move")),
+			connect.WithClientOptions(opts...),
+		),
+		previewOccurrences: connect.NewClient[v1.PreviewOccurrencesRequest, v1.PreviewOccurrencesResponse](
+			httpClient,
+			baseURL+TodosServicePreviewOccurrencesProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("PreviewOccurrences")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		export: connect.NewClient[v1.ExportRequest, v1.ExportResponse](
+			httpClient,
+			baseURL+TodosServiceExportProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("Export")),
+			connect.WithClientOptions(opts...),
+		),
+		_import: connect.NewClient[v1.ImportRequest, v1.ImportResponse](
+			httpClient,
+			baseURL+TodosServiceImportProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("Import")),
+			connect.WithClientOptions(opts...),
+		),
+		createCalendarFeed: connect.NewClient[v1.CreateCalendarFeedRequest, v1.CreateCalendarFeedResponse](
+			httpClient,
+			baseURL+TodosServiceCreateCalendarFeedProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("CreateCalendarFeed")),
+			connect.WithClientOptions(opts...),
+		),
+		listCalendarFeeds: connect.NewClient[v1.ListCalendarFeedsRequest, v1.ListCalendarFeedsResponse](
+			httpClient,
+			baseURL+TodosServiceListCalendarFeedsProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListCalendarFeeds")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		revokeCalendarFeed: connect.NewClient[v1.RevokeCalendarFeedRequest, v1.RevokeCalendarFeedResponse](
+			httpClient,
+			baseURL+TodosServiceRevokeCalendarFeedProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("RevokeCalendarFeed")),
+			connect.WithClientOptions(opts...),
+		),
+		createApiKey: connect.NewClient[v1.CreateApiKeyRequest, v1.CreateApiKeyResponse](
+			httpClient,
+			baseURL+TodosServiceCreateApiKeyProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("CreateApiKey")),
+			connect.WithClientOptions(opts...),
+		),
+		listApiKeys: connect.NewClient[v1.ListApiKeysRequest, v1.ListApiKeysResponse](
+			httpClient,
+			baseURL+TodosServiceListApiKeysProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListApiKeys")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		rotateApiKey: connect.NewClient[v1.RotateApiKeyRequest, v1.RotateApiKeyResponse](
+			httpClient,
+			baseURL+TodosServiceRotateApiKeyProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("RotateApiKey")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeApiKey: connect.NewClient[v1.RevokeApiKeyRequest, v1.RevokeApiKeyResponse](
+			httpClient,
+			baseURL+TodosServiceRevokeApiKeyProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("RevokeApiKey")),
+			connect.WithClientOptions(opts...),
+		),
+		createList: connect.NewClient[v1.CreateListRequest, v1.CreateListResponse](
+			httpClient,
+			baseURL+TodosServiceCreateListProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("CreateList")),
+			connect.WithClientOptions(opts...),
+		),
+		listLists: connect.NewClient[v1.ListListsRequest, v1.ListListsResponse](
+			httpClient,
+			baseURL+TodosServiceListListsProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListLists")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		inviteMember: connect.NewClient[v1.InviteMemberRequest, v1.InviteMemberResponse](
+			httpClient,
+			baseURL+TodosServiceInviteMemberProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("InviteMember")),
+			connect.WithClientOptions(opts...),
+		),
+		listInvitations: connect.NewClient[v1.ListInvitationsRequest, v1.ListInvitationsResponse](
+			httpClient,
+			baseURL+TodosServiceListInvitationsProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListInvitations")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		acceptInvitation: connect.NewClient[v1.AcceptInvitationRequest, v1.AcceptInvitationResponse](
+			httpClient,
+			baseURL+TodosServiceAcceptInvitationProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("AcceptInvitation")),
+			connect.WithClientOptions(opts...),
+		),
+		declineInvitation: connect.NewClient[v1.DeclineInvitationRequest, v1.DeclineInvitationResponse](
+			httpClient,
+			baseURL+TodosServiceDeclineInvitationProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("DeclineInvitation")),
+			connect.WithClientOptions(opts...),
+		),
+		listMembers: connect.NewClient[v1.ListMembersRequest, v1.ListMembersResponse](
+			httpClient,
+			baseURL+TodosServiceListMembersProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListMembers")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		updateMember: connect.NewClient[v1.UpdateMemberRequest, v1.UpdateMemberResponse](
+			httpClient,
+			baseURL+TodosServiceUpdateMemberProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("UpdateMember")),
+			connect.WithClientOptions(opts...),
+		),
+		removeMember: connect.NewClient[v1.RemoveMemberRequest, v1.RemoveMemberResponse](
+			httpClient,
+			baseURL+TodosServiceRemoveMemberProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("RemoveMember")),
+			connect.WithClientOptions(opts...),
+		),
+		uploadAttachment: connect.NewClient[v1.UploadAttachmentRequest, v1.UploadAttachmentResponse](
+			httpClient,
+			baseURL+TodosServiceUploadAttachmentProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("UploadAttachment")),
+			connect.WithClientOptions(opts...),
+		),
+		listAttachments: connect.NewClient[v1.ListAttachmentsRequest, v1.ListAttachmentsResponse](
+			httpClient,
+			baseURL+TodosServiceListAttachmentsProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListAttachments")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		downloadAttachment: connect.NewClient[v1.DownloadAttachmentRequest, v1.DownloadAttachmentResponse](
+			httpClient,
+			baseURL+TodosServiceDownloadAttachmentProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("DownloadAttachment")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteAttachment: connect.NewClient[v1.DeleteAttachmentRequest, v1.DeleteAttachmentResponse](
+			httpClient,
+			baseURL+TodosServiceDeleteAttachmentProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("DeleteAttachment")),
+			connect.WithClientOptions(opts...),
+		),
+		createComment: connect.NewClient[v1.CreateCommentRequest, v1.CreateCommentResponse](
+			httpClient,
+			baseURL+TodosServiceCreateCommentProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("CreateComment")),
+			connect.WithClientOptions(opts...),
+		),
+		listComments: connect.NewClient[v1.ListCommentsRequest, v1.ListCommentsResponse](
+			httpClient,
+			baseURL+TodosServiceListCommentsProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListComments")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getComment: connect.NewClient[v1.GetCommentRequest, v1.GetCommentResponse](
+			httpClient,
+			baseURL+TodosServiceGetCommentProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("GetComment")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		updateComment: connect.NewClient[v1.UpdateCommentRequest, v1.UpdateCommentResponse](
+			httpClient,
+			baseURL+TodosServiceUpdateCommentProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("UpdateComment")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteComment: connect.NewClient[v1.DeleteCommentRequest, v1.DeleteCommentResponse](
+			httpClient,
+			baseURL+TodosServiceDeleteCommentProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("DeleteComment")),
+			connect.WithClientOptions(opts...),
+		),
+		addDependency: connect.NewClient[v1.AddDependencyRequest, v1.AddDependencyResponse](
+			httpClient,
+			baseURL+TodosServiceAddDependencyProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("AddDependency")),
+			connect.WithClientOptions(opts...),
+		),
+		removeDependency: connect.NewClient[v1.RemoveDependencyRequest, v1.RemoveDependencyResponse](
+			httpClient,
+			baseURL+TodosServiceRemoveDependencyProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("RemoveDependency")),
+			connect.WithClientOptions(opts...),
+		),
+		getDependencyGraph: connect.NewClient[v1.GetDependencyGraphRequest, v1.GetDependencyGraphResponse](
+			httpClient,
+			baseURL+TodosServiceGetDependencyGraphProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("GetDependencyGraph")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listNext: connect.NewClient[v1.ListNextRequest, v1.ListNextResponse](
+			httpClient,
+			baseURL+TodosServiceListNextProcedure,
+			connect.WithSchema(todosServiceMethods.ByName("ListNext")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 	}
@@ -100,11 +500,46 @@ func NewTodosServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 
 // todosServiceClient implements TodosServiceClient.
 type todosServiceClient struct {
-	create *connect.Client[v1.CreateRequest, v1.CreateResponse]
-	get    *connect.Client[v1.GetRequest, v1.GetResponse]
-	update *connect.Client[v1.UpdateRequest, v1.UpdateResponse]
-	delete *connect.Client[v1.DeleteRequest, v1.DeleteResponse]
-	list   *connect.Client[v1.ListRequest, v1.ListResponse]
+	create             *connect.Client[v1.CreateRequest, v1.CreateResponse]
+	get                *connect.Client[v1.GetRequest, v1.GetResponse]
+	update             *connect.Client[v1.UpdateRequest, v1.UpdateResponse]
+	delete             *connect.Client[v1.DeleteRequest, v1.DeleteResponse]
+	list               *connect.Client[v1.ListRequest, v1.ListResponse]
+	sync               *connect.Client[v1.SyncRequest, v1.SyncResponse]
+	push               *connect.Client[v1.PushRequest, v1.PushResponse]
+	move               *connect.Client[v1.MoveRequest, v1.MoveResponse]
+	previewOccurrences *connect.Client[v1.PreviewOccurrencesRequest, v1.PreviewOccurrencesResponse]
+	export             *connect.Client[v1.ExportRequest, v1.ExportResponse]
+	_import            *connect.Client[v1.ImportRequest, v1.ImportResponse]
+	createCalendarFeed *connect.Client[v1.CreateCalendarFeedRequest, v1.CreateCalendarFeedResponse]
+	listCalendarFeeds  *connect.Client[v1.ListCalendarFeedsRequest, v1.ListCalendarFeedsResponse]
+	revokeCalendarFeed *connect.Client[v1.RevokeCalendarFeedRequest, v1.RevokeCalendarFeedResponse]
+	createApiKey       *connect.Client[v1.CreateApiKeyRequest, v1.CreateApiKeyResponse]
+	listApiKeys        *connect.Client[v1.ListApiKeysRequest, v1.ListApiKeysResponse]
+	rotateApiKey       *connect.Client[v1.RotateApiKeyRequest, v1.RotateApiKeyResponse]
+	revokeApiKey       *connect.Client[v1.RevokeApiKeyRequest, v1.RevokeApiKeyResponse]
+	createList         *connect.Client[v1.CreateListRequest, v1.CreateListResponse]
+	listLists          *connect.Client[v1.ListListsRequest, v1.ListListsResponse]
+	inviteMember       *connect.Client[v1.InviteMemberRequest, v1.InviteMemberResponse]
+	listInvitations    *connect.Client[v1.ListInvitationsRequest, v1.ListInvitationsResponse]
+	acceptInvitation   *connect.Client[v1.AcceptInvitationRequest, v1.AcceptInvitationResponse]
+	declineInvitation  *connect.Client[v1.DeclineInvitationRequest, v1.DeclineInvitationResponse]
+	listMembers        *connect.Client[v1.ListMembersRequest, v1.ListMembersResponse]
+	updateMember       *connect.Client[v1.UpdateMemberRequest, v1.UpdateMemberResponse]
+	removeMember       *connect.Client[v1.RemoveMemberRequest, v1.RemoveMemberResponse]
+	uploadAttachment   *connect.Client[v1.UploadAttachmentRequest, v1.UploadAttachmentResponse]
+	listAttachments    *connect.Client[v1.ListAttachmentsRequest, v1.ListAttachmentsResponse]
+	downloadAttachment *connect.Client[v1.DownloadAttachmentRequest, v1.DownloadAttachmentResponse]
+	deleteAttachment   *connect.Client[v1.DeleteAttachmentRequest, v1.DeleteAttachmentResponse]
+	createComment      *connect.Client[v1.CreateCommentRequest, v1.CreateCommentResponse]
+	listComments       *connect.Client[v1.ListCommentsRequest, v1.ListCommentsResponse]
+	getComment         *connect.Client[v1.GetCommentRequest, v1.GetCommentResponse]
+	updateComment      *connect.Client[v1.UpdateCommentRequest, v1.UpdateCommentResponse]
+	deleteComment      *connect.Client[v1.DeleteCommentRequest, v1.DeleteCommentResponse]
+	addDependency      *connect.Client[v1.AddDependencyRequest, v1.AddDependencyResponse]
+	removeDependency   *connect.Client[v1.RemoveDependencyRequest, v1.RemoveDependencyResponse]
+	getDependencyGraph *connect.Client[v1.GetDependencyGraphRequest, v1.GetDependencyGraphResponse]
+	listNext           *connect.Client[v1.ListNextRequest, v1.ListNextResponse]
 }
 
 // Create calls todos.v1.TodosService.Create.
@@ -132,13 +567,268 @@ func (c *todosServiceClient) List(ctx context.Context, req *connect.Request[v1.L
 	return c.list.CallUnary(ctx, req)
 }
 
+// Sync calls todos.v1.TodosService.Sync.
+func (c *todosServiceClient) Sync(ctx context.Context, req *connect.Request[v1.SyncRequest]) (*connect.Response[v1.SyncResponse], error) {
+	return c.sync.CallUnary(ctx, req)
+}
+
+// Push calls todos.v1.TodosService.Push.
+func (c *todosServiceClient) Push(ctx context.Context, req *connect.Request[v1.PushRequest]) (*connect.Response[v1.PushResponse], error) {
+	return c.push.CallUnary(ctx, req)
+}
+
+// Move calls todos.v1.TodosService.Move.
+func (c *todosServiceClient) Move(ctx context.Context, req *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error) {
+	return c.move.CallUnary(ctx, req)
+}
+
+// PreviewOccurrences calls todos.v1.TodosService.PreviewOccurrences.
+func (c *todosServiceClient) PreviewOccurrences(ctx context.Context, req *connect.Request[v1.PreviewOccurrencesRequest]) (*connect.Response[v1.PreviewOccurrencesResponse], error) {
+	return c.previewOccurrences.CallUnary(ctx, req)
+}
+
+// Export calls todos.v1.TodosService.Export.
+func (c *todosServiceClient) Export(ctx context.Context, req *connect.Request[v1.ExportRequest]) (*connect.ServerStreamForClient[v1.ExportResponse], error) {
+	return c.export.CallServerStream(ctx, req)
+}
+
+// Import calls todos.v1.TodosService.Import.
+func (c *todosServiceClient) Import(ctx context.Context) *connect.ClientStreamForClient[v1.ImportRequest, v1.ImportResponse] {
+	return c._import.CallClientStream(ctx)
+}
+
+// CreateCalendarFeed calls todos.v1.TodosService.CreateCalendarFeed.
+func (c *todosServiceClient) CreateCalendarFeed(ctx context.Context, req *connect.Request[v1.CreateCalendarFeedRequest]) (*connect.Response[v1.CreateCalendarFeedResponse], error) {
+	return c.createCalendarFeed.CallUnary(ctx, req)
+}
+
+// ListCalendarFeeds calls todos.v1.TodosService.ListCalendarFeeds.
+func (c *todosServiceClient) ListCalendarFeeds(ctx context.Context, req *connect.Request[v1.ListCalendarFeedsRequest]) (*connect.Response[v1.ListCalendarFeedsResponse], error) {
+	return c.listCalendarFeeds.CallUnary(ctx, req)
+}
+
+// RevokeCalendarFeed calls todos.v1.TodosService.RevokeCalendarFeed.
+func (c *todosServiceClient) RevokeCalendarFeed(ctx context.Context, req *connect.Request[v1.RevokeCalendarFeedRequest]) (*connect.Response[v1.RevokeCalendarFeedResponse], error) {
+	return c.revokeCalendarFeed.CallUnary(ctx, req)
+}
+
+// CreateApiKey calls todos.v1.TodosService.CreateApiKey.
+func (c *todosServiceClient) CreateApiKey(ctx context.Context, req *connect.Request[v1.CreateApiKeyRequest]) (*connect.Response[v1.CreateApiKeyResponse], error) {
+	return c.createApiKey.CallUnary(ctx, req)
+}
+
+// ListApiKeys calls todos.v1.TodosService.ListApiKeys.
+func (c *todosServiceClient) ListApiKeys(ctx context.Context, req *connect.Request[v1.ListApiKeysRequest]) (*connect.Response[v1.ListApiKeysResponse], error) {
+	return c.listApiKeys.CallUnary(ctx, req)
+}
+
+// RotateApiKey calls todos.v1.TodosService.RotateApiKey.
+func (c *todosServiceClient) RotateApiKey(ctx context.Context, req *connect.Request[v1.RotateApiKeyRequest]) (*connect.Response[v1.RotateApiKeyResponse], error) {
+	return c.rotateApiKey.CallUnary(ctx, req)
+}
+
+// RevokeApiKey calls todos.v1.TodosService.RevokeApiKey.
+func (c *todosServiceClient) RevokeApiKey(ctx context.Context, req *connect.Request[v1.RevokeApiKeyRequest]) (*connect.Response[v1.RevokeApiKeyResponse], error) {
+	return c.revokeApiKey.CallUnary(ctx, req)
+}
+
+// CreateList calls todos.v1.TodosService.CreateList.
+func (c *todosServiceClient) CreateList(ctx context.Context, req *connect.Request[v1.CreateListRequest]) (*connect.Response[v1.CreateListResponse], error) {
+	return c.createList.CallUnary(ctx, req)
+}
+
+// ListLists calls todos.v1.TodosService.ListLists.
+func (c *todosServiceClient) ListLists(ctx context.Context, req *connect.Request[v1.ListListsRequest]) (*connect.Response[v1.ListListsResponse], error) {
+	return c.listLists.CallUnary(ctx, req)
+}
+
+// InviteMember calls todos.v1.TodosService.InviteMember.
+func (c *todosServiceClient) InviteMember(ctx context.Context, req *connect.Request[v1.InviteMemberRequest]) (*connect.Response[v1.InviteMemberResponse], error) {
+	return c.inviteMember.CallUnary(ctx, req)
+}
+
+// ListInvitations calls todos.v1.TodosService.ListInvitations.
+func (c *todosServiceClient) ListInvitations(ctx context.Context, req *connect.Request[v1.ListInvitationsRequest]) (*connect.Response[v1.ListInvitationsResponse], error) {
+	return c.listInvitations.CallUnary(ctx, req)
+}
+
+// AcceptInvitation calls todos.v1.TodosService.AcceptInvitation.
+func (c *todosServiceClient) AcceptInvitation(ctx context.Context, req *connect.Request[v1.AcceptInvitationRequest]) (*connect.Response[v1.AcceptInvitationResponse], error) {
+	return c.acceptInvitation.CallUnary(ctx, req)
+}
+
+// DeclineInvitation calls todos.v1.TodosService.DeclineInvitation.
+func (c *todosServiceClient) DeclineInvitation(ctx context.Context, req *connect.Request[v1.DeclineInvitationRequest]) (*connect.Response[v1.DeclineInvitationResponse], error) {
+	return c.declineInvitation.CallUnary(ctx, req)
+}
+
+// ListMembers calls todos.v1.TodosService.ListMembers.
+func (c *todosServiceClient) ListMembers(ctx context.Context, req *connect.Request[v1.ListMembersRequest]) (*connect.Response[v1.ListMembersResponse], error) {
+	return c.listMembers.CallUnary(ctx, req)
+}
+
+// UpdateMember calls todos.v1.TodosService.UpdateMember.
+func (c *todosServiceClient) UpdateMember(ctx context.Context, req *connect.Request[v1.UpdateMemberRequest]) (*connect.Response[v1.UpdateMemberResponse], error) {
+	return c.updateMember.CallUnary(ctx, req)
+}
+
+// RemoveMember calls todos.v1.TodosService.RemoveMember.
+func (c *todosServiceClient) RemoveMember(ctx context.Context, req *connect.Request[v1.RemoveMemberRequest]) (*connect.Response[v1.RemoveMemberResponse], error) {
+	return c.removeMember.CallUnary(ctx, req)
+}
+
+// UploadAttachment calls todos.v1.TodosService.UploadAttachment.
+func (c *todosServiceClient) UploadAttachment(ctx context.Context) *connect.ClientStreamForClient[v1.UploadAttachmentRequest, v1.UploadAttachmentResponse] {
+	return c.uploadAttachment.CallClientStream(ctx)
+}
+
+// ListAttachments calls todos.v1.TodosService.ListAttachments.
+func (c *todosServiceClient) ListAttachments(ctx context.Context, req *connect.Request[v1.ListAttachmentsRequest]) (*connect.Response[v1.ListAttachmentsResponse], error) {
+	return c.listAttachments.CallUnary(ctx, req)
+}
+
+// DownloadAttachment calls todos.v1.TodosService.DownloadAttachment.
+func (c *todosServiceClient) DownloadAttachment(ctx context.Context, req *connect.Request[v1.DownloadAttachmentRequest]) (*connect.ServerStreamForClient[v1.DownloadAttachmentResponse], error) {
+	return c.downloadAttachment.CallServerStream(ctx, req)
+}
+
+// DeleteAttachment calls todos.v1.TodosService.DeleteAttachment.
+func (c *todosServiceClient) DeleteAttachment(ctx context.Context, req *connect.Request[v1.DeleteAttachmentRequest]) (*connect.Response[v1.DeleteAttachmentResponse], error) {
+	return c.deleteAttachment.CallUnary(ctx, req)
+}
+
+// CreateComment calls todos.v1.TodosService.CreateComment.
+func (c *todosServiceClient) CreateComment(ctx context.Context, req *connect.Request[v1.CreateCommentRequest]) (*connect.Response[v1.CreateCommentResponse], error) {
+	return c.createComment.CallUnary(ctx, req)
+}
+
+// ListComments calls todos.v1.TodosService.ListComments.
+func (c *todosServiceClient) ListComments(ctx context.Context, req *connect.Request[v1.ListCommentsRequest]) (*connect.Response[v1.ListCommentsResponse], error) {
+	return c.listComments.CallUnary(ctx, req)
+}
+
+// GetComment calls todos.v1.TodosService.GetComment.
+func (c *todosServiceClient) GetComment(ctx context.Context, req *connect.Request[v1.GetCommentRequest]) (*connect.Response[v1.GetCommentResponse], error) {
+	return c.getComment.CallUnary(ctx, req)
+}
+
+// UpdateComment calls todos.v1.TodosService.UpdateComment.
+func (c *todosServiceClient) UpdateComment(ctx context.Context, req *connect.Request[v1.UpdateCommentRequest]) (*connect.Response[v1.UpdateCommentResponse], error) {
+	return c.updateComment.CallUnary(ctx, req)
+}
+
+// DeleteComment calls todos.v1.TodosService.DeleteComment.
+func (c *todosServiceClient) DeleteComment(ctx context.Context, req *connect.Request[v1.DeleteCommentRequest]) (*connect.Response[v1.DeleteCommentResponse], error) {
+	return c.deleteComment.CallUnary(ctx, req)
+}
+
+// AddDependency calls todos.v1.TodosService.AddDependency.
+func (c *todosServiceClient) AddDependency(ctx context.Context, req *connect.Request[v1.AddDependencyRequest]) (*connect.Response[v1.AddDependencyResponse], error) {
+	return c.addDependency.CallUnary(ctx, req)
+}
+
+// RemoveDependency calls todos.v1.TodosService.RemoveDependency.
+func (c *todosServiceClient) RemoveDependency(ctx context.Context, req *connect.Request[v1.RemoveDependencyRequest]) (*connect.Response[v1.RemoveDependencyResponse], error) {
+	return c.removeDependency.CallUnary(ctx, req)
+}
+
+// GetDependencyGraph calls todos.v1.TodosService.GetDependencyGraph.
+func (c *todosServiceClient) GetDependencyGraph(ctx context.Context, req *connect.Request[v1.GetDependencyGraphRequest]) (*connect.Response[v1.GetDependencyGraphResponse], error) {
+	return c.getDependencyGraph.CallUnary(ctx, req)
+}
+
+// ListNext calls todos.v1.TodosService.ListNext.
+func (c *todosServiceClient) ListNext(ctx context.Context, req *connect.Request[v1.ListNextRequest]) (*connect.Response[v1.ListNextResponse], error) {
+	return c.listNext.CallUnary(ctx, req)
+}
+
 // TodosServiceHandler is an implementation of the todos.v1.TodosService service.
 type TodosServiceHandler interface {
 	Create(context.Context, *connect.Request[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error)
+	// Get and List can be sent as HTTP GET and answer conditional requests
+	// with 304 Not Modified.
 	Get(context.Context, *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error)
+	// Update replaces every field of a todo, or only the fields in update_mask.
+	// PATCH requests over REST set update_mask to the fields in the body.
 	Update(context.Context, *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error)
 	Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error)
 	List(context.Context, *connect.Request[v1.ListRequest]) (*connect.Response[v1.ListResponse], error)
+	// Sync returns every todo changed or deleted since the given sync token.
+	Sync(context.Context, *connect.Request[v1.SyncRequest]) (*connect.Response[v1.SyncResponse], error)
+	// Push applies a batch of offline client mutations and reports a result per item.
+	Push(context.Context, *connect.Request[v1.PushRequest]) (*connect.Response[v1.PushResponse], error)
+	// Move places a todo directly before or after a sibling in the manual order.
+	Move(context.Context, *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error)
+	// PreviewOccurrences returns the next dates a recurrence rule produces.
+	PreviewOccurrences(context.Context, *connect.Request[v1.PreviewOccurrencesRequest]) (*connect.Response[v1.PreviewOccurrencesResponse], error)
+	// Export streams all todos encoded in the requested format.
+	Export(context.Context, *connect.Request[v1.ExportRequest], *connect.ServerStream[v1.ExportResponse]) error
+	// Import reads a file streamed in chunks and creates its todos in one transaction.
+	Import(context.Context, *connect.ClientStream[v1.ImportRequest]) (*connect.Response[v1.ImportResponse], error)
+	// CreateCalendarFeed creates a secret token for subscribing to the iCalendar feed.
+	CreateCalendarFeed(context.Context, *connect.Request[v1.CreateCalendarFeedRequest]) (*connect.Response[v1.CreateCalendarFeedResponse], error)
+	ListCalendarFeeds(context.Context, *connect.Request[v1.ListCalendarFeedsRequest]) (*connect.Response[v1.ListCalendarFeedsResponse], error)
+	RevokeCalendarFeed(context.Context, *connect.Request[v1.RevokeCalendarFeedRequest]) (*connect.Response[v1.RevokeCalendarFeedResponse], error)
+	// CreateApiKey creates a key for callers that cannot log in interactively.
+	// The API key methods require the keys:admin scope.
+	CreateApiKey(context.Context, *connect.Request[v1.CreateApiKeyRequest]) (*connect.Response[v1.CreateApiKeyResponse], error)
+	ListApiKeys(context.Context, *connect.Request[v1.ListApiKeysRequest]) (*connect.Response[v1.ListApiKeysResponse], error)
+	// RotateApiKey replaces the secret of a key; the old token stops working at once.
+	RotateApiKey(context.Context, *connect.Request[v1.RotateApiKeyRequest]) (*connect.Response[v1.RotateApiKeyResponse], error)
+	RevokeApiKey(context.Context, *connect.Request[v1.RevokeApiKeyRequest]) (*connect.Response[v1.RevokeApiKeyResponse], error)
+	// CreateList creates a shared list owned by the caller. Todos in a list are
+	// only visible to its members, and only editors and owners may change them.
+	CreateList(context.Context, *connect.Request[v1.CreateListRequest]) (*connect.Response[v1.CreateListResponse], error)
+	// ListLists returns the lists the caller is a member of.
+	ListLists(context.Context, *connect.Request[v1.ListListsRequest]) (*connect.Response[v1.ListListsResponse], error)
+	// InviteMember invites a user, by user ID or email, to a list. Owners only.
+	InviteMember(context.Context, *connect.Request[v1.InviteMemberRequest]) (*connect.Response[v1.InviteMemberResponse], error)
+	// ListInvitations returns the pending invitations addressed to the caller.
+	ListInvitations(context.Context, *connect.Request[v1.ListInvitationsRequest]) (*connect.Response[v1.ListInvitationsResponse], error)
+	// AcceptInvitation makes the caller a member. The next Sync returns the
+	// todos the list already holds.
+	AcceptInvitation(context.Context, *connect.Request[v1.AcceptInvitationRequest]) (*connect.Response[v1.AcceptInvitationResponse], error)
+	DeclineInvitation(context.Context, *connect.Request[v1.DeclineInvitationRequest]) (*connect.Response[v1.DeclineInvitationResponse], error)
+	ListMembers(context.Context, *connect.Request[v1.ListMembersRequest]) (*connect.Response[v1.ListMembersResponse], error)
+	// UpdateMember changes the role of a member. Owners only; a list always
+	// keeps at least one owner.
+	UpdateMember(context.Context, *connect.Request[v1.UpdateMemberRequest]) (*connect.Response[v1.UpdateMemberResponse], error)
+	// RemoveMember removes a member. Owners may remove anyone, members themselves.
+	RemoveMember(context.Context, *connect.Request[v1.RemoveMemberRequest]) (*connect.Response[v1.RemoveMemberResponse], error)
+	// UploadAttachment attaches a file streamed in chunks to a todo. Over
+	// plain HTTP, files are uploaded as multipart/form-data with a "file"
+	// part to POST /v1/todos/{todo_id}/attachments.
+	UploadAttachment(context.Context, *connect.ClientStream[v1.UploadAttachmentRequest]) (*connect.Response[v1.UploadAttachmentResponse], error)
+	ListAttachments(context.Context, *connect.Request[v1.ListAttachmentsRequest]) (*connect.Response[v1.ListAttachmentsResponse], error)
+	// DownloadAttachment streams the metadata of an attachment followed by its
+	// content. Over plain HTTP, GET /v1/attachments/{id}/content returns the
+	// content as is.
+	DownloadAttachment(context.Context, *connect.Request[v1.DownloadAttachmentRequest], *connect.ServerStream[v1.DownloadAttachmentResponse]) error
+	// DeleteAttachment removes an attachment; its content is deleted in the
+	// background.
+	DeleteAttachment(context.Context, *connect.Request[v1.DeleteAttachmentRequest]) (*connect.Response[v1.DeleteAttachmentResponse], error)
+	// CreateComment adds a comment to a todo. "@user" in the body mentions
+	// that user.
+	CreateComment(context.Context, *connect.Request[v1.CreateCommentRequest]) (*connect.Response[v1.CreateCommentResponse], error)
+	// ListComments returns the comments of a todo, oldest first.
+	ListComments(context.Context, *connect.Request[v1.ListCommentsRequest]) (*connect.Response[v1.ListCommentsResponse], error)
+	GetComment(context.Context, *connect.Request[v1.GetCommentRequest]) (*connect.Response[v1.GetCommentResponse], error)
+	// UpdateComment changes the body of a comment. Authors only.
+	UpdateComment(context.Context, *connect.Request[v1.UpdateCommentRequest]) (*connect.Response[v1.UpdateCommentResponse], error)
+	// DeleteComment removes a comment. Its author and the owners of the list
+	// of the todo may delete it.
+	DeleteComment(context.Context, *connect.Request[v1.DeleteCommentRequest]) (*connect.Response[v1.DeleteCommentResponse], error)
+	// AddDependency records that a todo cannot be completed before another
+	// one, its blocker. Both must be in the same list, and a todo cannot end
+	// up blocking itself, directly or through others.
+	AddDependency(context.Context, *connect.Request[v1.AddDependencyRequest]) (*connect.Response[v1.AddDependencyResponse], error)
+	RemoveDependency(context.Context, *connect.Request[v1.RemoveDependencyRequest]) (*connect.Response[v1.RemoveDependencyResponse], error)
+	// GetDependencyGraph returns the todos that block or are blocked by
+	// others, and the dependencies between them.
+	GetDependencyGraph(context.Context, *connect.Request[v1.GetDependencyGraphRequest]) (*connect.Response[v1.GetDependencyGraphResponse], error)
+	// ListNext returns the open todos in an order they can be done in: every
+	// todo comes after its blockers.
+	ListNext(context.Context, *connect.Request[v1.ListNextRequest]) (*connect.Response[v1.ListNextResponse], error)
 }
 
 // NewTodosServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -158,6 +848,7 @@ func NewTodosServiceHandler(svc TodosServiceHandler, opts ...connect.HandlerOpti
 		TodosServiceGetProcedure,
 		svc.Get,
 		connect.WithSchema(todosServiceMethods.ByName("Get")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	todosServiceUpdateHandler := connect.NewUnaryHandler(
@@ -176,6 +867,229 @@ func NewTodosServiceHandler(svc TodosServiceHandler, opts ...connect.HandlerOpti
 		TodosServiceListProcedure,
 		svc.List,
 		connect.WithSchema(todosServiceMethods.ByName("List")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceSyncHandler := connect.NewUnaryHandler(
+		TodosServiceSyncProcedure,
+		svc.Sync,
+		connect.WithSchema(todosServiceMethods.ByName("Sync")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServicePushHandler := connect.NewUnaryHandler(
+		TodosServicePushProcedure,
+		svc.Push,
+		connect.WithSchema(todosServiceMethods.ByName("Push")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceMoveHandler := connect.NewUnaryHandler(
+		TodosServiceMoveProcedure,
+		svc.Move,
+		connect.WithSchema(todosServiceMethods.ByName("Move")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServicePreviewOccurrencesHandler := connect.NewUnaryHandler(
+		TodosServicePreviewOccurrencesProcedure,
+		svc.PreviewOccurrences,
+		connect.WithSchema(todosServiceMethods.ByName("PreviewOccurrences")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceExportHandler := connect.NewServerStreamHandler(
+		TodosServiceExportProcedure,
+		svc.Export,
+		connect.WithSchema(todosServiceMethods.ByName("Export")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceImportHandler := connect.NewClientStreamHandler(
+		TodosServiceImportProcedure,
+		svc.Import,
+		connect.WithSchema(todosServiceMethods.ByName("Import")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceCreateCalendarFeedHandler := connect.NewUnaryHandler(
+		TodosServiceCreateCalendarFeedProcedure,
+		svc.CreateCalendarFeed,
+		connect.WithSchema(todosServiceMethods.ByName("CreateCalendarFeed")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListCalendarFeedsHandler := connect.NewUnaryHandler(
+		TodosServiceListCalendarFeedsProcedure,
+		svc.ListCalendarFeeds,
+		connect.WithSchema(todosServiceMethods.ByName("ListCalendarFeeds")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceRevokeCalendarFeedHandler := connect.NewUnaryHandler(
+		TodosServiceRevokeCalendarFeedProcedure,
+		svc.RevokeCalendarFeed,
+		connect.WithSchema(todosServiceMethods.ByName("RevokeCalendarFeed")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceCreateApiKeyHandler := connect.NewUnaryHandler(
+		TodosServiceCreateApiKeyProcedure,
+		svc.CreateApiKey,
+		connect.WithSchema(todosServiceMethods.ByName("CreateApiKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListApiKeysHandler := connect.NewUnaryHandler(
+		TodosServiceListApiKeysProcedure,
+		svc.ListApiKeys,
+		connect.WithSchema(todosServiceMethods.ByName("ListApiKeys")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceRotateApiKeyHandler := connect.NewUnaryHandler(
+		TodosServiceRotateApiKeyProcedure,
+		svc.RotateApiKey,
+		connect.WithSchema(todosServiceMethods.ByName("RotateApiKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceRevokeApiKeyHandler := connect.NewUnaryHandler(
+		TodosServiceRevokeApiKeyProcedure,
+		svc.RevokeApiKey,
+		connect.WithSchema(todosServiceMethods.ByName("RevokeApiKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceCreateListHandler := connect.NewUnaryHandler(
+		TodosServiceCreateListProcedure,
+		svc.CreateList,
+		connect.WithSchema(todosServiceMethods.ByName("CreateList")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListListsHandler := connect.NewUnaryHandler(
+		TodosServiceListListsProcedure,
+		svc.ListLists,
+		connect.WithSchema(todosServiceMethods.ByName("ListLists")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceInviteMemberHandler := connect.NewUnaryHandler(
+		TodosServiceInviteMemberProcedure,
+		svc.InviteMember,
+		connect.WithSchema(todosServiceMethods.ByName("InviteMember")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListInvitationsHandler := connect.NewUnaryHandler(
+		TodosServiceListInvitationsProcedure,
+		svc.ListInvitations,
+		connect.WithSchema(todosServiceMethods.ByName("ListInvitations")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceAcceptInvitationHandler := connect.NewUnaryHandler(
+		TodosServiceAcceptInvitationProcedure,
+		svc.AcceptInvitation,
+		connect.WithSchema(todosServiceMethods.ByName("AcceptInvitation")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceDeclineInvitationHandler := connect.NewUnaryHandler(
+		TodosServiceDeclineInvitationProcedure,
+		svc.DeclineInvitation,
+		connect.WithSchema(todosServiceMethods.ByName("DeclineInvitation")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListMembersHandler := connect.NewUnaryHandler(
+		TodosServiceListMembersProcedure,
+		svc.ListMembers,
+		connect.WithSchema(todosServiceMethods.ByName("ListMembers")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceUpdateMemberHandler := connect.NewUnaryHandler(
+		TodosServiceUpdateMemberProcedure,
+		svc.UpdateMember,
+		connect.WithSchema(todosServiceMethods.ByName("UpdateMember")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceRemoveMemberHandler := connect.NewUnaryHandler(
+		TodosServiceRemoveMemberProcedure,
+		svc.RemoveMember,
+		connect.WithSchema(todosServiceMethods.ByName("RemoveMember")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceUploadAttachmentHandler := connect.NewClientStreamHandler(
+		TodosServiceUploadAttachmentProcedure,
+		svc.UploadAttachment,
+		connect.WithSchema(todosServiceMethods.ByName("UploadAttachment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListAttachmentsHandler := connect.NewUnaryHandler(
+		TodosServiceListAttachmentsProcedure,
+		svc.ListAttachments,
+		connect.WithSchema(todosServiceMethods.ByName("ListAttachments")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceDownloadAttachmentHandler := connect.NewServerStreamHandler(
+		TodosServiceDownloadAttachmentProcedure,
+		svc.DownloadAttachment,
+		connect.WithSchema(todosServiceMethods.ByName("DownloadAttachment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceDeleteAttachmentHandler := connect.NewUnaryHandler(
+		TodosServiceDeleteAttachmentProcedure,
+		svc.DeleteAttachment,
+		connect.WithSchema(todosServiceMethods.ByName("DeleteAttachment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceCreateCommentHandler := connect.NewUnaryHandler(
+		TodosServiceCreateCommentProcedure,
+		svc.CreateComment,
+		connect.WithSchema(todosServiceMethods.ByName("CreateComment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListCommentsHandler := connect.NewUnaryHandler(
+		TodosServiceListCommentsProcedure,
+		svc.ListComments,
+		connect.WithSchema(todosServiceMethods.ByName("ListComments")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceGetCommentHandler := connect.NewUnaryHandler(
+		TodosServiceGetCommentProcedure,
+		svc.GetComment,
+		connect.WithSchema(todosServiceMethods.ByName("GetComment")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceUpdateCommentHandler := connect.NewUnaryHandler(
+		TodosServiceUpdateCommentProcedure,
+		svc.UpdateComment,
+		connect.WithSchema(todosServiceMethods.ByName("UpdateComment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceDeleteCommentHandler := connect.NewUnaryHandler(
+		TodosServiceDeleteCommentProcedure,
+		svc.DeleteComment,
+		connect.WithSchema(todosServiceMethods.ByName("DeleteComment")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceAddDependencyHandler := connect.NewUnaryHandler(
+		TodosServiceAddDependencyProcedure,
+		svc.AddDependency,
+		connect.WithSchema(todosServiceMethods.ByName("AddDependency")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceRemoveDependencyHandler := connect.NewUnaryHandler(
+		TodosServiceRemoveDependencyProcedure,
+		svc.RemoveDependency,
+		connect.WithSchema(todosServiceMethods.ByName("RemoveDependency")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceGetDependencyGraphHandler := connect.NewUnaryHandler(
+		TodosServiceGetDependencyGraphProcedure,
+		svc.GetDependencyGraph,
+		connect.WithSchema(todosServiceMethods.ByName("GetDependencyGraph")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	todosServiceListNextHandler := connect.NewUnaryHandler(
+		TodosServiceListNextProcedure,
+		svc.ListNext,
+		connect.WithSchema(todosServiceMethods.ByName("ListNext")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	return "/todos.v1.TodosService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -190,6 +1104,76 @@ func NewTodosServiceHandler(svc TodosServiceHandler, opts ...connect.HandlerOpti
 			todosServiceDeleteHandler.ServeHTTP(w, r)
 		case TodosServiceListProcedure:
 			todosServiceListHandler.ServeHTTP(w, r)
+		case TodosServiceSyncProcedure:
+			todosServiceSyncHandler.ServeHTTP(w, r)
+		case TodosServicePushProcedure:
+			todosServicePushHandler.ServeHTTP(w, r)
+		case TodosServiceMoveProcedure:
+			todosServiceMoveHandler.ServeHTTP(w, r)
+		case TodosServicePreviewOccurrencesProcedure:
+			todosServicePreviewOccurrencesHandler.ServeHTTP(w, r)
+		case TodosServiceExportProcedure:
+			todosServiceExportHandler.ServeHTTP(w, r)
+		case TodosServiceImportProcedure:
+			todosServiceImportHandler.ServeHTTP(w, r)
+		case TodosServiceCreateCalendarFeedProcedure:
+			todosServiceCreateCalendarFeedHandler.ServeHTTP(w, r)
+		case TodosServiceListCalendarFeedsProcedure:
+			todosServiceListCalendarFeedsHandler.ServeHTTP(w, r)
+		case TodosServiceRevokeCalendarFeedProcedure:
+			todosServiceRevokeCalendarFeedHandler.ServeHTTP(w, r)
+		case TodosServiceCreateApiKeyProcedure:
+			todosServiceCreateApiKeyHandler.ServeHTTP(w, r)
+		case TodosServiceListApiKeysProcedure:
+			todosServiceListApiKeysHandler.ServeHTTP(w, r)
+		case TodosServiceRotateApiKeyProcedure:
+			todosServiceRotateApiKeyHandler.ServeHTTP(w, r)
+		case TodosServiceRevokeApiKeyProcedure:
+			todosServiceRevokeApiKeyHandler.ServeHTTP(w, r)
+		case TodosServiceCreateListProcedure:
+			todosServiceCreateListHandler.ServeHTTP(w, r)
+		case TodosServiceListListsProcedure:
+			todosServiceListListsHandler.ServeHTTP(w, r)
+		case TodosServiceInviteMemberProcedure:
+			todosServiceInviteMemberHandler.ServeHTTP(w, r)
+		case TodosServiceListInvitationsProcedure:
+			todosServiceListInvitationsHandler.ServeHTTP(w, r)
+		case TodosServiceAcceptInvitationProcedure:
+			todosServiceAcceptInvitationHandler.ServeHTTP(w, r)
+		case TodosServiceDeclineInvitationProcedure:
+			todosServiceDeclineInvitationHandler.ServeHTTP(w, r)
+		case TodosServiceListMembersProcedure:
+			todosServiceListMembersHandler.ServeHTTP(w, r)
+		case TodosServiceUpdateMemberProcedure:
+			todosServiceUpdateMemberHandler.ServeHTTP(w, r)
+		case TodosServiceRemoveMemberProcedure:
+			todosServiceRemoveMemberHandler.ServeHTTP(w, r)
+		case TodosServiceUploadAttachmentProcedure:
+			todosServiceUploadAttachmentHandler.ServeHTTP(w, r)
+		case TodosServiceListAttachmentsProcedure:
+			todosServiceListAttachmentsHandler.ServeHTTP(w, r)
+		case TodosServiceDownloadAttachmentProcedure:
+			todosServiceDownloadAttachmentHandler.ServeHTTP(w, r)
+		case TodosServiceDeleteAttachmentProcedure:
+			todosServiceDeleteAttachmentHandler.ServeHTTP(w, r)
+		case TodosServiceCreateCommentProcedure:
+			todosServiceCreateCommentHandler.ServeHTTP(w, r)
+		case TodosServiceListCommentsProcedure:
+			todosServiceListCommentsHandler.ServeHTTP(w, r)
+		case TodosServiceGetCommentProcedure:
+			todosServiceGetCommentHandler.ServeHTTP(w, r)
+		case TodosServiceUpdateCommentProcedure:
+			todosServiceUpdateCommentHandler.ServeHTTP(w, r)
+		case TodosServiceDeleteCommentProcedure:
+			todosServiceDeleteCommentHandler.ServeHTTP(w, r)
+		case TodosServiceAddDependencyProcedure:
+			todosServiceAddDependencyHandler.ServeHTTP(w, r)
+		case TodosServiceRemoveDependencyProcedure:
+			todosServiceRemoveDependencyHandler.ServeHTTP(w, r)
+		case TodosServiceGetDependencyGraphProcedure:
+			todosServiceGetDependencyGraphHandler.ServeHTTP(w, r)
+		case TodosServiceListNextProcedure:
+			todosServiceListNextHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -218,3 +1202,143 @@ func (UnimplementedTodosServiceHandler) Delete(context.Context, *connect.Request
 func (UnimplementedTodosServiceHandler) List(context.Context, *connect.Request[v1.ListRequest]) (*connect.Response[v1.ListResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.List is not implemented"))
 }
+
+func (UnimplementedTodosServiceHandler) Sync(context.Context, *connect.Request[v1.SyncRequest]) (*connect.Response[v1.SyncResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.Sync is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Push(context.Context, *connect.Request[v1.PushRequest]) (*connect.Response[v1.PushResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.Push is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Move(context.Context, *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.Move is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) PreviewOccurrences(context.Context, *connect.Request[v1.PreviewOccurrencesRequest]) (*connect.Response[v1.PreviewOccurrencesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.PreviewOccurrences is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Export(context.Context, *connect.Request[v1.ExportRequest], *connect.ServerStream[v1.ExportResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.Export is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) Import(context.Context, *connect.ClientStream[v1.ImportRequest]) (*connect.Response[v1.ImportResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.Import is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) CreateCalendarFeed(context.Context, *connect.Request[v1.CreateCalendarFeedRequest]) (*connect.Response[v1.CreateCalendarFeedResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.CreateCalendarFeed is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListCalendarFeeds(context.Context, *connect.Request[v1.ListCalendarFeedsRequest]) (*connect.Response[v1.ListCalendarFeedsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListCalendarFeeds is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) RevokeCalendarFeed(context.Context, *connect.Request[v1.RevokeCalendarFeedRequest]) (*connect.Response[v1.RevokeCalendarFeedResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.RevokeCalendarFeed is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) CreateApiKey(context.Context, *connect.Request[v1.CreateApiKeyRequest]) (*connect.Response[v1.CreateApiKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.CreateApiKey is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListApiKeys(context.Context, *connect.Request[v1.ListApiKeysRequest]) (*connect.Response[v1.ListApiKeysResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListApiKeys is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) RotateApiKey(context.Context, *connect.Request[v1.RotateApiKeyRequest]) (*connect.Response[v1.RotateApiKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.RotateApiKey is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) RevokeApiKey(context.Context, *connect.Request[v1.RevokeApiKeyRequest]) (*connect.Response[v1.RevokeApiKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.RevokeApiKey is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) CreateList(context.Context, *connect.Request[v1.CreateListRequest]) (*connect.Response[v1.CreateListResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.CreateList is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListLists(context.Context, *connect.Request[v1.ListListsRequest]) (*connect.Response[v1.ListListsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListLists is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) InviteMember(context.Context, *connect.Request[v1.InviteMemberRequest]) (*connect.Response[v1.InviteMemberResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.InviteMember is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListInvitations(context.Context, *connect.Request[v1.ListInvitationsRequest]) (*connect.Response[v1.ListInvitationsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListInvitations is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) AcceptInvitation(context.Context, *connect.Request[v1.AcceptInvitationRequest]) (*connect.Response[v1.AcceptInvitationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.AcceptInvitation is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) DeclineInvitation(context.Context, *connect.Request[v1.DeclineInvitationRequest]) (*connect.Response[v1.DeclineInvitationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.DeclineInvitation is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListMembers(context.Context, *connect.Request[v1.ListMembersRequest]) (*connect.Response[v1.ListMembersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListMembers is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) UpdateMember(context.Context, *connect.Request[v1.UpdateMemberRequest]) (*connect.Response[v1.UpdateMemberResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.UpdateMember is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) RemoveMember(context.Context, *connect.Request[v1.RemoveMemberRequest]) (*connect.Response[v1.RemoveMemberResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.RemoveMember is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) UploadAttachment(context.Context, *connect.ClientStream[v1.UploadAttachmentRequest]) (*connect.Response[v1.UploadAttachmentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.UploadAttachment is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListAttachments(context.Context, *connect.Request[v1.ListAttachmentsRequest]) (*connect.Response[v1.ListAttachmentsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListAttachments is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) DownloadAttachment(context.Context, *connect.Request[v1.DownloadAttachmentRequest], *connect.ServerStream[v1.DownloadAttachmentResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.DownloadAttachment is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) DeleteAttachment(context.Context, *connect.Request[v1.DeleteAttachmentRequest]) (*connect.Response[v1.DeleteAttachmentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.DeleteAttachment is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) CreateComment(context.Context, *connect.Request[v1.CreateCommentRequest]) (*connect.Response[v1.CreateCommentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.CreateComment is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListComments(context.Context, *connect.Request[v1.ListCommentsRequest]) (*connect.Response[v1.ListCommentsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListComments is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) GetComment(context.Context, *connect.Request[v1.GetCommentRequest]) (*connect.Response[v1.GetCommentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.GetComment is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) UpdateComment(context.Context, *connect.Request[v1.UpdateCommentRequest]) (*connect.Response[v1.UpdateCommentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.UpdateComment is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) DeleteComment(context.Context, *connect.Request[v1.DeleteCommentRequest]) (*connect.Response[v1.DeleteCommentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.DeleteComment is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) AddDependency(context.Context, *connect.Request[v1.AddDependencyRequest]) (*connect.Response[v1.AddDependencyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.AddDependency is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) RemoveDependency(context.Context, *connect.Request[v1.RemoveDependencyRequest]) (*connect.Response[v1.RemoveDependencyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.RemoveDependency is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) GetDependencyGraph(context.Context, *connect.Request[v1.GetDependencyGraphRequest]) (*connect.Response[v1.GetDependencyGraphResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.GetDependencyGraph is not implemented"))
+}
+
+func (UnimplementedTodosServiceHandler) ListNext(context.Context, *connect.Request[v1.ListNextRequest]) (*connect.Response[v1.ListNextResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.TodosService.ListNext is not implemented"))
+}
@@ -0,0 +1,241 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: protos/todos/v1/webhooks.proto
+
+package todosv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// WebhooksServiceName is the fully-qualified name of the WebhooksService service.
+	WebhooksServiceName = "todos.v1.WebhooksService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// WebhooksServiceCreateWebhookProcedure is the fully-qualified name of the WebhooksService's
+	// CreateWebhook RPC.
+	WebhooksServiceCreateWebhookProcedure = "/todos.v1.WebhooksService/CreateWebhook"
+	// WebhooksServiceListWebhooksProcedure is the fully-qualified name of the WebhooksService's
+	// ListWebhooks RPC.
+	WebhooksServiceListWebhooksProcedure = "/todos.v1.WebhooksService/ListWebhooks"
+	// WebhooksServiceDeleteWebhookProcedure is the fully-qualified name of the WebhooksService's
+	// DeleteWebhook RPC.
+	WebhooksServiceDeleteWebhookProcedure = "/todos.v1.WebhooksService/DeleteWebhook"
+	// WebhooksServiceListDeliveriesProcedure is the fully-qualified name of the WebhooksService's
+	// ListDeliveries RPC.
+	WebhooksServiceListDeliveriesProcedure = "/todos.v1.WebhooksService/ListDeliveries"
+	// WebhooksServiceRedeliverDeliveryProcedure is the fully-qualified name of the WebhooksService's
+	// RedeliverDelivery RPC.
+	WebhooksServiceRedeliverDeliveryProcedure = "/todos.v1.WebhooksService/RedeliverDelivery"
+)
+
+// WebhooksServiceClient is a client for the todos.v1.WebhooksService service.
+type WebhooksServiceClient interface {
+	// CreateWebhook subscribes a URL to todo events. Every delivery is signed
+	// with the webhook's secret, see the Todos-Signature header.
+	CreateWebhook(context.Context, *connect.Request[v1.CreateWebhookRequest]) (*connect.Response[v1.CreateWebhookResponse], error)
+	ListWebhooks(context.Context, *connect.Request[v1.ListWebhooksRequest]) (*connect.Response[v1.ListWebhooksResponse], error)
+	// DeleteWebhook removes a webhook together with its delivery log.
+	DeleteWebhook(context.Context, *connect.Request[v1.DeleteWebhookRequest]) (*connect.Response[v1.DeleteWebhookResponse], error)
+	// ListDeliveries returns the most recent deliveries of a webhook.
+	ListDeliveries(context.Context, *connect.Request[v1.ListDeliveriesRequest]) (*connect.Response[v1.ListDeliveriesResponse], error)
+	// RedeliverDelivery sends a delivery again with a fresh set of attempts,
+	// whatever its status.
+	RedeliverDelivery(context.Context, *connect.Request[v1.RedeliverDeliveryRequest]) (*connect.Response[v1.RedeliverDeliveryResponse], error)
+}
+
+// NewWebhooksServiceClient constructs a client for the todos.v1.WebhooksService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewWebhooksServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) WebhooksServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	webhooksServiceMethods := v1.File_protos_todos_v1_webhooks_proto.Services().ByName("WebhooksService").Methods()
+	return &webhooksServiceClient{
+		createWebhook: connect.NewClient[v1.CreateWebhookRequest, v1.CreateWebhookResponse](
+			httpClient,
+			baseURL+WebhooksServiceCreateWebhookProcedure,
+			connect.WithSchema(webhooksServiceMethods.ByName("CreateWebhook")),
+			connect.WithClientOptions(opts...),
+		),
+		listWebhooks: connect.NewClient[v1.ListWebhooksRequest, v1.ListWebhooksResponse](
+			httpClient,
+			baseURL+WebhooksServiceListWebhooksProcedure,
+			connect.WithSchema(webhooksServiceMethods.ByName("ListWebhooks")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		deleteWebhook: connect.NewClient[v1.DeleteWebhookRequest, v1.DeleteWebhookResponse](
+			httpClient,
+			baseURL+WebhooksServiceDeleteWebhookProcedure,
+			connect.WithSchema(webhooksServiceMethods.ByName("DeleteWebhook")),
+			connect.WithClientOptions(opts...),
+		),
+		listDeliveries: connect.NewClient[v1.ListDeliveriesRequest, v1.ListDeliveriesResponse](
+			httpClient,
+			baseURL+WebhooksServiceListDeliveriesProcedure,
+			connect.WithSchema(webhooksServiceMethods.ByName("ListDeliveries")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		redeliverDelivery: connect.NewClient[v1.RedeliverDeliveryRequest, v1.RedeliverDeliveryResponse](
+			httpClient,
+			baseURL+WebhooksServiceRedeliverDeliveryProcedure,
+			connect.WithSchema(webhooksServiceMethods.ByName("RedeliverDelivery")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// webhooksServiceClient implements WebhooksServiceClient.
+type webhooksServiceClient struct {
+	createWebhook     *connect.Client[v1.CreateWebhookRequest, v1.CreateWebhookResponse]
+	listWebhooks      *connect.Client[v1.ListWebhooksRequest, v1.ListWebhooksResponse]
+	deleteWebhook     *connect.Client[v1.DeleteWebhookRequest, v1.DeleteWebhookResponse]
+	listDeliveries    *connect.Client[v1.ListDeliveriesRequest, v1.ListDeliveriesResponse]
+	redeliverDelivery *connect.Client[v1.RedeliverDeliveryRequest, v1.RedeliverDeliveryResponse]
+}
+
+// CreateWebhook calls todos.v1.WebhooksService.CreateWebhook.
+func (c *webhooksServiceClient) CreateWebhook(ctx context.Context, req *connect.Request[v1.CreateWebhookRequest]) (*connect.Response[v1.CreateWebhookResponse], error) {
+	return c.createWebhook.CallUnary(ctx, req)
+}
+
+// ListWebhooks calls todos.v1.WebhooksService.ListWebhooks.
+func (c *webhooksServiceClient) ListWebhooks(ctx context.Context, req *connect.Request[v1.ListWebhooksRequest]) (*connect.Response[v1.ListWebhooksResponse], error) {
+	return c.listWebhooks.CallUnary(ctx, req)
+}
+
+// DeleteWebhook calls todos.v1.WebhooksService.DeleteWebhook.
+func (c *webhooksServiceClient) DeleteWebhook(ctx context.Context, req *connect.Request[v1.DeleteWebhookRequest]) (*connect.Response[v1.DeleteWebhookResponse], error) {
+	return c.deleteWebhook.CallUnary(ctx, req)
+}
+
+// ListDeliveries calls todos.v1.WebhooksService.ListDeliveries.
+func (c *webhooksServiceClient) ListDeliveries(ctx context.Context, req *connect.Request[v1.ListDeliveriesRequest]) (*connect.Response[v1.ListDeliveriesResponse], error) {
+	return c.listDeliveries.CallUnary(ctx, req)
+}
+
+// RedeliverDelivery calls todos.v1.WebhooksService.RedeliverDelivery.
+func (c *webhooksServiceClient) RedeliverDelivery(ctx context.Context, req *connect.Request[v1.RedeliverDeliveryRequest]) (*connect.Response[v1.RedeliverDeliveryResponse], error) {
+	return c.redeliverDelivery.CallUnary(ctx, req)
+}
+
+// WebhooksServiceHandler is an implementation of the todos.v1.WebhooksService service.
+type WebhooksServiceHandler interface {
+	// CreateWebhook subscribes a URL to todo events. Every delivery is signed
+	// with the webhook's secret, see the Todos-Signature header.
+	CreateWebhook(context.Context, *connect.Request[v1.CreateWebhookRequest]) (*connect.Response[v1.CreateWebhookResponse], error)
+	ListWebhooks(context.Context, *connect.Request[v1.ListWebhooksRequest]) (*connect.Response[v1.ListWebhooksResponse], error)
+	// DeleteWebhook removes a webhook together with its delivery log.
+	DeleteWebhook(context.Context, *connect.Request[v1.DeleteWebhookRequest]) (*connect.Response[v1.DeleteWebhookResponse], error)
+	// ListDeliveries returns the most recent deliveries of a webhook.
+	ListDeliveries(context.Context, *connect.Request[v1.ListDeliveriesRequest]) (*connect.Response[v1.ListDeliveriesResponse], error)
+	// RedeliverDelivery sends a delivery again with a fresh set of attempts,
+	// whatever its status.
+	RedeliverDelivery(context.Context, *connect.Request[v1.RedeliverDeliveryRequest]) (*connect.Response[v1.RedeliverDeliveryResponse], error)
+}
+
+// NewWebhooksServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewWebhooksServiceHandler(svc WebhooksServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	webhooksServiceMethods := v1.File_protos_todos_v1_webhooks_proto.Services().ByName("WebhooksService").Methods()
+	webhooksServiceCreateWebhookHandler := connect.NewUnaryHandler(
+		WebhooksServiceCreateWebhookProcedure,
+		svc.CreateWebhook,
+		connect.WithSchema(webhooksServiceMethods.ByName("CreateWebhook")),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhooksServiceListWebhooksHandler := connect.NewUnaryHandler(
+		WebhooksServiceListWebhooksProcedure,
+		svc.ListWebhooks,
+		connect.WithSchema(webhooksServiceMethods.ByName("ListWebhooks")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhooksServiceDeleteWebhookHandler := connect.NewUnaryHandler(
+		WebhooksServiceDeleteWebhookProcedure,
+		svc.DeleteWebhook,
+		connect.WithSchema(webhooksServiceMethods.ByName("DeleteWebhook")),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhooksServiceListDeliveriesHandler := connect.NewUnaryHandler(
+		WebhooksServiceListDeliveriesProcedure,
+		svc.ListDeliveries,
+		connect.WithSchema(webhooksServiceMethods.ByName("ListDeliveries")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhooksServiceRedeliverDeliveryHandler := connect.NewUnaryHandler(
+		WebhooksServiceRedeliverDeliveryProcedure,
+		svc.RedeliverDelivery,
+		connect.WithSchema(webhooksServiceMethods.ByName("RedeliverDelivery")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/todos.v1.WebhooksService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case WebhooksServiceCreateWebhookProcedure:
+			webhooksServiceCreateWebhookHandler.ServeHTTP(w, r)
+		case WebhooksServiceListWebhooksProcedure:
+			webhooksServiceListWebhooksHandler.ServeHTTP(w, r)
+		case WebhooksServiceDeleteWebhookProcedure:
+			webhooksServiceDeleteWebhookHandler.ServeHTTP(w, r)
+		case WebhooksServiceListDeliveriesProcedure:
+			webhooksServiceListDeliveriesHandler.ServeHTTP(w, r)
+		case WebhooksServiceRedeliverDeliveryProcedure:
+			webhooksServiceRedeliverDeliveryHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedWebhooksServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedWebhooksServiceHandler struct{}
+
+func (UnimplementedWebhooksServiceHandler) CreateWebhook(context.Context, *connect.Request[v1.CreateWebhookRequest]) (*connect.Response[v1.CreateWebhookResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.WebhooksService.CreateWebhook is not implemented"))
+}
+
+func (UnimplementedWebhooksServiceHandler) ListWebhooks(context.Context, *connect.Request[v1.ListWebhooksRequest]) (*connect.Response[v1.ListWebhooksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.WebhooksService.ListWebhooks is not implemented"))
+}
+
+func (UnimplementedWebhooksServiceHandler) DeleteWebhook(context.Context, *connect.Request[v1.DeleteWebhookRequest]) (*connect.Response[v1.DeleteWebhookResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.WebhooksService.DeleteWebhook is not implemented"))
+}
+
+func (UnimplementedWebhooksServiceHandler) ListDeliveries(context.Context, *connect.Request[v1.ListDeliveriesRequest]) (*connect.Response[v1.ListDeliveriesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.WebhooksService.ListDeliveries is not implemented"))
+}
+
+func (UnimplementedWebhooksServiceHandler) RedeliverDelivery(context.Context, *connect.Request[v1.RedeliverDeliveryRequest]) (*connect.Response[v1.RedeliverDeliveryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todos.v1.WebhooksService.RedeliverDelivery is not implemented"))
+}
@@ -0,0 +1,465 @@
+// Package gateway serves the REST mappings declared with google.api.http
+// annotations by transcoding each request into a Connect unary JSON call and
+// handing it to the Connect handler, so interceptors and validation apply to
+// REST callers just the same.
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// maxBodySize bounds REST request bodies.
+const maxBodySize = 4 << 20
+
+// Handler transcodes REST requests for one service.
+type Handler struct {
+	routes []*route
+	target http.Handler
+	errors *connect.ErrorWriter
+}
+
+// NewHandler builds the routes of every annotated unary method of service.
+// target must serve the Connect protocol for that service.
+func NewHandler(service protoreflect.ServiceDescriptor, target http.Handler) (*Handler, error) {
+	h := &Handler{target: target, errors: connect.NewErrorWriter()}
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			return nil, fmt.Errorf("gateway: %s: streaming methods cannot be mapped", method.FullName())
+		}
+
+		procedure := "/" + string(service.FullName()) + "/" + string(method.Name())
+		for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			rt, err := newRoute(r, procedure, method.Input())
+			if err != nil {
+				return nil, fmt.Errorf("gateway: %s: %w", method.FullName(), err)
+			}
+			rt.cacheable = rt.method == http.MethodGet && idempotency(method) == descriptorpb.MethodOptions_NO_SIDE_EFFECTS
+			h.routes = append(h.routes, rt)
+		}
+	}
+
+	// "/v1/todos/{id}" would also capture "/v1/todos/x:move"
+	sort.SliceStable(h.routes, func(i, j int) bool {
+		return h.routes[i].verb != "" && h.routes[j].verb == ""
+	})
+	return h, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pathMatched := false
+	for _, rt := range h.routes {
+		vars, ok := rt.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method == r.Method {
+			h.serve(w, r, rt, vars)
+			return
+		}
+	}
+
+	if pathMatched {
+		w.Header().Set("Allow", strings.Join(h.allowed(r.URL.Path), ", "))
+		h.writeError(w, r, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("method %s not allowed", r.Method)), http.StatusMethodNotAllowed)
+		return
+	}
+	h.writeError(w, r, connect.NewError(connect.CodeNotFound, errors.New("no route for "+r.URL.Path)), 0)
+}
+
+func (h *Handler) allowed(path string) []string {
+	var methods []string
+	for _, rt := range h.routes {
+		if _, ok := rt.match(path); ok {
+			methods = append(methods, rt.method)
+		}
+	}
+	return methods
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, rt *route, vars map[string]string) {
+	msg := dynamicpb.NewMessage(rt.input)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		h.writeError(w, r, connect.NewError(connect.CodeInvalidArgument, err), 0)
+		return
+	}
+	if err := rt.decode(msg, r, body, vars); err != nil {
+		h.writeError(w, r, connect.NewError(connect.CodeInvalidArgument, err), 0)
+		return
+	}
+
+	payload, err := protojson.Marshal(msg)
+	if err != nil {
+		h.writeError(w, r, err, 0)
+		return
+	}
+
+	forward := r.Clone(r.Context())
+	forward.Header.Del("Content-Encoding")
+	forward.Header.Del("Content-Length")
+	if rt.cacheable {
+		// a Connect GET keeps conditional request headers meaningful
+		query := url.Values{"connect": {"v1"}, "encoding": {"json"}, "message": {string(payload)}}
+		forward.URL = &url.URL{Path: rt.procedure, RawQuery: query.Encode()}
+		forward.Body = http.NoBody
+		forward.ContentLength = 0
+		forward.Header.Del("Content-Type")
+	} else {
+		forward.Method = http.MethodPost
+		forward.URL = &url.URL{Path: rt.procedure}
+		forward.Body = io.NopCloser(bytes.NewReader(payload))
+		forward.ContentLength = int64(len(payload))
+		forward.Header.Set("Content-Type", "application/json")
+		forward.Header.Set("Connect-Protocol-Version", "1")
+	}
+	forward.RequestURI = forward.URL.RequestURI()
+
+	h.target.ServeHTTP(w, forward)
+}
+
+// writeError writes err as Connect JSON, with status overriding the status
+// code derived from the error when it is set.
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error, status int) {
+	if status != 0 {
+		w = statusWriter{ResponseWriter: w, status: status}
+	}
+	_ = h.errors.Write(w, r, err)
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w statusWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// route is one HTTP binding of a method.
+type route struct {
+	method    string
+	segments  []segment
+	verb      string
+	body      string
+	procedure string
+	input     protoreflect.MessageDescriptor
+	// cacheable routes are forwarded as Connect GET requests.
+	cacheable bool
+}
+
+func idempotency(method protoreflect.MethodDescriptor) descriptorpb.MethodOptions_IdempotencyLevel {
+	opts, _ := method.Options().(*descriptorpb.MethodOptions)
+	return opts.GetIdempotencyLevel()
+}
+
+// segment is a literal path segment or, if field is set, a variable bound
+// to that field path.
+type segment struct {
+	literal string
+	field   string
+}
+
+func newRoute(rule *annotations.HttpRule, procedure string, input protoreflect.MessageDescriptor) (*route, error) {
+	rt := &route{body: rule.GetBody(), procedure: procedure, input: input}
+
+	var template string
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		rt.method, template = http.MethodGet, p.Get
+	case *annotations.HttpRule_Put:
+		rt.method, template = http.MethodPut, p.Put
+	case *annotations.HttpRule_Post:
+		rt.method, template = http.MethodPost, p.Post
+	case *annotations.HttpRule_Delete:
+		rt.method, template = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		rt.method, template = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Custom:
+		rt.method, template = p.Custom.GetKind(), p.Custom.GetPath()
+	default:
+		return nil, errors.New("missing HTTP pattern")
+	}
+	if rule.GetResponseBody() != "" {
+		return nil, errors.New("response_body is not supported")
+	}
+	if rt.body != "" && rt.body != "*" {
+		if _, err := fieldPath(input, rt.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if !strings.HasPrefix(template, "/") {
+		return nil, fmt.Errorf("template %q must start with /", template)
+	}
+	template = template[1:]
+	if i := strings.LastIndex(template, ":"); i > strings.LastIndex(template, "/") && i > strings.LastIndex(template, "}") {
+		template, rt.verb = template[:i], template[i+1:]
+	}
+
+	for _, part := range strings.Split(template, "/") {
+		if !strings.HasPrefix(part, "{") {
+			if part == "" || strings.ContainsAny(part, "*{}") {
+				return nil, fmt.Errorf("unsupported path segment %q", part)
+			}
+			rt.segments = append(rt.segments, segment{literal: part})
+			continue
+		}
+
+		field, pattern, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(part, "{"), "}"), "=")
+		if pattern != "" && pattern != "*" {
+			return nil, fmt.Errorf("unsupported variable pattern %q", part)
+		}
+		if _, err := fieldPath(input, field); err != nil {
+			return nil, err
+		}
+		rt.segments = append(rt.segments, segment{field: field})
+	}
+	return rt, nil
+}
+
+// match returns the path variables if path matches the route.
+func (rt *route) match(path string) (map[string]string, bool) {
+	path = strings.TrimPrefix(path, "/")
+	if rt.verb != "" {
+		var ok bool
+		if path, ok = strings.CutSuffix(path, ":"+rt.verb); !ok {
+			return nil, false
+		}
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != len(rt.segments) {
+		return nil, false
+	}
+
+	vars := map[string]string{}
+	for i, seg := range rt.segments {
+		switch {
+		case seg.field == "":
+			if parts[i] != seg.literal {
+				return nil, false
+			}
+		case parts[i] == "":
+			return nil, false
+		default:
+			vars[seg.field] = parts[i]
+		}
+	}
+	return vars, true
+}
+
+// decode fills msg from the body, the path variables and, for fields not
+// bound otherwise, the query string.
+func (rt *route) decode(msg *dynamicpb.Message, r *http.Request, body []byte, vars map[string]string) error {
+	unmarshal := protojson.UnmarshalOptions{}
+
+	switch rt.body {
+	case "":
+		if len(bytes.TrimSpace(body)) > 0 {
+			return errors.New("request body is not allowed")
+		}
+	case "*":
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := unmarshal.Unmarshal(body, msg); err != nil {
+				return fmt.Errorf("invalid body: %w", err)
+			}
+		}
+	default:
+		fields, _ := fieldPath(rt.input, rt.body)
+		target := mutableMessage(msg, fields)
+		if err := unmarshal.Unmarshal(body, target.Interface()); err != nil {
+			return fmt.Errorf("invalid body: %w", err)
+		}
+	}
+
+	for name, value := range vars {
+		if err := setField(msg, name, []string{value}); err != nil {
+			return err
+		}
+	}
+
+	if rt.body != "*" {
+		for name, values := range r.URL.Query() {
+			if _, bound := vars[name]; bound {
+				continue
+			}
+			if err := setField(msg, name, values); err != nil {
+				return err
+			}
+		}
+	}
+
+	if rt.method == http.MethodPatch && rt.body == "*" {
+		return setUpdateMask(msg, body, vars)
+	}
+	return nil
+}
+
+// setUpdateMask fills an unset update_mask field with the top-level fields
+// present in a PATCH body, so that only those fields are changed.
+func setUpdateMask(msg *dynamicpb.Message, body []byte, vars map[string]string) error {
+	maskField := msg.Descriptor().Fields().ByName("update_mask")
+	if maskField == nil || maskField.Message() == nil ||
+		maskField.Message().FullName() != "google.protobuf.FieldMask" || msg.Has(maskField) {
+		return nil
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+
+	mask := &fieldmaskpb.FieldMask{}
+	for key := range keys {
+		fd := lookupField(msg.Descriptor(), key)
+		if fd == nil || fd == maskField {
+			continue
+		}
+		if _, bound := vars[string(fd.Name())]; bound {
+			continue
+		}
+		mask.Paths = append(mask.Paths, string(fd.Name()))
+	}
+	sort.Strings(mask.Paths)
+
+	data, err := proto.Marshal(mask)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, msg.Mutable(maskField).Message().Interface())
+}
+
+// lookupField finds a field by proto or JSON name.
+func lookupField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return md.Fields().ByJSONName(name)
+}
+
+// fieldPath resolves a dotted field path such as "todo.id".
+func fieldPath(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	var fields []protoreflect.FieldDescriptor
+	for i, name := range strings.Split(path, ".") {
+		if md == nil {
+			return nil, fmt.Errorf("field %q is not a message", strings.Join(strings.Split(path, ".")[:i], "."))
+		}
+		fd := lookupField(md, name)
+		if fd == nil {
+			return nil, fmt.Errorf("unknown field %q", path)
+		}
+		fields = append(fields, fd)
+		md = fd.Message()
+	}
+	return fields, nil
+}
+
+func mutableMessage(msg protoreflect.Message, fields []protoreflect.FieldDescriptor) protoreflect.Message {
+	for _, fd := range fields {
+		msg = msg.Mutable(fd).Message()
+	}
+	return msg
+}
+
+// setField parses values into the field at path. Repeated fields take every
+// value, singular fields exactly one.
+func setField(msg protoreflect.Message, path string, values []string) error {
+	fields, err := fieldPath(msg.Descriptor(), path)
+	if err != nil {
+		return err
+	}
+	leaf := fields[len(fields)-1]
+	parent := mutableMessage(msg, fields[:len(fields)-1])
+
+	if leaf.IsMap() {
+		return fmt.Errorf("field %q cannot be set from the URL", path)
+	}
+	if leaf.IsList() {
+		list := parent.Mutable(leaf).List()
+		for _, v := range values {
+			value, err := parseValue(leaf, v)
+			if err != nil {
+				return fmt.Errorf("field %q: %w", path, err)
+			}
+			list.Append(value)
+		}
+		return nil
+	}
+
+	if len(values) != 1 {
+		return fmt.Errorf("field %q takes a single value", path)
+	}
+	value, err := parseValue(leaf, values[0])
+	if err != nil {
+		return fmt.Errorf("field %q: %w", path, err)
+	}
+	parent.Set(leaf, value)
+	return nil
+}
+
+// parseValue parses a URL value the way protojson parses a JSON string.
+func parseValue(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid value %q", s)
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid value %q", s)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	}
+
+	// protojson accepts numbers, bytes and well-known types such as
+	// FieldMask as JSON strings, so decode a one-field document
+	value, _ := json.Marshal(s)
+	if fd.IsList() {
+		value = append(append([]byte("["), value...), ']')
+	}
+	doc := append([]byte(`{"`+fd.JSONName()+`":`), append(value, '}')...)
+
+	holder := dynamicpb.NewMessage(fd.ContainingMessage())
+	if err := protojson.Unmarshal(doc, holder); err != nil {
+		return protoreflect.Value{}, fmt.Errorf("invalid value %q", s)
+	}
+	if fd.IsList() {
+		return holder.Get(fd).List().Get(0), nil
+	}
+	return holder.Get(fd), nil
+}
@@ -0,0 +1,236 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/validate"
+	"github.com/haakaashs/todos-backend/gen/openapi"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	gen "github.com/haakaashs/todos-backend/gen/protos/todos/v1/todosv1connect"
+)
+
+const todoID = "6f1c1d5e-8a4b-4a8e-9f0e-3b2a1c0d9e8f"
+
+// fakeTodos records the last request it received.
+type fakeTodos struct {
+	gen.UnimplementedTodosServiceHandler
+	last any
+}
+
+func (f *fakeTodos) Get(_ context.Context, req *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error) {
+	f.last = req.Msg
+	return connect.NewResponse(&v1.GetResponse{Todo: &v1.Todo{Id: req.Msg.Id, Title: "Water plants"}}), nil
+}
+
+func (f *fakeTodos) List(_ context.Context, req *connect.Request[v1.ListRequest]) (*connect.Response[v1.ListResponse], error) {
+	f.last = req.Msg
+	return connect.NewResponse(&v1.ListResponse{}), nil
+}
+
+func (f *fakeTodos) Update(_ context.Context, req *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error) {
+	f.last = req.Msg
+	return connect.NewResponse(&v1.UpdateResponse{Todo: &v1.Todo{Id: req.Msg.Id}}), nil
+}
+
+func (f *fakeTodos) Move(_ context.Context, req *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error) {
+	f.last = req.Msg
+	return connect.NewResponse(&v1.MoveResponse{Todo: &v1.Todo{Id: req.Msg.Id}}), nil
+}
+
+func newTestServer(t *testing.T) (*fakeTodos, *httptest.Server) {
+	t.Helper()
+	fake := &fakeTodos{}
+	_, target := gen.NewTodosServiceHandler(fake, connect.WithInterceptors(validate.NewInterceptor()))
+
+	h, err := NewHandler(v1.File_protos_todos_v1_todos_proto.Services().ByName("TodosService"), target)
+	if err != nil {
+		t.Fatalf("NewHandler failed: %v", err)
+	}
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func do(t *testing.T, method, url, body string) (*http.Response, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var doc map[string]any
+	json.NewDecoder(res.Body).Decode(&doc)
+	return res, doc
+}
+
+func TestGetWithPathVariable(t *testing.T) {
+	fake, server := newTestServer(t)
+
+	res, doc := do(t, http.MethodGet, server.URL+"/v1/todos/"+todoID, "")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", res.StatusCode, doc)
+	}
+	if got := fake.last.(*v1.GetRequest).Id; got != todoID {
+		t.Errorf("Expected id %s, got %s", todoID, got)
+	}
+	if todo, _ := doc["todo"].(map[string]any); todo["title"] != "Water plants" {
+		t.Errorf("Expected the todo in the response, got %v", doc)
+	}
+}
+
+func TestListWithQueryParameter(t *testing.T) {
+	fake, server := newTestServer(t)
+
+	res, _ := do(t, http.MethodGet, server.URL+"/v1/todos?order=LIST_ORDER_PRIORITY", "")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", res.StatusCode)
+	}
+	if got := fake.last.(*v1.ListRequest).Order; got != v1.ListOrder_LIST_ORDER_PRIORITY {
+		t.Errorf("Expected LIST_ORDER_PRIORITY, got %v", got)
+	}
+
+	res, doc := do(t, http.MethodGet, server.URL+"/v1/todos?colour=red", "")
+	if res.StatusCode != http.StatusBadRequest || doc["code"] != "invalid_argument" {
+		t.Errorf("Expected invalid_argument for an unknown parameter, got %d %v", res.StatusCode, doc)
+	}
+}
+
+func TestPatchSetsUpdateMask(t *testing.T) {
+	fake, server := newTestServer(t)
+
+	res, doc := do(t, http.MethodPatch, server.URL+"/v1/todos/"+todoID, `{"completed": true, "dueAt": "2024-05-01T09:00:00Z"}`)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", res.StatusCode, doc)
+	}
+	req := fake.last.(*v1.UpdateRequest)
+	if req.Id != todoID || !req.Completed {
+		t.Errorf("Expected the id and completed flag to be set, got %v", req)
+	}
+	if got := strings.Join(req.GetUpdateMask().GetPaths(), ","); got != "completed,due_at" {
+		t.Errorf("Expected update mask completed,due_at, got %s", got)
+	}
+}
+
+func TestPutReplacesAndIsValidated(t *testing.T) {
+	_, server := newTestServer(t)
+
+	res, doc := do(t, http.MethodPut, server.URL+"/v1/todos/"+todoID, `{"completed": true}`)
+	if res.StatusCode != http.StatusBadRequest || doc["code"] != "invalid_argument" {
+		t.Errorf("Expected a missing title to be rejected, got %d %v", res.StatusCode, doc)
+	}
+
+	res, doc = do(t, http.MethodPut, server.URL+"/v1/todos/"+todoID, `{"title": "Water plants"}`)
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %v", res.StatusCode, doc)
+	}
+}
+
+func TestCustomVerb(t *testing.T) {
+	fake, server := newTestServer(t)
+
+	res, doc := do(t, http.MethodPost, server.URL+"/v1/todos/"+todoID+":move", `{"afterId": "`+todoID[:35]+`0"}`)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", res.StatusCode, doc)
+	}
+	if req := fake.last.(*v1.MoveRequest); req.Id != todoID || req.GetAfterId() == "" {
+		t.Errorf("Expected a move after a sibling, got %v", req)
+	}
+}
+
+func TestUnknownRoutes(t *testing.T) {
+	_, server := newTestServer(t)
+
+	res, _ := do(t, http.MethodGet, server.URL+"/v1/projects", "")
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", res.StatusCode)
+	}
+
+	res, _ = do(t, http.MethodPost, server.URL+"/v1/todos/"+todoID, "{}")
+	if res.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", res.StatusCode)
+	}
+	if allow := res.Header.Get("Allow"); !strings.Contains(allow, "PATCH") {
+		t.Errorf("Expected PATCH to be allowed, got %q", allow)
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	h, err := OpenAPIHandler(openapi.Spec)
+	if err != nil {
+		t.Fatalf("OpenAPIHandler failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected JSON, got %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("Expected an OpenAPI 3 document, got %q", doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/v1/todos/{id}"]["patch"]; !ok {
+		t.Errorf("Expected PATCH /v1/todos/{id} in the document")
+	}
+}
+
+// cachingTodos answers conditional Gets like the real handler does.
+type cachingTodos struct {
+	fakeTodos
+	method string
+}
+
+func (f *cachingTodos) Get(ctx context.Context, req *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error) {
+	f.method = req.HTTPMethod()
+	if req.Header().Get("If-None-Match") == `W/"7"` {
+		return nil, connect.NewNotModifiedError(http.Header{"Etag": {`W/"7"`}})
+	}
+	res, err := f.fakeTodos.Get(ctx, req)
+	if err == nil {
+		res.Header().Set("ETag", `W/"7"`)
+	}
+	return res, err
+}
+
+func TestCacheableRoutesUseConnectGet(t *testing.T) {
+	fake := &cachingTodos{}
+	_, target := gen.NewTodosServiceHandler(fake)
+	h, err := NewHandler(v1.File_protos_todos_v1_todos_proto.Services().ByName("TodosService"), target)
+	if err != nil {
+		t.Fatalf("NewHandler failed: %v", err)
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	res, _ := do(t, http.MethodGet, server.URL+"/v1/todos/"+todoID, "")
+	if res.StatusCode != http.StatusOK || fake.method != http.MethodGet {
+		t.Fatalf("Expected a Connect GET, got %d via %s", res.StatusCode, fake.method)
+	}
+	etag := res.Header.Get("ETag")
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/todos/"+todoID, nil)
+	req.Header.Set("If-None-Match", etag)
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected 304, got %d", res.StatusCode)
+	}
+}
@@ -0,0 +1,28 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIHandler serves a YAML OpenAPI document as JSON. The document is
+// converted once, so a broken spec fails at startup.
+func OpenAPIHandler(spec []byte) (http.Handler, error) {
+	var doc any
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("gateway: invalid OpenAPI document: %w", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("gateway: invalid OpenAPI document: %w", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(data)
+	}), nil
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	gen "github.com/haakaashs/todos-backend/gen/protos/todos/v1/todosv1connect"
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// ProcedureScopes is the scope an API key needs for each procedure.
+var ProcedureScopes = map[string]string{
+	gen.TodosServiceGetProcedure:                auth.ScopeTodosRead,
+	gen.TodosServiceListProcedure:               auth.ScopeTodosRead,
+	gen.TodosServiceSyncProcedure:               auth.ScopeTodosRead,
+	gen.TodosServicePreviewOccurrencesProcedure: auth.ScopeTodosRead,
+	gen.TodosServiceExportProcedure:             auth.ScopeTodosRead,
+	gen.TodosServiceListCalendarFeedsProcedure:  auth.ScopeTodosRead,
+	gen.TodosServiceListListsProcedure:          auth.ScopeTodosRead,
+	gen.TodosServiceListInvitationsProcedure:    auth.ScopeTodosRead,
+	gen.TodosServiceListMembersProcedure:        auth.ScopeTodosRead,
+	gen.TodosServiceListAttachmentsProcedure:    auth.ScopeTodosRead,
+	gen.TodosServiceDownloadAttachmentProcedure: auth.ScopeTodosRead,
+	gen.TodosServiceListCommentsProcedure:       auth.ScopeTodosRead,
+	gen.TodosServiceGetCommentProcedure:         auth.ScopeTodosRead,
+	gen.TodosServiceGetDependencyGraphProcedure: auth.ScopeTodosRead,
+	gen.TodosServiceListNextProcedure:           auth.ScopeTodosRead,
+	gen.WebhooksServiceListWebhooksProcedure:    auth.ScopeTodosRead,
+	gen.WebhooksServiceListDeliveriesProcedure:  auth.ScopeTodosRead,
+
+	gen.TodosServiceCreateProcedure:               auth.ScopeTodosWrite,
+	gen.TodosServiceUpdateProcedure:               auth.ScopeTodosWrite,
+	gen.TodosServiceDeleteProcedure:               auth.ScopeTodosWrite,
+	gen.TodosServicePushProcedure:                 auth.ScopeTodosWrite,
+	gen.TodosServiceMoveProcedure:                 auth.ScopeTodosWrite,
+	gen.TodosServiceImportProcedure:               auth.ScopeTodosWrite,
+	gen.TodosServiceCreateCalendarFeedProcedure:   auth.ScopeTodosWrite,
+	gen.TodosServiceRevokeCalendarFeedProcedure:   auth.ScopeTodosWrite,
+	gen.TodosServiceCreateListProcedure:           auth.ScopeTodosWrite,
+	gen.TodosServiceInviteMemberProcedure:         auth.ScopeTodosWrite,
+	gen.TodosServiceAcceptInvitationProcedure:     auth.ScopeTodosWrite,
+	gen.TodosServiceDeclineInvitationProcedure:    auth.ScopeTodosWrite,
+	gen.TodosServiceUpdateMemberProcedure:         auth.ScopeTodosWrite,
+	gen.TodosServiceRemoveMemberProcedure:         auth.ScopeTodosWrite,
+	gen.TodosServiceUploadAttachmentProcedure:     auth.ScopeTodosWrite,
+	gen.TodosServiceDeleteAttachmentProcedure:     auth.ScopeTodosWrite,
+	gen.TodosServiceCreateCommentProcedure:        auth.ScopeTodosWrite,
+	gen.TodosServiceUpdateCommentProcedure:        auth.ScopeTodosWrite,
+	gen.TodosServiceDeleteCommentProcedure:        auth.ScopeTodosWrite,
+	gen.TodosServiceAddDependencyProcedure:        auth.ScopeTodosWrite,
+	gen.TodosServiceRemoveDependencyProcedure:     auth.ScopeTodosWrite,
+	gen.WebhooksServiceCreateWebhookProcedure:     auth.ScopeTodosWrite,
+	gen.WebhooksServiceDeleteWebhookProcedure:     auth.ScopeTodosWrite,
+	gen.WebhooksServiceRedeliverDeliveryProcedure: auth.ScopeTodosWrite,
+
+	gen.TodosServiceCreateApiKeyProcedure: auth.ScopeKeysAdmin,
+	gen.TodosServiceListApiKeysProcedure:  auth.ScopeKeysAdmin,
+	gen.TodosServiceRotateApiKeyProcedure: auth.ScopeKeysAdmin,
+	gen.TodosServiceRevokeApiKeyProcedure: auth.ScopeKeysAdmin,
+}
+
+// CreateApiKey implements the CreateApiKey method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) CreateApiKey(ctx context.Context, req *connect.Request[v1.CreateApiKeyRequest]) (*connect.Response[v1.CreateApiKeyResponse], error) {
+	log.Default().Println("CreateApiKey method called")
+
+	domainModel := &model.APIKey{}
+	err := helper.TransformStruct(req.Msg, domainModel)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	created, err := h.service.CreateAPIKey(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.CreateApiKeyResponse{}
+	err = helper.TransformStruct(created, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully created API key")
+	return connect.NewResponse(res), nil
+}
+
+// ListApiKeys implements the ListApiKeys method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListApiKeys(ctx context.Context, req *connect.Request[v1.ListApiKeysRequest]) (*connect.Response[v1.ListApiKeysResponse], error) {
+	log.Default().Println("ListApiKeys method called")
+
+	keys, err := h.service.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resKeys []*v1.ApiKey
+	err = helper.TransformStruct(keys, &resKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed API keys")
+	return connect.NewResponse(&v1.ListApiKeysResponse{ApiKeys: resKeys}), nil
+}
+
+// RotateApiKey implements the RotateApiKey method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) RotateApiKey(ctx context.Context, req *connect.Request[v1.RotateApiKeyRequest]) (*connect.Response[v1.RotateApiKeyResponse], error) {
+	log.Default().Println("RotateApiKey method called")
+
+	rotated, err := h.service.RotateAPIKey(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.RotateApiKeyResponse{}
+	err = helper.TransformStruct(rotated, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully rotated API key")
+	return connect.NewResponse(res), nil
+}
+
+// RevokeApiKey implements the RevokeApiKey method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) RevokeApiKey(ctx context.Context, req *connect.Request[v1.RevokeApiKeyRequest]) (*connect.Response[v1.RevokeApiKeyResponse], error) {
+	log.Default().Println("RevokeApiKey method called")
+
+	err := h.service.RevokeAPIKey(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully revoked API key")
+	return connect.NewResponse(&v1.RevokeApiKeyResponse{}), nil
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"testing"
+
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestProcedureScopesCoverEveryMethod(t *testing.T) {
+	services := []protoreflect.ServiceDescriptor{
+		v1.File_protos_todos_v1_todos_proto.Services().ByName("TodosService"),
+		v1.File_protos_todos_v1_webhooks_proto.Services().ByName("WebhooksService"),
+	}
+	count := 0
+	for _, service := range services {
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			procedure := "/" + string(service.FullName()) + "/" + string(methods.Get(i).Name())
+			if _, ok := ProcedureScopes[procedure]; !ok {
+				t.Errorf("Expected a scope for %s", procedure)
+			}
+		}
+		count += methods.Len()
+	}
+	if len(ProcedureScopes) != count {
+		t.Errorf("Expected %d scopes, got %d", count, len(ProcedureScopes))
+	}
+}
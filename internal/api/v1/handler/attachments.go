@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Patterns under which AttachmentHandler serves uploads and downloads over
+// plain HTTP.
+const (
+	AttachmentUploadPattern  = "POST /v1/todos/{todo_id}/attachments"
+	AttachmentContentPattern = "GET /v1/attachments/{id}/content"
+)
+
+// downloadChunkSize is the size of each DownloadAttachmentResponse message.
+const downloadChunkSize = 64 * 1024
+
+// uploadReader reads the chunks of an UploadAttachment stream.
+type uploadReader struct {
+	stream *connect.ClientStream[v1.UploadAttachmentRequest]
+	chunk  []byte
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		if !r.stream.Receive() {
+			if err := r.stream.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		r.chunk = r.stream.Msg().Chunk
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+// UploadAttachment implements the UploadAttachment method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) UploadAttachment(ctx context.Context, stream *connect.ClientStream[v1.UploadAttachmentRequest]) (*connect.Response[v1.UploadAttachmentResponse], error) {
+	log.Default().Println("UploadAttachment method called")
+
+	if !stream.Receive() {
+		if err := stream.Err(); err != nil {
+			return nil, err
+		}
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("no attachment was sent"))
+	}
+	first := stream.Msg()
+	if first.TodoId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("todo_id is required"))
+	}
+
+	domainModel := &model.Attachment{TodoId: first.TodoId, Filename: first.Filename, ContentType: first.ContentType}
+	created, err := h.service.UploadAttachment(ctx, domainModel, &uploadReader{stream: stream, chunk: first.Chunk})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Attachment{}
+	err = helper.TransformStruct(created, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully uploaded attachment")
+	return connect.NewResponse(&v1.UploadAttachmentResponse{Attachment: res}), nil
+}
+
+// ListAttachments implements the ListAttachments method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListAttachments(ctx context.Context, req *connect.Request[v1.ListAttachmentsRequest]) (*connect.Response[v1.ListAttachmentsResponse], error) {
+	log.Default().Println("ListAttachments method called")
+
+	attachments, err := h.service.ListAttachments(ctx, req.Msg.TodoId)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resAttachments []*v1.Attachment
+	err = helper.TransformStruct(attachments, &resAttachments)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed attachments")
+	return connect.NewResponse(&v1.ListAttachmentsResponse{Attachments: resAttachments}), nil
+}
+
+// DownloadAttachment implements the DownloadAttachment method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) DownloadAttachment(ctx context.Context, req *connect.Request[v1.DownloadAttachmentRequest], stream *connect.ServerStream[v1.DownloadAttachmentResponse]) error {
+	log.Default().Println("DownloadAttachment method called")
+
+	attachment, content, err := h.service.OpenAttachment(ctx, req.Msg.Id)
+	if err != nil {
+		return toConnectError(err)
+	}
+	defer content.Close()
+
+	res := &v1.Attachment{}
+	err = helper.TransformStruct(attachment, res)
+	if err != nil {
+		return err
+	}
+
+	msg := &v1.DownloadAttachmentResponse{Attachment: res}
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if n > 0 || msg.Attachment != nil {
+			msg.Chunk = buf[:n]
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+			msg = &v1.DownloadAttachmentResponse{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Default().Println("Successfully downloaded attachment")
+	return nil
+}
+
+// DeleteAttachment implements the DeleteAttachment method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) DeleteAttachment(ctx context.Context, req *connect.Request[v1.DeleteAttachmentRequest]) (*connect.Response[v1.DeleteAttachmentResponse], error) {
+	log.Default().Println("DeleteAttachment method called")
+
+	err := h.service.DeleteAttachment(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully deleted attachment")
+	return connect.NewResponse(&v1.DeleteAttachmentResponse{}), nil
+}
+
+// AttachmentHandler uploads attachments as multipart/form-data and serves
+// their content as is, for clients that cannot stream Connect messages such
+// as browsers. It must run behind the same authentication as the
+// TodosService.
+type AttachmentHandler struct {
+	service *service.Service
+	errors  *connect.ErrorWriter
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler.
+func NewAttachmentHandler(service *service.Service) *AttachmentHandler {
+	return &AttachmentHandler{service: service, errors: connect.NewErrorWriter()}
+}
+
+// Upload stores the "file" part of a multipart/form-data request as an
+// attachment of the todo in the path, and responds with an
+// UploadAttachmentResponse as JSON.
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	todoID := r.PathValue("todo_id")
+	if _, err := uuid.Parse(todoID); err != nil {
+		h.errors.Write(w, r, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid todo_id %q", todoID)))
+		return
+	}
+	parts, err := r.MultipartReader()
+	if err != nil {
+		h.errors.Write(w, r, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			h.errors.Write(w, r, connect.NewError(connect.CodeInvalidArgument, errors.New(`the request has no "file" part`)))
+			return
+		}
+		if err != nil {
+			h.errors.Write(w, r, connect.NewError(connect.CodeInvalidArgument, err))
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		domainModel := &model.Attachment{TodoId: todoID, Filename: part.FileName(), ContentType: part.Header.Get("Content-Type")}
+		created, err := h.service.UploadAttachment(r.Context(), domainModel, part)
+		if err != nil {
+			h.errors.Write(w, r, toConnectError(err))
+			return
+		}
+		res := &v1.Attachment{}
+		if err := helper.TransformStruct(created, res); err != nil {
+			h.errors.Write(w, r, err)
+			return
+		}
+		body, err := protojson.Marshal(&v1.UploadAttachmentResponse{Attachment: res})
+		if err != nil {
+			h.errors.Write(w, r, err)
+			return
+		}
+
+		log.Default().Println("Successfully uploaded attachment")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+		return
+	}
+}
+
+// Content serves the content of the attachment in the path as a download.
+// Its digest doubles as a strong ETag.
+func (h *AttachmentHandler) Content(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.errors.Write(w, r, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid id %q", id)))
+		return
+	}
+	attachment, content, err := h.service.OpenAttachment(r.Context(), id)
+	if err != nil {
+		h.errors.Write(w, r, toConnectError(err))
+		return
+	}
+	defer content.Close()
+
+	header := w.Header()
+	etag := `"` + attachment.Sha256 + `"`
+	header.Set("ETag", etag)
+	// content never changes, only the private flag keeps shared caches out
+	header.Set("Cache-Control", "private, max-age=31536000, immutable")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	// uploaded HTML must not run scripts in the origin of the API
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", "sandbox")
+	if _, err := io.Copy(w, content); err != nil {
+		log.Default().Println("attachments: failed to send content:", err)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// validators are the HTTP cache validators of a response. Versions only
+// grow, so a weak ETag built from one changes with every write.
+type validators struct {
+	etag     string
+	modified time.Time
+}
+
+func newValidators(modified time.Time, tag ...any) validators {
+	parts := make([]string, len(tag))
+	for i, p := range tag {
+		parts[i] = fmt.Sprint(p)
+	}
+	return validators{etag: `W/"` + strings.Join(parts, "-") + `"`, modified: modified}
+}
+
+// queryTag identifies a search query in an ETag, which cannot hold the
+// query itself since it may contain quotes.
+func queryTag(query string) string {
+	if query == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:8])
+}
+
+// set writes the validators to header. Clients may keep the response but
+// must revalidate it before reuse.
+func (v validators) set(header http.Header) {
+	header.Set("ETag", v.etag)
+	if !v.modified.IsZero() {
+		header.Set("Last-Modified", v.modified.UTC().Format(http.TimeFormat))
+	}
+	header.Set("Cache-Control", "private, no-cache")
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when there is
+// no If-None-Match, as RFC 9110 prescribes for GET.
+func (v validators) notModified(header http.Header) bool {
+	if inm := header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, v.etag)
+	}
+	if ims := header.Get("If-Modified-Since"); ims != "" && !v.modified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !v.modified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// checkNotModified returns a Connect "not modified" error, which is sent as
+// 304, when a GET request is conditional on validators that still match.
+func (v validators) checkNotModified(method string, header http.Header) error {
+	if method != http.MethodGet || !v.notModified(header) {
+		return nil
+	}
+	headers := http.Header{}
+	v.set(headers)
+	return connect.NewNotModifiedError(headers)
+}
+
+func todoValidators(t model.Todo) validators {
+	var modified time.Time
+	if t.UpdatedAt != nil {
+		modified = *t.UpdatedAt
+	}
+	// completing or reopening a blocker does not change the version of the
+	// todos it blocks
+	if t.Blocked {
+		return newValidators(modified, t.Version, "blocked")
+	}
+	return newValidators(modified, t.Version)
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 prescribes for it.
+func etagMatches(ifNoneMatch, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestValidatorsNotModified(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 9, 0, 0, 500, time.UTC)
+	v := newValidators(modified, 42, 1)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{"unconditional", http.Header{}, false},
+		{"matching etag", http.Header{"If-None-Match": {`W/"42-1"`}}, true},
+		{"strong form of etag", http.Header{"If-None-Match": {`"41-1", "42-1"`}}, true},
+		{"stale etag", http.Header{"If-None-Match": {`W/"41-1"`}}, false},
+		{"wildcard", http.Header{"If-None-Match": {"*"}}, true},
+		{"not modified since", http.Header{"If-Modified-Since": {modified.Format(http.TimeFormat)}}, true},
+		{"modified since", http.Header{"If-Modified-Since": {modified.Add(-time.Minute).Format(http.TimeFormat)}}, false},
+		{"etag wins over date", http.Header{
+			"If-None-Match":     {`W/"41-1"`},
+			"If-Modified-Since": {modified.Format(http.TimeFormat)},
+		}, false},
+	}
+	for _, tt := range tests {
+		if got := v.notModified(tt.header); got != tt.want {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCheckNotModifiedOnlyForGet(t *testing.T) {
+	v := newValidators(time.Time{}, 7)
+	header := http.Header{"If-None-Match": {`W/"7"`}}
+
+	if err := v.checkNotModified(http.MethodPost, header); err != nil {
+		t.Errorf("Expected POST requests to be served, got %v", err)
+	}
+
+	err := v.checkNotModified(http.MethodGet, header)
+	if !connect.IsNotModifiedError(err) {
+		t.Fatalf("Expected a not modified error, got %v", err)
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Meta().Get("ETag") != `W/"7"` {
+		t.Errorf("Expected the ETag on the 304 response")
+	}
+}
+
+func TestTodoValidatorsChangeWhenBlocked(t *testing.T) {
+	open := todoValidators(model.Todo{Version: 7})
+	blocked := todoValidators(model.Todo{Version: 7, Blocked: true})
+	if open.etag == blocked.etag {
+		t.Errorf("Expected blocking to change the ETag %s", open.etag)
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/haakaashs/todos-backend/internal/transfer"
+)
+
+// CalendarFeedPattern is the mux pattern under which CalendarFeedHandler
+// serves feeds, e.g. /v1/calendar/<token>.ics.
+const CalendarFeedPattern = "GET /v1/calendar/{file}"
+
+// CreateCalendarFeed implements the CreateCalendarFeed method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) CreateCalendarFeed(ctx context.Context, req *connect.Request[v1.CreateCalendarFeedRequest]) (*connect.Response[v1.CreateCalendarFeedResponse], error) {
+	log.Default().Println("CreateCalendarFeed method called")
+
+	created, err := h.service.CreateCalendarFeed(ctx, req.Msg.Name)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.CreateCalendarFeedResponse{}
+	err = helper.TransformStruct(created, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully created calendar feed")
+	return connect.NewResponse(res), nil
+}
+
+// ListCalendarFeeds implements the ListCalendarFeeds method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListCalendarFeeds(ctx context.Context, req *connect.Request[v1.ListCalendarFeedsRequest]) (*connect.Response[v1.ListCalendarFeedsResponse], error) {
+	log.Default().Println("ListCalendarFeeds method called")
+
+	feeds, err := h.service.ListCalendarFeeds(ctx)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resFeeds []*v1.CalendarFeed
+	err = helper.TransformStruct(feeds, &resFeeds)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed calendar feeds")
+	return connect.NewResponse(&v1.ListCalendarFeedsResponse{Feeds: resFeeds}), nil
+}
+
+// RevokeCalendarFeed implements the RevokeCalendarFeed method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) RevokeCalendarFeed(ctx context.Context, req *connect.Request[v1.RevokeCalendarFeedRequest]) (*connect.Response[v1.RevokeCalendarFeedResponse], error) {
+	log.Default().Println("RevokeCalendarFeed method called")
+
+	err := h.service.RevokeCalendarFeed(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully revoked calendar feed")
+	return connect.NewResponse(&v1.RevokeCalendarFeedResponse{}), nil
+}
+
+// CalendarFeedHandler serves the todos as an iCalendar file to calendar
+// clients, which authenticate with the secret token in the URL.
+type CalendarFeedHandler struct {
+	service *service.Service
+}
+
+// NewCalendarFeedHandler creates a new CalendarFeedHandler.
+func NewCalendarFeedHandler(service *service.Service) *CalendarFeedHandler {
+	return &CalendarFeedHandler{service: service}
+}
+
+func (h *CalendarFeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := strings.TrimSuffix(r.PathValue("file"), ".ics")
+
+	feed, err := h.service.CalendarFeed(ctx, token)
+	if err != nil {
+		// unknown and revoked tokens look the same to the caller
+		if errors.Is(err, service.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Default().Println("calendar: failed to look up feed:", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	component := transfer.ComponentTodo
+	if r.URL.Query().Get("component") == "event" {
+		component = transfer.ComponentEvent
+	}
+
+	// the version and the lists of the feed's owner decide the validators
+	watermark, err := h.service.Watermark(service.FeedContext(ctx, feed))
+	if err != nil {
+		log.Default().Println("calendar: failed to read version:", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/calendar; charset=utf-8")
+	if watermark.Version > 0 {
+		v := newValidators(watermark.UpdatedAt, watermark.Version, int(component), feed.Id, watermark.Scope)
+		v.set(header)
+		if v.notModified(r.Header) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := h.service.WriteCalendar(ctx, bw, feed, component); err != nil {
+		log.Default().Println("calendar: failed to write feed:", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if err := bw.Flush(); err != nil {
+		log.Default().Println("calendar: failed to send feed:", err)
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// CreateComment implements the CreateComment method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) CreateComment(ctx context.Context, req *connect.Request[v1.CreateCommentRequest]) (*connect.Response[v1.CreateCommentResponse], error) {
+	log.Default().Println("CreateComment method called")
+
+	domainModel := &model.Comment{TodoId: req.Msg.TodoId, Body: req.Msg.Body}
+	created, err := h.service.CreateComment(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Comment{}
+	err = helper.TransformStruct(created, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully created comment")
+	return connect.NewResponse(&v1.CreateCommentResponse{Comment: res}), nil
+}
+
+// ListComments implements the ListComments method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListComments(ctx context.Context, req *connect.Request[v1.ListCommentsRequest]) (*connect.Response[v1.ListCommentsResponse], error) {
+	log.Default().Println("ListComments method called")
+
+	opts := model.CommentOptions{TodoId: req.Msg.TodoId, PageSize: req.Msg.PageSize, PageToken: req.Msg.PageToken}
+	page, err := h.service.ListComments(ctx, opts)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.ListCommentsResponse{}
+	err = helper.TransformStruct(page, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed comments")
+	return connect.NewResponse(res), nil
+}
+
+// GetComment implements the GetComment method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) GetComment(ctx context.Context, req *connect.Request[v1.GetCommentRequest]) (*connect.Response[v1.GetCommentResponse], error) {
+	log.Default().Println("GetComment method called")
+
+	comment, err := h.service.GetComment(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Comment{}
+	err = helper.TransformStruct(comment, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&v1.GetCommentResponse{Comment: res}), nil
+}
+
+// UpdateComment implements the UpdateComment method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) UpdateComment(ctx context.Context, req *connect.Request[v1.UpdateCommentRequest]) (*connect.Response[v1.UpdateCommentResponse], error) {
+	log.Default().Println("UpdateComment method called")
+
+	domainModel := &model.Comment{Id: req.Msg.Id, Body: req.Msg.Body}
+	updated, err := h.service.UpdateComment(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Comment{}
+	err = helper.TransformStruct(updated, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully updated comment")
+	return connect.NewResponse(&v1.UpdateCommentResponse{Comment: res}), nil
+}
+
+// DeleteComment implements the DeleteComment method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) DeleteComment(ctx context.Context, req *connect.Request[v1.DeleteCommentRequest]) (*connect.Response[v1.DeleteCommentResponse], error) {
+	log.Default().Println("DeleteComment method called")
+
+	err := h.service.DeleteComment(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully deleted comment")
+	return connect.NewResponse(&v1.DeleteCommentResponse{}), nil
+}
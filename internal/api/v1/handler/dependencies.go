@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// AddDependency implements the AddDependency method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) AddDependency(ctx context.Context, req *connect.Request[v1.AddDependencyRequest]) (*connect.Response[v1.AddDependencyResponse], error) {
+	log.Default().Println("AddDependency method called")
+
+	domainModel := &model.Dependency{TodoId: req.Msg.TodoId, BlockedById: req.Msg.BlockedById}
+	created, err := h.service.AddDependency(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Dependency{}
+	err = helper.TransformStruct(created, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully added dependency")
+	return connect.NewResponse(&v1.AddDependencyResponse{Dependency: res}), nil
+}
+
+// RemoveDependency implements the RemoveDependency method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) RemoveDependency(ctx context.Context, req *connect.Request[v1.RemoveDependencyRequest]) (*connect.Response[v1.RemoveDependencyResponse], error) {
+	log.Default().Println("RemoveDependency method called")
+
+	err := h.service.RemoveDependency(ctx, req.Msg.TodoId, req.Msg.BlockedById)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully removed dependency")
+	return connect.NewResponse(&v1.RemoveDependencyResponse{}), nil
+}
+
+// GetDependencyGraph implements the GetDependencyGraph method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) GetDependencyGraph(ctx context.Context, req *connect.Request[v1.GetDependencyGraphRequest]) (*connect.Response[v1.GetDependencyGraphResponse], error) {
+	log.Default().Println("GetDependencyGraph method called")
+
+	graph, err := h.service.GetDependencyGraph(ctx, req.Msg.ListId, req.Msg.TodoId)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.GetDependencyGraphResponse{}
+	err = helper.TransformStruct(graph, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully fetched dependency graph")
+	return connect.NewResponse(res), nil
+}
+
+// ListNext implements the ListNext method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListNext(ctx context.Context, req *connect.Request[v1.ListNextRequest]) (*connect.Response[v1.ListNextResponse], error) {
+	log.Default().Println("ListNext method called")
+
+	todos, err := h.service.ListNext(ctx, req.Msg.ListId, req.Msg.ReadyOnly)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resTodos []*v1.Todo
+	err = helper.TransformStruct(todos, &resTodos)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed next todo items")
+	return connect.NewResponse(&v1.ListNextResponse{Todos: resTodos}), nil
+}
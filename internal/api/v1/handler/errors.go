@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// toConnectError maps service errors onto Connect status codes so clients get
+// something better than CodeUnknown.
+func toConnectError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, service.ErrAlreadyExists):
+		return connect.NewError(connect.CodeAlreadyExists, err)
+	case errors.Is(err, service.ErrVersionMismatch):
+		return connect.NewError(connect.CodeAborted, err)
+	case errors.Is(err, service.ErrInvalidSyncToken),
+		errors.Is(err, service.ErrInvalidMove),
+		errors.Is(err, service.ErrInvalidRecurrence),
+		errors.Is(err, service.ErrInvalidImport),
+		errors.Is(err, service.ErrInvalidDescription),
+		errors.Is(err, service.ErrInvalidMask),
+		errors.Is(err, service.ErrInvalidExpiry),
+		errors.Is(err, service.ErrInvalidComment),
+		errors.Is(err, service.ErrInvalidPageToken),
+		errors.Is(err, service.ErrInvalidDependency):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, service.ErrSyncUnsupported),
+		errors.Is(err, service.ErrCalendarUnsupported),
+		errors.Is(err, service.ErrAPIKeysUnsupported),
+		errors.Is(err, service.ErrSharingUnsupported),
+		errors.Is(err, service.ErrWebhooksUnsupported),
+		errors.Is(err, service.ErrAttachmentsUnsupported),
+		errors.Is(err, service.ErrCommentsUnsupported),
+		errors.Is(err, service.ErrDependenciesUnsupported):
+		return connect.NewError(connect.CodeUnimplemented, err)
+	case errors.Is(err, service.ErrUnauthenticated):
+		return connect.NewError(connect.CodeUnauthenticated, err)
+	case errors.Is(err, service.ErrPermissionDenied):
+		return connect.NewError(connect.CodePermissionDenied, err)
+	case errors.Is(err, service.ErrLastOwner),
+		errors.Is(err, service.ErrDependencyCycle),
+		errors.Is(err, service.ErrBlocked):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	case errors.Is(err, service.ErrQuotaExceeded),
+		errors.Is(err, service.ErrAttachmentTooLarge):
+		return connect.NewError(connect.CodeResourceExhausted, err)
+	case errors.Is(err, service.ErrAttachmentMissing):
+		return connect.NewError(connect.CodeDataLoss, err)
+	}
+	return err
+}
@@ -27,9 +27,15 @@ func NewTodosServiceHandler(service *service.Service) *TodosServiceHandler {
 func (h *TodosServiceHandler) Create(ctx context.Context, req *connect.Request[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error) {
 	log.Default().Println("Create todo method called")
 
-	todo, err := h.service.Create(ctx, req.Msg.Title)
+	domainModel := &model.Todo{}
+	err := helper.TransformStruct(req.Msg, domainModel)
 	if err != nil {
-		return nil, err
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	todo, err := h.service.Create(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
 	}
 
 	res := &v1.Todo{}
@@ -48,7 +54,15 @@ func (h *TodosServiceHandler) Get(ctx context.Context, req *connect.Request[v1.G
 
 	todo, err := h.service.Get(ctx, req.Msg.Id)
 	if err != nil {
-		return nil, err
+		return nil, toConnectError(err)
+	}
+
+	cache := todoValidators(todo)
+	if todo.Version > 0 {
+		if err := cache.checkNotModified(req.HTTPMethod(), req.Header()); err != nil {
+			log.Default().Println("Todo item not modified")
+			return nil, err
+		}
 	}
 
 	res := &v1.Todo{}
@@ -58,7 +72,11 @@ func (h *TodosServiceHandler) Get(ctx context.Context, req *connect.Request[v1.G
 	}
 
 	log.Default().Println("Successfully fetched todo item")
-	return connect.NewResponse(&v1.GetResponse{Todo: res}), nil
+	response := connect.NewResponse(&v1.GetResponse{Todo: res})
+	if todo.Version > 0 {
+		cache.set(response.Header())
+	}
+	return response, nil
 }
 
 // Update implements the Update method of the TodoServiceHandler interface.
@@ -68,12 +86,17 @@ func (h *TodosServiceHandler) Update(ctx context.Context, req *connect.Request[v
 	domainModel := &model.Todo{}
 	err := helper.TransformStruct(req.Msg, domainModel)
 	if err != nil {
-		return nil, err
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
-	todo, err := h.service.Update(ctx, domainModel)
+	var todo model.Todo
+	if paths := req.Msg.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		todo, err = h.service.Patch(ctx, domainModel, paths)
+	} else {
+		todo, err = h.service.Update(ctx, domainModel)
+	}
 	if err != nil {
-		return nil, err
+		return nil, toConnectError(err)
 	}
 
 	res := &v1.Todo{}
@@ -92,7 +115,7 @@ func (h *TodosServiceHandler) Delete(ctx context.Context, req *connect.Request[v
 
 	err := h.service.Delete(ctx, req.Msg.Id)
 	if err != nil {
-		return nil, err
+		return nil, toConnectError(err)
 	}
 
 	log.Default().Println("Successfully deleted todo item")
@@ -103,10 +126,30 @@ func (h *TodosServiceHandler) Delete(ctx context.Context, req *connect.Request[v
 func (h *TodosServiceHandler) List(ctx context.Context, req *connect.Request[v1.ListRequest]) (*connect.Response[v1.ListResponse], error) {
 	log.Default().Println("List todos method called")
 
-	todos, err := h.service.List(ctx)
+	opts := model.ListOptions{}
+	err := helper.TransformStruct(req.Msg, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the watermark is read before listing, so a write in between makes the
+	// next request refetch rather than serve stale data as current
+	watermark, err := h.service.Watermark(ctx)
 	if err != nil {
 		return nil, err
 	}
+	cache := newValidators(watermark.UpdatedAt, watermark.Version, int32(opts.Order), opts.ListId, watermark.Scope, queryTag(opts.Query))
+	if watermark.Version > 0 {
+		if err := cache.checkNotModified(req.HTTPMethod(), req.Header()); err != nil {
+			log.Default().Println("Todo items not modified")
+			return nil, err
+		}
+	}
+
+	todos, err := h.service.List(ctx, opts)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
 
 	var resTodos []*v1.Todo
 	err = helper.TransformStruct(todos, &resTodos)
@@ -115,5 +158,107 @@ func (h *TodosServiceHandler) List(ctx context.Context, req *connect.Request[v1.
 	}
 
 	log.Default().Println("Successfully Listed todo items")
-	return connect.NewResponse(&v1.ListResponse{Todos: resTodos}), nil
+	response := connect.NewResponse(&v1.ListResponse{Todos: resTodos})
+	if watermark.Version > 0 {
+		cache.set(response.Header())
+	}
+	return response, nil
+}
+
+// Move implements the Move method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) Move(ctx context.Context, req *connect.Request[v1.MoveRequest]) (*connect.Response[v1.MoveResponse], error) {
+	log.Default().Println("Move todo method called")
+
+	todo, err := h.service.Move(ctx, &model.MoveRequest{
+		Id:       req.Msg.Id,
+		BeforeId: req.Msg.GetBeforeId(),
+		AfterId:  req.Msg.GetAfterId(),
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Todo{}
+	err = helper.TransformStruct(todo, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully moved todo item")
+	return connect.NewResponse(&v1.MoveResponse{Todo: res}), nil
+}
+
+// PreviewOccurrences implements the PreviewOccurrences method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) PreviewOccurrences(ctx context.Context, req *connect.Request[v1.PreviewOccurrencesRequest]) (*connect.Response[v1.PreviewOccurrencesResponse], error) {
+	log.Default().Println("PreviewOccurrences method called")
+
+	domainModel := &model.PreviewOccurrencesRequest{}
+	err := helper.TransformStruct(req.Msg, domainModel)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	occurrences, err := h.service.PreviewOccurrences(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.PreviewOccurrencesResponse{}
+	err = helper.TransformStruct(model.PreviewOccurrencesResponse{Occurrences: occurrences}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully previewed occurrences, count:", len(occurrences))
+	return connect.NewResponse(res), nil
+}
+
+// Sync implements the Sync method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) Sync(ctx context.Context, req *connect.Request[v1.SyncRequest]) (*connect.Response[v1.SyncResponse], error) {
+	log.Default().Println("Sync todos method called")
+
+	domainModel := &model.SyncRequest{}
+	err := helper.TransformStruct(req.Msg, domainModel)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := h.service.Sync(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.SyncResponse{}
+	err = helper.TransformStruct(changes, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully synced todo items")
+	return connect.NewResponse(res), nil
+}
+
+// Push implements the Push method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) Push(ctx context.Context, req *connect.Request[v1.PushRequest]) (*connect.Response[v1.PushResponse], error) {
+	log.Default().Println("Push mutations method called")
+
+	domainModel := &model.PushRequest{}
+	err := helper.TransformStruct(req.Msg, domainModel)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := h.service.Push(ctx, domainModel.Mutations)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.PushResponse{}
+	err = helper.TransformStruct(model.PushResponse{Results: results}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully pushed mutations, count:", len(results))
+	return connect.NewResponse(res), nil
 }
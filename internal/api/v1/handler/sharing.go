@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// CreateList implements the CreateList method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) CreateList(ctx context.Context, req *connect.Request[v1.CreateListRequest]) (*connect.Response[v1.CreateListResponse], error) {
+	log.Default().Println("CreateList method called")
+
+	list, err := h.service.CreateList(ctx, req.Msg.Name)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.TodoList{}
+	err = helper.TransformStruct(list, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully created list")
+	return connect.NewResponse(&v1.CreateListResponse{List: res}), nil
+}
+
+// ListLists implements the ListLists method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListLists(ctx context.Context, req *connect.Request[v1.ListListsRequest]) (*connect.Response[v1.ListListsResponse], error) {
+	log.Default().Println("ListLists method called")
+
+	lists, err := h.service.ListLists(ctx)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resLists []*v1.TodoList
+	err = helper.TransformStruct(lists, &resLists)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed lists")
+	return connect.NewResponse(&v1.ListListsResponse{Lists: resLists}), nil
+}
+
+// InviteMember implements the InviteMember method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) InviteMember(ctx context.Context, req *connect.Request[v1.InviteMemberRequest]) (*connect.Response[v1.InviteMemberResponse], error) {
+	log.Default().Println("InviteMember method called")
+
+	domainModel := &model.Invitation{}
+	err := helper.TransformStruct(req.Msg, domainModel)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	invitation, err := h.service.InviteMember(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Invitation{}
+	err = helper.TransformStruct(invitation, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully invited member")
+	return connect.NewResponse(&v1.InviteMemberResponse{Invitation: res}), nil
+}
+
+// ListInvitations implements the ListInvitations method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListInvitations(ctx context.Context, req *connect.Request[v1.ListInvitationsRequest]) (*connect.Response[v1.ListInvitationsResponse], error) {
+	log.Default().Println("ListInvitations method called")
+
+	invitations, err := h.service.ListInvitations(ctx)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resInvitations []*v1.Invitation
+	err = helper.TransformStruct(invitations, &resInvitations)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed invitations")
+	return connect.NewResponse(&v1.ListInvitationsResponse{Invitations: resInvitations}), nil
+}
+
+// AcceptInvitation implements the AcceptInvitation method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) AcceptInvitation(ctx context.Context, req *connect.Request[v1.AcceptInvitationRequest]) (*connect.Response[v1.AcceptInvitationResponse], error) {
+	log.Default().Println("AcceptInvitation method called")
+
+	member, err := h.service.RespondInvitation(ctx, req.Msg.Id, true)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Member{}
+	err = helper.TransformStruct(member, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully accepted invitation")
+	return connect.NewResponse(&v1.AcceptInvitationResponse{Member: res}), nil
+}
+
+// DeclineInvitation implements the DeclineInvitation method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) DeclineInvitation(ctx context.Context, req *connect.Request[v1.DeclineInvitationRequest]) (*connect.Response[v1.DeclineInvitationResponse], error) {
+	log.Default().Println("DeclineInvitation method called")
+
+	_, err := h.service.RespondInvitation(ctx, req.Msg.Id, false)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully declined invitation")
+	return connect.NewResponse(&v1.DeclineInvitationResponse{}), nil
+}
+
+// ListMembers implements the ListMembers method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) ListMembers(ctx context.Context, req *connect.Request[v1.ListMembersRequest]) (*connect.Response[v1.ListMembersResponse], error) {
+	log.Default().Println("ListMembers method called")
+
+	members, err := h.service.ListMembers(ctx, req.Msg.ListId)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resMembers []*v1.Member
+	err = helper.TransformStruct(members, &resMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed members")
+	return connect.NewResponse(&v1.ListMembersResponse{Members: resMembers}), nil
+}
+
+// UpdateMember implements the UpdateMember method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) UpdateMember(ctx context.Context, req *connect.Request[v1.UpdateMemberRequest]) (*connect.Response[v1.UpdateMemberResponse], error) {
+	log.Default().Println("UpdateMember method called")
+
+	member, err := h.service.UpdateMember(ctx, req.Msg.ListId, req.Msg.UserId, model.Role(req.Msg.Role))
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.Member{}
+	err = helper.TransformStruct(member, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully updated member")
+	return connect.NewResponse(&v1.UpdateMemberResponse{Member: res}), nil
+}
+
+// RemoveMember implements the RemoveMember method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) RemoveMember(ctx context.Context, req *connect.Request[v1.RemoveMemberRequest]) (*connect.Response[v1.RemoveMemberResponse], error) {
+	log.Default().Println("RemoveMember method called")
+
+	err := h.service.RemoveMember(ctx, req.Msg.ListId, req.Msg.UserId)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully removed member")
+	return connect.NewResponse(&v1.RemoveMemberResponse{}), nil
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+const (
+	// exportChunkSize is the size of each ExportResponse message.
+	exportChunkSize = 32 * 1024
+	// maxImportSize bounds the total size of an imported file.
+	maxImportSize = 16 << 20
+)
+
+// streamWriter sends everything written to it as ExportResponse chunks.
+type streamWriter struct {
+	stream *connect.ServerStream[v1.ExportResponse]
+}
+
+func (w streamWriter) Write(p []byte) (int, error) {
+	// p is reused by the buffered writer, so send a copy
+	chunk := bytes.Clone(p)
+	if err := w.stream.Send(&v1.ExportResponse{Chunk: chunk}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Export implements the Export method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) Export(ctx context.Context, req *connect.Request[v1.ExportRequest], stream *connect.ServerStream[v1.ExportResponse]) error {
+	log.Default().Println("Export todos method called")
+
+	w := bufio.NewWriterSize(streamWriter{stream: stream}, exportChunkSize)
+	err := h.service.Export(ctx, model.DataFormat(req.Msg.Format), w)
+	if err != nil {
+		return toConnectError(err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	log.Default().Println("Successfully exported todo items")
+	return nil
+}
+
+// Import implements the Import method of the TodoServiceHandler interface.
+func (h *TodosServiceHandler) Import(ctx context.Context, stream *connect.ClientStream[v1.ImportRequest]) (*connect.Response[v1.ImportResponse], error) {
+	log.Default().Println("Import todos method called")
+
+	var (
+		opts  model.ImportOptions
+		data  bytes.Buffer
+		first = true
+	)
+	for stream.Receive() {
+		msg := stream.Msg()
+		if first {
+			opts = model.ImportOptions{
+				Format:          model.DataFormat(msg.Format),
+				DryRun:          msg.DryRun,
+				AllowDuplicates: msg.AllowDuplicates,
+			}
+			first = false
+		}
+		if data.Len()+len(msg.Chunk) > maxImportSize {
+			return nil, connect.NewError(connect.CodeResourceExhausted, errors.New("import file is too large"))
+		}
+		data.Write(msg.Chunk)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := h.service.Import(ctx, opts, &data)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.ImportResponse{}
+	err = helper.TransformStruct(result, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully imported todo items, count:", len(result.Todos))
+	return connect.NewResponse(res), nil
+}
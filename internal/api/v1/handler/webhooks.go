@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"connectrpc.com/connect"
+	v1 "github.com/haakaashs/todos-backend/gen/protos/todos/v1"
+	gen "github.com/haakaashs/todos-backend/gen/protos/todos/v1/todosv1connect"
+	"github.com/haakaashs/todos-backend/internal/helper"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// WebhooksServiceHandler handles the WebhooksService gRPC requests.
+type WebhooksServiceHandler struct {
+	gen.UnimplementedWebhooksServiceHandler
+	service *service.Service
+}
+
+// NewWebhooksServiceHandler creates a new WebhooksServiceHandler.
+func NewWebhooksServiceHandler(service *service.Service) *WebhooksServiceHandler {
+	return &WebhooksServiceHandler{service: service}
+}
+
+// CreateWebhook implements the CreateWebhook method of the WebhooksServiceHandler interface.
+func (h *WebhooksServiceHandler) CreateWebhook(ctx context.Context, req *connect.Request[v1.CreateWebhookRequest]) (*connect.Response[v1.CreateWebhookResponse], error) {
+	log.Default().Println("CreateWebhook method called")
+
+	domainModel := &model.Webhook{Url: req.Msg.Url, Events: req.Msg.Events, Secret: req.Msg.Secret}
+	created, err := h.service.CreateWebhook(ctx, domainModel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.CreateWebhookResponse{}
+	err = helper.TransformStruct(created, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully created webhook")
+	return connect.NewResponse(res), nil
+}
+
+// ListWebhooks implements the ListWebhooks method of the WebhooksServiceHandler interface.
+func (h *WebhooksServiceHandler) ListWebhooks(ctx context.Context, req *connect.Request[v1.ListWebhooksRequest]) (*connect.Response[v1.ListWebhooksResponse], error) {
+	log.Default().Println("ListWebhooks method called")
+
+	webhooks, err := h.service.ListWebhooks(ctx)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resWebhooks []*v1.Webhook
+	err = helper.TransformStruct(webhooks, &resWebhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed webhooks")
+	return connect.NewResponse(&v1.ListWebhooksResponse{Webhooks: resWebhooks}), nil
+}
+
+// DeleteWebhook implements the DeleteWebhook method of the WebhooksServiceHandler interface.
+func (h *WebhooksServiceHandler) DeleteWebhook(ctx context.Context, req *connect.Request[v1.DeleteWebhookRequest]) (*connect.Response[v1.DeleteWebhookResponse], error) {
+	log.Default().Println("DeleteWebhook method called")
+
+	err := h.service.DeleteWebhook(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	log.Default().Println("Successfully deleted webhook")
+	return connect.NewResponse(&v1.DeleteWebhookResponse{}), nil
+}
+
+// ListDeliveries implements the ListDeliveries method of the WebhooksServiceHandler interface.
+func (h *WebhooksServiceHandler) ListDeliveries(ctx context.Context, req *connect.Request[v1.ListDeliveriesRequest]) (*connect.Response[v1.ListDeliveriesResponse], error) {
+	log.Default().Println("ListDeliveries method called")
+
+	opts := model.DeliveryOptions{}
+	err := helper.TransformStruct(req.Msg, &opts)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	deliveries, err := h.service.ListDeliveries(ctx, opts)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	var resDeliveries []*v1.WebhookDelivery
+	err = helper.TransformStruct(deliveries, &resDeliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully listed deliveries")
+	return connect.NewResponse(&v1.ListDeliveriesResponse{Deliveries: resDeliveries}), nil
+}
+
+// RedeliverDelivery implements the RedeliverDelivery method of the WebhooksServiceHandler interface.
+func (h *WebhooksServiceHandler) RedeliverDelivery(ctx context.Context, req *connect.Request[v1.RedeliverDeliveryRequest]) (*connect.Response[v1.RedeliverDeliveryResponse], error) {
+	log.Default().Println("RedeliverDelivery method called")
+
+	delivery, err := h.service.RedeliverDelivery(ctx, req.Msg.WebhookId, req.Msg.Id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &v1.WebhookDelivery{}
+	err = helper.TransformStruct(delivery, res)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Default().Println("Successfully queued redelivery")
+	return connect.NewResponse(&v1.RedeliverDeliveryResponse{Delivery: res}), nil
+}
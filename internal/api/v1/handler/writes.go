@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// LastWriteHeader carries the time of the last write of a client, in unix
+// milliseconds. Responses to requests that wrote set it, and a client that
+// sends it back with its next requests reads its own writes on any server,
+// even with read replicas.
+const LastWriteHeader = "Todos-Last-Write"
+
+// LastWriteInterceptor passes LastWriteHeader of requests to the repository
+// as service.Writes and sets it on the responses of requests that wrote.
+type LastWriteInterceptor struct{}
+
+// NewLastWriteInterceptor creates a LastWriteInterceptor.
+func NewLastWriteInterceptor() *LastWriteInterceptor {
+	return &LastWriteInterceptor{}
+}
+
+func (i *LastWriteInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		writes := lastWrite(req.Header())
+		before := writes.Last()
+		res, err := next(service.WithWrites(ctx, writes), req)
+		if err != nil {
+			var cerr *connect.Error
+			if errors.As(err, &cerr) {
+				setLastWrite(cerr.Meta(), writes, before)
+			}
+			return nil, err
+		}
+		setLastWrite(res.Header(), writes, before)
+		return res, nil
+	}
+}
+
+func (i *LastWriteInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *LastWriteInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		writes := lastWrite(conn.RequestHeader())
+		tracked := &lastWriteConn{StreamingHandlerConn: conn, writes: writes, before: writes.Last()}
+		err := next(service.WithWrites(ctx, writes), tracked)
+		// writes after the headers went out are reported in the trailers
+		setLastWrite(conn.ResponseTrailer(), writes, tracked.before)
+		return err
+	}
+}
+
+// Handler does the same for plain HTTP requests to next, for endpoints
+// served outside of Connect.
+func (i *LastWriteInterceptor) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes := lastWrite(r.Header)
+		tracked := &lastWriteWriter{ResponseWriter: w, writes: writes, before: writes.Last()}
+		next.ServeHTTP(tracked, r.WithContext(service.WithWrites(r.Context(), writes)))
+	})
+}
+
+// lastWrite returns the writes of the client from header. A missing or
+// malformed time counts as no write.
+func lastWrite(header http.Header) *service.Writes {
+	ms, err := strconv.ParseInt(header.Get(LastWriteHeader), 10, 64)
+	if err != nil || ms <= 0 {
+		return service.NewWrites(time.Time{})
+	}
+	return service.NewWrites(time.UnixMilli(ms))
+}
+
+// setLastWrite sets LastWriteHeader in header if the request wrote after
+// before.
+func setLastWrite(header http.Header, writes *service.Writes, before time.Time) {
+	if last := writes.Last(); last.After(before) {
+		header.Set(LastWriteHeader, strconv.FormatInt(last.UnixMilli(), 10))
+	}
+}
+
+// lastWriteConn sets LastWriteHeader before the first message goes out,
+// which for client streams is the response after all writes.
+type lastWriteConn struct {
+	connect.StreamingHandlerConn
+	writes *service.Writes
+	before time.Time
+	sent   bool
+}
+
+func (c *lastWriteConn) Send(msg any) error {
+	if !c.sent {
+		c.sent = true
+		setLastWrite(c.ResponseHeader(), c.writes, c.before)
+		// the trailers only need writes made after this
+		c.before = c.writes.Last()
+	}
+	return c.StreamingHandlerConn.Send(msg)
+}
+
+// lastWriteWriter sets LastWriteHeader before the response goes out.
+type lastWriteWriter struct {
+	http.ResponseWriter
+	writes *service.Writes
+	before time.Time
+	sent   bool
+}
+
+func (w *lastWriteWriter) WriteHeader(status int) {
+	if !w.sent {
+		w.sent = true
+		setLastWrite(w.Header(), w.writes, w.before)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *lastWriteWriter) Write(b []byte) (int, error) {
+	if !w.sent {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *lastWriteWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+func TestLastWriteHandler(t *testing.T) {
+	written := time.UnixMilli(time.Now().UnixMilli())
+	var got time.Time
+	h := NewLastWriteInterceptor().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes := service.WritesFrom(r.Context())
+		got = writes.Last()
+		if r.Method == http.MethodPost {
+			writes.Mark(written)
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	sent := written.Add(-time.Second)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(LastWriteHeader, strconv.FormatInt(sent.UnixMilli(), 10))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if !got.Equal(sent) {
+		t.Errorf("Expected the last write of the client, got %v", got)
+	}
+	if v := rec.Header().Get(LastWriteHeader); v != "" {
+		t.Errorf("Expected no header without a write, got %q", v)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(LastWriteHeader, "garbage")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if !got.IsZero() {
+		t.Errorf("Expected a malformed header to count as no write, got %v", got)
+	}
+	if v := rec.Header().Get(LastWriteHeader); v != strconv.FormatInt(written.UnixMilli(), 10) {
+		t.Errorf("Expected the time of the write, got %q", v)
+	}
+}
@@ -0,0 +1,82 @@
+// Package auth carries the authenticated caller of a request through its
+// context.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Scopes an API key can be granted.
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+	ScopeKeysAdmin  = "keys:admin"
+)
+
+// DefaultTenant is the tenant of anonymous requests and of data written
+// before the server was multi-tenant.
+const DefaultTenant = "default"
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// UserID identifies the user the request acts for.
+	UserID string
+	// Email is the verified email address of the user, if known.
+	Email string
+	// APIKeyID is set when the caller authenticated with an API key.
+	APIKeyID string
+	// Scopes are the scopes granted to the API key.
+	Scopes []string
+	// TenantID is the workspace the request acts in.
+	TenantID string
+}
+
+// Subject identifies the principal for ownership and quotas: the user if
+// known, otherwise the API key.
+func (p Principal) Subject() string {
+	if p.UserID != "" {
+		return "user:" + p.UserID
+	}
+	if p.APIKeyID != "" {
+		return "key:" + p.APIKeyID
+	}
+	return ""
+}
+
+// HasScope reports whether the principal may act within scope. Users have
+// every scope except ScopeKeysAdmin, API keys the ones they were granted.
+func (p Principal) HasScope(scope string) bool {
+	if p.APIKeyID == "" {
+		return scope != ScopeKeysAdmin
+	}
+	return slices.Contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the request, if it is authenticated.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok && p.Subject() != ""
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context scoped to tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// Tenant returns the tenant a request acts in, DefaultTenant if none is set.
+func Tenant(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// KeyPrefix starts every API key token, which tells them apart from other
+// bearer tokens.
+const KeyPrefix = "todos_"
+
+// AdminTokenID is the APIKeyID of callers using the admin token.
+const AdminTokenID = "admin-token"
+
+// ErrInvalidKey is returned for unknown, revoked and expired API keys.
+var ErrInvalidKey = errors.New("invalid API key")
+
+// KeyVerifier resolves API key tokens to their principal.
+type KeyVerifier interface {
+	// VerifyAPIKey returns ErrInvalidKey if the token is not an active key.
+	VerifyAPIKey(ctx context.Context, token string) (Principal, error)
+}
+
+// Config configures an Interceptor.
+type Config struct {
+	Keys KeyVerifier
+	// Scopes maps each procedure to the scope API keys need to call it. API
+	// keys cannot call procedures missing from it.
+	Scopes map[string]string
+	// AdminToken, if set, is a static bearer token granted ScopeKeysAdmin
+	// only, so that the first API keys can be created.
+	AdminToken string
+	// UserHeader and EmailHeader, if set, name the headers in which an
+	// authenticating proxy in front of the server, such as oauth2-proxy,
+	// passes the signed-in user. The proxy must overwrite them on every
+	// request, or clients could claim to be anyone.
+	UserHeader  string
+	EmailHeader string
+	// TenantHeader, if set, names the header in which the proxy passes the
+	// tenant of the signed-in user. Users without one, API keys created
+	// without one and anonymous callers act in DefaultTenant.
+	TenantHeader string
+}
+
+// Interceptor authenticates API keys sent as "Authorization: Bearer <key>"
+// and users signed in by the proxy, and enforces their scopes. Requests
+// without credentials pass through anonymously, except for procedures that
+// require ScopeKeysAdmin.
+type Interceptor struct {
+	config Config
+}
+
+// NewInterceptor creates an Interceptor.
+func NewInterceptor(config Config) *Interceptor {
+	return &Interceptor{config: config}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx, err := i.authenticate(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authenticate(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// Handler authenticates plain HTTP requests to next as calls of procedure,
+// for endpoints served outside of Connect. Errors are written in the
+// Connect format.
+func (i *Interceptor) Handler(procedure string, next http.Handler) http.Handler {
+	errorWriter := connect.NewErrorWriter()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := i.authenticate(r.Context(), procedure, r.Header)
+		if err != nil {
+			errorWriter.Write(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (i *Interceptor) authenticate(ctx context.Context, procedure string, header http.Header) (context.Context, error) {
+	scope, known := i.config.Scopes[procedure]
+
+	token, hasToken := bearerToken(header)
+
+	var p Principal
+	switch {
+	case hasToken && i.config.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(i.config.AdminToken)) == 1:
+		p = Principal{APIKeyID: AdminTokenID, Scopes: []string{ScopeKeysAdmin}, TenantID: i.tenant(header)}
+	case hasToken && strings.HasPrefix(token, KeyPrefix) && i.config.Keys != nil:
+		var err error
+		p, err = i.config.Keys.VerifyAPIKey(ctx, token)
+		if errors.Is(err, ErrInvalidKey) {
+			return nil, unauthenticated(err)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case hasToken:
+		return nil, unauthenticated(errors.New("unsupported bearer token"))
+	case i.config.UserHeader != "" && header.Get(i.config.UserHeader) != "":
+		p = Principal{UserID: header.Get(i.config.UserHeader), TenantID: i.tenant(header)}
+		if i.config.EmailHeader != "" {
+			p.Email = header.Get(i.config.EmailHeader)
+		}
+	case scope == ScopeKeysAdmin:
+		return nil, unauthenticated(fmt.Errorf("an API key with the %s scope is required", ScopeKeysAdmin))
+	default:
+		return ctx, nil
+	}
+
+	if !known || !p.HasScope(scope) {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("caller lacks the scope to call %s", procedure))
+	}
+	return WithTenant(WithPrincipal(ctx, p), p.TenantID), nil
+}
+
+// tenant returns the tenant named by the proxy, or DefaultTenant.
+func (i *Interceptor) tenant(header http.Header) string {
+	if i.config.TenantHeader != "" {
+		if tenant := header.Get(i.config.TenantHeader); tenant != "" {
+			return tenant
+		}
+	}
+	return DefaultTenant
+}
+
+func bearerToken(header http.Header) (string, bool) {
+	scheme, token, ok := strings.Cut(header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthenticated(err error) error {
+	cerr := connect.NewError(connect.CodeUnauthenticated, err)
+	cerr.Meta().Set("WWW-Authenticate", "Bearer")
+	return cerr
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+)
+
+const (
+	readProcedure  = "/todos.v1.TodosService/List"
+	writeProcedure = "/todos.v1.TodosService/Create"
+	adminProcedure = "/todos.v1.TodosService/CreateApiKey"
+)
+
+type fakeKeys map[string]Principal
+
+func (f fakeKeys) VerifyAPIKey(_ context.Context, token string) (Principal, error) {
+	p, ok := f[token]
+	if !ok {
+		return Principal{}, ErrInvalidKey
+	}
+	return p, nil
+}
+
+func bearer(token string) http.Header {
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header
+}
+
+func TestInterceptorAuthenticate(t *testing.T) {
+	i := NewInterceptor(Config{
+		Keys: fakeKeys{
+			"todos_reader": {APIKeyID: "k1", Scopes: []string{ScopeTodosRead}},
+		},
+		Scopes: map[string]string{
+			readProcedure:  ScopeTodosRead,
+			writeProcedure: ScopeTodosWrite,
+			adminProcedure: ScopeKeysAdmin,
+		},
+		AdminToken: "bootstrap",
+		UserHeader: "X-Auth-Request-User",
+	})
+
+	tests := []struct {
+		name      string
+		procedure string
+		token     string
+		user      string
+		code      connect.Code
+		subject   string
+	}{
+		{"anonymous read", readProcedure, "", "", 0, ""},
+		{"anonymous admin", adminProcedure, "", "", connect.CodeUnauthenticated, ""},
+		{"key within scope", readProcedure, "todos_reader", "", 0, "key:k1"},
+		{"key outside scope", writeProcedure, "todos_reader", "", connect.CodePermissionDenied, ""},
+		{"unknown key", readProcedure, "todos_unknown", "", connect.CodeUnauthenticated, ""},
+		{"unsupported token", readProcedure, "something-else", "", connect.CodeUnauthenticated, ""},
+		{"admin token", adminProcedure, "bootstrap", "", 0, "key:" + AdminTokenID},
+		{"admin token on todos", readProcedure, "bootstrap", "", connect.CodePermissionDenied, ""},
+		{"user", writeProcedure, "", "alice", 0, "user:alice"},
+		{"user on admin", adminProcedure, "", "alice", connect.CodePermissionDenied, ""},
+		{"key wins over user", writeProcedure, "todos_reader", "alice", connect.CodePermissionDenied, ""},
+		{"unmapped procedure", "/todos.v1.TodosService/Unknown", "todos_reader", "", connect.CodePermissionDenied, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := bearer(tt.token)
+			if tt.user != "" {
+				header.Set("X-Auth-Request-User", tt.user)
+			}
+			ctx, err := i.authenticate(context.Background(), tt.procedure, header)
+			if tt.code != 0 {
+				var connectErr *connect.Error
+				if !errors.As(err, &connectErr) || connectErr.Code() != tt.code {
+					t.Fatalf("Expected %v, got %v", tt.code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			p, _ := FromContext(ctx)
+			if p.Subject() != tt.subject {
+				t.Errorf("Expected subject %q, got %q", tt.subject, p.Subject())
+			}
+		})
+	}
+}
+
+func TestInterceptorTenant(t *testing.T) {
+	i := NewInterceptor(Config{
+		Keys: fakeKeys{
+			"todos_acme": {APIKeyID: "k1", Scopes: []string{ScopeTodosRead}, TenantID: "acme"},
+		},
+		Scopes:       map[string]string{readProcedure: ScopeTodosRead, adminProcedure: ScopeKeysAdmin},
+		AdminToken:   "bootstrap",
+		UserHeader:   "X-Auth-Request-User",
+		TenantHeader: "X-Auth-Request-Tenant",
+	})
+
+	tests := []struct {
+		name      string
+		procedure string
+		token     string
+		user      string
+		tenant    string
+		want      string
+	}{
+		{"anonymous", readProcedure, "", "", "", DefaultTenant},
+		{"anonymous cannot pick a tenant", readProcedure, "", "", "acme", DefaultTenant},
+		{"user", readProcedure, "", "alice", "globex", "globex"},
+		{"user without tenant", readProcedure, "", "alice", "", DefaultTenant},
+		{"key", readProcedure, "todos_acme", "", "", "acme"},
+		{"key ignores header", readProcedure, "todos_acme", "", "globex", "acme"},
+		{"admin token", adminProcedure, "bootstrap", "", "globex", "globex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := bearer(tt.token)
+			if tt.user != "" {
+				header.Set("X-Auth-Request-User", tt.user)
+			}
+			if tt.tenant != "" {
+				header.Set("X-Auth-Request-Tenant", tt.tenant)
+			}
+			ctx, err := i.authenticate(context.Background(), tt.procedure, header)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := Tenant(ctx); got != tt.want {
+				t.Errorf("Expected tenant %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+// Package backup writes consistent snapshots of every table of a Store to a
+// compressed file and restores them.
+//
+// A backup is a gzip-compressed stream of JSON lines: a header naming the
+// format version, the tables in restore order and the sequence values, then
+// for every table its name, one line per row and an end line with the row
+// count and the SHA-256 of the rows, and finally a trailer with the SHA-256
+// of every line before it. A restore checks all of them, so a corrupted or
+// truncated backup fails and rolls back instead of restoring part of it.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+const (
+	// Format identifies backups in their header.
+	Format = "todos-backup"
+	// Version is the version of the format written. Restore reads this
+	// version and older ones.
+	Version = 1
+
+	// batchSize is the number of rows restored at once.
+	batchSize = 500
+)
+
+var (
+	ErrCorrupt     = errors.New("corrupt backup")
+	ErrUnsupported = errors.New("unsupported backup version")
+	ErrNotEmpty    = errors.New("database is not empty")
+)
+
+// Store is implemented by repositories that can be backed up.
+type Store interface {
+	// ReadSnapshot calls fn with a consistent view of every table of every
+	// tenant, as of a single point in time.
+	ReadSnapshot(ctx context.Context, fn func(Reader) error) error
+	// WriteSnapshot calls fn with a Writer whose writes are committed
+	// together if fn returns nil and discarded otherwise.
+	WriteSnapshot(ctx context.Context, fn func(Writer) error) error
+}
+
+// Reader reads a snapshot.
+type Reader interface {
+	// Tables returns the tables in an order in which they can be restored,
+	// every table after those it references.
+	Tables(ctx context.Context) ([]string, error)
+	// Rows calls fn with every row of table as a JSON object.
+	Rows(ctx context.Context, table string, fn func(row json.RawMessage) error) error
+	// Sequences returns the current value of every sequence.
+	Sequences(ctx context.Context) (map[string]int64, error)
+}
+
+// Writer restores a snapshot.
+type Writer interface {
+	// Count returns the number of rows in table.
+	Count(ctx context.Context, table string) (int64, error)
+	// Insert writes rows to table, resolving conflicts with existing rows
+	// by policy, and returns the number of rows written.
+	Insert(ctx context.Context, table string, rows []json.RawMessage, policy ConflictPolicy) (int64, error)
+	// AdvanceSequences moves every sequence that exists to at least the
+	// given value, so restored rows are never handed out again.
+	AdvanceSequences(ctx context.Context, values map[string]int64) error
+}
+
+// ConflictPolicy decides what happens to a restored row whose key already
+// exists.
+type ConflictPolicy int
+
+const (
+	// ConflictFail fails the restore.
+	ConflictFail ConflictPolicy = iota
+	// ConflictSkip keeps the existing row.
+	ConflictSkip
+	// ConflictOverwrite replaces the existing row with the restored one.
+	ConflictOverwrite
+)
+
+// ParseConflictPolicy parses "fail", "skip" or "overwrite".
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch s {
+	case "fail":
+		return ConflictFail, nil
+	case "skip":
+		return ConflictSkip, nil
+	case "overwrite":
+		return ConflictOverwrite, nil
+	}
+	return 0, fmt.Errorf("unknown conflict policy %q", s)
+}
+
+// RestoreOptions configures Restore.
+type RestoreOptions struct {
+	// Merge restores into a database that may already hold rows, resolving
+	// conflicts by OnConflict. Otherwise every table must be empty.
+	Merge      bool
+	OnConflict ConflictPolicy
+}
+
+// Manifest describes a backup.
+type Manifest struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	Sequences map[string]int64 `json:"sequences,omitempty"`
+	Tables    []Table          `json:"tables"`
+}
+
+// Table describes a table in a backup.
+type Table struct {
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+	// Restored is the number of rows written by Restore, which is less
+	// than Rows when conflicting rows are skipped.
+	Restored int64 `json:"restored,omitempty"`
+}
+
+// header is the first line of a backup.
+type header struct {
+	Format    string           `json:"format"`
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	Tables    []string         `json:"tables"`
+	Sequences map[string]int64 `json:"sequences,omitempty"`
+}
+
+// line is a line of a backup, with exactly one field set.
+type line struct {
+	Header  *header         `json:"header,omitempty"`
+	Table   string          `json:"table,omitempty"`
+	Row     json.RawMessage `json:"row,omitempty"`
+	End     *Table          `json:"end,omitempty"`
+	Trailer *trailer        `json:"trailer,omitempty"`
+}
+
+type trailer struct {
+	SHA256 string `json:"sha256"`
+}
+
+// Backup writes a snapshot of store to w.
+func Backup(ctx context.Context, store Store, w io.Writer) (Manifest, error) {
+	gz := gzip.NewWriter(w)
+	out := &lineWriter{w: bufio.NewWriter(gz), sum: sha256.New()}
+
+	var manifest Manifest
+	err := store.ReadSnapshot(ctx, func(snapshot Reader) error {
+		tables, err := snapshot.Tables(ctx)
+		if err != nil {
+			return err
+		}
+		sequences, err := snapshot.Sequences(ctx)
+		if err != nil {
+			return err
+		}
+		manifest = Manifest{Version: Version, CreatedAt: time.Now().UTC(), Sequences: sequences}
+		h := &header{Format: Format, Version: Version, CreatedAt: manifest.CreatedAt, Tables: tables, Sequences: sequences}
+		if err := out.write(line{Header: h}); err != nil {
+			return err
+		}
+
+		for _, name := range tables {
+			if err := out.write(line{Table: name}); err != nil {
+				return err
+			}
+			table := Table{Name: name}
+			rows := sha256.New()
+			err := snapshot.Rows(ctx, name, func(row json.RawMessage) error {
+				var compact bytes.Buffer
+				if err := json.Compact(&compact, row); err != nil {
+					return fmt.Errorf("row of %s: %w", name, err)
+				}
+				table.Rows++
+				rows.Write(compact.Bytes())
+				rows.Write([]byte("\n"))
+				return out.write(line{Row: compact.Bytes()})
+			})
+			if err != nil {
+				return err
+			}
+			table.SHA256 = hex.EncodeToString(rows.Sum(nil))
+			if err := out.write(line{End: &table}); err != nil {
+				return err
+			}
+			manifest.Tables = append(manifest.Tables, table)
+		}
+		return nil
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	if err := out.writeTrailer(); err != nil {
+		return Manifest{}, err
+	}
+	if err := out.w.Flush(); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, gz.Close()
+}
+
+// lineWriter writes lines and hashes them.
+type lineWriter struct {
+	w   *bufio.Writer
+	sum hash.Hash
+}
+
+func (lw *lineWriter) write(l line) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	// rows are written as they are, so their checksum holds
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(l); err != nil {
+		return err
+	}
+	lw.sum.Write(b.Bytes())
+	_, err := lw.w.Write(b.Bytes())
+	return err
+}
+
+func (lw *lineWriter) writeTrailer() error {
+	b, err := json.Marshal(line{Trailer: &trailer{SHA256: hex.EncodeToString(lw.sum.Sum(nil))}})
+	if err != nil {
+		return err
+	}
+	_, err = lw.w.Write(append(b, '\n'))
+	return err
+}
+
+// Verify reads a backup and checks its checksums without restoring it.
+func Verify(r io.Reader) (Manifest, error) {
+	return read(r, nil)
+}
+
+// Restore writes the backup read from r to store in a single transaction,
+// which is rolled back if the backup turns out to be corrupt.
+func Restore(ctx context.Context, store Store, r io.Reader, opts RestoreOptions) (Manifest, error) {
+	var manifest Manifest
+	err := store.WriteSnapshot(ctx, func(w Writer) error {
+		var err error
+		manifest, err = read(r, &restorer{ctx: ctx, w: w, opts: opts})
+		return err
+	})
+	return manifest, err
+}
+
+// restorer writes the rows read by read.
+type restorer struct {
+	ctx  context.Context
+	w    Writer
+	opts RestoreOptions
+
+	table string
+	batch []json.RawMessage
+	// restored counts the rows written to table.
+	restored int64
+}
+
+// begin checks that the tables are empty, unless merging.
+func (res *restorer) begin(tables []string) error {
+	if res.opts.Merge {
+		return nil
+	}
+	for _, table := range tables {
+		n, err := res.w.Count(res.ctx, table)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: %s has %d rows", ErrNotEmpty, table, n)
+		}
+	}
+	return nil
+}
+
+func (res *restorer) add(row json.RawMessage) error {
+	res.batch = append(res.batch, row)
+	if len(res.batch) < batchSize {
+		return nil
+	}
+	return res.flush()
+}
+
+func (res *restorer) flush() error {
+	if len(res.batch) == 0 {
+		return nil
+	}
+	policy := ConflictFail
+	if res.opts.Merge {
+		policy = res.opts.OnConflict
+	}
+	n, err := res.w.Insert(res.ctx, res.table, res.batch, policy)
+	if err != nil {
+		return fmt.Errorf("restoring %s: %w", res.table, err)
+	}
+	res.restored += n
+	res.batch = res.batch[:0]
+	return nil
+}
+
+// read reads and verifies a backup, passing its rows to res if it is not
+// nil.
+func read(r io.Reader, res *restorer) (Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	in := bufio.NewReader(gz)
+	sum := sha256.New()
+
+	var (
+		manifest Manifest
+		h        *header
+		table    *Table
+		rows     hash.Hash
+	)
+	for {
+		raw, err := in.ReadBytes('\n')
+		if err == io.EOF {
+			return Manifest{}, fmt.Errorf("%w: missing trailer, the backup is truncated", ErrCorrupt)
+		}
+		if err != nil {
+			return Manifest{}, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		var l line
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return Manifest{}, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+
+		switch {
+		case h == nil:
+			if l.Header == nil || l.Header.Format != Format {
+				return Manifest{}, fmt.Errorf("%w: not a backup", ErrCorrupt)
+			}
+			h = l.Header
+			if h.Version > Version {
+				return Manifest{}, fmt.Errorf("%w: %d, this server reads up to %d", ErrUnsupported, h.Version, Version)
+			}
+			manifest = Manifest{Version: h.Version, CreatedAt: h.CreatedAt, Sequences: h.Sequences}
+			if res != nil {
+				if err := res.begin(h.Tables); err != nil {
+					return Manifest{}, err
+				}
+			}
+
+		case l.Table != "":
+			if table != nil {
+				return Manifest{}, fmt.Errorf("%w: %s has no end", ErrCorrupt, table.Name)
+			}
+			table, rows = &Table{Name: l.Table}, sha256.New()
+			if res != nil {
+				res.table, res.restored = l.Table, 0
+			}
+
+		case l.Row != nil:
+			if table == nil {
+				return Manifest{}, fmt.Errorf("%w: row outside a table", ErrCorrupt)
+			}
+			table.Rows++
+			rows.Write(l.Row)
+			rows.Write([]byte("\n"))
+			if res != nil {
+				if err := res.add(l.Row); err != nil {
+					return Manifest{}, err
+				}
+			}
+
+		case l.End != nil:
+			if table == nil || l.End.Name != table.Name {
+				return Manifest{}, fmt.Errorf("%w: unexpected end of %s", ErrCorrupt, l.End.Name)
+			}
+			table.SHA256 = hex.EncodeToString(rows.Sum(nil))
+			if l.End.Rows != table.Rows || l.End.SHA256 != table.SHA256 {
+				return Manifest{}, fmt.Errorf("%w: checksum mismatch in %s", ErrCorrupt, table.Name)
+			}
+			if res != nil {
+				if err := res.flush(); err != nil {
+					return Manifest{}, err
+				}
+				table.Restored = res.restored
+			}
+			manifest.Tables = append(manifest.Tables, *table)
+			table = nil
+
+		case l.Trailer != nil:
+			if table != nil {
+				return Manifest{}, fmt.Errorf("%w: %s has no end", ErrCorrupt, table.Name)
+			}
+			if l.Trailer.SHA256 != hex.EncodeToString(sum.Sum(nil)) {
+				return Manifest{}, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+			}
+			if res != nil {
+				if err := res.w.AdvanceSequences(res.ctx, h.Sequences); err != nil {
+					return Manifest{}, err
+				}
+			}
+			return manifest, nil
+
+		default:
+			return Manifest{}, fmt.Errorf("%w: unexpected line %q", ErrCorrupt, bytes.TrimSpace(raw))
+		}
+		sum.Write(raw)
+	}
+}
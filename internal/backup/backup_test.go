@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// memoryStore is an in-memory Store whose rows are keyed by their "id".
+// Writes go to a copy that replaces the tables once fn succeeds.
+type memoryStore struct {
+	order     []string
+	tables    map[string]map[string]json.RawMessage
+	sequences map[string]int64
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		order:     []string{"lists", "todos"},
+		tables:    map[string]map[string]json.RawMessage{"lists": {}, "todos": {}},
+		sequences: map[string]int64{},
+	}
+}
+
+func (m *memoryStore) put(table, id, row string) {
+	m.tables[table][id] = json.RawMessage(row)
+}
+
+func (m *memoryStore) ReadSnapshot(_ context.Context, fn func(Reader) error) error {
+	return fn(m)
+}
+
+func (m *memoryStore) WriteSnapshot(_ context.Context, fn func(Writer) error) error {
+	unit := &memoryStore{order: m.order, tables: map[string]map[string]json.RawMessage{}, sequences: maps.Clone(m.sequences)}
+	for name, rows := range m.tables {
+		unit.tables[name] = maps.Clone(rows)
+	}
+	if err := fn(unit); err != nil {
+		return err
+	}
+	m.tables, m.sequences = unit.tables, unit.sequences
+	return nil
+}
+
+func (m *memoryStore) Tables(context.Context) ([]string, error) {
+	return m.order, nil
+}
+
+func (m *memoryStore) Rows(_ context.Context, table string, fn func(json.RawMessage) error) error {
+	for _, id := range slices.Sorted(maps.Keys(m.tables[table])) {
+		if err := fn(m.tables[table][id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) Sequences(context.Context) (map[string]int64, error) {
+	return m.sequences, nil
+}
+
+func (m *memoryStore) Count(_ context.Context, table string) (int64, error) {
+	return int64(len(m.tables[table])), nil
+}
+
+func (m *memoryStore) Insert(_ context.Context, table string, rows []json.RawMessage, policy ConflictPolicy) (int64, error) {
+	var n int64
+	for _, row := range rows {
+		var key struct {
+			Id string `json:"id"`
+		}
+		json.Unmarshal(row, &key)
+		if _, ok := m.tables[table][key.Id]; ok {
+			switch policy {
+			case ConflictFail:
+				return n, errors.New("duplicate key " + key.Id)
+			case ConflictSkip:
+				continue
+			}
+		}
+		m.tables[table][key.Id] = row
+		n++
+	}
+	return n, nil
+}
+
+func (m *memoryStore) AdvanceSequences(_ context.Context, values map[string]int64) error {
+	for name, value := range values {
+		m.sequences[name] = max(m.sequences[name], value)
+	}
+	return nil
+}
+
+// snapshotOf returns a backup of a store with a list, two todos and a
+// sequence.
+func snapshotOf(t *testing.T) []byte {
+	t.Helper()
+	store := newMemoryStore()
+	store.put("lists", "l1", `{"id":"l1","name":"Groceries"}`)
+	store.put("todos", "t1", `{"id": "t1", "title": "Milk & <eggs>", "list_id": "l1"}`)
+	store.put("todos", "t2", `{"id":"t2","title":"Bread"}`)
+	store.sequences["todos_version_seq"] = 42
+
+	var b bytes.Buffer
+	manifest, err := Backup(context.Background(), store, &b)
+	if err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	if len(manifest.Tables) != 2 || manifest.Tables[1].Rows != 2 {
+		t.Fatalf("Expected both tables in the manifest, got %+v", manifest)
+	}
+	return b.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := snapshotOf(t)
+	if _, err := Verify(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	target := newMemoryStore()
+	manifest, err := Restore(context.Background(), target, bytes.NewReader(data), RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if manifest.Version != Version || manifest.Tables[1].Restored != 2 {
+		t.Errorf("Expected 2 restored todos, got %+v", manifest)
+	}
+	if got := string(target.tables["todos"]["t1"]); got != `{"id":"t1","title":"Milk & <eggs>","list_id":"l1"}` {
+		t.Errorf("Expected the row as it was, got %s", got)
+	}
+	if target.sequences["todos_version_seq"] != 42 {
+		t.Errorf("Expected the sequence to be restored, got %v", target.sequences)
+	}
+}
+
+func TestRestoreIntoNonEmptyDatabase(t *testing.T) {
+	data := snapshotOf(t)
+	ctx := context.Background()
+
+	target := newMemoryStore()
+	target.put("todos", "t1", `{"id":"t1","title":"Changed"}`)
+	target.sequences["todos_version_seq"] = 50
+	if _, err := Restore(ctx, target, bytes.NewReader(data), RestoreOptions{}); !errors.Is(err, ErrNotEmpty) {
+		t.Errorf("Expected ErrNotEmpty, got %v", err)
+	}
+	if _, err := Restore(ctx, target, bytes.NewReader(data), RestoreOptions{Merge: true}); err == nil {
+		t.Errorf("Expected a conflict to fail the restore")
+	}
+	if len(target.tables["todos"]) != 1 || len(target.tables["lists"]) != 0 {
+		t.Errorf("Expected a failed restore to change nothing, got %v", target.tables)
+	}
+
+	manifest, err := Restore(ctx, target, bytes.NewReader(data), RestoreOptions{Merge: true, OnConflict: ConflictSkip})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if manifest.Tables[1].Restored != 1 || !strings.Contains(string(target.tables["todos"]["t1"]), "Changed") {
+		t.Errorf("Expected the existing todo to be kept, got %+v %s", manifest.Tables[1], target.tables["todos"]["t1"])
+	}
+	if target.sequences["todos_version_seq"] != 50 {
+		t.Errorf("Expected the sequence not to move back, got %v", target.sequences)
+	}
+
+	if _, err := Restore(ctx, target, bytes.NewReader(data), RestoreOptions{Merge: true, OnConflict: ConflictOverwrite}); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if strings.Contains(string(target.tables["todos"]["t1"]), "Changed") {
+		t.Errorf("Expected the todo to be overwritten, got %s", target.tables["todos"]["t1"])
+	}
+}
+
+// rewrite decompresses a backup, applies edit to its text and compresses it
+// again.
+func rewrite(t *testing.T, data []byte, edit func(string) string) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, _ := io.ReadAll(gz)
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	io.WriteString(w, edit(string(text)))
+	w.Close()
+	return b.Bytes()
+}
+
+func TestCorruptBackups(t *testing.T) {
+	data := snapshotOf(t)
+	tests := map[string][]byte{
+		"changed row": rewrite(t, data, func(s string) string { return strings.Replace(s, "Bread", "Toast", 1) }),
+		"dropped row": rewrite(t, data, func(s string) string {
+			lines := strings.SplitAfter(s, "\n")
+			return strings.Join(slices.Delete(lines, 4, 5), "")
+		}),
+		"truncated": rewrite(t, data, func(s string) string {
+			lines := strings.SplitAfter(s, "\n")
+			return strings.Join(lines[:len(lines)-2], "")
+		}),
+		"not gzip":       []byte("id,title\n"),
+		"truncated gzip": data[:len(data)/2],
+	}
+	for name, corrupt := range tests {
+		if _, err := Verify(bytes.NewReader(corrupt)); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: Expected ErrCorrupt, got %v", name, err)
+		}
+		target := newMemoryStore()
+		if _, err := Restore(context.Background(), target, bytes.NewReader(corrupt), RestoreOptions{}); err == nil || len(target.tables["lists"]) != 0 {
+			t.Errorf("%s: Expected the restore to fail and change nothing, got %v %v", name, err, target.tables)
+		}
+	}
+
+	newer := rewrite(t, data, func(s string) string { return strings.Replace(s, `"version":1`, `"version":2`, 1) })
+	if _, err := Verify(bytes.NewReader(newer)); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// testStore checks the behaviour every store shares.
+func testStore(t *testing.T, s store) {
+	t.Helper()
+	ctx := context.Background()
+	key := "attachments/acme corp/" + time.Now().Format("150405.000000") + "+log.txt"
+	content := "panic: runtime error\n"
+
+	if err := s.Put(ctx, key, strings.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	r, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	got, _ := io.ReadAll(r)
+	r.Close()
+	if string(got) != content {
+		t.Errorf("Expected %q, got %q", content, got)
+	}
+
+	if err := s.Put(ctx, key+".empty", bytes.NewReader(nil), 0); err != nil {
+		t.Fatalf("Put of an empty blob failed: %v", err)
+	}
+	if err := s.Delete(ctx, key+".empty"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist after Delete, got %v", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Expected deleting a missing blob to succeed, got %v", err)
+	}
+}
+
+func TestFS(t *testing.T) {
+	s, err := NewFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	if err := s.Put(context.Background(), "../escape", strings.NewReader("x"), 1); err == nil {
+		t.Errorf("Expected a key outside the root to be refused")
+	}
+	if err := s.Put(context.Background(), "short", strings.NewReader("x"), 2); err == nil {
+		t.Errorf("Expected a short blob to be refused")
+	}
+	if _, err := s.Get(context.Background(), "short"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a failed Put to leave nothing, got %v", err)
+	}
+}
+
+// fakeS3 is a stand-in for an S3-compatible service with path-style
+// buckets. It checks the signature and payload hash of every request.
+type fakeS3 struct {
+	secret  string
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/xml")
+
+	if !f.signed(r) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>")
+		return
+	}
+	sum := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, "<Error><Code>XAmzContentSHA256Mismatch</Code></Error>")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		object, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Write(object)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// signed recomputes the Signature Version 4 signature of r over the
+// headers it names, and reports whether it matches.
+func (f *fakeS3) signed(r *http.Request) bool {
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 "), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		fields[name] = value
+	}
+	_, scope, _ := strings.Cut(fields["Credential"], "/")
+
+	var headers strings.Builder
+	for _, name := range strings.Split(fields["SignedHeaders"], ";") {
+		value := r.Header.Get(name)
+		switch name {
+		case "host":
+			value = r.Host
+		case "content-length":
+			value = strconv.FormatInt(r.ContentLength, 10)
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.Query().Encode(),
+		headers.String(),
+		fields["SignedHeaders"],
+		r.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + r.Header.Get("X-Amz-Date") + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := []byte("AWS4" + f.secret)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign)) == fields["Signature"]
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func TestS3(t *testing.T) {
+	config := S3Config{Bucket: "todos", AccessKeyID: "minio", SecretAccessKey: "minio123", PathStyle: true}
+	fake := &fakeS3{secret: config.SecretAccessKey, objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	config.Endpoint = server.URL
+
+	s, err := NewS3(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	// a reader that cannot be rewound is spooled, so its payload is signed
+	if err := s.Put(context.Background(), "stream", io.MultiReader(strings.NewReader("abc")), 3); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if string(fake.objects["/todos/stream"]) != "abc" {
+		t.Errorf("Expected the object at /todos/stream, got %v", fake.objects)
+	}
+
+	wrong := config
+	wrong.SecretAccessKey = "wrong"
+	s, _ = NewS3(wrong)
+	if err := s.Put(context.Background(), "denied", strings.NewReader("x"), 1); err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Expected SignatureDoesNotMatch, got %v", err)
+	}
+}
+
+// TestS3Service runs against a real S3-compatible service such as MinIO,
+// e.g. TODOS_TEST_S3_ENDPOINT=http://localhost:9000 with an existing bucket
+// in TODOS_TEST_S3_BUCKET, and is skipped unless the endpoint is set.
+func TestS3Service(t *testing.T) {
+	endpoint := os.Getenv("TODOS_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("TODOS_TEST_S3_ENDPOINT is not set")
+	}
+	s, err := NewS3(S3Config{
+		Endpoint:        endpoint,
+		Bucket:          os.Getenv("TODOS_TEST_S3_BUCKET"),
+		Region:          os.Getenv("TODOS_TEST_S3_REGION"),
+		AccessKeyID:     os.Getenv("TODOS_TEST_S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("TODOS_TEST_S3_SECRET_ACCESS_KEY"),
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+}
@@ -0,0 +1,162 @@
+package blob
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config configures an S3 store.
+type S3Config struct {
+	// Endpoint is the URL of the service, e.g. "https://s3.eu-west-1.amazonaws.com"
+	// or "http://minio:9000". An https endpoint is verified against the
+	// system roots.
+	Endpoint string
+	Bucket   string
+	// Region signs the requests; "us-east-1" by default, which MinIO accepts.
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket as the first path segment instead of a
+	// subdomain of the endpoint, as MinIO and most other stand-ins expect.
+	PathStyle bool
+	// Timeout bounds connecting and waiting for each response; 30 seconds
+	// by default. The transfer of the content itself is bounded by the
+	// context.
+	Timeout time.Duration
+}
+
+// S3 stores blobs as objects in a bucket of an S3-compatible service.
+// Every request, including the content of an upload, is signed with AWS
+// Signature Version 4.
+type S3 struct {
+	bucket string
+	client minio.Core
+}
+
+// NewS3 creates an S3 store. The bucket must exist.
+func NewS3(config S3Config) (*S3, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") ||
+		strings.Trim(endpoint.Path, "/") != "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, errors.New("an S3 bucket is required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	secure := endpoint.Scheme == "https"
+	transport, err := minio.DefaultTransport(secure)
+	if err != nil {
+		return nil, err
+	}
+	transport.DialContext = (&net.Dialer{Timeout: config.Timeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = config.Timeout
+	transport.ResponseHeaderTimeout = config.Timeout
+
+	lookup := minio.BucketLookupDNS
+	if config.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.NewCore(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, ""),
+		Secure:       secure,
+		Region:       cmp.Or(config.Region, "us-east-1"),
+		BucketLookup: lookup,
+		Transport:    transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &S3{bucket: config.Bucket, client: *client}, nil
+}
+
+// Put uploads the blob in a single request with its payload signed. A
+// reader that cannot be rewound is spooled to a temporary file first, since
+// the content is read once to hash it and once to send it.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	seeker, ok := r.(io.ReadSeeker)
+	if !ok {
+		tmp, err := os.CreateTemp("", "blob-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		if _, err := io.Copy(tmp, io.LimitReader(r, size)); err != nil {
+			return err
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		seeker = tmp
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, io.LimitReader(seeker, size)); err != nil {
+		return err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, key, io.LimitReader(seeker, size), size, "", hex.EncodeToString(h.Sum(nil)),
+		minio.PutObjectOptions{ContentType: "application/octet-stream", DisableContentSha256: true})
+	return s3Error(http.MethodPut, key, err)
+}
+
+// Get downloads the blob. A missing blob fails with an fs.ErrNotExist error.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, _, _, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Error(http.MethodGet, key, err)
+	}
+	return body, nil
+}
+
+// Delete removes the blob. S3 reports success for missing objects too.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+	if err = s3Error(http.MethodDelete, key, err); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// s3Error names the request and the error code of the service in err, and
+// makes a missing object an fs.ErrNotExist error.
+func s3Error(method, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	res := minio.ToErrorResponse(err)
+	if res.Code == "" {
+		return fmt.Errorf("s3: %s %s: %w", method, key, err)
+	}
+	err = fmt.Errorf("s3: %s %s: %s: %w", method, key, res.Code, err)
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %w", fs.ErrNotExist, err)
+	}
+	return err
+}
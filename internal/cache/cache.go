@@ -0,0 +1,265 @@
+// Package cache serves Get and List from a cache in front of any
+// service.Repository.
+//
+// Entries are keyed by a generation of their tenant, which every write of the
+// tenant replaces, so a write invalidates all cached reads of its tenant at
+// once and a read that raced a write can only store its result under a
+// generation that is already gone. Other servers learn about writes from a
+// Notifier, unless they share the backend.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"golang.org/x/sync/singleflight"
+)
+
+// Backend stores cache entries.
+type Backend interface {
+	// Get returns the value of key, or false if it is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl, or without expiry if ttl is 0.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Notifier tells the caches of other servers that a tenant changed.
+type Notifier interface {
+	Notify(ctx context.Context, tenant string) error
+}
+
+// Config configures a Cache. Zero values take the defaults below.
+type Config struct {
+	Backend Backend
+	// TTL bounds how long an entry is served; a minute by default.
+	TTL time.Duration
+	// Notifier, if set, is told about every write.
+	Notifier Notifier
+}
+
+// Stats counts cache lookups since the Cache was created.
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// Shared counts misses served by the load of a concurrent miss.
+	Shared int64 `json:"shared"`
+	// Errors counts failed backend calls, which fall back to the repository.
+	Errors        int64 `json:"errors"`
+	Invalidations int64 `json:"invalidations"`
+}
+
+// Cache holds the entries of the repositories it wraps.
+type Cache struct {
+	config Config
+	loads  singleflight.Group
+	// epoch prefixes generations, so replacing it drops every entry.
+	epoch atomic.Pointer[string]
+
+	hits, misses, shared, errors, invalidations atomic.Int64
+}
+
+// New creates a Cache.
+func New(config Config) *Cache {
+	if config.TTL <= 0 {
+		config.TTL = time.Minute
+	}
+	c := &Cache{config: config}
+	epoch := ""
+	c.epoch.Store(&epoch)
+	return c
+}
+
+// Wrap returns a Repository that serves Get and List of next, and the
+// comment counts and blocked todos that annotate them, from the cache and
+// invalidates the tenant on every write. Reads inside a unit of work and
+// reads marked with service.WithPrimary always go to next. The wrapper
+// implements service.ChangeRecorder if next does, so that Put invalidates
+// too; the other optional stores of next are found with Unwrap.
+func (c *Cache) Wrap(next service.Repository) service.Repository {
+	r := &repository{next: next, cache: c}
+	if rec, ok := next.(service.ChangeRecorder); ok {
+		return &recordingRepository{repository: r, rec: rec}
+	}
+	return r
+}
+
+// Stats returns the current counters.
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Shared:        c.shared.Load(),
+		Errors:        c.errors.Load(),
+		Invalidations: c.invalidations.Load(),
+	}
+}
+
+// Invalidate drops the entries of tenant on this server and, for a shared
+// backend, on every server. It does not notify other servers.
+func (c *Cache) Invalidate(ctx context.Context, tenant string) {
+	c.invalidations.Add(1)
+	if _, err := c.newGeneration(ctx, tenant); err != nil {
+		c.failed("invalidating", err)
+	}
+}
+
+// InvalidateAll drops every entry this server reads, for when it may have
+// missed notifications.
+func (c *Cache) InvalidateAll() {
+	c.invalidations.Add(1)
+	epoch := randomID()
+	c.epoch.Store(&epoch)
+}
+
+// changed invalidates the tenant of ctx after a write and notifies the other
+// servers.
+func (c *Cache) changed(ctx context.Context) {
+	tenant := auth.Tenant(ctx)
+	c.Invalidate(ctx, tenant)
+	if c.config.Notifier != nil {
+		if err := c.config.Notifier.Notify(ctx, tenant); err != nil {
+			log.Default().Println("cache: failed to notify other servers:", err)
+		}
+	}
+}
+
+// read returns the value cached under op for the tenant of ctx, or loads,
+// caches and returns it. Concurrent misses of a key share one load. Errors
+// are not cached. A value that is cached is loaded from the primary, since a
+// lagging replica would keep serving every caller, the one that just wrote
+// included, a stale value for the whole TTL.
+func read[T any](ctx context.Context, c *Cache, op string, load func(context.Context) (T, error)) (T, error) {
+	if service.ReadsPrimary(ctx) {
+		return load(ctx)
+	}
+	gen, err := c.generation(ctx, auth.Tenant(ctx))
+	if err != nil {
+		c.failed("reading generation", err)
+		return load(ctx)
+	}
+	key := gen + "/" + op
+
+	data, ok, err := c.config.Backend.Get(ctx, key)
+	if err != nil {
+		c.failed("reading", err)
+	}
+	var value T
+	if ok && decode(data, &value) == nil {
+		c.hits.Add(1)
+		return value, nil
+	}
+
+	c.misses.Add(1)
+	v, err, shared := c.loads.Do(key, func() (any, error) {
+		value, err := load(service.WithPrimary(ctx))
+		if err != nil {
+			return nil, err
+		}
+		data, err := encode(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.config.Backend.Set(ctx, key, data, c.config.TTL); err != nil {
+			c.failed("writing", err)
+		}
+		return data, nil
+	})
+	if shared {
+		c.shared.Add(1)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	// every caller decodes its own copy, which it may change
+	var loaded T
+	err = decode(v.([]byte), &loaded)
+	return loaded, err
+}
+
+// generation returns the current generation of tenant, starting one if it
+// has none. Since a new generation is stored before anything is loaded
+// under it, a write that commits after a load started always replaces it.
+func (c *Cache) generation(ctx context.Context, tenant string) (string, error) {
+	data, ok, err := c.config.Backend.Get(ctx, c.generationKey(tenant))
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return string(data), nil
+	}
+	return c.newGeneration(ctx, tenant)
+}
+
+// newGeneration replaces the generation of tenant with a random one, which
+// is never reused, so entries of an evicted generation cannot come back.
+func (c *Cache) newGeneration(ctx context.Context, tenant string) (string, error) {
+	gen := "todos:" + tenant + "/" + randomID()
+	return gen, c.config.Backend.Set(ctx, c.generationKey(tenant), []byte(gen), 0)
+}
+
+func (c *Cache) generationKey(tenant string) string {
+	return "todos:generation:" + *c.epoch.Load() + "/" + tenant
+}
+
+func (c *Cache) failed(op string, err error) {
+	c.errors.Add(1)
+	log.Default().Println("cache: failed", op+":", err)
+}
+
+// listKey identifies the result of List with opts. The visible lists are
+// sorted, since their order does not change the result, and hashed with the
+// rest to keep keys short.
+func listKey(opts model.ListOptions) string {
+	lists := slices.Sorted(slices.Values(opts.VisibleLists))
+	sum := sha256.Sum256([]byte(strings.Join(append([]string{strconv.Itoa(int(opts.Order)), opts.ListId, opts.Query, opts.CreatedBy}, lists...), "\x00")))
+	return "list/" + hex.EncodeToString(sum[:16])
+}
+
+// idsKey identifies the result of the read op for the todos of ids, which
+// does not depend on their order.
+func idsKey(op string, ids []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(slices.Sorted(slices.Values(ids)), "\x00")))
+	return op + "/" + hex.EncodeToString(sum[:16])
+}
+
+// entry wraps cached values, since gob cannot encode a nil slice on its own.
+type entry[T any] struct {
+	Value T
+}
+
+// encode uses gob rather than JSON, since the JSON of a todo leaves out
+// fields the service needs.
+func encode[T any](v T) ([]byte, error) {
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(entry[T]{v})
+	return b.Bytes(), err
+}
+
+func decode[T any](data []byte, v *T) error {
+	var e entry[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return err
+	}
+	*v = e.Value
+	return nil
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,582 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// countingRepository is an in-memory service.Repository and
+// service.ChangeRecorder that counts reads, and those a replica could serve.
+// With gate set, reads wait for it to be closed.
+type countingRepository struct {
+	mu           sync.Mutex
+	todos        map[string]model.Todo
+	reads        atomic.Int64
+	replicaReads atomic.Int64
+	gate         chan struct{}
+}
+
+func newCountingRepository() *countingRepository {
+	return &countingRepository{todos: map[string]model.Todo{}}
+}
+
+func (r *countingRepository) read(ctx context.Context) {
+	r.reads.Add(1)
+	if !service.ReadsPrimary(ctx) {
+		r.replicaReads.Add(1)
+	}
+	if r.gate != nil {
+		<-r.gate
+	}
+}
+
+func (r *countingRepository) Create(_ context.Context, t *model.Todo) (model.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.Version = 1
+	r.todos[t.Id] = *t
+	return *t, nil
+}
+
+func (r *countingRepository) CreateBatch(ctx context.Context, todos []*model.Todo) ([]model.Todo, error) {
+	var created []model.Todo
+	for _, t := range todos {
+		todo, _ := r.Create(ctx, t)
+		created = append(created, todo)
+	}
+	return created, nil
+}
+
+func (r *countingRepository) Get(ctx context.Context, id string) (model.Todo, error) {
+	r.read(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.todos[id]
+	if !ok {
+		return model.Todo{}, service.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *countingRepository) Update(_ context.Context, t *model.Todo) (model.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.Version++
+	r.todos[t.Id] = *t
+	return *t, nil
+}
+
+func (r *countingRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.todos, id)
+	return nil
+}
+
+func (r *countingRepository) List(ctx context.Context, _ model.ListOptions) ([]model.Todo, error) {
+	r.read(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var todos []model.Todo
+	for _, t := range r.todos {
+		todos = append(todos, t)
+	}
+	return todos, nil
+}
+
+func (r *countingRepository) Move(_ context.Context, id, position string) (model.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.todos[id]
+	t.Position = position
+	r.todos[id] = t
+	return t, nil
+}
+
+func (r *countingRepository) AdjacentPosition(context.Context, string, string, bool) (string, error) {
+	return "", nil
+}
+
+func (r *countingRepository) Rebalance(context.Context) error {
+	return nil
+}
+
+func (r *countingRepository) WithinTx(_ context.Context, _ service.TxOptions, fn func(service.Repository) error) error {
+	return fn(r)
+}
+
+func (r *countingRepository) Changes(context.Context, model.ChangeCursor, int) ([]model.Todo, error) {
+	return nil, nil
+}
+
+func (r *countingRepository) Lookup(ctx context.Context, id string) (model.Todo, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *countingRepository) Put(ctx context.Context, t *model.Todo, _ int64) (model.Todo, error) {
+	return r.Update(ctx, t)
+}
+
+func (r *countingRepository) LatestChange(context.Context) (model.Watermark, error) {
+	return model.Watermark{}, nil
+}
+
+// CommentCounts counts one comment on every todo.
+func (r *countingRepository) CommentCounts(ctx context.Context, todoIDs []string) (map[string]int32, error) {
+	r.read(ctx)
+	counts := map[string]int32{}
+	for _, id := range todoIDs {
+		counts[id] = 1
+	}
+	return counts, nil
+}
+
+// BlockedTodos reports every todo blocked.
+func (r *countingRepository) BlockedTodos(ctx context.Context, todoIDs []string) (map[string]bool, error) {
+	r.read(ctx)
+	blocked := map[string]bool{}
+	for _, id := range todoIDs {
+		blocked[id] = true
+	}
+	return blocked, nil
+}
+
+func tenant(name string) context.Context {
+	return auth.WithTenant(context.Background(), name)
+}
+
+func TestReadThrough(t *testing.T) {
+	next := newCountingRepository()
+	c := New(Config{Backend: NewMemory(100)})
+	repo := c.Wrap(next)
+	acme, globex := tenant("acme"), tenant("globex")
+
+	repo.Create(acme, &model.Todo{Id: "t1", Title: "Write docs"})
+	for range 3 {
+		todo, err := repo.Get(acme, "t1")
+		if err != nil || todo.Title != "Write docs" {
+			t.Fatalf("Expected the todo, got %v %v", todo, err)
+		}
+	}
+	if got := next.reads.Load(); got != 1 {
+		t.Errorf("Expected 1 read of the repository, got %d", got)
+	}
+
+	repo.Update(acme, &model.Todo{Id: "t1", Title: "Write more docs"})
+	if todo, _ := repo.Get(acme, "t1"); todo.Title != "Write more docs" {
+		t.Errorf("Expected the update to invalidate the cache, got %v", todo)
+	}
+
+	// other tenants and reads of the primary do not share entries
+	repo.Get(globex, "t1")
+	repo.Get(service.WithPrimary(acme), "t1")
+	if got := next.reads.Load(); got != 4 {
+		t.Errorf("Expected 4 reads of the repository, got %d", got)
+	}
+
+	if got, want := c.Stats(), (Stats{Hits: 2, Misses: 3, Invalidations: 2}); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestMissesLoadFromThePrimary(t *testing.T) {
+	next := newCountingRepository()
+	repo := New(Config{Backend: NewMemory(100)}).Wrap(next)
+	acme := tenant("acme")
+
+	repo.Create(acme, &model.Todo{Id: "t1", Title: "Write docs"})
+	repo.Get(acme, "t1")
+	repo.List(acme, model.ListOptions{})
+	repo.(service.CommentCounter).CommentCounts(acme, []string{"t1"})
+	repo.(service.BlockedChecker).BlockedTodos(acme, []string{"t1"})
+	if got := next.reads.Load(); got != 4 {
+		t.Errorf("Expected 4 reads of the repository, got %d", got)
+	}
+	if got := next.replicaReads.Load(); got != 0 {
+		t.Errorf("Expected every cached value to be loaded from the primary, got %d replica reads", got)
+	}
+}
+
+func TestWritesInvalidate(t *testing.T) {
+	acme := tenant("acme")
+	writes := map[string]func(service.Repository){
+		"CreateBatch": func(r service.Repository) { r.CreateBatch(acme, []*model.Todo{{Id: "t2"}}) },
+		"Delete":      func(r service.Repository) { r.Delete(acme, "t2") },
+		"Move":        func(r service.Repository) { r.Move(acme, "t1", "a") },
+		"Rebalance":   func(r service.Repository) { r.Rebalance(acme) },
+		"WithinTx": func(r service.Repository) {
+			r.WithinTx(acme, service.TxOptions{}, func(service.Repository) error { return nil })
+		},
+		"Put": func(r service.Repository) { r.(service.ChangeRecorder).Put(acme, &model.Todo{Id: "t1"}, 1) },
+	}
+	for name, write := range writes {
+		next := newCountingRepository()
+		repo := New(Config{Backend: NewMemory(100)}).Wrap(next)
+		repo.Create(acme, &model.Todo{Id: "t1"})
+
+		repo.List(acme, model.ListOptions{})
+		write(repo)
+		repo.List(acme, model.ListOptions{})
+		if got := next.reads.Load(); got != 2 {
+			t.Errorf("%s: Expected the write to invalidate the cache, got %d reads", name, got)
+		}
+	}
+}
+
+func TestConcurrentMissesShareOneLoad(t *testing.T) {
+	next := newCountingRepository()
+	next.gate = make(chan struct{})
+	c := New(Config{Backend: NewMemory(100)})
+	repo := c.Wrap(next)
+	acme := tenant("acme")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo.List(acme, model.ListOptions{VisibleLists: []string{"b", "a"}})
+		}()
+	}
+	for next.reads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// give the others time to join the load
+	time.Sleep(50 * time.Millisecond)
+	close(next.gate)
+	wg.Wait()
+
+	if got := next.reads.Load(); got != 1 {
+		t.Errorf("Expected 1 read of the repository, got %d", got)
+	}
+	if s := c.Stats(); s.Misses != 10 || s.Shared != 10 {
+		t.Errorf("Expected 10 misses sharing one load, got %+v", s)
+	}
+	// the order of the visible lists does not matter
+	repo.List(acme, model.ListOptions{VisibleLists: []string{"a", "b"}})
+	if got := next.reads.Load(); got != 1 {
+		t.Errorf("Expected a hit, got %d reads", got)
+	}
+}
+
+func TestWrapperForwardsStores(t *testing.T) {
+	repo := New(Config{Backend: NewMemory(100)}).Wrap(newCountingRepository())
+	if _, ok := repo.(service.ChangeRecorder); !ok {
+		t.Errorf("Expected the wrapper to record changes like the repository it wraps")
+	}
+	if w, ok := repo.(service.Wrapper); !ok || w.Unwrap() == nil {
+		t.Errorf("Expected the wrapper to unwrap")
+	}
+
+	type plain struct{ service.Repository }
+	repo = New(Config{Backend: NewMemory(100)}).Wrap(plain{newCountingRepository()})
+	if _, ok := repo.(service.ChangeRecorder); ok {
+		t.Errorf("Expected no change recorder around a repository without one")
+	}
+}
+
+func TestAnnotationsAreCached(t *testing.T) {
+	next := newCountingRepository()
+	repo := New(Config{Backend: NewMemory(100)}).Wrap(next)
+	acme := tenant("acme")
+	counter := repo.(service.CommentCounter)
+	checker := repo.(service.BlockedChecker)
+
+	for _, ids := range [][]string{{"t1", "t2"}, {"t2", "t1"}} {
+		if counts, err := counter.CommentCounts(acme, ids); err != nil || counts["t1"] != 1 {
+			t.Fatalf("Expected the comment counts, got %v %v", counts, err)
+		}
+		if blocked, err := checker.BlockedTodos(acme, ids); err != nil || !blocked["t2"] {
+			t.Fatalf("Expected the blocked todos, got %v %v", blocked, err)
+		}
+	}
+	if got := next.reads.Load(); got != 2 {
+		t.Errorf("Expected 2 reads of the repository, got %d", got)
+	}
+
+	// comments and dependencies are written in units of work
+	repo.WithinTx(acme, service.TxOptions{}, func(service.Repository) error { return nil })
+	counter.CommentCounts(acme, []string{"t1", "t2"})
+	checker.BlockedTodos(acme, []string{"t1", "t2"})
+	if got := next.reads.Load(); got != 4 {
+		t.Errorf("Expected the write to invalidate the annotations, got %d reads", got)
+	}
+
+	type plain struct{ service.Repository }
+	repo = New(Config{Backend: NewMemory(100)}).Wrap(plain{newCountingRepository()})
+	if counts, err := repo.(service.CommentCounter).CommentCounts(acme, []string{"t1"}); err != nil || len(counts) != 0 {
+		t.Errorf("Expected no comment counts without comments, got %v %v", counts, err)
+	}
+}
+
+func TestInvalidateAll(t *testing.T) {
+	next := newCountingRepository()
+	c := New(Config{Backend: NewMemory(100)})
+	repo := c.Wrap(next)
+	acme := tenant("acme")
+
+	repo.List(acme, model.ListOptions{})
+	c.InvalidateAll()
+	repo.List(acme, model.ListOptions{})
+	if got := next.reads.Load(); got != 2 {
+		t.Errorf("Expected 2 reads of the repository, got %d", got)
+	}
+}
+
+func TestMemoryEvictsAndExpires(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(2)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	m.Set(ctx, "a", []byte("1"), time.Minute)
+	m.Set(ctx, "b", []byte("2"), 0)
+	m.Get(ctx, "a")
+	m.Set(ctx, "c", []byte("3"), 0)
+	if _, ok, _ := m.Get(ctx, "b"); ok {
+		t.Errorf("Expected the least recently used entry to be evicted")
+	}
+	if v, ok, _ := m.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Errorf("Expected a to be kept, got %q %v", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := m.Get(ctx, "a"); ok {
+		t.Errorf("Expected a to expire")
+	}
+	if _, ok, _ := m.Get(ctx, "c"); !ok {
+		t.Errorf("Expected c to never expire")
+	}
+	if m.Len() != 1 {
+		t.Errorf("Expected 1 entry, got %d", m.Len())
+	}
+}
+
+// redisServer is an in-memory stand-in for a Redis older than HELLO that
+// supports the commands the backend sends. It requires the password, if
+// set.
+type redisServer struct {
+	listener net.Listener
+	password string
+
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+func newRedisServer(t *testing.T, password string) *redisServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &redisServer{listener: listener, password: password, values: map[string]string{}, expires: map[string]time.Time{}}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *redisServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *redisServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(args[0])
+		if !authenticated && command != "AUTH" {
+			io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+
+		s.mu.Lock()
+		switch command {
+		case "AUTH":
+			authenticated = args[len(args)-1] == s.password
+			if authenticated {
+				io.WriteString(conn, "+OK\r\n")
+			} else {
+				io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+			}
+		case "SELECT":
+			io.WriteString(conn, "+OK\r\n")
+		case "GET":
+			value, ok := s.values[args[1]]
+			if at, expires := s.expires[args[1]]; expires && !time.Now().Before(at) {
+				ok = false
+			}
+			if ok {
+				io.WriteString(conn, "$"+strconv.Itoa(len(value))+"\r\n"+value+"\r\n")
+			} else {
+				io.WriteString(conn, "$-1\r\n")
+			}
+		case "SET":
+			s.values[args[1]] = args[2]
+			delete(s.expires, args[1])
+			if len(args) == 5 {
+				n, _ := strconv.Atoi(args[4])
+				switch strings.ToUpper(args[3]) {
+				case "PX":
+					s.expires[args[1]] = time.Now().Add(time.Duration(n) * time.Millisecond)
+				case "EX":
+					s.expires[args[1]] = time.Now().Add(time.Duration(n) * time.Second)
+				}
+			}
+			io.WriteString(conn, "+OK\r\n")
+		default:
+			io.WriteString(conn, "-ERR unknown command '"+args[0]+"'\r\n")
+		}
+		s.mu.Unlock()
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, n)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	server := newRedisServer(t, "s3cret")
+	backend, err := NewRedis(RedisConfig{URL: "redis://:s3cret@" + server.listener.Addr().String() + "/1"})
+	if err != nil {
+		t.Fatalf("NewRedis failed: %v", err)
+	}
+	defer backend.Close()
+	ctx := context.Background()
+
+	if err := backend.Set(ctx, "key", []byte("binary\r\n\x00value"), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if v, ok, err := backend.Get(ctx, "key"); err != nil || !ok || string(v) != "binary\r\n\x00value" {
+		t.Errorf("Expected the value back, got %q %v %v", v, ok, err)
+	}
+	if _, ok, err := backend.Get(ctx, "missing"); err != nil || ok {
+		t.Errorf("Expected a miss, got %v %v", ok, err)
+	}
+
+	backend.Set(ctx, "short", []byte("lived"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := backend.Get(ctx, "short"); ok {
+		t.Errorf("Expected the value to expire")
+	}
+
+	// a cache on another server sharing the backend sees the invalidation
+	next := newCountingRepository()
+	one, other := New(Config{Backend: backend}).Wrap(next), New(Config{Backend: backend}).Wrap(next)
+	acme := tenant("acme")
+	one.Create(acme, &model.Todo{Id: "t1", Title: "Old"})
+	other.Get(acme, "t1")
+	one.Update(acme, &model.Todo{Id: "t1", Title: "New"})
+	if todo, _ := other.Get(acme, "t1"); todo.Title != "New" {
+		t.Errorf("Expected the other cache to be invalidated, got %v", todo)
+	}
+}
+
+func TestRedisErrors(t *testing.T) {
+	server := newRedisServer(t, "s3cret")
+	backend, _ := NewRedis(RedisConfig{URL: "redis://:wrong@" + server.listener.Addr().String()})
+	if _, _, err := backend.Get(context.Background(), "key"); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Expected the server error, got %v", err)
+	}
+
+	if _, err := NewRedis(RedisConfig{URL: "http://redis"}); err == nil {
+		t.Errorf("Expected an invalid URL to be rejected")
+	}
+
+	// reads fall back to the repository while the backend is down
+	server.listener.Close()
+	next := newCountingRepository()
+	c := New(Config{Backend: backend})
+	repo := c.Wrap(next)
+	acme := tenant("acme")
+	repo.Create(acme, &model.Todo{Id: "t1"})
+	if _, err := repo.Get(acme, "t1"); err != nil {
+		t.Errorf("Expected the todo from the repository, got %v", err)
+	}
+	if c.Stats().Errors == 0 {
+		t.Errorf("Expected the backend errors to be counted")
+	}
+}
+
+func TestPostgresNotifier(t *testing.T) {
+	dsn := os.Getenv("TODOS_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TODOS_TEST_DATABASE_URL is not set")
+	}
+	sender, err := NewPostgresNotifier(dsn)
+	if err != nil {
+		t.Fatalf("NewPostgresNotifier failed: %v", err)
+	}
+	defer sender.Close()
+	receiver, err := NewPostgresNotifier(dsn)
+	if err != nil {
+		t.Fatalf("NewPostgresNotifier failed: %v", err)
+	}
+	defer receiver.Close()
+
+	// two servers with their own in-process caches
+	next := newCountingRepository()
+	one := New(Config{Backend: NewMemory(100), Notifier: sender}).Wrap(next)
+	otherCache := New(Config{Backend: NewMemory(100)})
+	other := otherCache.Wrap(next)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go receiver.Run(ctx, otherCache)
+
+	acme := tenant("acme")
+	one.Create(acme, &model.Todo{Id: "t1", Title: "Old"})
+	other.Get(acme, "t1")
+	one.Update(acme, &model.Todo{Id: "t1", Title: "New"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if todo, _ := other.Get(acme, "t1"); todo.Title == "New" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected the other cache to be invalidated by the notification")
+}
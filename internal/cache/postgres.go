@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// notifyChannel is the Postgres channel invalidations are sent on.
+const notifyChannel = "todos_cache"
+
+// PostgresNotifier sends invalidations to the other servers with Postgres
+// NOTIFY and receives theirs with LISTEN. Notifications are not transactional
+// with the write, so another server may serve the old value for the moment it
+// takes the notification to arrive.
+type PostgresNotifier struct {
+	db       *sql.DB
+	listener *pq.Listener
+	// id tells the notifications of this server from those of others.
+	id string
+}
+
+// NewPostgresNotifier connects to the database of dsn with lib/pq, whatever
+// driver the repository uses, and starts listening.
+func NewPostgresNotifier(dsn string) (*PostgresNotifier, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(2)
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Default().Println("cache: notification listener:", err)
+		}
+	})
+	if err := listener.Listen(notifyChannel); err != nil {
+		listener.Close()
+		db.Close()
+		return nil, err
+	}
+	return &PostgresNotifier{db: db, listener: listener, id: randomID()}, nil
+}
+
+// Notify tells the other servers that tenant changed.
+func (n *PostgresNotifier) Notify(ctx context.Context, tenant string) error {
+	_, err := n.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", notifyChannel, n.id+" "+tenant)
+	return err
+}
+
+// Run invalidates c for the notifications of other servers until ctx is
+// done. After the listener reconnected, it drops every entry, since
+// notifications may have been missed in between.
+func (n *PostgresNotifier) Run(ctx context.Context, c *Cache) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-n.listener.Notify:
+			if notification == nil {
+				c.InvalidateAll()
+				continue
+			}
+			sender, tenant, _ := strings.Cut(notification.Extra, " ")
+			if sender != n.id {
+				c.Invalidate(ctx, tenant)
+			}
+		}
+	}
+}
+
+// Close stops listening and closes the connections.
+func (n *PostgresNotifier) Close() {
+	n.listener.Close()
+	n.db.Close()
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// repository is the service.Repository returned by Cache.Wrap.
+type repository struct {
+	next  service.Repository
+	cache *Cache
+}
+
+// Unwrap returns the wrapped repository, for service.Wrapper.
+func (r *repository) Unwrap() service.Repository {
+	return r.next
+}
+
+func (r *repository) Get(ctx context.Context, id string) (model.Todo, error) {
+	return read(ctx, r.cache, "get/"+id, func(ctx context.Context) (model.Todo, error) {
+		return r.next.Get(ctx, id)
+	})
+}
+
+func (r *repository) List(ctx context.Context, opts model.ListOptions) ([]model.Todo, error) {
+	return read(ctx, r.cache, listKey(opts), func(ctx context.Context) ([]model.Todo, error) {
+		return r.next.List(ctx, opts)
+	})
+}
+
+// CommentCounts serves the comment counts of next, if it counts comments,
+// from the cache. Comments are written in units of work, which invalidate
+// the tenant like any other write.
+func (r *repository) CommentCounts(ctx context.Context, todoIDs []string) (map[string]int32, error) {
+	counter, ok := service.Lookup[service.CommentCounter](r.next)
+	if !ok {
+		return nil, nil
+	}
+	return read(ctx, r.cache, idsKey("comment-counts", todoIDs), func(ctx context.Context) (map[string]int32, error) {
+		return counter.CommentCounts(ctx, todoIDs)
+	})
+}
+
+// BlockedTodos serves the blocked todos of next, if it records
+// dependencies, from the cache. Dependencies are written in units of work,
+// and blockers completed with Update or Put, which all invalidate the
+// tenant.
+func (r *repository) BlockedTodos(ctx context.Context, todoIDs []string) (map[string]bool, error) {
+	checker, ok := service.Lookup[service.BlockedChecker](r.next)
+	if !ok {
+		return nil, nil
+	}
+	return read(ctx, r.cache, idsKey("blocked", todoIDs), func(ctx context.Context) (map[string]bool, error) {
+		return checker.BlockedTodos(ctx, todoIDs)
+	})
+}
+
+func (r *repository) AdjacentPosition(ctx context.Context, position, excludeID string, after bool) (string, error) {
+	return r.next.AdjacentPosition(ctx, position, excludeID, after)
+}
+
+func (r *repository) Create(ctx context.Context, t *model.Todo) (model.Todo, error) {
+	defer r.cache.changed(ctx)
+	return r.next.Create(ctx, t)
+}
+
+func (r *repository) CreateBatch(ctx context.Context, todos []*model.Todo) ([]model.Todo, error) {
+	defer r.cache.changed(ctx)
+	return r.next.CreateBatch(ctx, todos)
+}
+
+func (r *repository) Update(ctx context.Context, t *model.Todo) (model.Todo, error) {
+	defer r.cache.changed(ctx)
+	return r.next.Update(ctx, t)
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	defer r.cache.changed(ctx)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *repository) Move(ctx context.Context, id, position string) (model.Todo, error) {
+	defer r.cache.changed(ctx)
+	return r.next.Move(ctx, id, position)
+}
+
+func (r *repository) Rebalance(ctx context.Context) error {
+	defer r.cache.changed(ctx)
+	return r.next.Rebalance(ctx)
+}
+
+// WithinTx passes fn a unit of work of next, which reads around the cache,
+// and invalidates once the unit of work is over unless it was read only.
+func (r *repository) WithinTx(ctx context.Context, opts service.TxOptions, fn func(service.Repository) error) error {
+	if !opts.ReadOnly {
+		defer r.cache.changed(ctx)
+	}
+	return r.next.WithinTx(ctx, opts, fn)
+}
+
+// recordingRepository is a repository whose next is a
+// service.ChangeRecorder.
+type recordingRepository struct {
+	*repository
+	rec service.ChangeRecorder
+}
+
+func (r *recordingRepository) Changes(ctx context.Context, since model.ChangeCursor, limit int) ([]model.Todo, error) {
+	return r.rec.Changes(ctx, since, limit)
+}
+
+func (r *recordingRepository) Lookup(ctx context.Context, id string) (model.Todo, error) {
+	return r.rec.Lookup(ctx, id)
+}
+
+func (r *recordingRepository) Put(ctx context.Context, t *model.Todo, expected int64) (model.Todo, error) {
+	defer r.cache.changed(ctx)
+	return r.rec.Put(ctx, t, expected)
+}
+
+func (r *recordingRepository) LatestChange(ctx context.Context) (model.Watermark, error) {
+	return r.rec.LatestChange(ctx)
+}
@@ -2,6 +2,7 @@ package configs
 
 import (
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -13,12 +14,19 @@ func TestLoadConfig(t *testing.T) {
 	t.Setenv("DB_PASSWORD", "testpassword")
 	t.Setenv("DB_NAME", "testdb")
 	t.Setenv("DB_SSLMODE", "disable")
+	t.Setenv("DB_DRIVER", "pgx")
+	t.Setenv("DB_ADMIN_USER", "todos_owner")
+	t.Setenv("DB_ADMIN_PASSWORD", "ownerpassword")
+	t.Setenv("DB_SKIP_CREATE_DATABASE", "true")
 
 	config := LoadConfig()
 
 	if config.DB.Provider != "postgres" {
 		t.Errorf("Expected provider to be 'postgres', got '%s'", config.DB.Provider)
 	}
+	if config.DB.Driver != "pgx" {
+		t.Errorf("Expected driver to be 'pgx', got '%s'", config.DB.Driver)
+	}
 	if config.DB.Host != "localhost" {
 		t.Errorf("Expected host to be 'localhost', got '%s'", config.DB.Host)
 	}
@@ -31,10 +39,110 @@ func TestLoadConfig(t *testing.T) {
 	if config.DB.Password != "testpassword" {
 		t.Errorf("Expected password to be 'testpassword', got '%s'", config.DB.Password)
 	}
-	if config.DB.DBName != "postgres" {
-		t.Errorf("Expected dbname to be 'postgres', got '%s'", config.DB.DBName)
+	if config.DB.DBName != "testdb" {
+		t.Errorf("Expected dbname to be 'testdb', got '%s'", config.DB.DBName)
 	}
 	if config.DB.SSLMode != "disable" {
 		t.Errorf("Expected sslmode to be 'disable', got '%s'", config.DB.SSLMode)
 	}
+	if config.DB.AdminUser != "todos_owner" || config.DB.AdminPassword != "ownerpassword" {
+		t.Errorf("Expected admin user 'todos_owner' with its password, got '%s'", config.DB.AdminUser)
+	}
+	if !config.DB.SkipCreateDatabase {
+		t.Errorf("Expected database creation to be skipped")
+	}
+}
+
+func TestLoadConfigLimitsAndAuth(t *testing.T) {
+	t.Setenv("RATE_LIMIT_RPS", "2.5")
+	t.Setenv("RATE_LIMIT_BURST", "10")
+	t.Setenv("RATE_LIMIT_PROCEDURES", "List=5:10")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+	t.Setenv("MAX_TODOS_PER_USER", "500")
+	t.Setenv("ADMIN_TOKEN", "s3cret")
+	t.Setenv("AUTH_USER_HEADER", "X-Auth-Request-User")
+	t.Setenv("AUTH_TENANT_HEADER", "X-Auth-Request-Tenant")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "5")
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "true")
+	t.Setenv("OUTBOX_NATS_URL", "nats://nats:4222")
+	t.Setenv("OUTBOX_NATS_JETSTREAM", "true")
+	t.Setenv("OUTBOX_FILE", "/var/log/todos/events.ndjson")
+	t.Setenv("DB_MAX_OPEN_CONNS", "50")
+	t.Setenv("DB_MAX_IDLE_CONNS", "20")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "10m")
+	t.Setenv("DB_CONNECT_TIMEOUT", "2m")
+	t.Setenv("DB_REPLICA_HOSTS", "replica-1,replica-2:5433")
+	t.Setenv("DB_REPLICA_MAX_LAG", "30s")
+	t.Setenv("DB_REPLICA_STICKY_WINDOW", "2s")
+	t.Setenv("ATTACHMENT_STORE", "s3")
+	t.Setenv("ATTACHMENT_MAX_BYTES", "10485760")
+	t.Setenv("ATTACHMENT_MAX_BYTES_PER_USER", "1073741824")
+	t.Setenv("ATTACHMENT_RETENTION", "168h")
+	t.Setenv("ATTACHMENT_S3_ENDPOINT", "http://minio:9000")
+	t.Setenv("ATTACHMENT_S3_BUCKET", "todos")
+	t.Setenv("ATTACHMENT_S3_PATH_STYLE", "true")
+
+	config := LoadConfig()
+
+	if config.RateLimit.RPS != 2.5 {
+		t.Errorf("Expected rps to be 2.5, got %v", config.RateLimit.RPS)
+	}
+	if config.RateLimit.Burst != 10 {
+		t.Errorf("Expected burst to be 10, got %d", config.RateLimit.Burst)
+	}
+	if config.RateLimit.Procedures != "List=5:10" {
+		t.Errorf("Expected procedures to be 'List=5:10', got '%s'", config.RateLimit.Procedures)
+	}
+	if config.RateLimit.TrustedProxies != "10.0.0.0/8" {
+		t.Errorf("Expected trusted proxies to be '10.0.0.0/8', got '%s'", config.RateLimit.TrustedProxies)
+	}
+	if config.MaxTodosPerUser != 500 {
+		t.Errorf("Expected max todos per user to be 500, got %d", config.MaxTodosPerUser)
+	}
+	if config.AdminToken != "s3cret" {
+		t.Errorf("Expected admin token to be 's3cret', got '%s'", config.AdminToken)
+	}
+	if config.UserHeader != "X-Auth-Request-User" {
+		t.Errorf("Expected user header to be 'X-Auth-Request-User', got '%s'", config.UserHeader)
+	}
+	if config.TenantHeader != "X-Auth-Request-Tenant" {
+		t.Errorf("Expected tenant header to be 'X-Auth-Request-Tenant', got '%s'", config.TenantHeader)
+	}
+	if config.Webhooks.MaxAttempts != 5 {
+		t.Errorf("Expected webhook max attempts to be 5, got %d", config.Webhooks.MaxAttempts)
+	}
+	if !config.Webhooks.AllowPrivateNetworks {
+		t.Errorf("Expected private networks to be allowed for webhooks")
+	}
+	if config.Outbox.NATSURL != "nats://nats:4222" || !config.Outbox.NATSJetStream {
+		t.Errorf("Expected the NATS outbox publisher with JetStream, got %+v", config.Outbox)
+	}
+	if config.Outbox.File != "/var/log/todos/events.ndjson" {
+		t.Errorf("Expected outbox file to be '/var/log/todos/events.ndjson', got '%s'", config.Outbox.File)
+	}
+	if config.DB.MaxOpenConns != 50 || config.DB.MaxIdleConns != 20 {
+		t.Errorf("Expected 50 open and 20 idle connections, got %d and %d", config.DB.MaxOpenConns, config.DB.MaxIdleConns)
+	}
+	if config.DB.ConnMaxLifetime != time.Hour || config.DB.ConnMaxIdleTime != 10*time.Minute {
+		t.Errorf("Expected a connection lifetime of 1h and idle time of 10m, got %v and %v", config.DB.ConnMaxLifetime, config.DB.ConnMaxIdleTime)
+	}
+	if config.DB.ConnectTimeout != 2*time.Minute {
+		t.Errorf("Expected a connect timeout of 2m, got %v", config.DB.ConnectTimeout)
+	}
+	if config.DB.ReplicaHosts != "replica-1,replica-2:5433" {
+		t.Errorf("Expected replica hosts to be 'replica-1,replica-2:5433', got '%s'", config.DB.ReplicaHosts)
+	}
+	if config.DB.ReplicaMaxLag != 30*time.Second || config.DB.ReplicaStickyWindow != 2*time.Second {
+		t.Errorf("Expected replica max lag 30s and sticky window 2s, got %v and %v", config.DB.ReplicaMaxLag, config.DB.ReplicaStickyWindow)
+	}
+	if config.Attachments.MaxBytes != 10<<20 || config.Attachments.MaxBytesPerUser != 1<<30 {
+		t.Errorf("Expected attachment limits of 10 MiB and 1 GiB, got %d and %d", config.Attachments.MaxBytes, config.Attachments.MaxBytesPerUser)
+	}
+	if config.Attachments.Retention != 7*24*time.Hour {
+		t.Errorf("Expected an attachment retention of 168h, got %v", config.Attachments.Retention)
+	}
+	if config.Attachments.Store != "s3" || config.Attachments.S3Endpoint != "http://minio:9000" || config.Attachments.S3Bucket != "todos" || !config.Attachments.S3PathStyle {
+		t.Errorf("Expected the path-style S3 attachment store, got %+v", config.Attachments)
+	}
 }
@@ -3,6 +3,7 @@ package configs
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 const ConfigFilePath = "configs/config.json"
@@ -10,17 +11,130 @@ const ConfigFilePath = "configs/config.json"
 // DBConfig holds the database configuration
 type DBConfig struct {
 	Provider string `json:"provider"`
+	// Driver selects the repository: "pgx" for the native pgx one, anything
+	// else for database/sql with lib/pq.
+	Driver   string `json:"driver"`
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	User     string `json:"user"`
 	Password string `json:"password"`
 	DBName   string `json:"dbname"`
 	SSLMode  string `json:"sslmode"`
+	// AdminUser and AdminPassword, if set, create the database and its
+	// tables, so User only needs to read and write rows. AdminDBName is the
+	// database the admin connects to in order to create DBName; "postgres"
+	// by default.
+	AdminUser     string `json:"admin_user"`
+	AdminPassword string `json:"admin_password"`
+	AdminDBName   string `json:"admin_dbname"`
+	// SkipCreateDatabase expects DBName to exist, for managed Postgres where
+	// no user may create databases.
+	SkipCreateDatabase bool `json:"skip_create_database"`
+	// MaxOpenConns, MaxIdleConns, ConnMaxLifetime and ConnMaxIdleTime size
+	// the connection pool; 0 means the default.
+	MaxOpenConns    int           `json:"max_open_conns"`
+	MaxIdleConns    int           `json:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time"`
+	// ConnectTimeout bounds waiting for the database at startup.
+	ConnectTimeout time.Duration `json:"connect_timeout"`
+	// ReplicaHosts lists read replicas as comma separated "host" or
+	// "host:port"; they share the user, password and database of the
+	// primary.
+	ReplicaHosts string `json:"replica_hosts"`
+	// ReplicaMaxLag and ReplicaStickyWindow tune read routing; 0 means the
+	// default.
+	ReplicaMaxLag       time.Duration `json:"replica_max_lag"`
+	ReplicaStickyWindow time.Duration `json:"replica_sticky_window"`
+}
+
+// RateLimitConfig holds the request rate limits
+type RateLimitConfig struct {
+	// RPS and Burst are the default limit per client; an RPS of 0 disables it.
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst"`
+	// Procedures overrides the limit per procedure, e.g. "List=5:10".
+	Procedures string `json:"procedures"`
+	// TrustedProxies lists the CIDRs whose X-Forwarded-For header is honored.
+	TrustedProxies string `json:"trusted_proxies"`
+}
+
+// WebhookConfig holds the webhook delivery settings
+type WebhookConfig struct {
+	// MaxAttempts is the number of attempts before a delivery is dead; 0
+	// means the default.
+	MaxAttempts int `json:"max_attempts"`
+	// AllowPrivateNetworks lets webhooks point at private addresses.
+	AllowPrivateNetworks bool `json:"allow_private_networks"`
+}
+
+// OutboxConfig holds where outbox events are published besides the
+// in-process bus
+type OutboxConfig struct {
+	// NATSURL, if set, also publishes every event to NATS on
+	// "<NATSSubject>.<event type>".
+	NATSURL     string `json:"nats_url"`
+	NATSSubject string `json:"nats_subject"`
+	// NATSJetStream waits for a JetStream acknowledgement of every event.
+	NATSJetStream bool `json:"nats_jetstream"`
+	// File, if set, also appends every event to this file as JSON lines.
+	File string `json:"file"`
+}
+
+// CacheConfig holds the read-through cache settings
+type CacheConfig struct {
+	// Backend is "memory" for an in-process cache per server, kept in step
+	// with Postgres notifications, or "redis" for one shared through
+	// RedisURL; anything else disables the cache.
+	Backend  string `json:"backend"`
+	RedisURL string `json:"redis_url"`
+	// TTL and MaxEntries bound how long and how many entries are kept; 0
+	// means the default. MaxEntries only applies to the memory backend.
+	TTL        time.Duration `json:"ttl"`
+	MaxEntries int           `json:"max_entries"`
+}
+
+// AttachmentConfig holds where and how much attachment content is stored
+type AttachmentConfig struct {
+	// Store is "fs" to keep content in files under Dir, or "s3" for a bucket
+	// of an S3-compatible service; anything else disables attachments.
+	Store string `json:"store"`
+	Dir   string `json:"dir"`
+	// MaxBytes caps each attachment and MaxBytesPerUser all attachments of a
+	// client; 0 means the default and no cap respectively.
+	MaxBytes        int64 `json:"max_bytes"`
+	MaxBytesPerUser int64 `json:"max_bytes_per_user"`
+	// Retention is how long the attachments of deleted todos are kept; 0
+	// means the default.
+	Retention time.Duration `json:"retention"`
+	// S3Endpoint, S3Bucket, S3Region and the keys address the bucket;
+	// S3PathStyle addresses it by path, as MinIO expects.
+	S3Endpoint        string `json:"s3_endpoint"`
+	S3Bucket          string `json:"s3_bucket"`
+	S3Region          string `json:"s3_region"`
+	S3AccessKeyID     string `json:"s3_access_key_id"`
+	S3SecretAccessKey string `json:"s3_secret_access_key"`
+	S3PathStyle       bool   `json:"s3_path_style"`
 }
 
 // Config holds the entire config structure
 type Config struct {
-	DB DBConfig `json:"db"`
+	DB        DBConfig        `json:"db"`
+	RateLimit RateLimitConfig `json:"rate_limit"`
+	Webhooks  WebhookConfig   `json:"webhooks"`
+	Outbox    OutboxConfig    `json:"outbox"`
+	Cache     CacheConfig     `json:"cache"`
+	// Attachments configures file attachments of todos.
+	Attachments AttachmentConfig `json:"attachments"`
+	// MaxTodosPerUser caps the todos a client may create; 0 means no cap.
+	MaxTodosPerUser int `json:"max_todos_per_user"`
+	// AdminToken is a bearer token allowed to manage API keys.
+	AdminToken string `json:"admin_token"`
+	// UserHeader and EmailHeader carry the user signed in by the auth proxy.
+	UserHeader  string `json:"user_header"`
+	EmailHeader string `json:"email_header"`
+	// TenantHeader carries the tenant of the user signed in by the auth proxy.
+	TenantHeader string `json:"tenant_header"`
 }
 
 // LoadConfig loads the configuration from config.json file
@@ -44,15 +158,91 @@ func LoadConfig() *Config {
 	// 	log.Fatalf("Failed to parse config file: %v", err)
 	// }
 	port, _ := strconv.Atoi(os.Getenv("DB_PORT"))
+	rps, _ := strconv.ParseFloat(os.Getenv("RATE_LIMIT_RPS"), 64)
+	burst, _ := strconv.Atoi(os.Getenv("RATE_LIMIT_BURST"))
+	maxTodos, _ := strconv.Atoi(os.Getenv("MAX_TODOS_PER_USER"))
+	maxAttempts, _ := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	allowPrivate, _ := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS"))
+	jetStream, _ := strconv.ParseBool(os.Getenv("OUTBOX_NATS_JETSTREAM"))
+	skipCreate, _ := strconv.ParseBool(os.Getenv("DB_SKIP_CREATE_DATABASE"))
+	maxOpenConns, _ := strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS"))
+	maxIdleConns, _ := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS"))
+	connMaxLifetime, _ := time.ParseDuration(os.Getenv("DB_CONN_MAX_LIFETIME"))
+	connMaxIdleTime, _ := time.ParseDuration(os.Getenv("DB_CONN_MAX_IDLE_TIME"))
+	connectTimeout, _ := time.ParseDuration(os.Getenv("DB_CONNECT_TIMEOUT"))
+	maxLag, _ := time.ParseDuration(os.Getenv("DB_REPLICA_MAX_LAG"))
+	stickyWindow, _ := time.ParseDuration(os.Getenv("DB_REPLICA_STICKY_WINDOW"))
+	cacheTTL, _ := time.ParseDuration(os.Getenv("CACHE_TTL"))
+	cacheMaxEntries, _ := strconv.Atoi(os.Getenv("CACHE_MAX_ENTRIES"))
+	attachmentMaxBytes, _ := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_BYTES"), 10, 64)
+	attachmentMaxBytesPerUser, _ := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_BYTES_PER_USER"), 10, 64)
+	attachmentRetention, _ := time.ParseDuration(os.Getenv("ATTACHMENT_RETENTION"))
+	s3PathStyle, _ := strconv.ParseBool(os.Getenv("ATTACHMENT_S3_PATH_STYLE"))
 	return &Config{
 		DB: DBConfig{
 			Provider: os.Getenv("DB_PROVIDER"),
+			Driver:   os.Getenv("DB_DRIVER"),
 			Host:     os.Getenv("DB_HOST"),
 			Port:     port,
 			User:     os.Getenv("DB_USER"),
 			Password: os.Getenv("DB_PASSWORD"),
 			SSLMode:  os.Getenv("DB_SSLMODE"),
-			DBName:   os.Getenv("DB_PROVIDER"),
+			DBName:   os.Getenv("DB_NAME"),
+
+			AdminUser:          os.Getenv("DB_ADMIN_USER"),
+			AdminPassword:      os.Getenv("DB_ADMIN_PASSWORD"),
+			AdminDBName:        os.Getenv("DB_ADMIN_DBNAME"),
+			SkipCreateDatabase: skipCreate,
+
+			MaxOpenConns:    maxOpenConns,
+			MaxIdleConns:    maxIdleConns,
+			ConnMaxLifetime: connMaxLifetime,
+			ConnMaxIdleTime: connMaxIdleTime,
+			ConnectTimeout:  connectTimeout,
+
+			ReplicaHosts:        os.Getenv("DB_REPLICA_HOSTS"),
+			ReplicaMaxLag:       maxLag,
+			ReplicaStickyWindow: stickyWindow,
+		},
+		RateLimit: RateLimitConfig{
+			RPS:            rps,
+			Burst:          burst,
+			Procedures:     os.Getenv("RATE_LIMIT_PROCEDURES"),
+			TrustedProxies: os.Getenv("TRUSTED_PROXIES"),
+		},
+		Webhooks: WebhookConfig{
+			MaxAttempts:          maxAttempts,
+			AllowPrivateNetworks: allowPrivate,
+		},
+		Outbox: OutboxConfig{
+			NATSURL:       os.Getenv("OUTBOX_NATS_URL"),
+			NATSSubject:   os.Getenv("OUTBOX_NATS_SUBJECT"),
+			NATSJetStream: jetStream,
+			File:          os.Getenv("OUTBOX_FILE"),
+		},
+		Cache: CacheConfig{
+			Backend:    os.Getenv("CACHE_BACKEND"),
+			RedisURL:   os.Getenv("CACHE_REDIS_URL"),
+			TTL:        cacheTTL,
+			MaxEntries: cacheMaxEntries,
+		},
+		Attachments: AttachmentConfig{
+			Store:             os.Getenv("ATTACHMENT_STORE"),
+			Dir:               os.Getenv("ATTACHMENT_DIR"),
+			MaxBytes:          attachmentMaxBytes,
+			MaxBytesPerUser:   attachmentMaxBytesPerUser,
+			Retention:         attachmentRetention,
+			S3Endpoint:        os.Getenv("ATTACHMENT_S3_ENDPOINT"),
+			S3Bucket:          os.Getenv("ATTACHMENT_S3_BUCKET"),
+			S3Region:          os.Getenv("ATTACHMENT_S3_REGION"),
+			S3AccessKeyID:     os.Getenv("ATTACHMENT_S3_ACCESS_KEY_ID"),
+			S3SecretAccessKey: os.Getenv("ATTACHMENT_S3_SECRET_ACCESS_KEY"),
+			S3PathStyle:       s3PathStyle,
 		},
+		MaxTodosPerUser: maxTodos,
+		AdminToken:      os.Getenv("ADMIN_TOKEN"),
+		UserHeader:      os.Getenv("AUTH_USER_HEADER"),
+		EmailHeader:     os.Getenv("AUTH_EMAIL_HEADER"),
+		TenantHeader:    os.Getenv("AUTH_TENANT_HEADER"),
 	}
 }
@@ -1,126 +1,565 @@
 package db
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/haakaashs/todos-backend/internal/auth"
 	configs "github.com/haakaashs/todos-backend/internal/configs"
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lib/pq"
 )
 
 const (
-	maxRetries    = 10
-	retryInterval = 3 * time.Second
+	// initialRetryDelay and maxRetryDelay bound the backoff while waiting
+	// for Postgres.
+	initialRetryDelay = 250 * time.Millisecond
+	maxRetryDelay     = 10 * time.Second
+
+	defaultMaxOpenConns    = 25
+	defaultMaxIdleConns    = 10
+	defaultConnMaxLifetime = 30 * time.Minute
+	defaultConnMaxIdleTime = 5 * time.Minute
+
+	// defaultAdminDBName is the database the admin user connects to in order
+	// to create the application database.
+	defaultAdminDBName = "postgres"
 )
 
 var config *configs.Config
 
-// ensureDatabase checks if the target database exists, and creates it if not
-func ensureDatabase(db *sql.DB) error {
+// ensureDatabase creates the database name unless it exists. The name is
+// looked up as a parameter and quoted as an identifier, so it may hold any
+// character.
+func ensureDatabase(ctx context.Context, db *sql.DB, name string) error {
 	var exists bool
-	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = '%s')", config.DB.DBName)
-	err := db.QueryRow(query).Scan(&exists)
+	err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", name).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("error checking database existence: %w", err)
 	}
 
 	if !exists {
-		_, err := db.Exec(fmt.Sprintf("CREATE DATABASE %s", config.DB.DBName))
+		_, err := db.ExecContext(ctx, "CREATE DATABASE "+pq.QuoteIdentifier(name))
 		if err != nil {
-			return fmt.Errorf("error creating database %s: %w", config.DB.DBName, err)
+			return fmt.Errorf("error creating database %s: %w", name, err)
 		}
-		log.Printf("Database %s created", config.DB.DBName)
+		log.Printf("Database %s created", name)
 	} else {
-		log.Printf("Database %s already exists", config.DB.DBName)
+		log.Printf("Database %s already exists", name)
 	}
 
 	return nil
 }
 
-// ensureTables creates tables if they do not exist
-func ensureTables(db *sql.DB) error {
-	tableSQL := `
+// grantAccess lets user read and write the rows of every table, without
+// owning them or being able to change the schema.
+func grantAccess(ctx context.Context, db *sql.DB, user string) error {
+	role := pq.QuoteIdentifier(user)
+	for _, stmt := range []string{
+		"GRANT USAGE ON SCHEMA public TO " + role,
+		"GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO " + role,
+		"GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO " + role,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("error granting access to %s: %w", user, err)
+		}
+	}
+	return nil
+}
+
+// EnsureTables creates tables if they do not exist
+func EnsureTables(db *sql.DB) error {
+	tableSQL := []string{`
 	CREATE TABLE IF NOT EXISTS todos (
 		id UUID PRIMARY KEY,
 		title TEXT NOT NULL,
 		completed BOOLEAN NOT NULL DEFAULT FALSE,
 		created_at TIMESTAMP NOT NULL DEFAULT NOW()
-	);`
+	);`,
+		// every write stamps the row with the next value of todos_version_seq so
+		// clients can ask for "everything changed since version N"
+		`CREATE SEQUENCE IF NOT EXISTS todos_version_seq;`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT nextval('todos_version_seq');`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;`,
+		`CREATE INDEX IF NOT EXISTS todos_version_idx ON todos (version);`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0;`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS position TEXT NOT NULL DEFAULT '';`,
+		// rank todos created before manual ordering existed, newest first,
+		// using fixed-width hex which is a valid fractional index
+		`UPDATE todos SET position = ranked.position
+		FROM (
+			SELECT id, lpad(to_hex(row_number() OVER (ORDER BY created_at DESC)), 8, '0') || 'V' AS position
+			FROM todos
+			WHERE position = ''
+		) ranked
+		WHERE todos.id = ranked.id;`,
+		`CREATE INDEX IF NOT EXISTS todos_position_idx ON todos (position COLLATE "C");`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS rrule TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS recurrence_mode SMALLINT NOT NULL DEFAULT 0;`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS series_id UUID;`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS occurrence INTEGER NOT NULL DEFAULT 1;`,
+		`UPDATE todos SET series_id = id WHERE series_id IS NULL;`,
+		// completing the same occurrence twice must not spawn two successors
+		`CREATE UNIQUE INDEX IF NOT EXISTS todos_series_occurrence_idx ON todos (series_id, occurrence);`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();`,
+		`CREATE TABLE IF NOT EXISTS calendar_feeds (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			token_hash BYTEA NOT NULL UNIQUE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			revoked_at TIMESTAMPTZ
+		);`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';`,
+		// the subject a feed shows the todos of; feeds without one show none
+		`ALTER TABLE calendar_feeds ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';`,
+		`CREATE INDEX IF NOT EXISTS todos_created_by_idx ON todos (created_by) WHERE deleted_at IS NULL;`,
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL UNIQUE,
+			scopes TEXT[] NOT NULL,
+			expires_at TIMESTAMPTZ,
+			salt BYTEA NOT NULL,
+			secret_hash BYTEA NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			last_used_at TIMESTAMPTZ,
+			revoked_at TIMESTAMPTZ
+		);`,
+		`CREATE TABLE IF NOT EXISTS lists (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE TABLE IF NOT EXISTS list_members (
+			list_id UUID NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			role SMALLINT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (list_id, user_id)
+		);`,
+		`CREATE INDEX IF NOT EXISTS list_members_user_idx ON list_members (user_id);`,
+		`CREATE TABLE IF NOT EXISTS list_invitations (
+			id UUID PRIMARY KEY,
+			list_id UUID NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL DEFAULT '',
+			email TEXT NOT NULL DEFAULT '',
+			role SMALLINT NOT NULL,
+			invited_by TEXT NOT NULL,
+			status SMALLINT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE INDEX IF NOT EXISTS list_invitations_user_idx ON list_invitations (user_id) WHERE status = 1;`,
+		`CREATE INDEX IF NOT EXISTS list_invitations_email_idx ON list_invitations (lower(email)) WHERE status = 1;`,
+		// todos outside any list keep the behaviour they had before sharing
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS list_id UUID REFERENCES lists (id);`,
+		`CREATE INDEX IF NOT EXISTS todos_list_idx ON todos (list_id) WHERE list_id IS NOT NULL;`,
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id UUID PRIMARY KEY,
+			url TEXT NOT NULL,
+			events TEXT[] NOT NULL,
+			secret TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id UUID PRIMARY KEY,
+			webhook_id UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+			event TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status SMALLINT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_status_code INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMPTZ,
+			delivered_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		// the subject whose todos a webhook hears about; webhooks without one
+		// hear about none
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';`,
+		`CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 1;`,
+		`CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at DESC);`,
+		// events written in the transaction of the change they describe, and
+		// deleted once the relay has published them
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id BIGSERIAL PRIMARY KEY,
+			event_id UUID NOT NULL,
+			aggregate_id TEXT NOT NULL,
+			event TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+		// the content of an attachment is a blob stored under blob_key; rows
+		// are deleted once the purge has removed the blob
+		`CREATE TABLE IF NOT EXISTS attachments (
+			id UUID PRIMARY KEY,
+			todo_id UUID NOT NULL REFERENCES todos (id),
+			filename TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			size BIGINT NOT NULL,
+			sha256 TEXT NOT NULL,
+			blob_key TEXT NOT NULL,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			deleted_at TIMESTAMPTZ
+		);`,
+		`CREATE TABLE IF NOT EXISTS comments (
+			id UUID PRIMARY KEY,
+			todo_id UUID NOT NULL REFERENCES todos (id),
+			author TEXT NOT NULL,
+			body TEXT NOT NULL,
+			mentions TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			edited_at TIMESTAMPTZ,
+			deleted_at TIMESTAMPTZ
+		);`,
+		// todo_id cannot be completed before blocked_by_id
+		`CREATE TABLE IF NOT EXISTS dependencies (
+			todo_id UUID NOT NULL REFERENCES todos (id),
+			blocked_by_id UUID NOT NULL REFERENCES todos (id),
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (todo_id, blocked_by_id),
+			CHECK (todo_id <> blocked_by_id)
+		);`,
+	}
+	for _, table := range tenantTables {
+		tableSQL = append(tableSQL, tenantSQL(table)...)
+	}
+	tableSQL = append(tableSQL,
+		`CREATE INDEX IF NOT EXISTS todos_tenant_version_idx ON todos (tenant_id, version);`,
+		// versions are drawn before commit, so sync reads changes in the order
+		// of the transactions that wrote them instead; rows written before
+		// this column existed sort first
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS txid BIGINT NOT NULL DEFAULT 0;`,
+		`CREATE OR REPLACE FUNCTION todos_stamp_txid() RETURNS trigger AS $$
+		BEGIN
+			NEW.txid := pg_current_xact_id()::text::bigint;
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE OR REPLACE TRIGGER todos_stamp_txid BEFORE INSERT OR UPDATE ON todos
+			FOR EACH ROW EXECUTE FUNCTION todos_stamp_txid();`,
+		`CREATE INDEX IF NOT EXISTS todos_tenant_txid_idx ON todos (tenant_id, txid, version);`,
+		`CREATE INDEX IF NOT EXISTS todos_tenant_position_idx ON todos (tenant_id, position COLLATE "C");`,
+		// Markdown, with the HTML, links and checklist rendered from it
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS description_html TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS links JSONB NOT NULL DEFAULT '[]';`,
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS checklist JSONB NOT NULL DEFAULT '[]';`,
+		// the 'simple' configuration neither stems nor drops stop words, so
+		// search works the same in every language; List queries the same
+		// expression
+		`CREATE INDEX IF NOT EXISTS todos_search_idx ON todos
+			USING GIN (to_tsvector('simple', title || ' ' || description)) WHERE deleted_at IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS attachments_todo_idx ON attachments (todo_id, created_at) WHERE deleted_at IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS attachments_created_by_idx ON attachments (created_by) WHERE deleted_at IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS attachments_deleted_idx ON attachments (deleted_at) WHERE deleted_at IS NOT NULL;`,
+		// serves both the pages of a todo's comments and the comment counts
+		`CREATE INDEX IF NOT EXISTS comments_todo_idx ON comments (todo_id, created_at, id) WHERE deleted_at IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS dependencies_blocked_by_idx ON dependencies (blocked_by_id);`,
+	)
 
-	_, err := db.Exec(tableSQL)
-	if err != nil {
-		return fmt.Errorf("error creating table todos: %w", err)
+	for _, stmt := range tableSQL {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("error creating tables: %w", err)
+		}
 	}
 
 	log.Println("Tables ensured")
 	return nil
 }
 
-// Prerequisite ensures database and tables exist
-func prerequisite(db *sql.DB) {
-	err := ensureDatabase(db)
-	if err != nil {
-		log.Default().Fatalf("Failed to ensure database exists: %v", err)
+// tenantTables are the tables whose rows belong to a tenant.
+var tenantTables = []string{"todos", "calendar_feeds", "api_keys", "lists", "list_members", "list_invitations", "webhooks", "webhook_deliveries", "outbox", "attachments", "comments", "dependencies"}
+
+// tenantSQL adds a tenant_id column to table, assigning existing rows to the
+// default tenant, and a row-level security policy that only shows rows of
+// the tenant in app.tenant_id. FORCE applies the policy to the table owner
+// too, but superusers and roles with BYPASSRLS still skip it. Backfills
+// placed after this statement run without a tenant and see no rows.
+//
+// API keys and calendar feeds are also readable by transactions that set
+// app.credential_lookup, which resolve a token to its tenant, and webhooks
+// and their deliveries by transactions that set app.webhook_dispatch, which
+// send the due deliveries of every tenant. The outbox is also readable by
+// transactions that set app.outbox_relay, which publish the events of every
+// tenant. Attachments, and the todos they belong to, are also visible to
+// transactions that set app.attachment_purge, which remove the attachments
+// of every tenant that were deleted. Every table is readable and writable by transactions that set
+// app.backup, which back up and restore all tenants at once.
+func tenantSQL(table string) []string {
+	stmts := []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '%s';`, table, auth.DefaultTenant),
+		fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN tenant_id DROP DEFAULT;`, table),
+		fmt.Sprintf(`ALTER TABLE %s ENABLE ROW LEVEL SECURITY;`, table),
+		fmt.Sprintf(`ALTER TABLE %s FORCE ROW LEVEL SECURITY;`, table),
+		createPolicy(table, "tenant_isolation",
+			`USING (tenant_id = current_setting('app.tenant_id', true))
+			WITH CHECK (tenant_id = current_setting('app.tenant_id', true))`),
+		createPolicy(table, "backup",
+			`USING (current_setting('app.backup', true) = 'on')
+			WITH CHECK (current_setting('app.backup', true) = 'on')`),
+	}
+	if table == "api_keys" || table == "calendar_feeds" {
+		stmts = append(stmts, createPolicy(table, "credential_lookup",
+			`FOR SELECT USING (current_setting('app.credential_lookup', true) = 'on')`))
+	}
+	if table == "webhooks" || table == "webhook_deliveries" {
+		stmts = append(stmts, createPolicy(table, "webhook_dispatch",
+			`USING (current_setting('app.webhook_dispatch', true) = 'on')`))
 	}
+	if table == "outbox" {
+		stmts = append(stmts, createPolicy(table, "outbox_relay",
+			`USING (current_setting('app.outbox_relay', true) = 'on')`))
+	}
+	if table == "attachments" {
+		stmts = append(stmts, createPolicy(table, "attachment_purge",
+			`USING (current_setting('app.attachment_purge', true) = 'on')`))
+	}
+	if table == "todos" {
+		stmts = append(stmts, createPolicy(table, "attachment_purge",
+			`FOR SELECT USING (current_setting('app.attachment_purge', true) = 'on')`))
+	}
+	return stmts
+}
 
-	err = ensureTables(db)
-	if err != nil {
-		log.Default().Fatalf("Failed to ensure tables exist: %v", err)
+// createPolicy creates a policy unless it exists; CREATE POLICY has no
+// IF NOT EXISTS.
+func createPolicy(table, name, definition string) string {
+	return fmt.Sprintf(`DO $$ BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_policies WHERE tablename = '%[1]s' AND policyname = '%[2]s') THEN
+			CREATE POLICY %[2]s ON %[1]s %[3]s;
+		END IF;
+	END $$;`, table, name, definition)
+}
+
+// dsn builds a libpq connection string to dbname on the host of config.
+// Every value is quoted, so passwords and names with spaces or quotes
+// survive.
+func dsn(config configs.DBConfig, user, password, dbname string) string {
+	params := []string{
+		"host", config.Host,
+		"user", user,
+		"password", password,
+		"dbname", dbname,
+		"sslmode", config.SSLMode,
+	}
+	if config.Port != 0 {
+		params = append(params, "port", strconv.Itoa(config.Port))
 	}
 
-	log.Println("Database and tables ready")
+	var b strings.Builder
+	for i := 0; i < len(params); i += 2 {
+		if params[i+1] == "" {
+			continue
+		}
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(params[i+1])
+		fmt.Fprintf(&b, "%s='%s' ", params[i], value)
+	}
+	return strings.TrimSpace(b.String())
 }
 
-// getDSN constructs the DSN for connecting to the default "postgres" database
-func getDSN(config *configs.Config) string {
-	// Connect to default "postgres" database to create the target DB
-	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		config.DB.Host,
-		config.DB.Port,
-		config.DB.User,
-		config.DB.Password,
-		config.DB.DBName,
-		config.DB.SSLMode,
-	)
+// appDSN connects to the application database as the application user.
+func appDSN(config configs.DBConfig) string {
+	return dsn(config, config.User, config.Password, config.DBName)
+}
+
+// DSN returns the connection string of the database opened by InitializeDB
+// or InitializePool.
+func DSN() string {
+	return appDSN(config.DB)
 }
 
-func InitializeDB() *sql.DB {
+// adminDSN connects to dbname as the admin user, or as the application user
+// if no admin user is configured.
+func adminDSN(config configs.DBConfig, dbname string) string {
+	if config.AdminUser == "" {
+		return dsn(config, config.User, config.Password, dbname)
+	}
+	return dsn(config, config.AdminUser, config.AdminPassword, dbname)
+}
+
+// InitializeDB prepares the application database with bootstrap and
+// connects to it with database/sql, waiting for Postgres to come up until
+// ctx is done.
+func InitializeDB(ctx context.Context) (*sql.DB, error) {
 	config = configs.LoadConfig()
+	c := config.DB
+	if err := bootstrap(ctx, c); err != nil {
+		return nil, err
+	}
+
+	db, err := open(ctx, c.Provider, appDSN(c))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database %s: %w", c.DBName, err)
+	}
+	configurePool(db, c)
+	log.Println("Connected to target database successfully")
+	return db, nil
+}
 
-	// Step 1: connect to default DB (postgres)
-	adminDB, err := sql.Open(config.DB.Provider, getDSN(config))
+// Connect connects to the application database like InitializeDB, but
+// without bootstrap: it neither creates the database nor ensures its tables,
+// and fails if Postgres does not come up before ctx is done.
+func Connect(ctx context.Context) (*sql.DB, error) {
+	config = configs.LoadConfig()
+	c := config.DB
+	db, err := open(ctx, c.Provider, appDSN(c))
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("connecting to database %s: %w", c.DBName, err)
 	}
+	configurePool(db, c)
+	return db, nil
+}
 
-	// wait until the DB is ready
-	for range maxRetries {
-		if err := adminDB.Ping(); err == nil {
-			log.Println("Connected to Postgres for DB creation")
-			break
+// InitializePool is InitializeDB for the pgx driver. MaxIdleConns does not
+// apply to its pool.
+func InitializePool(ctx context.Context) (*pgxpool.Pool, error) {
+	config = configs.LoadConfig()
+	c := config.DB
+	if err := bootstrap(ctx, c); err != nil {
+		return nil, err
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(appDSN(c))
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.MaxConns = int32(cmp.Or(c.MaxOpenConns, defaultMaxOpenConns))
+	poolConfig.MaxConnLifetime = cmp.Or(c.ConnMaxLifetime, defaultConnMaxLifetime)
+	poolConfig.MaxConnIdleTime = cmp.Or(c.ConnMaxIdleTime, defaultConnMaxIdleTime)
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := wait(ctx, pool.Ping); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("connecting to database %s: %w", c.DBName, err)
+	}
+	log.Println("Connected to target database successfully")
+	return pool, nil
+}
+
+// bootstrap creates the application database if it does not exist, unless
+// SkipCreateDatabase is set, and ensures its tables. The tables are ensured
+// by the admin user, which then grants the application user access to their
+// rows, or by the application user itself if no admin user is configured.
+func bootstrap(ctx context.Context, c configs.DBConfig) error {
+	if c.DBName == "" {
+		return errors.New("no database name configured")
+	}
+
+	if !c.SkipCreateDatabase {
+		adminDB, err := open(ctx, c.Provider, adminDSN(c, cmp.Or(c.AdminDBName, defaultAdminDBName)))
+		if err != nil {
+			return fmt.Errorf("connecting to Postgres: %w", err)
+		}
+		log.Println("Connected to Postgres for DB creation")
+		err = ensureDatabase(ctx, adminDB, c.DBName)
+		adminDB.Close()
+		if err != nil {
+			return err
 		}
-		log.Printf("Waiting for Postgres to be ready... (%v)", err)
-		time.Sleep(retryInterval)
 	}
 
-	// Step 2: ensure target database exists
-	config.DB.DBName = os.Getenv("DB_NAME")
-	prerequisite(adminDB)
-	defer adminDB.Close()
+	ownerDB, err := open(ctx, c.Provider, adminDSN(c, c.DBName))
+	if err != nil {
+		return fmt.Errorf("connecting to database %s: %w", c.DBName, err)
+	}
+	defer ownerDB.Close()
+	if err := EnsureTables(ownerDB); err != nil {
+		return err
+	}
+	if c.AdminUser != "" && c.User != c.AdminUser {
+		if err := grantAccess(ctx, ownerDB, c.User); err != nil {
+			return err
+		}
+	}
+	log.Println("Database and tables ready")
+	return nil
+}
+
+// InitializeReplicas opens the read replicas of the database opened by
+// InitializeDB. It does not wait for them: the repository reads from the
+// primary until a replica answers its health check.
+func InitializeReplicas() ([]*sql.DB, error) {
+	var replicas []*sql.DB
+	for _, host := range strings.Split(config.DB.ReplicaHosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		replica := config.DB
+		replica.Host = host
+		if h, port, err := net.SplitHostPort(host); err == nil {
+			replica.Host = h
+			replica.Port, _ = strconv.Atoi(port)
+		}
+		db, err := sql.Open(config.DB.Provider, appDSN(replica))
+		if err != nil {
+			for _, db := range replicas {
+				db.Close()
+			}
+			return nil, err
+		}
+		configurePool(db, config.DB)
+		log.Printf("Using read replica %s", host)
+		replicas = append(replicas, db)
+	}
+	return replicas, nil
+}
 
-	// Step 3: now connect to the actual database
-	db, err := sql.Open(config.DB.Provider, getDSN(config))
+// open opens dsn and waits until it answers.
+func open(ctx context.Context, provider, dsn string) (*sql.DB, error) {
+	db, err := sql.Open(provider, dsn)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	if err := db.Ping(); err != nil {
-		log.Fatal(err)
+	if err := wait(ctx, db.PingContext); err != nil {
+		db.Close()
+		return nil, err
 	}
+	return db, nil
+}
 
-	log.Println("Connected to target database successfully")
-	ensureTables(db)
-	return db
+// configurePool applies the pool settings of config, or the defaults for
+// those that are not set. Connections are recycled regularly so that the
+// pool follows a failover of the host name within ConnMaxLifetime.
+func configurePool(db *sql.DB, config configs.DBConfig) {
+	maxOpen := cmp.Or(config.MaxOpenConns, defaultMaxOpenConns)
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(min(cmp.Or(config.MaxIdleConns, defaultMaxIdleConns), maxOpen))
+	db.SetConnMaxLifetime(cmp.Or(config.ConnMaxLifetime, defaultConnMaxLifetime))
+	db.SetConnMaxIdleTime(cmp.Or(config.ConnMaxIdleTime, defaultConnMaxIdleTime))
+}
+
+// wait calls ping until it succeeds, backing off exponentially with full
+// jitter, and returns the last error once ctx is done.
+func wait(ctx context.Context, ping func(context.Context) error) error {
+	delay := initialRetryDelay
+	for {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Waiting for Postgres to be ready... (%v)", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(rand.N(delay) + 1):
+		}
+		delay = min(2*delay, maxRetryDelay)
+	}
 }
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	configs "github.com/haakaashs/todos-backend/internal/configs"
+)
+
+func TestWaitRetriesUntilReady(t *testing.T) {
+	calls := 0
+	err := wait(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success on the third ping, got %v after %d pings", err, calls)
+	}
+}
+
+func TestWaitStopsAtDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	refused := errors.New("connection refused")
+	start := time.Now()
+	err := wait(ctx, func(context.Context) error { return refused })
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, refused) {
+		t.Errorf("Expected the deadline and the last error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected to give up at the deadline, took %v", elapsed)
+	}
+}
+
+func TestDSNQuotesValues(t *testing.T) {
+	config := configs.DBConfig{
+		Host:          "db",
+		Port:          5432,
+		User:          "app",
+		Password:      `it's a \ secret`,
+		DBName:        "todos db",
+		AdminUser:     "owner",
+		AdminPassword: "ownerpassword",
+	}
+
+	want := `host='db' user='app' password='it\'s a \\ secret' dbname='todos db' port='5432'`
+	if got := appDSN(config); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	want = `host='db' user='owner' password='ownerpassword' dbname='postgres' port='5432'`
+	if got := adminDSN(config, "postgres"); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,121 @@
+// Package markdown renders the Markdown descriptions of todos to sanitized
+// HTML and extracts their links and checklists.
+package markdown
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Rendered is what Render derives from a description.
+type Rendered struct {
+	HTML      string
+	Links     []model.Link
+	Checklist []model.ChecklistItem
+}
+
+// GitHub Flavored Markdown, so "- [ ]" items and bare URLs work as they do in
+// issue trackers. Raw HTML in the source is dropped by goldmark before the
+// result is sanitized.
+var md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// policy allows what user generated content may contain, plus the disabled
+// checkboxes of task list items.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}()
+
+// Render converts source to sanitized HTML and collects its links, in order
+// and without duplicates, and its checklist items. Only links to http, https
+// and mailto URLs are collected.
+func Render(source string) (Rendered, error) {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+
+	var r Rendered
+	seen := map[string]bool{}
+	addLink := func(dest, label string) {
+		u, err := url.Parse(dest)
+		if err != nil || seen[dest] {
+			return
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https", "mailto":
+		default:
+			return
+		}
+		seen[dest] = true
+		r.Links = append(r.Links, model.Link{Url: dest, Text: label})
+	}
+
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link:
+			addLink(string(n.Destination), plainText(n, src))
+		case *ast.AutoLink:
+			dest := string(n.URL(src))
+			if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(strings.ToLower(dest), "mailto:") {
+				dest = "mailto:" + dest
+			}
+			addLink(dest, string(n.Label(src)))
+		case *east.TaskCheckBox:
+			// the checkbox opens the text block of its list item
+			r.Checklist = append(r.Checklist, model.ChecklistItem{
+				Text:    strings.TrimSpace(plainText(n.Parent(), src)),
+				Checked: n.IsChecked,
+			})
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	var html bytes.Buffer
+	if err := md.Renderer().Render(&html, src, doc); err != nil {
+		return Rendered{}, err
+	}
+	r.HTML = policy.Sanitize(html.String())
+	return r, nil
+}
+
+// plainText returns the text of the inline nodes under n, with line breaks
+// turned into spaces.
+func plainText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch c := c.(type) {
+		case *ast.Text:
+			b.Write(c.Segment.Value(source))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(c.Value)
+		case *ast.AutoLink:
+			b.Write(c.Label(source))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
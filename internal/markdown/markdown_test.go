@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestRender(t *testing.T) {
+	r, err := Render(`Release **1.2** once [CI](https://ci.example.com/run/7) is green.
+
+- [ ] Update the [changelog](https://example.com/changelog)
+- [x] Tag the release
+- not a task
+
+Ask ops@example.com, see https://example.com/changelog or [this](ftp://example.com/file).`)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	for _, want := range []string{"<strong>1.2</strong>", `<a href="https://ci.example.com/run/7" rel="nofollow">CI</a>`, `type="checkbox"`} {
+		if !strings.Contains(r.HTML, want) {
+			t.Errorf("Expected the HTML to contain %s, got %s", want, r.HTML)
+		}
+	}
+
+	links := []model.Link{
+		{Url: "https://ci.example.com/run/7", Text: "CI"},
+		{Url: "https://example.com/changelog", Text: "changelog"},
+		{Url: "mailto:ops@example.com", Text: "ops@example.com"},
+	}
+	if !reflect.DeepEqual(r.Links, links) {
+		t.Errorf("Expected links %v, got %v", links, r.Links)
+	}
+
+	checklist := []model.ChecklistItem{
+		{Text: "Update the changelog", Checked: false},
+		{Text: "Tag the release", Checked: true},
+	}
+	if !reflect.DeepEqual(r.Checklist, checklist) {
+		t.Errorf("Expected checklist %v, got %v", checklist, r.Checklist)
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	r, err := Render(`<script>alert(1)</script>
+
+<img src=x onerror=alert(1)> [click](javascript:alert(1)) <a href="https://example.com" onclick="alert(1)">raw</a>`)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, unsafe := range []string{"<script", "onerror", "onclick", "javascript:"} {
+		if strings.Contains(r.HTML, unsafe) {
+			t.Errorf("Expected %s to be removed, got %s", unsafe, r.HTML)
+		}
+	}
+	if len(r.Links) != 0 {
+		t.Errorf("Expected no links from raw HTML or unsafe URLs, got %v", r.Links)
+	}
+}
@@ -1,13 +1,148 @@
 package model
 
+import "time"
+
+type Priority int32
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+type RecurrenceMode int32
+
+const (
+	RecurrenceModeUnspecified RecurrenceMode = iota
+	RecurrenceModeFixedSchedule
+	RecurrenceModeAfterCompletion
+)
+
+type DataFormat int32
+
+const (
+	DataFormatUnspecified DataFormat = iota
+	DataFormatNDJSON
+	DataFormatCSV
+	DataFormatMarkdown
+	DataFormatTodoTxt
+	DataFormatICalendar
+)
+
+type Role int32
+
+// Roles are ordered, each one allows what the previous ones do.
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleEditor
+	RoleOwner
+)
+
+type InvitationStatus int32
+
+const (
+	InvitationStatusUnspecified InvitationStatus = iota
+	InvitationStatusPending
+	InvitationStatusAccepted
+	InvitationStatusDeclined
+)
+
+type DeliveryStatus int32
+
+const (
+	DeliveryStatusUnspecified DeliveryStatus = iota
+	DeliveryStatusPending
+	DeliveryStatusSucceeded
+	DeliveryStatusDead
+)
+
+// Webhook event types.
+const (
+	EventTodoCreated   = "todo.created"
+	EventTodoUpdated   = "todo.updated"
+	EventTodoCompleted = "todo.completed"
+	EventTodoDeleted   = "todo.deleted"
+)
+
+type ListOrder int32
+
+const (
+	ListOrderCreatedAt ListOrder = iota
+	ListOrderPriority
+	ListOrderPosition
+)
+
 type Todo struct {
-	Id        string `json:"id"`
-	Title     string `json:"title"`
-	Completed bool   `json:"completed"`
+	Id        string   `json:"id"`
+	Title     string   `json:"title"`
+	Completed bool     `json:"completed"`
+	Version   int64    `json:"version"`
+	Priority  Priority `json:"priority"`
+	Position  string   `json:"position"`
+	Deleted   bool     `json:"-"`
+
+	DueAt          *time.Time     `json:"due_at,omitempty"`
+	Rrule          string         `json:"rrule"`
+	Timezone       string         `json:"timezone"`
+	RecurrenceMode RecurrenceMode `json:"recurrence_mode"`
+	// SeriesId and Occurrence identify the nth todo generated by a rule.
+	SeriesId   string `json:"-"`
+	Occurrence int32  `json:"-"`
+
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// CreatedBy is the subject of the principal that created the todo.
+	CreatedBy string `json:"-"`
+	ListId    string `json:"list_id,omitempty"`
+
+	// Description is Markdown. DescriptionHtml, Links and Checklist are
+	// derived from it whenever it is written.
+	Description     string          `json:"description"`
+	DescriptionHtml string          `json:"description_html,omitempty"`
+	Links           []Link          `json:"links,omitempty"`
+	Checklist       []ChecklistItem `json:"checklist,omitempty"`
+
+	// Txid is the transaction that last wrote the todo, set by Changes only.
+	Txid int64 `json:"-"`
+
+	// CommentCount is filled in by reads that return it, it is not stored.
+	CommentCount int32 `json:"comment_count,omitempty"`
+	// Blocked is filled in by reads that return it, it is set when a blocker
+	// of the todo is neither completed nor deleted.
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// Link is a link in the description of a todo.
+type Link struct {
+	Url  string `json:"url"`
+	Text string `json:"text"`
+}
+
+// ChecklistItem is a "- [ ]" or "- [x]" item in the description of a todo.
+type ChecklistItem struct {
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+// Watermark identifies the latest change to a set of todos, tombstones
+// included, and serves as an HTTP cache validator.
+type Watermark struct {
+	Version   int64
+	UpdatedAt time.Time
+	// Scope identifies the lists visible to the caller.
+	Scope string
 }
 
 type CreateRequest struct {
-	Title string `json:"title"`
+	Title          string         `json:"title"`
+	Priority       Priority       `json:"priority"`
+	DueAt          *time.Time     `json:"due_at,omitempty"`
+	Rrule          string         `json:"rrule"`
+	Timezone       string         `json:"timezone"`
+	RecurrenceMode RecurrenceMode `json:"recurrence_mode"`
+	Description    string         `json:"description"`
 }
 
 type CreateResponse struct {
@@ -22,14 +157,35 @@ type GetResponse struct {
 	Todo *Todo `json:"todo"`
 }
 
+type ListOptions struct {
+	Order ListOrder `json:"order"`
+	// ListId limits the result to one shared list.
+	ListId string `json:"list_id"`
+	// VisibleLists are the lists whose todos may be returned in addition to
+	// todos outside any list, when ListId is empty.
+	VisibleLists []string `json:"-"`
+	// Query limits the result to todos whose title or description match
+	// these search terms.
+	Query string `json:"query"`
+	// CreatedBy, if set, limits the todos outside any list to those created
+	// by this subject.
+	CreatedBy string `json:"-"`
+}
+
 type ListResponse struct {
 	Todos []*Todo `json:"todos"`
 }
 
 type UpdateRequest struct {
-	Id        string `json:"id"`
-	Title     string `json:"title"`
-	Completed bool   `json:"completed"`
+	Id             string         `json:"id"`
+	Title          string         `json:"title"`
+	Completed      bool           `json:"completed"`
+	Priority       Priority       `json:"priority"`
+	DueAt          *time.Time     `json:"due_at,omitempty"`
+	Rrule          string         `json:"rrule"`
+	Timezone       string         `json:"timezone"`
+	RecurrenceMode RecurrenceMode `json:"recurrence_mode"`
+	Description    string         `json:"description"`
 }
 
 type UpdateResponse struct {
@@ -39,3 +195,276 @@ type UpdateResponse struct {
 type DeleteRequest struct {
 	Id string `json:"id"`
 }
+
+type MoveRequest struct {
+	Id       string `json:"id"`
+	BeforeId string `json:"before_id"`
+	AfterId  string `json:"after_id"`
+}
+
+// ChangeCursor is the position of a change in the order of Changes: by the
+// transaction that wrote it, then by version.
+type ChangeCursor struct {
+	Txid    int64
+	Version int64
+}
+
+type SyncRequest struct {
+	SyncToken string `json:"sync_token"`
+	PageSize  int32  `json:"page_size"`
+}
+
+type SyncResponse struct {
+	Todos      []*Todo  `json:"todos"`
+	DeletedIds []string `json:"deleted_ids"`
+	SyncToken  string   `json:"sync_token"`
+	HasMore    bool     `json:"has_more"`
+}
+
+type MutationOp int32
+
+const (
+	MutationOpUnspecified MutationOp = iota
+	MutationOpCreate
+	MutationOpUpdate
+	MutationOpDelete
+)
+
+type Mutation struct {
+	Op          MutationOp `json:"op"`
+	Id          string     `json:"id"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	BaseVersion int64      `json:"base_version"`
+	Priority    Priority   `json:"priority"`
+}
+
+type MutationStatus int32
+
+const (
+	MutationStatusUnspecified MutationStatus = iota
+	MutationStatusApplied
+	MutationStatusConflict
+	MutationStatusNotFound
+	MutationStatusRejected
+)
+
+type MutationResult struct {
+	Id      string         `json:"id"`
+	Status  MutationStatus `json:"status"`
+	Todo    *Todo          `json:"todo,omitempty"`
+	Deleted bool           `json:"deleted"`
+	Error   string         `json:"error,omitempty"`
+}
+
+type PushRequest struct {
+	Mutations []*Mutation `json:"mutations"`
+}
+
+type PushResponse struct {
+	Results []*MutationResult `json:"results"`
+}
+
+type PreviewOccurrencesRequest struct {
+	Rrule    string    `json:"rrule"`
+	StartAt  time.Time `json:"start_at"`
+	Timezone string    `json:"timezone"`
+	Count    int32     `json:"count"`
+}
+
+type PreviewOccurrencesResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+type ImportOptions struct {
+	Format          DataFormat `json:"format"`
+	DryRun          bool       `json:"dry_run"`
+	AllowDuplicates bool       `json:"allow_duplicates"`
+}
+
+type ImportResponse struct {
+	Todos      []*Todo  `json:"todos"`
+	Duplicates []string `json:"duplicates"`
+	DryRun     bool     `json:"dry_run"`
+}
+
+type CalendarFeed struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	TenantId  string    `json:"-"`
+	// CreatedBy is the subject the feed shows the todos of.
+	CreatedBy string `json:"-"`
+}
+
+type CreateCalendarFeedResponse struct {
+	Feed  *CalendarFeed `json:"feed"`
+	Token string        `json:"token"`
+}
+
+type APIKey struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	TenantId   string     `json:"-"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey *APIKey `json:"api_key"`
+	Token  string  `json:"token"`
+}
+
+type TodoList struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Role      Role      `json:"role"`
+}
+
+type Member struct {
+	ListId    string    `json:"list_id"`
+	UserId    string    `json:"user_id"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Invitation struct {
+	Id        string           `json:"id"`
+	ListId    string           `json:"list_id"`
+	ListName  string           `json:"list_name"`
+	UserId    string           `json:"user_id,omitempty"`
+	Email     string           `json:"email,omitempty"`
+	Role      Role             `json:"role"`
+	InvitedBy string           `json:"invited_by"`
+	Status    InvitationStatus `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type Webhook struct {
+	Id        string    `json:"id"`
+	Url       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	Secret    string    `json:"-"`
+	// CreatedBy is the subject whose todos the webhook hears about.
+	CreatedBy string `json:"-"`
+}
+
+type CreateWebhookResponse struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
+type WebhookDelivery struct {
+	Id             string         `json:"id"`
+	WebhookId      string         `json:"webhook_id"`
+	Event          string         `json:"event"`
+	Payload        string         `json:"payload"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	LastStatusCode int32          `json:"last_status_code"`
+	LastError      string         `json:"last_error"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	// Url and Secret of the webhook, set on deliveries being dispatched.
+	Url    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// DeliveryOptions filters a delivery log.
+type DeliveryOptions struct {
+	WebhookId string         `json:"webhook_id"`
+	Status    DeliveryStatus `json:"status"`
+	PageSize  int32          `json:"page_size"`
+}
+
+// Event is the JSON body of an outbox message and of a webhook delivery.
+type Event struct {
+	Id        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      EventData `json:"data"`
+}
+
+type EventData struct {
+	Todo Todo `json:"todo"`
+}
+
+// OutboxMessage is an event recorded in the same transaction as the change
+// it describes, waiting to be published.
+type OutboxMessage struct {
+	// Id orders the messages; later changes to a todo have higher ids.
+	Id       int64  `json:"id"`
+	EventId  string `json:"event_id"`
+	TenantId string `json:"tenant_id"`
+	// AggregateId is the id of the todo that changed.
+	AggregateId string    `json:"aggregate_id"`
+	Type        string    `json:"type"`
+	Payload     []byte    `json:"payload"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Attachment is a file attached to a todo. Its content is kept in a blob
+// store under BlobKey.
+type Attachment struct {
+	Id          string `json:"id"`
+	TodoId      string `json:"todo_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Sha256 is the hex SHA-256 digest of the content.
+	Sha256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"-"`
+	BlobKey   string    `json:"-"`
+	TenantId  string    `json:"-"`
+}
+
+// Comment is a comment on a todo.
+type Comment struct {
+	Id     string `json:"id"`
+	TodoId string `json:"todo_id"`
+	// Author is the subject of the principal that wrote the comment.
+	Author string `json:"author"`
+	Body   string `json:"body"`
+	// Mentions are the ids of the users mentioned in the body.
+	Mentions  []string   `json:"mentions,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+// CommentOptions selects a page of the comments of a todo, which are ordered
+// by creation time and id.
+type CommentOptions struct {
+	TodoId   string `json:"todo_id"`
+	PageSize int32  `json:"page_size"`
+	// PageToken is decoded into AfterTime and AfterId, the position of the
+	// last comment of the previous page.
+	PageToken string    `json:"page_token"`
+	AfterTime time.Time `json:"-"`
+	AfterId   string    `json:"-"`
+}
+
+type CommentPage struct {
+	Comments      []Comment `json:"comments"`
+	NextPageToken string    `json:"next_page_token"`
+}
+
+// Dependency records that the todo TodoId cannot be completed before the
+// todo BlockedById.
+type Dependency struct {
+	TodoId      string    `json:"todo_id"`
+	BlockedById string    `json:"blocked_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DependencyGraph holds todos as nodes and their dependencies as edges.
+type DependencyGraph struct {
+	Nodes []Todo       `json:"nodes"`
+	Edges []Dependency `json:"edges"`
+}
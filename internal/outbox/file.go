@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// FileSink is a Publisher that appends every message to a file as a line
+// of JSON, for consumers that tail the file or ship it elsewhere.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// fileLine is a message as written by FileSink, with the payload inlined.
+type fileLine struct {
+	Id          int64           `json:"id"`
+	EventId     string          `json:"event_id"`
+	TenantId    string          `json:"tenant_id"`
+	AggregateId string          `json:"aggregate_id"`
+	Type        string          `json:"type"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// NewFileSink opens path for appending, creating it if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Publish writes the message and syncs the file, so a published message
+// survives a crash.
+func (f *FileSink) Publish(_ context.Context, m model.OutboxMessage) error {
+	line, err := json.Marshal(fileLine{
+		Id:          m.Id,
+		EventId:     m.EventId,
+		TenantId:    m.TenantId,
+		AggregateId: m.AggregateId,
+		Type:        m.Type,
+		CreatedAt:   m.CreatedAt,
+		Payload:     m.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return f.file.Sync()
+}
+
+func (f *FileSink) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,148 @@
+// Package outbox relays the events that the repository records in the
+// transaction of every change to a Publisher. Delivery is at least once: a
+// message is deleted only after it is published, so a crash in between
+// publishes it again, and consumers should drop duplicates by event id.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// Publisher hands a message to consumers. Publish returns once the message
+// is safely delivered or queued; an error makes the relay try again later.
+type Publisher interface {
+	Publish(ctx context.Context, m model.OutboxMessage) error
+}
+
+// Store is the outbox the relay reads.
+type Store interface {
+	// RelayOutbox passes up to limit messages, oldest first, to publish and
+	// deletes those whose ids it returns, while keeping other relays out.
+	RelayOutbox(ctx context.Context, limit int, publish func([]model.OutboxMessage) []int64) (int, error)
+}
+
+// Config configures a Relay. Zero values take the defaults below.
+type Config struct {
+	Store     Store
+	Publisher Publisher
+	// PollInterval is how often Run looks for new messages; a second by
+	// default.
+	PollInterval time.Duration
+	// BatchSize is the number of messages read at once; 100 by default.
+	BatchSize int
+}
+
+// Relay publishes the outbox in order. Once a message of a todo fails, the
+// later messages of that todo wait for the next batch, so consumers always
+// see the changes of a todo in the order they were made.
+type Relay struct {
+	config Config
+}
+
+// NewRelay creates a Relay.
+func NewRelay(config Config) *Relay {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &Relay{config: config}
+}
+
+// Run relays messages every PollInterval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// drain the outbox before waiting again
+		for {
+			n, err := r.RelayPending(ctx)
+			if err != nil {
+				log.Default().Println("outbox: failed to relay messages:", err)
+			}
+			if err != nil || n < r.config.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes one batch of messages and returns its size.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	return r.config.Store.RelayOutbox(ctx, r.config.BatchSize, func(messages []model.OutboxMessage) []int64 {
+		return r.publish(ctx, messages)
+	})
+}
+
+func (r *Relay) publish(ctx context.Context, messages []model.OutboxMessage) []int64 {
+	var published []int64
+	blocked := map[string]bool{}
+	for _, m := range messages {
+		if blocked[m.AggregateId] {
+			continue
+		}
+		if err := r.config.Publisher.Publish(ctx, m); err != nil {
+			log.Default().Println("outbox: failed to publish", m.Type, "event", m.EventId, ":", err)
+			blocked[m.AggregateId] = true
+			continue
+		}
+		published = append(published, m.Id)
+	}
+	return published
+}
+
+// Handler consumes messages published on a Bus.
+type Handler func(ctx context.Context, m model.OutboxMessage) error
+
+// Bus is an in-process Publisher that calls its handlers in turn.
+type Bus struct {
+	handlers []Handler
+}
+
+// NewBus creates a Bus without handlers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a handler. It must be called before the relay starts.
+func (b *Bus) Subscribe(h Handler) {
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish calls every handler and fails if any of them fails, in which case
+// all of them see the message again.
+func (b *Bus) Publish(ctx context.Context, m model.OutboxMessage) error {
+	var errs []error
+	for _, h := range b.handlers {
+		if err := h(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Publishers publishes to each publisher in turn, stopping at the first
+// failure.
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, m model.OutboxMessage) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/nats-io/nats.go"
+)
+
+// memoryOutbox is an in-memory Store.
+type memoryOutbox struct {
+	messages []model.OutboxMessage
+}
+
+func (o *memoryOutbox) add(aggregate, event string) {
+	id := int64(len(o.messages) + 1)
+	for _, m := range o.messages {
+		id = max(id, m.Id+1)
+	}
+	o.messages = append(o.messages, model.OutboxMessage{
+		Id:          id,
+		EventId:     fmt.Sprintf("e%d", id),
+		TenantId:    "default",
+		AggregateId: aggregate,
+		Type:        event,
+		Payload:     []byte(`{}`),
+	})
+}
+
+func (o *memoryOutbox) RelayOutbox(_ context.Context, limit int, publish func([]model.OutboxMessage) []int64) (int, error) {
+	batch := o.messages[:min(limit, len(o.messages))]
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	published := publish(slices.Clone(batch))
+	o.messages = slices.DeleteFunc(o.messages, func(m model.OutboxMessage) bool {
+		return slices.Contains(published, m.Id)
+	})
+	return len(batch), nil
+}
+
+// recorder records the event ids published and fails the ones in fail.
+type recorder struct {
+	published []string
+	fail      map[string]bool
+}
+
+func (r *recorder) Publish(_ context.Context, m model.OutboxMessage) error {
+	if r.fail[m.EventId] {
+		return errors.New("broker unavailable")
+	}
+	r.published = append(r.published, m.EventId)
+	return nil
+}
+
+func TestRelayKeepsOrderPerTodo(t *testing.T) {
+	store := &memoryOutbox{}
+	store.add("a", model.EventTodoCreated) // e1
+	store.add("b", model.EventTodoCreated) // e2
+	store.add("a", model.EventTodoUpdated) // e3
+	store.add("b", model.EventTodoUpdated) // e4
+
+	publisher := &recorder{fail: map[string]bool{"e1": true}}
+	relay := NewRelay(Config{Store: store, Publisher: publisher})
+	ctx := context.Background()
+
+	if _, err := relay.RelayPending(ctx); err != nil {
+		t.Fatalf("RelayPending failed: %v", err)
+	}
+	if want := []string{"e2", "e4"}; !slices.Equal(publisher.published, want) {
+		t.Errorf("Expected %v while todo a is blocked, got %v", want, publisher.published)
+	}
+	if len(store.messages) != 2 {
+		t.Errorf("Expected the messages of todo a to stay in the outbox, got %v", store.messages)
+	}
+
+	publisher.fail = nil
+	relay.RelayPending(ctx)
+	if want := []string{"e2", "e4", "e1", "e3"}; !slices.Equal(publisher.published, want) {
+		t.Errorf("Expected %v, got %v", want, publisher.published)
+	}
+	if len(store.messages) != 0 {
+		t.Errorf("Expected an empty outbox, got %v", store.messages)
+	}
+}
+
+func TestBusFailsIfAnyHandlerFails(t *testing.T) {
+	bus := NewBus()
+	var seen []string
+	bus.Subscribe(func(_ context.Context, m model.OutboxMessage) error {
+		seen = append(seen, m.EventId)
+		return nil
+	})
+	bus.Subscribe(func(context.Context, model.OutboxMessage) error {
+		return errors.New("search index down")
+	})
+
+	err := bus.Publish(context.Background(), model.OutboxMessage{EventId: "e1"})
+	if err == nil || !slices.Equal(seen, []string{"e1"}) {
+		t.Errorf("Expected every handler to run and the error to be returned, got %v %v", seen, err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	for i, event := range []string{model.EventTodoCreated, model.EventTodoDeleted} {
+		m := model.OutboxMessage{Id: int64(i + 1), EventId: "e" + strconv.Itoa(i+1), Type: event, Payload: []byte(`{"type":"` + event + `"}`)}
+		if err := sink.Publish(context.Background(), m); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", data)
+	}
+	var line struct {
+		EventId string `json:"event_id"`
+		Payload struct {
+			Type string `json:"type"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &line); err != nil || line.EventId != "e2" || line.Payload.Type != model.EventTodoDeleted {
+		t.Errorf("Expected the second event with its payload inline, got %s %v", lines[1], err)
+	}
+}
+
+// natsServer is a fake NATS server that records the messages published to
+// it. With jetStream set it acknowledges them like a stream would.
+type natsServer struct {
+	listener  net.Listener
+	jetStream bool
+	messages  chan string
+}
+
+func newNATSServer(t *testing.T, jetStream bool) *natsServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &natsServer{listener: listener, jetStream: jetStream, messages: make(chan string, 10)}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *natsServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *natsServer) handle(conn net.Conn) {
+	defer conn.Close()
+	io.WriteString(conn, `INFO {"server_id":"test","headers":true,"max_payload":1048576}`+"\r\n")
+
+	reader := bufio.NewReader(conn)
+	var inbox string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch op {
+		case "PING":
+			io.WriteString(conn, "PONG\r\n")
+		case "SUB":
+			// the client's inbox for replies
+			inbox = args
+		case "HPUB":
+			fields := strings.Fields(args)
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			data := make([]byte, size+2)
+			io.ReadFull(reader, data)
+			s.messages <- fields[0] + "\n" + string(data[:size])
+			if s.jetStream && len(fields) == 4 {
+				ack := `{"stream":"TODOS","seq":1}`
+				sid := inbox[strings.LastIndex(inbox, " ")+1:]
+				fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", fields[1], sid, len(ack), ack)
+			}
+		}
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	for _, jetStream := range []bool{false, true} {
+		server := newNATSServer(t, jetStream)
+		publisher, err := NewNATSPublisher(NATSConfig{URL: "nats://" + server.listener.Addr().String(), JetStream: jetStream})
+		if err != nil {
+			t.Fatalf("NewNATSPublisher failed: %v", err)
+		}
+		defer publisher.Close()
+
+		m := model.OutboxMessage{EventId: "e1", TenantId: "acme", AggregateId: "t1", Type: model.EventTodoCompleted, Payload: []byte(`{"id":"e1"}`)}
+		if err := publisher.Publish(context.Background(), m); err != nil {
+			t.Fatalf("Publish failed with JetStream %v: %v", jetStream, err)
+		}
+
+		got := <-server.messages
+		if !strings.HasPrefix(got, "todos.todo.completed\n") || !strings.Contains(got, "Nats-Msg-Id: e1\r\n") ||
+			!strings.Contains(got, "Todos-Tenant: acme\r\n") || !strings.HasSuffix(got, `{"id":"e1"}`) {
+			t.Errorf("Expected the message with its headers, got %q", got)
+		}
+	}
+}
+
+func TestNATSPublisherReportsErrors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, `INFO {"headers":true}`+"\r\n")
+		bufio.NewReader(conn).ReadString('\n')
+		io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+	}()
+
+	publisher, _ := NewNATSPublisher(NATSConfig{URL: "nats://" + listener.Addr().String()})
+	err = publisher.Publish(context.Background(), model.OutboxMessage{EventId: "e1", Type: model.EventTodoCreated})
+	if !errors.Is(err, nats.ErrAuthorization) {
+		t.Errorf("Expected the server error, got %v", err)
+	}
+}
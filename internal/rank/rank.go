@@ -0,0 +1,175 @@
+// Package rank implements fractional indexing: positions are strings that
+// compare lexicographically (byte-wise) and a new position can always be
+// generated between any two existing ones, so moving an item rewrites only
+// that item.
+//
+// Positions are base-62 fractions without the leading "0." and never end in
+// the zero digit, which guarantees there is always room below any position.
+// Because the comparison is byte-wise, databases must order them with the
+// "C" collation.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var ErrInvalidRange = errors.New("rank: lower bound must sort before upper bound")
+
+func digit(c byte) int {
+	return strings.IndexByte(digits, c)
+}
+
+// Valid reports whether p is a well-formed position.
+func Valid(p string) bool {
+	if p == "" || p[len(p)-1] == digits[0] {
+		return false
+	}
+	for i := 0; i < len(p); i++ {
+		if digit(p[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Between returns a position that sorts strictly between a and b. An empty a
+// means "before everything" and an empty b means "after everything".
+func Between(a, b string) (string, error) {
+	if (a != "" && !Valid(a)) || (b != "" && !Valid(b)) {
+		return "", ErrInvalidRange
+	}
+	if a != "" && b != "" && a >= b {
+		return "", ErrInvalidRange
+	}
+	return midpoint(a, b), nil
+}
+
+func midpoint(a, b string) string {
+	if b != "" {
+		// skip the common prefix, treating a as padded with zero digits
+		n := 0
+		for n < len(b) {
+			c := digits[0]
+			if n < len(a) {
+				c = a[n]
+			}
+			if c != b[n] {
+				break
+			}
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	da, db := 0, base
+	if a != "" {
+		da = digit(a[0])
+	}
+	if b != "" {
+		db = digit(b[0])
+	}
+	if db-da > 1 {
+		return string(digits[(da+db+1)/2])
+	}
+	// consecutive digits: b's first digit alone works if b has more digits,
+	// otherwise keep a's first digit and look for room after the rest of a
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[da]) + midpoint(rest, "")
+}
+
+// BetweenN returns n ascending positions that sort strictly between a and b,
+// splitting the range evenly so the keys stay short.
+func BetweenN(a, b string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	mid, err := Between(a, b)
+	if err != nil {
+		return nil, err
+	}
+	left, err := BetweenN(a, mid, (n-1)/2)
+	if err != nil {
+		return nil, err
+	}
+	right, err := BetweenN(mid, b, n-1-(n-1)/2)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(left, mid), right...), nil
+}
+
+// Before returns a position that sorts before b, preferring short keys so
+// that repeatedly prepending grows positions slowly.
+func Before(b string) string {
+	for i := 0; i < len(b); i++ {
+		switch d := digit(b[i]); {
+		case d > 1:
+			return b[:i] + string(digits[d-1])
+		case d == 1:
+			// only zeros before this digit: go one level deeper, as high as
+			// possible so the next prepends have room
+			return b[:i] + string(digits[0]) + string(digits[base-1])
+		}
+	}
+	return midpoint("", b)
+}
+
+// After returns a position that sorts after a, preferring short keys so that
+// repeatedly appending grows positions slowly.
+func After(a string) string {
+	if a == "" {
+		return midpoint("", "")
+	}
+	for i := 0; i < len(a); i++ {
+		if d := digit(a[i]); d < base-1 {
+			return a[:i] + string(digits[d+1])
+		}
+	}
+	return a + string(digits[1])
+}
+
+// Spread returns n ascending positions evenly spaced over the whole range,
+// all as short as possible while leaving room for inserts between them.
+func Spread(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	// pick the smallest width that leaves at least base-1 free slots
+	// between neighbours
+	width, space := 1, uint64(base)
+	for space/uint64(n+1) < uint64(base) && width < 10 {
+		width++
+		space *= uint64(base)
+	}
+
+	step := space / uint64(n+1)
+	result := make([]string, n)
+	buf := make([]byte, width)
+	for i := range result {
+		v := step * uint64(i+1)
+		for j := width - 1; j >= 0; j-- {
+			buf[j] = digits[v%uint64(base)]
+			v /= uint64(base)
+		}
+		result[i] = strings.TrimRight(string(buf), digits[:1])
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package rank
+
+import (
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	cases := [][2]string{
+		{"", ""},
+		{"", "1"},
+		{"", "01"},
+		{"V", ""},
+		{"z", ""},
+		{"zz", ""},
+		{"A", "B"},
+		{"A", "A1"},
+		{"Ay", "B"},
+		{"0z", "1"},
+		{"a", "a01"},
+	}
+	for _, c := range cases {
+		got, err := Between(c[0], c[1])
+		if err != nil {
+			t.Errorf("Between(%q, %q) failed: %v", c[0], c[1], err)
+			continue
+		}
+		if !Valid(got) || (c[0] != "" && got <= c[0]) || (c[1] != "" && got >= c[1]) {
+			t.Errorf("Between(%q, %q) = %q, not strictly between", c[0], c[1], got)
+		}
+	}
+
+	if _, err := Between("B", "A"); err != ErrInvalidRange {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+	if _, err := Between("A0", ""); err != ErrInvalidRange {
+		t.Errorf("Expected ErrInvalidRange for trailing zero, got %v", err)
+	}
+}
+
+func TestRepeatedInsertsStayOrdered(t *testing.T) {
+	// always insert right after the first item, the worst case for key growth
+	first, last := "1", "2"
+	for i := 0; i < 200; i++ {
+		mid, err := Between(first, last)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) failed: %v", first, last, err)
+		}
+		if mid <= first || mid >= last {
+			t.Fatalf("Between(%q, %q) = %q", first, last, mid)
+		}
+		last = mid
+	}
+
+	p := "V"
+	for i := 0; i < 500; i++ {
+		next := Before(p)
+		if next >= p || !Valid(next) {
+			t.Fatalf("Before(%q) = %q", p, next)
+		}
+		p = next
+	}
+	if len(p) > 10 {
+		t.Errorf("Expected prepends to grow slowly, got %d chars", len(p))
+	}
+
+	p = "V"
+	for i := 0; i < 500; i++ {
+		next := After(p)
+		if next <= p || !Valid(next) {
+			t.Fatalf("After(%q) = %q", p, next)
+		}
+		p = next
+	}
+}
+
+func TestBetweenN(t *testing.T) {
+	positions, err := BetweenN("", "1", 1000)
+	if err != nil {
+		t.Fatalf("BetweenN failed: %v", err)
+	}
+	if len(positions) != 1000 {
+		t.Fatalf("Expected 1000 positions, got %d", len(positions))
+	}
+	for i, p := range positions {
+		if !Valid(p) || p >= "1" || (i > 0 && positions[i-1] >= p) {
+			t.Fatalf("Position %d = %q out of order", i, p)
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{1, 2, 61, 62, 1000, 5000} {
+		positions := Spread(n)
+		if len(positions) != n {
+			t.Fatalf("Spread(%d) returned %d positions", n, len(positions))
+		}
+		for i, p := range positions {
+			if !Valid(p) {
+				t.Errorf("Spread(%d)[%d] = %q is not valid", n, i, p)
+			}
+			if i > 0 && positions[i-1] >= p {
+				t.Errorf("Spread(%d) not ascending at %d: %q >= %q", n, i, positions[i-1], p)
+			}
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Package ratelimit provides a Connect interceptor that applies token bucket
+// rate limits per client and procedure.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// sweepInterval is how often idle buckets are dropped.
+	sweepInterval = time.Minute
+	// idleTimeout is how long a bucket is kept after its last request.
+	idleTimeout = 10 * time.Minute
+)
+
+// Limit is a sustained rate in requests per second and a burst size. A zero
+// Rate disables limiting.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Config configures an Interceptor.
+type Config struct {
+	// Default applies to procedures without an entry in Procedures.
+	Default Limit
+	// Procedures maps a full procedure such as "/todos.v1.TodosService/List"
+	// or just a method name such as "List" to its own limit.
+	Procedures map[string]Limit
+	// TrustedProxies are the networks of proxies, such as the ingress, whose
+	// X-Forwarded-For header is believed. It is ignored when empty.
+	TrustedProxies []netip.Prefix
+}
+
+// withDefaultBurst returns l with a burst of at least the rate rounded up if
+// none is set.
+func (l Limit) withDefaultBurst() Limit {
+	if l.Burst < 1 {
+		l.Burst = max(int(math.Ceil(l.Rate)), 1)
+	}
+	return l
+}
+
+// ParseLimit parses "rate:burst" or just "rate", e.g. "5:10".
+func ParseLimit(s string) (Limit, error) {
+	rateStr, burstStr, hasBurst := strings.Cut(strings.TrimSpace(s), ":")
+	r, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || r < 0 {
+		return Limit{}, fmt.Errorf("invalid rate %q", rateStr)
+	}
+
+	var burst int
+	if hasBurst {
+		burst, err = strconv.Atoi(burstStr)
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("invalid burst %q", burstStr)
+		}
+	}
+	return Limit{Rate: r, Burst: burst}.withDefaultBurst(), nil
+}
+
+// ParseProcedureLimits parses comma separated "procedure=rate:burst" pairs,
+// e.g. "List=5:10,Create=1:5".
+func ParseProcedureLimits(s string) (map[string]Limit, error) {
+	limits := map[string]Limit{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid procedure limit %q", pair)
+		}
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("procedure %s: %w", name, err)
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+	return limits, nil
+}
+
+// ParsePrefixes parses comma separated CIDRs or addresses.
+func ParsePrefixes(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(part, "/") {
+			addr, err := netip.ParseAddr(part)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(part)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Interceptor rejects requests over their limit with CodeResourceExhausted
+// and a RetryInfo detail. Clients are told apart by principal, API key or
+// IP address, in that order, so it must run after authentication.
+type Interceptor struct {
+	config Config
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	// procedure is empty for the shared default bucket.
+	procedure string
+	client    string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewInterceptor creates an Interceptor.
+func NewInterceptor(config Config) *Interceptor {
+	config.Default = config.Default.withDefaultBurst()
+	return &Interceptor{
+		config:  config,
+		now:     time.Now,
+		buckets: map[bucketKey]*bucket{},
+	}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.allow(ctx, req.Spec().Procedure, req.Peer(), req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.allow(ctx, conn.Spec().Procedure, conn.Peer(), conn.RequestHeader()); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// Handler applies the limits of procedure to plain HTTP requests to next,
+// for endpoints served outside of Connect. Like the interceptor, it must
+// run after authentication.
+func (i *Interceptor) Handler(procedure string, next http.Handler) http.Handler {
+	errorWriter := connect.NewErrorWriter()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peer := connect.Peer{Addr: r.RemoteAddr}
+		if err := i.allow(r.Context(), procedure, peer, r.Header); err != nil {
+			errorWriter.Write(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (i *Interceptor) allow(ctx context.Context, procedure string, peer connect.Peer, header http.Header) error {
+	limit, key := i.limitFor(procedure)
+	if limit.Rate <= 0 {
+		return nil
+	}
+	key.client = i.clientKey(ctx, peer, header)
+
+	now := i.now()
+	delay := i.reserve(key, limit, now)
+	if delay <= 0 {
+		return nil
+	}
+
+	err := connect.NewError(connect.CodeResourceExhausted, errors.New("rate limit exceeded, retry later"))
+	if detail, derr := connect.NewErrorDetail(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); derr == nil {
+		err.AddDetail(detail)
+	}
+	err.Meta().Set("Retry-After", strconv.Itoa(int((delay+time.Second-1)/time.Second)))
+	return err
+}
+
+// limitFor returns the limit of a procedure and its bucket key without the
+// client part.
+func (i *Interceptor) limitFor(procedure string) (Limit, bucketKey) {
+	if limit, ok := i.config.Procedures[procedure]; ok {
+		return limit, bucketKey{procedure: procedure}
+	}
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+	if limit, ok := i.config.Procedures[method]; ok {
+		return limit, bucketKey{procedure: procedure}
+	}
+	return i.config.Default, bucketKey{}
+}
+
+// reserve takes a token from the bucket and returns zero, or leaves the
+// bucket untouched and returns how long until a token is available.
+func (i *Interceptor) reserve(key bucketKey, limit Limit, now time.Time) time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if now.Sub(i.lastSweep) >= sweepInterval {
+		for k, b := range i.buckets {
+			if now.Sub(b.lastSeen) >= idleTimeout {
+				delete(i.buckets, k)
+			}
+		}
+		i.lastSweep = now
+	}
+
+	b, ok := i.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)}
+		i.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return idleTimeout
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// clientKey identifies the caller: the authenticated principal if any,
+// otherwise the client IP.
+func (i *Interceptor) clientKey(ctx context.Context, peer connect.Peer, header http.Header) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		if p.APIKeyID != "" {
+			return "key:" + p.APIKeyID
+		}
+		return p.Subject()
+	}
+	return "ip:" + i.clientIP(peer.Addr, header).String()
+}
+
+// clientIP returns the address of the peer or, if the peer is a trusted
+// proxy, the rightmost address in X-Forwarded-For that is not a trusted
+// proxy itself. Entries left of it could be forged by the client.
+func (i *Interceptor) clientIP(remoteAddr string, header http.Header) netip.Addr {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	addr = addr.Unmap()
+	if !i.trusted(addr) {
+		return addr
+	}
+
+	hops := strings.Split(strings.Join(header.Values("X-Forwarded-For"), ","), ",")
+	for j := len(hops) - 1; j >= 0; j-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[j]))
+		if err != nil {
+			break
+		}
+		addr = hop.Unmap()
+		if !i.trusted(addr) {
+			break
+		}
+	}
+	return addr
+}
+
+func (i *Interceptor) trusted(addr netip.Addr) bool {
+	for _, prefix := range i.config.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
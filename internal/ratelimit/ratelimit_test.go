@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+const listProcedure = "/todos.v1.TodosService/List"
+
+func newTestInterceptor(config Config) (*Interceptor, *time.Time) {
+	i := NewInterceptor(config)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	i.now = func() time.Time { return now }
+	return i, &now
+}
+
+func TestInterceptorRejectsWhenBucketIsEmpty(t *testing.T) {
+	i, now := newTestInterceptor(Config{Default: Limit{Rate: 1, Burst: 2}})
+	peer := connect.Peer{Addr: "192.0.2.1:5000"}
+
+	for n := 0; n < 2; n++ {
+		if err := i.allow(context.Background(), listProcedure, peer, http.Header{}); err != nil {
+			t.Fatalf("Expected request %d within the burst to pass, got %v", n, err)
+		}
+	}
+
+	err := i.allow(context.Background(), listProcedure, peer, http.Header{})
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeResourceExhausted {
+		t.Fatalf("Expected CodeResourceExhausted, got %v", err)
+	}
+	if got := connectErr.Meta().Get("Retry-After"); got != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", got)
+	}
+	details := connectErr.Details()
+	if len(details) != 1 {
+		t.Fatalf("Expected one error detail, got %d", len(details))
+	}
+	value, err := details[0].Value()
+	if err != nil {
+		t.Fatalf("Failed to decode detail: %v", err)
+	}
+	info, ok := value.(*errdetails.RetryInfo)
+	if !ok || info.RetryDelay.AsDuration() != time.Second {
+		t.Errorf("Expected RetryInfo with a delay of 1s, got %v", value)
+	}
+
+	other := connect.Peer{Addr: "192.0.2.2:5000"}
+	if err := i.allow(context.Background(), listProcedure, other, http.Header{}); err != nil {
+		t.Errorf("Expected another client to have its own bucket, got %v", err)
+	}
+
+	*now = now.Add(time.Second)
+	if err := i.allow(context.Background(), listProcedure, peer, http.Header{}); err != nil {
+		t.Errorf("Expected the bucket to refill, got %v", err)
+	}
+}
+
+func TestInterceptorProcedureLimits(t *testing.T) {
+	procedures, err := ParseProcedureLimits("List=1:1, /todos.v1.TodosService/Create=0")
+	if err != nil {
+		t.Fatalf("ParseProcedureLimits failed: %v", err)
+	}
+	i, _ := newTestInterceptor(Config{Default: Limit{Rate: 100, Burst: 100}, Procedures: procedures})
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "alice"})
+
+	if err := i.allow(ctx, listProcedure, connect.Peer{}, http.Header{}); err != nil {
+		t.Fatalf("Expected first List to pass, got %v", err)
+	}
+	if err := i.allow(ctx, listProcedure, connect.Peer{}, http.Header{}); err == nil {
+		t.Errorf("Expected second List to be limited")
+	}
+	if err := i.allow(ctx, "/todos.v1.TodosService/Get", connect.Peer{}, http.Header{}); err != nil {
+		t.Errorf("Expected Get to use the default limit, got %v", err)
+	}
+	for n := 0; n < 200; n++ {
+		if err := i.allow(ctx, "/todos.v1.TodosService/Create", connect.Peer{}, http.Header{}); err != nil {
+			t.Fatalf("Expected Create to be unlimited, got %v", err)
+		}
+	}
+}
+
+func TestClientIPHonorsForwardedForOnlyFromTrustedProxies(t *testing.T) {
+	proxies, err := ParsePrefixes("10.0.0.0/8, 192.0.2.10")
+	if err != nil {
+		t.Fatalf("ParsePrefixes failed: %v", err)
+	}
+	i := NewInterceptor(Config{TrustedProxies: proxies})
+
+	header := http.Header{}
+	header.Add("X-Forwarded-For", "203.0.113.9, 198.51.100.7")
+	header.Add("X-Forwarded-For", "10.1.2.3")
+
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"10.0.0.1:443", "198.51.100.7"},
+		{"192.0.2.10:443", "198.51.100.7"},
+		{"198.51.100.50:443", "198.51.100.50"},
+	}
+	for _, tt := range tests {
+		if got := i.clientIP(tt.remote, header); got != netip.MustParseAddr(tt.want) {
+			t.Errorf("Expected client IP %s for peer %s, got %s", tt.want, tt.remote, got)
+		}
+	}
+}
+
+func TestClientKeyPrefersPrincipal(t *testing.T) {
+	i := NewInterceptor(Config{})
+	peer := connect.Peer{Addr: "192.0.2.1:5000"}
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "alice", APIKeyID: "k1"})
+	if got := i.clientKey(ctx, peer, http.Header{}); got != "key:k1" {
+		t.Errorf("Expected key:k1, got %s", got)
+	}
+	ctx = auth.WithPrincipal(context.Background(), auth.Principal{UserID: "alice"})
+	if got := i.clientKey(ctx, peer, http.Header{}); got != "user:alice" {
+		t.Errorf("Expected user:alice, got %s", got)
+	}
+	if got := i.clientKey(context.Background(), peer, http.Header{}); got != "ip:192.0.2.1" {
+		t.Errorf("Expected ip:192.0.2.1, got %s", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package recurrence evaluates iCalendar (RFC 5545) RRULE strings for
+// recurring todos. The start of a series always comes from the todo's due
+// date, so rules carry no DTSTART of their own.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// MaxPreview caps the number of occurrences returned by Preview.
+const MaxPreview = 100
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Rule is a parsed RRULE bound to a time zone.
+type Rule struct {
+	opt   rrule.ROption
+	count int
+	loc   *time.Location
+}
+
+// Parse validates an RRULE, with or without the "RRULE:" prefix, and the
+// IANA time zone its occurrences are computed in. An empty zone means UTC.
+func Parse(rule, timezone string) (*Rule, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidRule, timezone)
+	}
+
+	rule = strings.TrimSpace(rule)
+	if strings.ContainsAny(rule, "\r\n") || strings.Contains(strings.ToUpper(rule), "DTSTART") {
+		return nil, fmt.Errorf("%w: DTSTART is taken from the due date", ErrInvalidRule)
+	}
+
+	opt, err := rrule.StrToROptionInLocation(rule, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	if opt.Freq == rrule.MINUTELY || opt.Freq == rrule.SECONDLY {
+		return nil, fmt.Errorf("%w: FREQ must be HOURLY or longer", ErrInvalidRule)
+	}
+	if opt.Count < 0 || opt.Interval < 0 {
+		return nil, fmt.Errorf("%w: COUNT and INTERVAL must be positive", ErrInvalidRule)
+	}
+	if _, err := rrule.NewRRule(*opt); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	// COUNT is tracked per series by the caller, see Next
+	r := &Rule{opt: *opt, count: opt.Count, loc: loc}
+	r.opt.Count = 0
+	return r, nil
+}
+
+// Location returns the time zone occurrences are computed in.
+func (r *Rule) Location() *time.Location {
+	return r.loc
+}
+
+func (r *Rule) at(start time.Time, count int) *rrule.RRule {
+	opt := r.opt
+	opt.Dtstart = start.In(r.loc)
+	opt.Count = count
+	// options were validated by Parse
+	rule, _ := rrule.NewRRule(opt)
+	return rule
+}
+
+// Next returns the first occurrence strictly after the given time for a
+// series anchored at start, where occurrence is the 1-based number of the
+// occurrence being completed. It reports false once COUNT or UNTIL end the
+// series.
+func (r *Rule) Next(start, after time.Time, occurrence int) (time.Time, bool) {
+	if r.count > 0 && occurrence >= r.count {
+		return time.Time{}, false
+	}
+	next := r.at(start, 0).After(after, false)
+	return next, !next.IsZero()
+}
+
+// Preview returns up to n occurrences of a series beginning at start, which
+// always counts as the first occurrence.
+func (r *Rule) Preview(start time.Time, n int) []time.Time {
+	if n > MaxPreview {
+		n = MaxPreview
+	}
+	if n <= 0 || (!r.opt.Until.IsZero() && start.After(r.opt.Until)) {
+		return nil
+	}
+
+	result := []time.Time{start.In(r.loc)}
+	next := r.at(start, r.count).Iterator()
+	for len(result) < n && (r.count == 0 || len(result) < r.count) {
+		t, ok := next()
+		if !ok {
+			break
+		}
+		if t.After(start) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// AtTimeOfDay returns the given day in loc at the wall-clock time of ref, so
+// a series rescheduled from its completion keeps its usual time of day.
+func AtTimeOfDay(day, ref time.Time, loc *time.Location) time.Time {
+	day, ref = day.In(loc), ref.In(loc)
+	return time.Date(day.Year(), day.Month(), day.Day(), ref.Hour(), ref.Minute(), ref.Second(), 0, loc)
+}
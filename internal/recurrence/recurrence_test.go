@@ -0,0 +1,61 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"FREQ=SOMETIMES",
+		"INTERVAL=2",
+		"FREQ=MINUTELY",
+		"FREQ=DAILY;BYHOUR=25",
+		"DTSTART:20260101T090000Z\nRRULE:FREQ=DAILY",
+	} {
+		if _, err := Parse(rule, ""); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Expected ErrInvalidRule for %q, got %v", rule, err)
+		}
+	}
+
+	if _, err := Parse("FREQ=DAILY", "Mars/Olympus_Mons"); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("Expected ErrInvalidRule for unknown zone, got %v", err)
+	}
+}
+
+func TestNextKeepsWallClockAcrossDST(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=WEEKLY;BYDAY=SA", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// the last Saturday before the clocks go back on 2026-10-25
+	start := time.Date(2026, 10, 24, 9, 0, 0, 0, rule.Location())
+	next, ok := rule.Next(start, start, 1)
+	if !ok {
+		t.Fatal("Expected another occurrence")
+	}
+	if next.Hour() != 9 || next.Day() != 31 {
+		t.Errorf("Expected 2026-10-31 09:00 local, got %v", next)
+	}
+}
+
+func TestCountAndUntilEndTheSeries(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	rule, _ := Parse("FREQ=DAILY;COUNT=3", "UTC")
+	if _, ok := rule.Next(start, start, 3); ok {
+		t.Error("Expected COUNT=3 to end after the third occurrence")
+	}
+	if got := rule.Preview(start, 10); len(got) != 3 {
+		t.Errorf("Expected 3 previewed occurrences, got %d", len(got))
+	}
+
+	rule, _ = Parse("FREQ=DAILY;UNTIL=20260107T090000Z", "UTC")
+	last := start.AddDate(0, 0, 2)
+	if _, ok := rule.Next(start, last, 3); ok {
+		t.Error("Expected UNTIL to end the series")
+	}
+}
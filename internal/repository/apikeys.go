@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/lib/pq"
+)
+
+// apiKeyColumns is the column list scanned by scanAPIKey.
+const apiKeyColumns = `id, name, prefix, scopes, expires_at, created_at, last_used_at`
+
+func scanAPIKey(row interface{ Scan(...any) error }, k *model.APIKey, extra ...any) error {
+	dest := append([]any{
+		&k.Id, &k.Name, &k.Prefix, pq.Array(&k.Scopes), &k.ExpiresAt, &k.CreatedAt, &k.LastUsedAt,
+	}, extra...)
+	return row.Scan(dest...)
+}
+
+// CreateAPIKey stores a key with the salted hash of its secret.
+func (r *Repository) CreateAPIKey(ctx context.Context, key *model.APIKey, secret service.APIKeySecret) (model.APIKey, error) {
+	key.Id = uuid.NewString()
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		key.TenantId = tenant
+		return tx.StmtContext(ctx, r.createKeyStmt).QueryRowContext(
+			ctx,
+			key.Id,
+			key.Name,
+			key.Prefix,
+			pq.Array(key.Scopes),
+			key.ExpiresAt,
+			secret.Salt,
+			secret.Hash,
+			tenant,
+		).Scan(&key.CreatedAt)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to create API key:", err)
+		return model.APIKey{}, err
+	}
+
+	log.Default().Println("repository: Created API key successfully:", key.Id)
+	return *key, nil
+}
+
+func (r *Repository) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	var result []model.APIKey
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.listKeysStmt).QueryContext(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			k := model.APIKey{TenantId: tenant}
+			if err := scanAPIKey(rows, &k); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, k)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list API keys:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// RotateAPIKey replaces the prefix and secret of an active key.
+func (r *Repository) RotateAPIKey(ctx context.Context, id, prefix string, secret service.APIKeySecret) (model.APIKey, error) {
+	var k model.APIKey
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		k.TenantId = tenant
+		return scanAPIKey(tx.StmtContext(ctx, r.rotateKeyStmt).QueryRowContext(ctx, id, prefix, secret.Salt, secret.Hash, tenant), &k)
+	})
+	if err == sql.ErrNoRows {
+		return model.APIKey{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to rotate API key:", err)
+		return model.APIKey{}, err
+	}
+
+	log.Default().Println("repository: Rotated API key successfully:", id)
+	return k, nil
+}
+
+func (r *Repository) RevokeAPIKey(ctx context.Context, id string) error {
+	var res sql.Result
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var err error
+		res, err = tx.StmtContext(ctx, r.revokeKeyStmt).ExecContext(ctx, id, tenant)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to revoke API key:", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrNotFound
+	}
+
+	log.Default().Println("repository: Revoked API key successfully:", id)
+	return nil
+}
+
+// APIKeyByPrefix returns the active key with the given prefix and its secret,
+// in whichever tenant it belongs to.
+func (r *Repository) APIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, service.APIKeySecret, error) {
+	var (
+		k      model.APIKey
+		secret service.APIKeySecret
+	)
+
+	err := r.inCredentialLookup(ctx, func(tx *sql.Tx) error {
+		return scanAPIKey(tx.StmtContext(ctx, r.keyByPrefixStmt).QueryRowContext(ctx, prefix), &k, &k.TenantId, &secret.Salt, &secret.Hash)
+	})
+	if err == sql.ErrNoRows {
+		return model.APIKey{}, service.APIKeySecret{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to look up API key:", err)
+		return model.APIKey{}, service.APIKeySecret{}, err
+	}
+	return k, secret, nil
+}
+
+// TouchAPIKey updates the last-used time of a key at most once a minute, so
+// busy keys do not turn every request into a write.
+func (r *Repository) TouchAPIKey(ctx context.Context, id string) error {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		_, err := tx.StmtContext(ctx, r.touchKeyStmt).ExecContext(ctx, id, tenant)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to touch API key:", err)
+		return err
+	}
+	return nil
+}
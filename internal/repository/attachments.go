@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/lib/pq"
+)
+
+// attachmentColumns is the column list scanned by scanAttachment.
+const attachmentColumns = `id, todo_id, filename, content_type, size, sha256, created_at, created_by, blob_key, tenant_id`
+
+// purgeableColumns is attachmentColumns of attachments a.
+const purgeableColumns = `a.id, a.todo_id, a.filename, a.content_type, a.size, a.sha256, a.created_at,
+	a.created_by, a.blob_key, a.tenant_id`
+
+func scanAttachment(row interface{ Scan(...any) error }, a *model.Attachment) error {
+	return row.Scan(&a.Id, &a.TodoId, &a.Filename, &a.ContentType, &a.Size, &a.Sha256, &a.CreatedAt,
+		&a.CreatedBy, &a.BlobKey, &a.TenantId)
+}
+
+// inAttachmentPurge runs fn in a transaction allowed to read and delete the
+// attachments of every tenant, and to read the todos they belong to.
+func (r *Repository) inAttachmentPurge(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return r.inTx(ctx, r.attachmentPurgeStmt, nil, fn)
+}
+
+// CreateAttachment records an attachment of a live todo, or returns
+// service.ErrNotFound if the todo does not exist.
+func (r *Repository) CreateAttachment(ctx context.Context, a *model.Attachment) (model.Attachment, error) {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		a.TenantId = tenant
+		return tx.StmtContext(ctx, r.createAttachmentStmt).QueryRowContext(
+			ctx,
+			a.Id,
+			a.TodoId,
+			a.Filename,
+			a.ContentType,
+			a.Size,
+			a.Sha256,
+			a.BlobKey,
+			a.CreatedBy,
+			tenant,
+		).Scan(&a.CreatedAt)
+	})
+	if err == sql.ErrNoRows {
+		return model.Attachment{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to create attachment:", err)
+		return model.Attachment{}, err
+	}
+
+	log.Default().Println("repository: Created attachment successfully:", a.Id)
+	return *a, nil
+}
+
+func (r *Repository) ListAttachments(ctx context.Context, todoID string) ([]model.Attachment, error) {
+	var result []model.Attachment
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.listAttachmentsStmt).QueryContext(ctx, todoID, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a model.Attachment
+			if err := scanAttachment(rows, &a); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list attachments:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) GetAttachment(ctx context.Context, id string) (model.Attachment, error) {
+	var a model.Attachment
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return scanAttachment(tx.StmtContext(ctx, r.getAttachmentStmt).QueryRowContext(ctx, id, tenant), &a)
+	})
+	if err == sql.ErrNoRows {
+		return model.Attachment{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to get attachment:", err)
+		return model.Attachment{}, err
+	}
+	return a, nil
+}
+
+func (r *Repository) DeleteAttachment(ctx context.Context, id string) error {
+	var res sql.Result
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var err error
+		res, err = tx.StmtContext(ctx, r.deleteAttachmentStmt).ExecContext(ctx, id, tenant)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to delete attachment:", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrNotFound
+	}
+
+	log.Default().Println("repository: Deleted attachment successfully:", id)
+	return nil
+}
+
+func (r *Repository) AttachmentBytesBy(ctx context.Context, subject string) (int64, error) {
+	var size int64
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, r.attachmentBytesStmt).QueryRowContext(ctx, subject, tenant).Scan(&size)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to sum attachment sizes:", err)
+		return 0, err
+	}
+	return size, nil
+}
+
+func (r *Repository) PurgeableAttachments(ctx context.Context, deletedBefore time.Time, limit int) ([]model.Attachment, error) {
+	var result []model.Attachment
+	err := r.inAttachmentPurge(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.StmtContext(ctx, r.purgeableStmt).QueryContext(ctx, deletedBefore, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a model.Attachment
+			if err := scanAttachment(rows, &a); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to find purgeable attachments:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) RemoveAttachments(ctx context.Context, ids []string) error {
+	err := r.inAttachmentPurge(ctx, func(tx *sql.Tx) error {
+		_, err := tx.StmtContext(ctx, r.removeAttachmentsStmt).ExecContext(ctx, pq.Array(ids))
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to remove attachments:", err)
+		return err
+	}
+	return nil
+}
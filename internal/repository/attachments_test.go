@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+func TestAttachments(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, globex := tenants()
+
+	todo, err := r.Create(acme, &model.Todo{Title: "Crash on start", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	attachment := model.Attachment{
+		Id:          uuid.NewString(),
+		TodoId:      todo.Id,
+		Filename:    "crash.log",
+		ContentType: "text/plain",
+		Size:        42,
+		Sha256:      "e3b0c442",
+		BlobKey:     "attachments/acme/crash.log",
+		CreatedBy:   "user:alice",
+	}
+	if _, err := r.CreateAttachment(acme, &attachment); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	missing := attachment
+	missing.Id, missing.TodoId = uuid.NewString(), uuid.NewString()
+	if _, err := r.CreateAttachment(acme, &missing); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing todo, got %v", err)
+	}
+
+	got, err := r.GetAttachment(acme, attachment.Id)
+	if err != nil || got.Filename != "crash.log" || got.BlobKey != attachment.BlobKey || got.CreatedAt.IsZero() {
+		t.Errorf("Expected the stored attachment, got %+v %v", got, err)
+	}
+	if _, err := r.GetAttachment(globex, attachment.Id); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound in another tenant, got %v", err)
+	}
+	if size, err := r.AttachmentBytesBy(acme, "user:alice"); err != nil || size != 42 {
+		t.Errorf("Expected 42 bytes, got %d %v", size, err)
+	}
+
+	if err := r.Delete(acme, todo.Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	purgeable := func(deletedBefore time.Time) bool {
+		t.Helper()
+		batch, err := r.PurgeableAttachments(acme, deletedBefore, 1000)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return slices.ContainsFunc(batch, func(a model.Attachment) bool { return a.Id == attachment.Id })
+	}
+	if purgeable(time.Now().Add(-time.Hour)) {
+		t.Errorf("Expected the attachment to be kept during the retention")
+	}
+	if !purgeable(time.Now().Add(time.Minute)) {
+		t.Errorf("Expected the attachment of the deleted todo to be purgeable")
+	}
+	if err := r.RemoveAttachments(globex, []string{attachment.Id}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if purgeable(time.Now().Add(time.Minute)) {
+		t.Errorf("Expected the attachment to be removed")
+	}
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/backup"
+	"github.com/lib/pq"
+)
+
+// ReadSnapshot implements backup.Store. fn reads every table of the current
+// schema in one repeatable read transaction, which sees every tenant.
+func (r *Repository) ReadSnapshot(ctx context.Context, fn func(backup.Reader) error) error {
+	return r.inBackup(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, func(tx *sql.Tx) error {
+		return fn(&snapshot{tx: tx})
+	})
+}
+
+// WriteSnapshot implements backup.Store. fn writes to the tables of every
+// tenant in one transaction.
+func (r *Repository) WriteSnapshot(ctx context.Context, fn func(backup.Writer) error) error {
+	return r.inBackup(ctx, nil, func(tx *sql.Tx) error {
+		return fn(&snapshot{tx: tx})
+	})
+}
+
+// inBackup runs fn in a transaction allowed to read and write the rows of
+// every tenant.
+func (r *Repository) inBackup(ctx context.Context, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	return retryStale(func(work *bool) (err error) {
+		conn, err := r.db.Conn(ctx)
+		if err != nil {
+			log.Default().Println("repository: failed to get connection:", err)
+			return err
+		}
+		defer func() { release(conn, err) }()
+
+		tx, err := conn.BeginTx(ctx, opts)
+		if err != nil {
+			log.Default().Println("repository: failed to begin transaction:", err)
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, `SELECT set_config('app.backup', 'on', true)`); err != nil {
+			log.Default().Println("repository: failed to scope transaction:", err)
+			return err
+		}
+		*work = true
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// snapshot is the backup.Reader and backup.Writer of a transaction.
+type snapshot struct {
+	tx *sql.Tx
+}
+
+// Tables returns the tables of the current schema, each after the tables
+// its foreign keys reference.
+func (s *snapshot) Tables(ctx context.Context) ([]string, error) {
+	rows, err := s.tx.QueryContext(ctx, `
+		SELECT c.relname::text, COALESCE(array_agg(DISTINCT ref.relname::text) FILTER (WHERE ref.oid <> c.oid), '{}')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_constraint fk ON fk.conrelid = c.oid AND fk.contype = 'f'
+		LEFT JOIN pg_class ref ON ref.oid = fk.confrelid
+		WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p')
+		GROUP BY c.relname
+		ORDER BY c.relname`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	references := map[string][]string{}
+	var names []string
+	for rows.Next() {
+		var name string
+		var refs pq.StringArray
+		if err := rows.Scan(&name, &refs); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		references[name] = refs
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var ordered []string
+	state := map[string]int{} // 1 while visiting, 2 once ordered
+	var visit func(string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("foreign keys of %s form a cycle", name)
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		for _, ref := range references[name] {
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		ordered = append(ordered, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+func (s *snapshot) Rows(ctx context.Context, table string, fn func(json.RawMessage) error) error {
+	rows, err := s.tx.QueryContext(ctx, fmt.Sprintf(`SELECT row_to_json(t)::text FROM %s t`, pq.QuoteIdentifier(table)))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Sequences returns the sequences that were used, with their last value.
+func (s *snapshot) Sequences(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.tx.QueryContext(ctx, `
+		SELECT sequencename, last_value FROM pg_sequences
+		WHERE schemaname = current_schema() AND last_value IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sequences := map[string]int64{}
+	for rows.Next() {
+		var name string
+		var value int64
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		sequences[name] = value
+	}
+	return sequences, rows.Err()
+}
+
+func (s *snapshot) Count(ctx context.Context, table string) (int64, error) {
+	var n int64
+	err := s.tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT count(*) FROM %s`, pq.QuoteIdentifier(table))).Scan(&n)
+	return n, err
+}
+
+// Insert writes the columns of the rows, leaving the columns added since the
+// backup to their defaults. Conflicts are those on any unique constraint when
+// skipping, and on the primary key when overwriting. An overwritten row takes
+// a new value for every column stamped from a sequence, such as the version
+// of a todo, so that sync clients see it changed.
+func (s *snapshot) Insert(ctx context.Context, table string, rows []json.RawMessage, policy backup.ConflictPolicy) (int64, error) {
+	var first map[string]json.RawMessage
+	if err := json.Unmarshal(rows[0], &first); err != nil {
+		return 0, err
+	}
+	columns, keys, stamped, err := s.columns(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+	var restored []string
+	for column := range first {
+		if !slices.Contains(columns, column) {
+			return 0, fmt.Errorf("column %s of the backup does not exist", column)
+		}
+		restored = append(restored, column)
+	}
+	slices.Sort(restored)
+
+	query := fmt.Sprintf(`INSERT INTO %[1]s (%[2]s) SELECT %[2]s FROM json_populate_recordset(NULL::%[1]s, $1::json)`,
+		pq.QuoteIdentifier(table), quoteIdentifiers(restored))
+	switch policy {
+	case backup.ConflictSkip:
+		query += ` ON CONFLICT DO NOTHING`
+	case backup.ConflictOverwrite:
+		if len(keys) == 0 {
+			return 0, fmt.Errorf("%s has no primary key to overwrite rows by", table)
+		}
+		var updates []string
+		for _, column := range restored {
+			quoted := pq.QuoteIdentifier(column)
+			switch {
+			case slices.Contains(keys, column):
+			case slices.Contains(stamped, column):
+				updates = append(updates, quoted+` = DEFAULT`)
+			default:
+				updates = append(updates, quoted+` = EXCLUDED.`+quoted)
+			}
+		}
+		if len(updates) == 0 {
+			query += fmt.Sprintf(` ON CONFLICT (%s) DO NOTHING`, quoteIdentifiers(keys))
+		} else {
+			query += fmt.Sprintf(` ON CONFLICT (%s) DO UPDATE SET %s`, quoteIdentifiers(keys), strings.Join(updates, ", "))
+		}
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return 0, err
+	}
+	res, err := s.tx.ExecContext(ctx, query, string(data))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// quoteIdentifiers quotes names and joins them with commas.
+func quoteIdentifiers(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = pq.QuoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// columns returns the columns of table, those of its primary key, and the
+// others whose default takes the next value of a sequence.
+func (s *snapshot) columns(ctx context.Context, table string) (columns, keys, stamped []string, err error) {
+	rows, err := s.tx.QueryContext(ctx, `
+		SELECT a.attname, COALESCE(a.attnum = ANY(i.indkey), false),
+			COALESCE(pg_get_expr(d.adbin, d.adrelid) LIKE 'nextval(%', false)
+		FROM pg_attribute a
+		LEFT JOIN pg_index i ON i.indrelid = a.attrelid AND i.indisprimary
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = (quote_ident(current_schema()) || '.' || quote_ident($1))::regclass
+			AND a.attnum > 0 AND NOT a.attisdropped`, table)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column string
+		var key, sequence bool
+		if err := rows.Scan(&column, &key, &sequence); err != nil {
+			return nil, nil, nil, err
+		}
+		columns = append(columns, column)
+		if key {
+			keys = append(keys, column)
+		} else if sequence {
+			stamped = append(stamped, column)
+		}
+	}
+	return columns, keys, stamped, rows.Err()
+}
+
+// AdvanceSequences never moves a sequence back, so that merging a backup
+// into a database that went on does not hand out its values again.
+func (s *snapshot) AdvanceSequences(ctx context.Context, values map[string]int64) error {
+	for name, value := range values {
+		var exists bool
+		err := s.tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_sequences WHERE schemaname = current_schema() AND sequencename = $1)`, name).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		quoted := pq.QuoteIdentifier(name)
+		query := fmt.Sprintf(`SELECT setval($1::regclass, GREATEST($2::bigint, last_value)) FROM %s`, quoted)
+		if _, err := s.tx.ExecContext(ctx, query, quoted, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
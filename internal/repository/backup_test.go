@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/backup"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, globex := tenants()
+	ctx := context.Background()
+
+	todo, err := r.Create(acme, &model.Todo{Title: "Back me up", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := r.Create(globex, &model.Todo{Title: "Me too", Position: "V"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var b bytes.Buffer
+	manifest, err := backup.Backup(ctx, r, &b)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	i := slices.IndexFunc(manifest.Tables, func(table backup.Table) bool { return table.Name == "todos" })
+	if i < 0 || manifest.Tables[i].Rows < 2 || manifest.Sequences["todos_version_seq"] == 0 {
+		t.Fatalf("Expected the todos of both tenants and their sequence, got %+v", manifest)
+	}
+
+	todo.Title = "Changed after the backup"
+	if _, err := r.Update(acme, &todo); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := b.Bytes()
+	if _, err := backup.Restore(ctx, r, bytes.NewReader(data), backup.RestoreOptions{}); err == nil {
+		t.Errorf("Expected restoring into a database with rows to fail")
+	}
+	if _, err := backup.Restore(ctx, r, bytes.NewReader(data), backup.RestoreOptions{Merge: true, OnConflict: backup.ConflictOverwrite}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got, err := r.Get(acme, todo.Id)
+	if err != nil || got.Title != "Back me up" {
+		t.Errorf("Expected the todo as it was backed up, got %v %v", got, err)
+	}
+	if got.Version <= todo.Version {
+		t.Errorf("Expected the overwritten todo to get a new version, got %d after %d", got.Version, todo.Version)
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// CreateCalendarFeed stores a feed with the hash of its secret token.
+func (r *Repository) CreateCalendarFeed(ctx context.Context, feed *model.CalendarFeed, tokenHash []byte) (model.CalendarFeed, error) {
+	feed.Id = uuid.NewString()
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		feed.TenantId = tenant
+		return tx.StmtContext(ctx, r.createFeedStmt).QueryRowContext(ctx, feed.Id, feed.Name, tokenHash, tenant, feed.CreatedBy).Scan(&feed.CreatedAt)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to create calendar feed:", err)
+		return model.CalendarFeed{}, err
+	}
+
+	log.Default().Println("repository: Created calendar feed successfully:", feed.Id)
+	return *feed, nil
+}
+
+func (r *Repository) ListCalendarFeeds(ctx context.Context) ([]model.CalendarFeed, error) {
+	var result []model.CalendarFeed
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.listFeedsStmt).QueryContext(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			f := model.CalendarFeed{TenantId: tenant}
+			if err := rows.Scan(&f.Id, &f.Name, &f.CreatedAt); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, f)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list calendar feeds:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) RevokeCalendarFeed(ctx context.Context, id string) error {
+	var res sql.Result
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var err error
+		res, err = tx.StmtContext(ctx, r.revokeFeedStmt).ExecContext(ctx, id, tenant)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to revoke calendar feed:", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrNotFound
+	}
+
+	log.Default().Println("repository: Revoked calendar feed successfully:", id)
+	return nil
+}
+
+// CalendarFeedByTokenHash returns the active feed whose token has the given
+// hash, in whichever tenant it belongs to.
+func (r *Repository) CalendarFeedByTokenHash(ctx context.Context, tokenHash []byte) (model.CalendarFeed, error) {
+	var f model.CalendarFeed
+
+	err := r.inCredentialLookup(ctx, func(tx *sql.Tx) error {
+		return tx.StmtContext(ctx, r.feedByHashStmt).QueryRowContext(ctx, tokenHash).Scan(&f.Id, &f.Name, &f.CreatedAt, &f.TenantId, &f.CreatedBy)
+	})
+	if err == sql.ErrNoRows {
+		return model.CalendarFeed{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to look up calendar feed:", err)
+		return model.CalendarFeed{}, err
+	}
+	return f, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/lib/pq"
+)
+
+// commentColumns is the column list scanned by scanComment.
+const commentColumns = `id, todo_id, author, body, mentions, created_at, edited_at`
+
+func scanComment(row interface{ Scan(...any) error }, c *model.Comment) error {
+	return row.Scan(&c.Id, &c.TodoId, &c.Author, &c.Body, pq.Array(&c.Mentions), &c.CreatedAt, &c.EditedAt)
+}
+
+// mentionsArray stores comments without mentions as an empty array rather
+// than NULL.
+func mentionsArray(mentions []string) any {
+	if mentions == nil {
+		mentions = []string{}
+	}
+	return pq.Array(mentions)
+}
+
+// CreateComment records a comment on a live todo and bumps the version of
+// the todo, or returns service.ErrNotFound if the todo does not exist.
+func (r *Repository) CreateComment(ctx context.Context, c *model.Comment) (model.Comment, error) {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, r.createCommentStmt).QueryRowContext(
+			ctx,
+			c.Id,
+			c.TodoId,
+			c.Author,
+			c.Body,
+			mentionsArray(c.Mentions),
+			tenant,
+		).Scan(&c.CreatedAt)
+	})
+	if err == sql.ErrNoRows {
+		return model.Comment{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to create comment:", err)
+		return model.Comment{}, err
+	}
+
+	log.Default().Println("repository: Created comment successfully:", c.Id)
+	return *c, nil
+}
+
+func (r *Repository) GetComment(ctx context.Context, id string) (model.Comment, error) {
+	var c model.Comment
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return scanComment(tx.StmtContext(ctx, r.getCommentStmt).QueryRowContext(ctx, id, tenant), &c)
+	})
+	if err == sql.ErrNoRows {
+		return model.Comment{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to get comment:", err)
+		return model.Comment{}, err
+	}
+	return c, nil
+}
+
+func (r *Repository) ListComments(ctx context.Context, opts model.CommentOptions) ([]model.Comment, error) {
+	// the nil UUID sorts before every other id
+	afterID := opts.AfterId
+	if afterID == "" {
+		afterID = uuid.Nil.String()
+	}
+
+	var result []model.Comment
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.listCommentsStmt).QueryContext(ctx, opts.TodoId, tenant, opts.AfterTime, afterID, opts.PageSize)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c model.Comment
+			if err := scanComment(rows, &c); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list comments:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) UpdateComment(ctx context.Context, c *model.Comment) (model.Comment, error) {
+	var updated model.Comment
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		row := tx.StmtContext(ctx, r.updateCommentStmt).QueryRowContext(ctx, c.Id, c.Body, mentionsArray(c.Mentions), tenant)
+		return scanComment(row, &updated)
+	})
+	if err == sql.ErrNoRows {
+		return model.Comment{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to update comment:", err)
+		return model.Comment{}, err
+	}
+
+	log.Default().Println("repository: Updated comment successfully:", c.Id)
+	return updated, nil
+}
+
+// DeleteComment marks a comment deleted and bumps the version of its todo.
+func (r *Repository) DeleteComment(ctx context.Context, id string) error {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var todoID string
+		return tx.StmtContext(ctx, r.deleteCommentStmt).QueryRowContext(ctx, id, tenant).Scan(&todoID)
+	})
+	if err == sql.ErrNoRows {
+		return service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to delete comment:", err)
+		return err
+	}
+
+	log.Default().Println("repository: Deleted comment successfully:", id)
+	return nil
+}
+
+// CommentCounts counts the comments of all todoIDs in one query, so listing
+// todos costs one more query rather than one per todo.
+func (r *Repository) CommentCounts(ctx context.Context, todoIDs []string) (map[string]int32, error) {
+	counts := map[string]int32{}
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.commentCountsStmt).QueryContext(ctx, pq.Array(todoIDs), tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id string
+			var n int32
+			if err := rows.Scan(&id, &n); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			counts[id] = n
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to count comments:", err)
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+func TestComments(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, globex := tenants()
+
+	todo, err := r.Create(acme, &model.Todo{Title: "Crash on start", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	quiet, err := r.Create(acme, &model.Todo{Title: "Update docs", Position: "W"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ids []string
+	for i, body := range []string{"Reproduced, @bob", "Fixed in 1.2", "Confirmed"} {
+		comment := &model.Comment{Id: uuid.NewString(), TodoId: todo.Id, Author: "user:alice", Body: body}
+		if i == 0 {
+			comment.Mentions = []string{"bob"}
+		}
+		c, err := r.CreateComment(acme, comment)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		ids = append(ids, c.Id)
+	}
+	if _, err := r.CreateComment(acme, &model.Comment{Id: uuid.NewString(), TodoId: uuid.NewString(), Author: "user:alice", Body: "?"}); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing todo, got %v", err)
+	}
+	if bumped, _ := r.Get(acme, todo.Id); bumped.Version <= todo.Version {
+		t.Errorf("Expected commenting to bump the version of the todo past %d, got %d", todo.Version, bumped.Version)
+	}
+
+	got, err := r.GetComment(acme, ids[0])
+	if err != nil || got.Body != "Reproduced, @bob" || !slices.Equal(got.Mentions, []string{"bob"}) || got.EditedAt != nil {
+		t.Errorf("Expected the stored comment, got %+v %v", got, err)
+	}
+	if _, err := r.GetComment(globex, ids[0]); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound in another tenant, got %v", err)
+	}
+
+	first, err := r.ListComments(acme, model.CommentOptions{TodoId: todo.Id, PageSize: 2})
+	if err != nil || len(first) != 2 || first[0].Id != ids[0] {
+		t.Fatalf("Expected the first 2 comments, got %v %v", first, err)
+	}
+	rest, err := r.ListComments(acme, model.CommentOptions{TodoId: todo.Id, PageSize: 2, AfterTime: first[1].CreatedAt, AfterId: first[1].Id})
+	if err != nil || len(rest) != 1 || rest[0].Id != ids[2] {
+		t.Errorf("Expected the last comment, got %v %v", rest, err)
+	}
+
+	edited, err := r.UpdateComment(acme, &model.Comment{Id: ids[1], Body: "Fixed in 1.3"})
+	if err != nil || edited.Body != "Fixed in 1.3" || edited.EditedAt == nil || edited.Mentions == nil {
+		t.Errorf("Expected the comment to be edited, got %+v %v", edited, err)
+	}
+
+	if err := r.DeleteComment(acme, ids[2]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := r.DeleteComment(acme, ids[2]); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a deleted comment, got %v", err)
+	}
+
+	counts, err := r.CommentCounts(acme, []string{todo.Id, quiet.Id})
+	if err != nil || counts[todo.Id] != 2 || counts[quiet.Id] != 0 {
+		t.Errorf("Expected 2 comments and none, got %v %v", counts, err)
+	}
+	if counts, err := r.CommentCounts(globex, []string{todo.Id}); err != nil || len(counts) != 0 {
+		t.Errorf("Expected no comments in another tenant, got %v %v", counts, err)
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/lib/pq"
+)
+
+// AddDependency records that d.TodoId is blocked by d.BlockedById and bumps
+// the version of d.TodoId, or returns the dependency as recorded before. It
+// returns service.ErrNotFound unless both todos exist.
+func (r *Repository) AddDependency(ctx context.Context, d *model.Dependency) (model.Dependency, error) {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		err := tx.StmtContext(ctx, r.addDependencyStmt).QueryRowContext(ctx, d.TodoId, d.BlockedById, tenant).Scan(&d.CreatedAt)
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return tx.StmtContext(ctx, r.getDependencyStmt).QueryRowContext(ctx, d.TodoId, d.BlockedById, tenant).Scan(&d.CreatedAt)
+	})
+	if err == sql.ErrNoRows {
+		return model.Dependency{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to add dependency:", err)
+		return model.Dependency{}, err
+	}
+
+	log.Default().Println("repository: Added dependency successfully:", d.TodoId, d.BlockedById)
+	return *d, nil
+}
+
+func (r *Repository) RemoveDependency(ctx context.Context, todoID, blockedByID string) error {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var id string
+		return tx.StmtContext(ctx, r.removeDependencyStmt).QueryRowContext(ctx, todoID, blockedByID, tenant).Scan(&id)
+	})
+	if err == sql.ErrNoRows {
+		return service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to remove dependency:", err)
+		return err
+	}
+
+	log.Default().Println("repository: Removed dependency successfully:", todoID, blockedByID)
+	return nil
+}
+
+// DependsOn follows the blockers of todoID recursively, so callers should
+// run it in a serializable transaction with the write it guards.
+func (r *Repository) DependsOn(ctx context.Context, todoID, blockedByID string) (bool, error) {
+	var found bool
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, r.dependsOnStmt).QueryRowContext(ctx, todoID, blockedByID, tenant).Scan(&found)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to follow dependencies:", err)
+		return false, err
+	}
+	return found, nil
+}
+
+func (r *Repository) Dependencies(ctx context.Context, todoIDs []string) ([]model.Dependency, error) {
+	var result []model.Dependency
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.dependenciesStmt).QueryContext(ctx, pq.Array(todoIDs), tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var d model.Dependency
+			if err := rows.Scan(&d.TodoId, &d.BlockedById, &d.CreatedAt); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list dependencies:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// BlockedTodos finds the blocked todos among todoIDs in one query, so
+// listing todos costs one more query rather than one per todo.
+func (r *Repository) BlockedTodos(ctx context.Context, todoIDs []string) (map[string]bool, error) {
+	blocked := map[string]bool{}
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.blockedTodosStmt).QueryContext(ctx, pq.Array(todoIDs), tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			blocked[id] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to find blocked todos:", err)
+		return nil, err
+	}
+	return blocked, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+func TestDependencies(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, globex := tenants()
+
+	var todos []model.Todo
+	var ids []string
+	for i, title := range []string{"Deploy", "Test", "Build"} {
+		todo, err := r.Create(acme, &model.Todo{Title: title, Position: string(rune('V' + i))})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		todos, ids = append(todos, todo), append(ids, todo.Id)
+	}
+	deploy, test, build := ids[0], ids[1], ids[2]
+
+	for _, d := range []model.Dependency{{TodoId: deploy, BlockedById: test}, {TodoId: test, BlockedById: build}} {
+		if _, err := r.AddDependency(acme, &d); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	again, err := r.AddDependency(acme, &model.Dependency{TodoId: deploy, BlockedById: test})
+	if err != nil || again.CreatedAt.IsZero() {
+		t.Errorf("Expected adding a dependency again to return it, got %+v %v", again, err)
+	}
+	if _, err := r.AddDependency(acme, &model.Dependency{TodoId: deploy, BlockedById: uuid.NewString()}); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing blocker, got %v", err)
+	}
+	if _, err := r.AddDependency(globex, &model.Dependency{TodoId: build, BlockedById: deploy}); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound in another tenant, got %v", err)
+	}
+
+	if found, err := r.DependsOn(acme, deploy, build); err != nil || !found {
+		t.Errorf("Expected Deploy to depend on Build through Test, got %v %v", found, err)
+	}
+	if found, err := r.DependsOn(acme, build, deploy); err != nil || found {
+		t.Errorf("Expected Build not to depend on Deploy, got %v %v", found, err)
+	}
+	if deps, err := r.Dependencies(acme, []string{deploy, test}); err != nil || len(deps) != 1 {
+		t.Errorf("Expected the one dependency between Deploy and Test, got %v %v", deps, err)
+	}
+
+	blocked, err := r.BlockedTodos(acme, ids)
+	if err != nil || !blocked[deploy] || !blocked[test] || blocked[build] {
+		t.Errorf("Expected Deploy and Test to be blocked, got %v %v", blocked, err)
+	}
+	todos[2].Completed = true
+	if _, err := r.Update(acme, &todos[2]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := r.Delete(acme, test); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if blocked, err := r.BlockedTodos(acme, ids); err != nil || len(blocked) != 0 {
+		t.Errorf("Expected completed and deleted blockers to block nothing, got %v %v", blocked, err)
+	}
+
+	if err := r.RemoveDependency(acme, deploy, test); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := r.RemoveDependency(acme, deploy, test); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a removed dependency, got %v", err)
+	}
+}
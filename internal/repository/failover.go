@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"strings"
+)
+
+// release returns conn to the pool once the transaction on it ended with
+// err, unless err shows that the connection outlived a failover or lost its
+// prepared statements. Such a connection is discarded, and database/sql
+// prepares the statements again on the connection that replaces it.
+func release(conn *sql.Conn, err error) {
+	if stale(err) {
+		log.Default().Println("repository: discarding connection after:", err)
+		conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	conn.Close()
+}
+
+// retryStale runs attempt, and once more if it failed on a stale connection
+// before it got to the work of the caller, which it marks by setting *work.
+// release discarded the connection, so the second attempt runs on a fresh
+// one. Work that ran is not repeated, since the caller may have kept part of
+// what it read.
+func retryStale(attempt func(work *bool) error) error {
+	var work bool
+	err := attempt(&work)
+	if err == nil || work || !stale(err) {
+		return err
+	}
+	log.Default().Println("repository: retrying on a fresh connection after:", err)
+	return attempt(&work)
+}
+
+// stale reports whether err means the connection should not be reused: the
+// server is shutting down or was demoted to a read-only standby, or a
+// prepared statement is gone or was planned for an older schema.
+func stale(err error) bool {
+	code, message := sqlState(err)
+	switch {
+	case strings.HasPrefix(code, "08"):
+		// connection exceptions
+		return true
+	case code == "57P01", code == "57P02", code == "57P03":
+		// admin shutdown, crash shutdown, and cannot connect now
+		return true
+	case code == "25006", code == "26000":
+		// read-only transaction, and prepared statement does not exist
+		return true
+	case code == "0A000":
+		return strings.Contains(message, "cached plan must not change result type")
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/lib/pq"
+)
+
+func TestStale(t *testing.T) {
+	for _, err := range []error{
+		&pq.Error{Code: "25006"},
+		fmt.Errorf("get: %w", &pq.Error{Code: "26000"}),
+		&pq.Error{Code: "57P01"},
+		&pq.Error{Code: "08006"},
+		&pq.Error{Code: "0A000", Message: "cached plan must not change result type"},
+	} {
+		if !stale(err) {
+			t.Errorf("Expected %v to discard the connection", err)
+		}
+	}
+	for _, err := range []error{&pq.Error{Code: "57014"}, &pq.Error{Code: "0A000"}, &pq.Error{Code: "23505"}, errors.New("boom"), nil} {
+		if stale(err) {
+			t.Errorf("Expected %v to keep the connection", err)
+		}
+	}
+}
+
+func TestRetryStale(t *testing.T) {
+	lost := &pq.Error{Code: "26000"}
+	attempts := 0
+	err := retryStale(func(work *bool) error {
+		attempts++
+		if attempts == 1 {
+			return lost
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("Expected a second attempt after a stale connection, got %d attempts and %v", attempts, err)
+	}
+
+	attempts = 0
+	err = retryStale(func(work *bool) error {
+		attempts++
+		*work = true
+		return lost
+	})
+	if err != lost || attempts != 1 {
+		t.Errorf("Expected the work not to run twice, got %d attempts and %v", attempts, err)
+	}
+
+	attempts = 0
+	err = retryStale(func(work *bool) error {
+		attempts++
+		return &pq.Error{Code: "23505"}
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Expected other errors not to be retried, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestStatementsPreparedAgain(t *testing.T) {
+	r, conn := newTestRepository(t)
+	acme, _ := tenants()
+	conn.SetMaxOpenConns(1)
+	defer conn.SetMaxOpenConns(0)
+
+	todo, err := r.Create(acme, &model.Todo{Title: "Survive a failover", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// forget the prepared statements of the only connection, as a
+	// connection pooler or a restarted server would
+	if _, err := conn.Exec("DEALLOCATE ALL"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got, err := r.Get(acme, todo.Id); err != nil || got.Id != todo.Id {
+		t.Errorf("Expected the todo on a new connection, got %v %v", got, err)
+	}
+}
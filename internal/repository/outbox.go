@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/lib/pq"
+)
+
+// todoState is what the events of a write are derived from: whether the
+// todo was completed and deleted before it.
+type todoState struct {
+	completed bool
+	deleted   bool
+}
+
+// eventTypes returns the events caused by a write that left t behind, where
+// before is nil if the write created the todo.
+func eventTypes(before *todoState, t model.Todo) []string {
+	switch {
+	case before == nil:
+		return []string{model.EventTodoCreated}
+	case t.Deleted && before.deleted:
+		return nil
+	case t.Deleted:
+		return []string{model.EventTodoDeleted}
+	case t.Completed && !before.completed:
+		return []string{model.EventTodoUpdated, model.EventTodoCompleted}
+	default:
+		return []string{model.EventTodoUpdated}
+	}
+}
+
+// before locks a todo and returns its state, or sql.ErrNoRows.
+func (r *Repository) before(ctx context.Context, tx *sql.Tx, tenant, id string) (*todoState, error) {
+	var state todoState
+	err := tx.StmtContext(ctx, r.beforeStmt).QueryRowContext(ctx, id, tenant).Scan(&state.completed, &state.deleted)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// recordEvents appends the events of a write to the outbox in the write's
+// transaction, so they are published if and only if it commits.
+func (r *Repository) recordEvents(ctx context.Context, tx *sql.Tx, tenant string, before *todoState, t model.Todo) error {
+	events, err := newEvents(before, t)
+	if err != nil {
+		return err
+	}
+	stmt := tx.StmtContext(ctx, r.appendOutboxStmt)
+	for _, e := range events {
+		if _, err := stmt.ExecContext(ctx, e.id, t.Id, e.event, e.payload, tenant); err != nil {
+			log.Default().Println("repository: failed to record event:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// outboxEvent is an event of a write, ready to be appended to the outbox.
+type outboxEvent struct {
+	id      string
+	event   string
+	payload string
+}
+
+// newEvents returns the events of a write that left t behind.
+func newEvents(before *todoState, t model.Todo) ([]outboxEvent, error) {
+	var events []outboxEvent
+	for _, event := range eventTypes(before, t) {
+		e := model.Event{
+			Id:        uuid.NewString(),
+			Type:      event,
+			CreatedAt: time.Now().UTC(),
+			Data:      model.EventData{Todo: t},
+		}
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, outboxEvent{id: e.Id, event: event, payload: string(payload)})
+	}
+	return events, nil
+}
+
+// inRelay runs fn in a transaction allowed to read and delete the outbox
+// messages of every tenant.
+func (r *Repository) inRelay(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return r.inTx(ctx, r.outboxRelayStmt, nil, fn)
+}
+
+// RelayOutbox passes up to limit unpublished messages of every tenant,
+// oldest first, to publish and deletes those whose ids it returns. It
+// returns the number of messages read, which is zero if another relay holds
+// the outbox. A crash before the deletion commits publishes the messages
+// again.
+func (r *Repository) RelayOutbox(ctx context.Context, limit int, publish func([]model.OutboxMessage) []int64) (int, error) {
+	var messages []model.OutboxMessage
+	err := r.inRelay(ctx, func(tx *sql.Tx) error {
+		var locked bool
+		if err := tx.StmtContext(ctx, r.lockOutboxStmt).QueryRowContext(ctx).Scan(&locked); err != nil || !locked {
+			return err
+		}
+
+		rows, err := tx.StmtContext(ctx, r.pendingOutboxStmt).QueryContext(ctx, limit)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var m model.OutboxMessage
+			var payload string
+			if err := rows.Scan(&m.Id, &m.EventId, &m.TenantId, &m.AggregateId, &m.Type, &payload, &m.CreatedAt); err != nil {
+				rows.Close()
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			m.Payload = []byte(payload)
+			messages = append(messages, m)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil || len(messages) == 0 {
+			return err
+		}
+
+		published := publish(messages)
+		_, err = tx.StmtContext(ctx, r.deleteOutboxStmt).ExecContext(ctx, pq.Array(published))
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to relay outbox:", err)
+		return 0, err
+	}
+	return len(messages), nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestEventTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		before *todoState
+		after  model.Todo
+		want   []string
+	}{
+		{"created", nil, model.Todo{}, []string{model.EventTodoCreated}},
+		{"updated", &todoState{}, model.Todo{Title: "renamed"}, []string{model.EventTodoUpdated}},
+		{"completed", &todoState{}, model.Todo{Completed: true}, []string{model.EventTodoUpdated, model.EventTodoCompleted}},
+		{"still completed", &todoState{completed: true}, model.Todo{Completed: true}, []string{model.EventTodoUpdated}},
+		{"deleted", &todoState{}, model.Todo{Deleted: true}, []string{model.EventTodoDeleted}},
+		{"deleted again", &todoState{deleted: true}, model.Todo{Deleted: true}, nil},
+	}
+	for _, tt := range tests {
+		if got := eventTypes(tt.before, tt.after); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestOutboxRecordsChanges(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, _ := tenants()
+
+	todo, err := r.Create(acme, &model.Todo{Title: "Ship release", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	todo.Completed = true
+	if _, err := r.Update(acme, &todo); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := r.Delete(acme, todo.Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// deleting twice changes nothing and records nothing
+	if err := r.Delete(acme, todo.Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var types []string
+	for {
+		n, err := r.RelayOutbox(context.Background(), 100, func(messages []model.OutboxMessage) []int64 {
+			var ids []int64
+			for _, m := range messages {
+				ids = append(ids, m.Id)
+				if m.TenantId != auth.Tenant(acme) {
+					continue
+				}
+				var e model.Event
+				if err := json.Unmarshal(m.Payload, &e); err != nil || e.Data.Todo.Id != todo.Id || m.AggregateId != todo.Id {
+					t.Errorf("Expected an event about the todo, got %s %v", m.Payload, err)
+				}
+				types = append(types, m.Type)
+			}
+			return ids
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if n == 0 {
+			break
+		}
+	}
+
+	want := []string{model.EventTodoCreated, model.EventTodoUpdated, model.EventTodoCompleted, model.EventTodoDeleted}
+	if !slices.Equal(types, want) {
+		t.Errorf("Expected %v, got %v", want, types)
+	}
+}
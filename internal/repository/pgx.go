@@ -0,0 +1,569 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// copyThreshold is the batch size from which CreateBatch copies todos with
+// COPY FROM instead of pipelining an insert per todo.
+const copyThreshold = 100
+
+// PgxRepository is a Repository on a pgx connection pool. Create,
+// CreateBatch, Get, Update, Delete and List talk to Postgres natively:
+// they pipeline the tenant setup with their queries, send UUIDs and
+// timestamps in binary, and copy large batches with COPY FROM, while pgx
+// prepares and caches their statements per connection. Everything else
+// runs on the embedded database/sql Repository, which shares the pool. In a
+// unit of work both run on its connection.
+type PgxRepository struct {
+	*Repository
+	pool *pgxpool.Pool
+	// conn is the connection of the unit of work, if the repository is
+	// bound to one.
+	conn *sql.Conn
+
+	// the queries of the embedded repository's statements
+	setTenantQuery      string
+	createQuery         string
+	getQuery            string
+	listQuery           string
+	listByPriorityQuery string
+	listByPositionQuery string
+	updateQuery         string
+	deleteQuery         string
+	beforeQuery         string
+	appendOutboxQuery   string
+}
+
+// NewPgxRepository creates a PgxRepository on pool. Closing it leaves the
+// pool open.
+func NewPgxRepository(pool *pgxpool.Pool, opts ...Option) (*PgxRepository, error) {
+	db := stdlib.OpenDBFromPool(pool)
+	r, err := NewRepository(db, opts...)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	queries := map[**sql.Stmt]string{}
+	for _, s := range r.statements() {
+		queries[s.stmt] = s.query
+	}
+	return &PgxRepository{
+		Repository: r,
+		pool:       pool,
+
+		setTenantQuery:      queries[&r.setTenantStmt],
+		createQuery:         queries[&r.createStmt],
+		getQuery:            queries[&r.getStmt],
+		listQuery:           queries[&r.listStmt],
+		listByPriorityQuery: queries[&r.listByPriorityStmt],
+		listByPositionQuery: queries[&r.listByPositionStmt],
+		updateQuery:         queries[&r.updateStmt],
+		deleteQuery:         queries[&r.deleteStmt],
+		beforeQuery:         queries[&r.beforeStmt],
+		appendOutboxQuery:   queries[&r.appendOutboxStmt],
+	}, nil
+}
+
+func (p *PgxRepository) Close() {
+	p.Repository.Close()
+	p.db.Close()
+}
+
+// WithinTx runs fn with a copy of the repository bound to a single
+// transaction, as Repository.WithinTx does. The native methods of the copy
+// send their queries on the connection of the transaction, so they join it.
+func (p *PgxRepository) WithinTx(ctx context.Context, opts service.TxOptions, fn func(service.Repository) error) error {
+	if p.tx != nil {
+		return fn(p)
+	}
+	return retryTx(ctx, func() error {
+		return p.runTx(ctx, opts, func(unit *Repository, conn *sql.Conn) error {
+			bound := *p
+			bound.Repository, bound.conn = unit, conn
+			return fn(&bound)
+		})
+	})
+}
+
+// pgxConn is what the native methods send their queries on: a transaction
+// of the pool, or the connection of a unit of work.
+type pgxConn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, rows pgx.CopyFromSource) (int64, error)
+}
+
+// withUnitConn calls fn with the pgx connection of the unit of work.
+func (p *PgxRepository) withUnitConn(fn func(conn *pgx.Conn) error) error {
+	return p.conn.Raw(func(driverConn any) error {
+		return fn(driverConn.(*stdlib.Conn).Conn())
+	})
+}
+
+// send sends b in the unit of work, if the repository is bound to one, and
+// otherwise on the pool, where it runs in a single implicit transaction.
+func (p *PgxRepository) send(ctx context.Context, b *pgx.Batch) error {
+	if p.conn != nil {
+		return p.withUnitConn(func(conn *pgx.Conn) error {
+			return conn.SendBatch(ctx, b).Close()
+		})
+	}
+	return p.pool.SendBatch(ctx, b).Close()
+}
+
+// tenantBatch returns a batch that starts by scoping its transaction to
+// tenant. Sent on its own, a batch runs in a single implicit transaction.
+func (p *PgxRepository) tenantBatch(tenant string) *pgx.Batch {
+	b := &pgx.Batch{}
+	b.Queue(p.setTenantQuery, tenant)
+	return b
+}
+
+// inTenant runs fn in a transaction and commits it if fn succeeds, or in
+// the unit of work of the repository if it has one. fn must send
+// tenantBatch first.
+func (p *PgxRepository) inTenant(ctx context.Context, fn func(tx pgxConn, tenant string) error) error {
+	tenant := auth.Tenant(ctx)
+	if p.conn != nil {
+		return p.withUnitConn(func(conn *pgx.Conn) error {
+			return fn(conn, tenant)
+		})
+	}
+	return pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		return fn(tx, tenant)
+	})
+}
+
+// Create inserts a new todo. A todo without a series starts its own; a todo
+// continuing a series fails with service.ErrAlreadyExists if that occurrence
+// was already created.
+func (p *PgxRepository) Create(ctx context.Context, t *model.Todo) (model.Todo, error) {
+	var created []model.Todo
+	err := p.inTenant(ctx, func(tx pgxConn, tenant string) error {
+		var err error
+		created, err = p.insert(ctx, tx, tenant, []*model.Todo{t})
+		return err
+	})
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	p.wrote(ctx)
+	log.Default().Println("repository: Created todo successfully:", created[0].Id)
+	return created[0], nil
+}
+
+// CreateBatch inserts all todos in a single transaction, so either all of
+// them are created or none is. Batches of copyThreshold todos or more are
+// copied with COPY FROM.
+func (p *PgxRepository) CreateBatch(ctx context.Context, todos []*model.Todo) ([]model.Todo, error) {
+	var result []model.Todo
+	err := p.inTenant(ctx, func(tx pgxConn, tenant string) error {
+		var err error
+		if len(todos) >= copyThreshold {
+			result, err = p.copyTodos(ctx, tx, tenant, todos)
+		} else {
+			result, err = p.insert(ctx, tx, tenant, todos)
+		}
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to create batch:", err)
+		return nil, err
+	}
+
+	p.wrote(ctx)
+	log.Default().Println("repository: Created todos successfully, count:", len(result))
+	return result, nil
+}
+
+// newTodo assigns t its id, and starts a series if it continues none.
+func newTodo(t *model.Todo) uuid.UUID {
+	id := uuid.New()
+	t.Id = id.String()
+	if t.SeriesId == "" {
+		t.SeriesId, t.Occurrence = t.Id, 1
+	}
+	return id
+}
+
+// insert pipelines an insert per todo in one round trip and their events in
+// a second one.
+func (p *PgxRepository) insert(ctx context.Context, tx pgxConn, tenant string, todos []*model.Todo) ([]model.Todo, error) {
+	b := p.tenantBatch(tenant)
+	for _, t := range todos {
+		id := newTodo(t)
+		b.Queue(p.createQuery,
+			id,
+			t.Title,
+			t.Completed,
+			t.Priority,
+			t.Position,
+			t.DueAt,
+			t.Rrule,
+			t.Timezone,
+			t.RecurrenceMode,
+			t.SeriesId,
+			t.Occurrence,
+			t.CreatedBy,
+			t.ListId,
+			tenant,
+			t.Description,
+			t.DescriptionHtml,
+			jsonColumn[[]model.Link]{&t.Links},
+			jsonColumn[[]model.ChecklistItem]{&t.Checklist},
+		).QueryRow(func(row pgx.Row) error {
+			err := row.Scan(&t.Version, &t.UpdatedAt)
+			if errors.Is(err, pgx.ErrNoRows) {
+				log.Default().Println("repository: todo occurrence already exists:", t.SeriesId, t.Occurrence)
+				return service.ErrAlreadyExists
+			}
+			return err
+		})
+	}
+	if err := tx.SendBatch(ctx, b).Close(); err != nil {
+		if !errors.Is(err, service.ErrAlreadyExists) {
+			log.Default().Println("repository: failed to create todo:", err)
+		}
+		return nil, err
+	}
+
+	result := make([]model.Todo, len(todos))
+	events := &pgx.Batch{}
+	for i, t := range todos {
+		result[i] = *t
+		if err := p.queueEvents(events, tenant, nil, *t); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.SendBatch(ctx, events).Close(); err != nil {
+		log.Default().Println("repository: failed to record event:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// copyTodos copies todos and their events into temporary tables with COPY
+// FROM and inserts them from there: COPY FROM cannot write to tables with
+// row-level security, which the INSERT applies.
+func (p *PgxRepository) copyTodos(ctx context.Context, tx pgxConn, tenant string, todos []*model.Todo) ([]model.Todo, error) {
+	if _, err := tx.Exec(ctx, p.setTenantQuery, tenant); err != nil {
+		log.Default().Println("repository: failed to scope transaction:", err)
+		return nil, err
+	}
+	_, err := tx.Exec(ctx, `
+		CREATE TEMPORARY TABLE todos_copy (
+			ord INTEGER, id UUID, title TEXT, completed BOOLEAN, priority SMALLINT, position TEXT,
+			due_at TIMESTAMPTZ, rrule TEXT, timezone TEXT, recurrence_mode SMALLINT,
+			series_id UUID, occurrence INTEGER, created_by TEXT, list_id UUID,
+			description TEXT, description_html TEXT, links JSONB, checklist JSONB
+		) ON COMMIT DROP
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, len(todos))
+	byId := make(map[uuid.UUID]*model.Todo, len(todos))
+	for i, t := range todos {
+		id := newTodo(t)
+		series, err := pgUUID(t.SeriesId)
+		if err != nil {
+			return nil, err
+		}
+		list, err := pgUUID(t.ListId)
+		if err != nil {
+			return nil, err
+		}
+		byId[id] = t
+		rows[i] = []any{
+			i, id, t.Title, t.Completed, t.Priority, t.Position,
+			t.DueAt, t.Rrule, t.Timezone, t.RecurrenceMode,
+			series, t.Occurrence, t.CreatedBy, list,
+			t.Description, t.DescriptionHtml, jsonColumn[[]model.Link]{&t.Links}, jsonColumn[[]model.ChecklistItem]{&t.Checklist},
+		}
+	}
+	columns := []string{
+		"ord", "id", "title", "completed", "priority", "position",
+		"due_at", "rrule", "timezone", "recurrence_mode",
+		"series_id", "occurrence", "created_by", "list_id",
+		"description", "description_html", "links", "checklist",
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"todos_copy"}, columns, pgx.CopyFromRows(rows)); err != nil {
+		log.Default().Println("repository: failed to copy todos:", err)
+		return nil, err
+	}
+
+	inserted, err := tx.Query(ctx, `
+		INSERT INTO todos (id, title, completed, priority, position,
+			due_at, rrule, timezone, recurrence_mode, series_id, occurrence, created_by, list_id, tenant_id,
+			description, description_html, links, checklist)
+		SELECT id, title, completed, priority, position,
+			due_at, rrule, timezone, recurrence_mode, series_id, occurrence, created_by, list_id, $1,
+			description, description_html, links, checklist
+		FROM todos_copy
+		ORDER BY ord
+		ON CONFLICT DO NOTHING
+		RETURNING id, version, updated_at
+	`, tenant)
+	if err != nil {
+		return nil, err
+	}
+	count := 0
+	for inserted.Next() {
+		var id uuid.UUID
+		var version int64
+		var updatedAt pgtype.Timestamptz
+		if err := inserted.Scan(&id, &version, &updatedAt); err != nil {
+			inserted.Close()
+			return nil, err
+		}
+		t := byId[id]
+		t.Version, t.UpdatedAt = version, &updatedAt.Time
+		count++
+	}
+	inserted.Close()
+	if err := inserted.Err(); err != nil {
+		log.Default().Println("repository: failed to create todos:", err)
+		return nil, err
+	}
+	if count < len(todos) {
+		log.Default().Println("repository: todo occurrences already exist:", len(todos)-count)
+		return nil, service.ErrAlreadyExists
+	}
+
+	result := make([]model.Todo, len(todos))
+	var events [][]any
+	for i, t := range todos {
+		result[i] = *t
+		created, err := newEvents(nil, *t)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range created {
+			events = append(events, []any{len(events), e.id, t.Id, e.event, e.payload})
+		}
+	}
+	_, err = tx.Exec(ctx, `
+		CREATE TEMPORARY TABLE outbox_copy (ord INTEGER, event_id UUID, aggregate_id TEXT, event TEXT, payload TEXT) ON COMMIT DROP
+	`)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"outbox_copy"}, []string{"ord", "event_id", "aggregate_id", "event", "payload"}, pgx.CopyFromRows(events)); err != nil {
+		log.Default().Println("repository: failed to copy events:", err)
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO outbox (event_id, aggregate_id, event, payload, tenant_id)
+		SELECT event_id, aggregate_id, event, payload, $1
+		FROM outbox_copy
+		ORDER BY ord
+	`, tenant)
+	if err != nil {
+		log.Default().Println("repository: failed to record events:", err)
+		return nil, err
+	}
+	// a unit of work may copy again before it commits
+	if _, err := tx.Exec(ctx, `DROP TABLE todos_copy, outbox_copy`); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// pgUUID converts an id to a UUID, NULL if it is empty.
+func pgUUID(id string) (pgtype.UUID, error) {
+	if id == "" {
+		return pgtype.UUID{}, nil
+	}
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return pgtype.UUID{}, err
+	}
+	return pgtype.UUID{Bytes: u, Valid: true}, nil
+}
+
+// queueEvents queues appending the events of a write that left t behind.
+func (p *PgxRepository) queueEvents(b *pgx.Batch, tenant string, before *todoState, t model.Todo) error {
+	events, err := newEvents(before, t)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		b.Queue(p.appendOutboxQuery, e.id, t.Id, e.event, e.payload, tenant)
+	}
+	return nil
+}
+
+// recordEvents appends the events of a write to the outbox in its
+// transaction.
+func (p *PgxRepository) recordEvents(ctx context.Context, tx pgxConn, tenant string, before *todoState, t model.Todo) error {
+	b := &pgx.Batch{}
+	if err := p.queueEvents(b, tenant, before, t); err != nil {
+		return err
+	}
+	if err := tx.SendBatch(ctx, b).Close(); err != nil {
+		log.Default().Println("repository: failed to record event:", err)
+		return err
+	}
+	return nil
+}
+
+func (p *PgxRepository) Get(ctx context.Context, id string) (model.Todo, error) {
+	if rep := p.reader(ctx); rep != nil {
+		// a todo missing on the replica may just not have arrived yet
+		t, err := rep.repo.Get(ctx, id)
+		if err == nil && t.Id != "" {
+			return t, nil
+		}
+		if err != nil {
+			p.replicaFailed(rep, err)
+		}
+	}
+
+	tenant := auth.Tenant(ctx)
+	var t model.Todo
+	b := p.tenantBatch(tenant)
+	b.Queue(p.getQuery, id, tenant).QueryRow(func(row pgx.Row) error {
+		return scanTodo(row, &t)
+	})
+	err := p.send(ctx, b)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Default().Println("repository: todo not found:", id)
+		return model.Todo{}, nil
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to get todo:", err)
+		return model.Todo{}, err
+	}
+
+	log.Default().Println("repository: Fetched todo successfully:", t.Id)
+	return t, nil
+}
+
+func (p *PgxRepository) Update(ctx context.Context, t *model.Todo) (model.Todo, error) {
+	var updated model.Todo
+
+	err := p.inTenant(ctx, func(tx pgxConn, tenant string) error {
+		var before todoState
+		b := p.tenantBatch(tenant)
+		b.Queue(p.beforeQuery, t.Id, tenant).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&before.completed, &before.deleted)
+		})
+		b.Queue(p.updateQuery,
+			t.Title,
+			t.Completed,
+			t.Priority,
+			t.DueAt,
+			t.Rrule,
+			t.Timezone,
+			t.RecurrenceMode,
+			t.Id,
+			tenant,
+			t.Description,
+			t.DescriptionHtml,
+			jsonColumn[[]model.Link]{&t.Links},
+			jsonColumn[[]model.ChecklistItem]{&t.Checklist},
+		).QueryRow(func(row pgx.Row) error {
+			return scanTodo(row, &updated)
+		})
+		if err := tx.SendBatch(ctx, b).Close(); err != nil {
+			return err
+		}
+		return p.recordEvents(ctx, tx, tenant, &before, updated)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Default().Println("repository: todo not found:", t.Id)
+		return model.Todo{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to update todo:", err)
+		return model.Todo{}, err
+	}
+
+	p.wrote(ctx)
+	log.Default().Println("repository: Updated todo successfully:", t.Id)
+	return updated, nil
+}
+
+func (p *PgxRepository) Delete(ctx context.Context, id string) error {
+	err := p.inTenant(ctx, func(tx pgxConn, tenant string) error {
+		var deleted model.Todo
+		b := p.tenantBatch(tenant)
+		b.Queue(p.deleteQuery, id, tenant).QueryRow(func(row pgx.Row) error {
+			return scanTodo(row, &deleted)
+		})
+		err := tx.SendBatch(ctx, b).Close()
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		deleted.Deleted = true
+		return p.recordEvents(ctx, tx, tenant, &todoState{completed: deleted.Completed}, deleted)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to delete todo:", err)
+		return err
+	}
+	p.wrote(ctx)
+	return nil
+}
+
+func (p *PgxRepository) List(ctx context.Context, opts model.ListOptions) ([]model.Todo, error) {
+	if rep := p.reader(ctx); rep != nil {
+		result, err := rep.repo.List(ctx, opts)
+		if err == nil {
+			return result, nil
+		}
+		p.replicaFailed(rep, err)
+	}
+
+	query := p.listQuery
+	switch opts.Order {
+	case model.ListOrderPriority:
+		query = p.listByPriorityQuery
+	case model.ListOrderPosition:
+		query = p.listByPositionQuery
+	}
+
+	tenant := auth.Tenant(ctx)
+	var result []model.Todo
+	b := p.tenantBatch(tenant)
+	b.Queue(query, opts.ListId, opts.VisibleLists, tenant, opts.Query, opts.CreatedBy).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var t model.Todo
+			if err := scanTodo(rows, &t); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				continue
+			}
+			result = append(result, t)
+		}
+		return rows.Err()
+	})
+	if err := p.send(ctx, b); err != nil {
+		log.Default().Println("repository: failed to list todos:", err)
+		return nil, err
+	}
+
+	log.Default().Println("repository: Listed todos successfully, count:", len(result))
+	return result, nil
+}
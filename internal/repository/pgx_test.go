@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestPgxRepository is newTestRepository for a PgxRepository, whose
+// connections report to tracer if set.
+func newTestPgxRepository(t testing.TB, tracer ...pgx.QueryTracer) *PgxRepository {
+	t.Helper()
+	newTestRepository(t) // ensures the tables
+
+	config, err := pgxpool.ParseConfig(os.Getenv("TODOS_TEST_DATABASE_URL"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tracer) > 0 {
+		config.ConnConfig.Tracer = tracer[0]
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(pool.Close)
+	r, err := NewPgxRepository(pool)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(r.Close)
+	return r
+}
+
+func TestPgxRepository(t *testing.T) {
+	r := newTestPgxRepository(t)
+	acme, globex := tenants()
+
+	todo, err := r.Create(acme, &model.Todo{Title: "Native", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got, err := r.Get(acme, todo.Id); err != nil || got.Title != "Native" || got.UpdatedAt == nil {
+		t.Errorf("Expected the created todo, got %v %v", got, err)
+	}
+	if got, err := r.Get(globex, todo.Id); err != nil || got.Id != "" {
+		t.Errorf("Expected another tenant's todo to be invisible, got %v %v", got, err)
+	}
+
+	todo.Completed = true
+	if updated, err := r.Update(acme, &todo); err != nil || !updated.Completed || updated.Version <= todo.Version {
+		t.Errorf("Expected the todo to be completed with a new version, got %v %v", updated, err)
+	}
+	if _, err := r.Update(globex, &todo); err != service.ErrNotFound {
+		t.Errorf("Expected ErrNotFound in another tenant, got %v", err)
+	}
+	if err := r.Delete(acme, todo.Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if list, err := r.List(acme, model.ListOptions{}); err != nil || len(list) != 0 {
+		t.Errorf("Expected no todos after the delete, got %v %v", list, err)
+	}
+}
+
+func TestPgxCreateBatch(t *testing.T) {
+	r := newTestPgxRepository(t)
+
+	// below the threshold inserts are pipelined, above it todos are copied
+	for _, n := range []int{3, copyThreshold + 1} {
+		acme, _ := tenants()
+		todos := make([]*model.Todo, n)
+		for i := range todos {
+			todos[i] = &model.Todo{Title: fmt.Sprintf("todo %d", i), Position: fmt.Sprintf("V%04d", i)}
+		}
+		created, err := r.CreateBatch(acme, todos)
+		if err != nil {
+			t.Fatalf("Expected no error creating %d todos, got %v", n, err)
+		}
+		if len(created) != n || created[n-1].Title != fmt.Sprintf("todo %d", n-1) || created[n-1].Version == 0 {
+			t.Errorf("Expected %d created todos in order, got %d", n, len(created))
+		}
+
+		list, err := r.List(acme, model.ListOptions{Order: model.ListOrderPosition})
+		if err != nil || len(list) != n || list[0].Title != "todo 0" {
+			t.Errorf("Expected %d todos, got %d %v", n, len(list), err)
+		}
+
+		// creating an existing occurrence again fails the whole batch
+		again := []*model.Todo{{Title: "fresh"}, {Title: "again", SeriesId: created[0].SeriesId, Occurrence: 1}}
+		if n > copyThreshold {
+			for len(again) < n {
+				again = append(again, &model.Todo{Title: "filler"})
+			}
+		}
+		if _, err := r.CreateBatch(acme, again); err != service.ErrAlreadyExists {
+			t.Errorf("Expected ErrAlreadyExists, got %v", err)
+		}
+	}
+}
+
+// copyTracer records the tables copied into with COPY FROM.
+type copyTracer struct {
+	mu     sync.Mutex
+	tables []string
+}
+
+func (c *copyTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (c *copyTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func (c *copyTracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tables = append(c.tables, data.TableName.Sanitize())
+	return ctx
+}
+
+func (c *copyTracer) TraceCopyFromEnd(context.Context, *pgx.Conn, pgx.TraceCopyFromEndData) {}
+
+func TestImportCopiesInUnitOfWork(t *testing.T) {
+	tracer := &copyTracer{}
+	r := newTestPgxRepository(t, tracer)
+	s := service.NewTodosService(r, service.WithMaxTodosPerUser(1000))
+	acme, _ := tenants()
+	alice := auth.WithPrincipal(acme, auth.Principal{UserID: "alice"})
+
+	var file strings.Builder
+	for i := range copyThreshold {
+		fmt.Fprintf(&file, "{\"title\":\"todo %d\"}\n", i)
+	}
+	res, err := s.Import(alice, model.ImportOptions{Format: model.DataFormatNDJSON}, strings.NewReader(file.String()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(res.Todos) != copyThreshold {
+		t.Errorf("Expected %d todos, got %d", copyThreshold, len(res.Todos))
+	}
+	if !slices.Contains(tracer.tables, `"todos_copy"`) {
+		t.Errorf("Expected the import to copy the todos, got copies into %v", tracer.tables)
+	}
+	if todos, err := s.List(alice, model.ListOptions{}); err != nil || len(todos) != copyThreshold {
+		t.Errorf("Expected the imported todos to be committed, got %d %v", len(todos), err)
+	}
+}
+
+func BenchmarkCreate(b *testing.B) {
+	repos := map[string]service.Repository{
+		"database/sql": func() service.Repository { r, _ := newTestRepository(b); return r }(),
+		"pgx":          newTestPgxRepository(b),
+	}
+	for name, r := range repos {
+		b.Run(name, func(b *testing.B) {
+			acme, _ := tenants()
+			for b.Loop() {
+				if _, err := r.Create(acme, &model.Todo{Title: "benchmark", Position: "V"}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	acme, _ := tenants()
+	repos := map[string]service.Repository{
+		"database/sql": func() service.Repository { r, _ := newTestRepository(b); return r }(),
+		"pgx":          newTestPgxRepository(b),
+	}
+	todos := make([]*model.Todo, 100)
+	for i := range todos {
+		todos[i] = &model.Todo{Title: fmt.Sprintf("todo %d", i), Position: "V"}
+	}
+	if _, err := repos["pgx"].CreateBatch(acme, todos); err != nil {
+		b.Fatal(err)
+	}
+
+	for name, r := range repos {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := r.List(acme, model.ListOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/rank"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// Move stores a new manual position for a todo.
+func (r *Repository) Move(ctx context.Context, id, position string) (model.Todo, error) {
+	var t model.Todo
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		err := scanTodo(tx.StmtContext(ctx, r.moveStmt).QueryRowContext(ctx, position, id, tenant), &t)
+		if err != nil {
+			return err
+		}
+		return r.recordEvents(ctx, tx, tenant, &todoState{completed: t.Completed}, t)
+	})
+	if err == sql.ErrNoRows {
+		log.Default().Println("repository: todo not found:", id)
+		return model.Todo{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to move todo:", err)
+		return model.Todo{}, err
+	}
+
+	r.wrote(ctx)
+	log.Default().Println("repository: Moved todo successfully:", t.Id, t.Position)
+	return t, nil
+}
+
+// AdjacentPosition returns the closest position at or after (or before) the
+// given one held by a todo other than excludeID, or an empty string when
+// there is none.
+func (r *Repository) AdjacentPosition(ctx context.Context, position, excludeID string, after bool) (string, error) {
+	stmt := r.prevPositionStmt
+	if after {
+		stmt = r.nextPositionStmt
+	}
+
+	var adjacent string
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, stmt).QueryRowContext(ctx, position, excludeID, tenant).Scan(&adjacent)
+	})
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to find adjacent position:", err)
+		return "", err
+	}
+	return adjacent, nil
+}
+
+// LockRanks holds the positions of the tenant shared until the unit of work
+// ends. Outside a unit of work the lock is released right away.
+func (r *Repository) LockRanks(ctx context.Context) error {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		_, err := tx.StmtContext(ctx, r.shareRanksStmt).ExecContext(ctx, tenant)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to lock ranks:", err)
+	}
+	return err
+}
+
+// Rebalance rewrites the positions of all todos to short, evenly spaced
+// ranks while keeping their current order, holding the ranks of the tenant
+// exclusively. Only todos whose position changes are written. Since the
+// order is unchanged it records no events.
+func (r *Repository) Rebalance(ctx context.Context) error {
+	var ids []string
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		if _, err := tx.StmtContext(ctx, r.lockRanksStmt).ExecContext(ctx, tenant); err != nil {
+			log.Default().Println("repository: failed to lock ranks:", err)
+			return err
+		}
+		rows, err := tx.StmtContext(ctx, r.rankedIdsStmt).QueryContext(ctx, tenant)
+		if err != nil {
+			log.Default().Println("repository: failed to list ranked todos:", err)
+			return err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		setPosition := tx.StmtContext(ctx, r.setPositionStmt)
+		for i, position := range rank.Spread(len(ids)) {
+			if _, err := setPosition.ExecContext(ctx, position, ids[i], tenant); err != nil {
+				log.Default().Println("repository: failed to rebalance todo:", err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to rebalance:", err)
+		return err
+	}
+
+	log.Default().Println("repository: Rebalanced todo positions, count:", len(ids))
+	return nil
+}
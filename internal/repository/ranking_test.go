@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+func TestRebalanceWritesMovedTodosOnly(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, _ := tenants()
+
+	for _, position := range []string{"V", "VV", "VVV"} {
+		if _, err := r.Create(acme, &model.Todo{Title: position, Position: position}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := r.Rebalance(acme); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	before, err := r.LatestChange(acme)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := r.Rebalance(acme); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if after, err := r.LatestChange(acme); err != nil || after.Version != before.Version {
+		t.Errorf("Expected a second rebalance to write nothing, got %v %v", after, err)
+	}
+}
+
+func TestRebalanceWaitsForRankLocks(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, _ := tenants()
+
+	if _, err := r.Create(acme, &model.Todo{Title: "Quarterly report", Position: "VVV"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rebalanced := make(chan error, 1)
+	err := r.WithinTx(acme, service.TxOptions{}, func(repo service.Repository) error {
+		if err := repo.(service.RankLocker).LockRanks(acme); err != nil {
+			return err
+		}
+		go func() { rebalanced <- r.Rebalance(acme) }()
+		select {
+		case err := <-rebalanced:
+			t.Errorf("Expected the rebalance to wait for the lock, got %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := <-rebalanced; err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// ReplicaConfig configures read replicas. Zero values take the defaults
+// below.
+type ReplicaConfig struct {
+	// MaxLag is how far a replica may fall behind the primary and still
+	// serve reads; 10 seconds by default.
+	MaxLag time.Duration
+	// StickyWindow is how long a caller reads from the primary after it
+	// wrote, so it sees its own writes; 5 seconds by default.
+	StickyWindow time.Duration
+	// CheckInterval is how often replicas are checked; 2 seconds by default.
+	CheckInterval time.Duration
+}
+
+// Option configures a Repository.
+type Option func(*Repository)
+
+// WithReplicas serves Get, List, Changes and LatestChange from the given
+// read replicas, round robin, while they are healthy and keep up with the
+// primary. Writes and every other query go to the primary. A replica that
+// cannot be reached yet joins once it answers its health check. A caller
+// reads its own writes through the time of its last write, which it sends
+// back with its next requests to any server, or else through the writes
+// this server remembers.
+func WithReplicas(dbs []*sql.DB, config ReplicaConfig) Option {
+	return func(r *Repository) {
+		if config.MaxLag <= 0 {
+			config.MaxLag = 10 * time.Second
+		}
+		if config.StickyWindow <= 0 {
+			config.StickyWindow = 5 * time.Second
+		}
+		if config.CheckInterval <= 0 {
+			config.CheckInterval = 2 * time.Second
+		}
+
+		if len(dbs) == 0 {
+			return
+		}
+		set := &replicaSet{
+			config: config,
+			writes: writeLog{at: map[string]time.Time{}},
+			stop:   make(chan struct{}),
+			done:   make(chan struct{}),
+		}
+		for _, db := range dbs {
+			set.replicas = append(set.replicas, &replica{db: db})
+		}
+		r.replicas = set
+		go set.monitor()
+	}
+}
+
+// replicaSet routes reads to replicas.
+type replicaSet struct {
+	replicas []*replica
+	config   ReplicaConfig
+	// writes are those of this server, for callers that do not send back
+	// the time of their last write.
+	writes writeLog
+	next   atomic.Uint32
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// replica is a read replica and its health. Only the monitor touches repo
+// and lagStmt until the replica is first healthy.
+type replica struct {
+	db *sql.DB
+	// repo has only the read statements prepared.
+	repo    *Repository
+	lagStmt *sql.Stmt
+	healthy atomic.Bool
+}
+
+// lagQuery returns how many seconds the replica's last replayed transaction
+// is behind, or zero if it replayed everything it received, which keeps an
+// idle primary from looking like lag.
+const lagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+	END
+`
+
+// prepare prepares the statements of the replica.
+func (rep *replica) prepare() error {
+	repo := &Repository{db: rep.db}
+	reads := map[**sql.Stmt]bool{
+		&repo.setTenantStmt:      true,
+		&repo.getStmt:            true,
+		&repo.listStmt:           true,
+		&repo.listByPriorityStmt: true,
+		&repo.listByPositionStmt: true,
+		&repo.changesStmt:        true,
+		&repo.latestStmt:         true,
+	}
+	for _, s := range repo.statements() {
+		if !reads[s.stmt] {
+			continue
+		}
+		stmt, err := rep.db.Prepare(s.query)
+		if err != nil {
+			repo.Close()
+			return err
+		}
+		*s.stmt = stmt
+	}
+
+	lagStmt, err := rep.db.Prepare(lagQuery)
+	if err != nil {
+		repo.Close()
+		return err
+	}
+	rep.repo, rep.lagStmt = repo, lagStmt
+	return nil
+}
+
+// check marks the replica healthy if it answers and is at most maxLag
+// behind.
+func (rep *replica) check(maxLag time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var seconds float64
+	err := rep.db.PingContext(ctx)
+	if err == nil && rep.lagStmt == nil {
+		err = rep.prepare()
+	}
+	if err == nil {
+		err = rep.lagStmt.QueryRowContext(ctx).Scan(&seconds)
+	}
+	lag := time.Duration(seconds * float64(time.Second))
+	healthy := err == nil && lag <= maxLag
+
+	if was := rep.healthy.Swap(healthy); was != healthy {
+		if healthy {
+			log.Default().Println("repository: replica is healthy again")
+		} else {
+			log.Default().Println("repository: replica is unhealthy, lag:", lag, "error:", err)
+		}
+	}
+}
+
+func (set *replicaSet) monitor() {
+	defer close(set.done)
+	ticker := time.NewTicker(set.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, rep := range set.replicas {
+			rep.check(set.config.MaxLag)
+		}
+		set.writes.prune(time.Now().Add(-set.config.StickyWindow))
+
+		select {
+		case <-set.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// close stops the monitor and closes the statements of the replicas, but
+// not their databases.
+func (set *replicaSet) close() {
+	close(set.stop)
+	<-set.done
+	for _, rep := range set.replicas {
+		if rep.lagStmt != nil {
+			rep.lagStmt.Close()
+			rep.repo.Close()
+		}
+	}
+}
+
+// reader returns a healthy replica to read from, or nil to read from the
+// primary: inside a unit of work, for reads that decide a write, for a
+// caller that wrote within the sticky window, and when no replica is
+// healthy.
+func (r *Repository) reader(ctx context.Context) *replica {
+	set := r.replicas
+	if set == nil || r.tx != nil || service.ReadsPrimary(ctx) {
+		return nil
+	}
+	if w := service.WritesFrom(ctx); w != nil && sticky(w.Last(), set.config.StickyWindow) {
+		return nil
+	}
+	if set.writes.since(writerKey(ctx)) < set.config.StickyWindow {
+		return nil
+	}
+
+	start := int(set.next.Add(1))
+	for i := range set.replicas {
+		rep := set.replicas[(start+i)%len(set.replicas)]
+		if rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+// replicaFailed takes a replica out of rotation until its next successful
+// check, after a read on it failed. A read the caller gave up on says
+// nothing about the replica.
+func (r *Repository) replicaFailed(rep *replica, err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	log.Default().Println("repository: replica read failed, reading from the primary:", err)
+	rep.healthy.Store(false)
+}
+
+// wrote starts the sticky window of the caller, in the time of its last
+// write it gets back and on this server.
+func (r *Repository) wrote(ctx context.Context) {
+	if r.replicas == nil {
+		return
+	}
+	now := time.Now()
+	if w := service.WritesFrom(ctx); w != nil {
+		w.Mark(now)
+	}
+	r.replicas.writes.mark(writerKey(ctx), now)
+}
+
+// sticky reports whether a write at last is within window. The clocks of
+// the servers may differ, so a write up to window ahead still counts, but
+// one further ahead is ignored, so a client cannot pin its reads to the
+// primary for longer than it would by writing.
+func sticky(last time.Time, window time.Duration) bool {
+	age := time.Since(last)
+	return age < window && age > -window
+}
+
+// writerKey identifies a caller for read-your-writes. Anonymous callers of
+// a tenant share one key.
+func writerKey(ctx context.Context) string {
+	p, _ := auth.FromContext(ctx)
+	return auth.Tenant(ctx) + "/" + p.Subject()
+}
+
+// writeLog remembers when each caller last wrote on this server.
+type writeLog struct {
+	mu sync.Mutex
+	at map[string]time.Time
+}
+
+func (w *writeLog) mark(key string, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.at[key] = now
+}
+
+// since returns the time since key last wrote.
+func (w *writeLog) since(key string) time.Duration {
+	w.mu.Lock()
+	at, ok := w.at[key]
+	w.mu.Unlock()
+	if !ok {
+		return math.MaxInt64
+	}
+	return time.Since(at)
+}
+
+// prune forgets writes before cutoff.
+func (w *writeLog) prune(cutoff time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, at := range w.at {
+		if at.Before(cutoff) {
+			delete(w.at, key)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// routingRepository returns a repository with the given replicas, none of
+// them connected, for testing reader.
+func routingRepository(replicas ...*replica) *Repository {
+	return &Repository{replicas: &replicaSet{
+		replicas: replicas,
+		config:   ReplicaConfig{MaxLag: time.Second, StickyWindow: time.Minute},
+		writes:   writeLog{at: map[string]time.Time{}},
+	}}
+}
+
+func TestReaderRouting(t *testing.T) {
+	healthy, unhealthy := &replica{}, &replica{}
+	healthy.healthy.Store(true)
+	r := routingRepository(unhealthy, healthy)
+
+	alice := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "bob"})
+
+	for range 3 {
+		if got := r.reader(alice); got != healthy {
+			t.Fatalf("Expected the healthy replica, got %v", got)
+		}
+	}
+	if got := r.reader(service.WithPrimary(alice)); got != nil {
+		t.Errorf("Expected reads that decide a write to use the primary, got %v", got)
+	}
+
+	r.wrote(alice)
+	if got := r.reader(alice); got != nil {
+		t.Errorf("Expected alice to read their own writes from the primary, got %v", got)
+	}
+	if got := r.reader(bob); got != healthy {
+		t.Errorf("Expected bob to keep reading from the replica, got %v", got)
+	}
+
+	// the client sends back the time of a write on another server
+	if got := r.reader(service.WithWrites(bob, service.NewWrites(time.Now().Add(-time.Second)))); got != nil {
+		t.Errorf("Expected a recent write of bob to keep their reads on the primary, got %v", got)
+	}
+	if got := r.reader(service.WithWrites(bob, service.NewWrites(time.Now().Add(-time.Hour)))); got != healthy {
+		t.Errorf("Expected an old write of bob to read from the replica, got %v", got)
+	}
+	if got := r.reader(service.WithWrites(bob, service.NewWrites(time.Now().Add(time.Hour)))); got != healthy {
+		t.Errorf("Expected a write far in the future to be ignored, got %v", got)
+	}
+	writes := service.NewWrites(time.Time{})
+	r.wrote(service.WithWrites(bob, writes))
+	if time.Since(writes.Last()) > time.Second {
+		t.Errorf("Expected the write to be handed back, got %v", writes.Last())
+	}
+	r.replicas.writes.prune(time.Now().Add(time.Hour))
+
+	r.replicaFailed(healthy, context.Canceled)
+	if got := r.reader(bob); got != healthy {
+		t.Errorf("Expected a canceled read to keep the replica, got %v", got)
+	}
+	r.replicaFailed(healthy, sql.ErrConnDone)
+	if got := r.reader(bob); got != nil {
+		t.Errorf("Expected the primary once no replica is healthy, got %v", got)
+	}
+
+	if got := (&Repository{}).reader(alice); got != nil {
+		t.Errorf("Expected the primary without replicas, got %v", got)
+	}
+}
+
+func TestWriteLogPrune(t *testing.T) {
+	w := writeLog{at: map[string]time.Time{}}
+	now := time.Now()
+	w.mark("old", now.Add(-time.Hour))
+	w.mark("new", now)
+
+	w.prune(now.Add(-time.Minute))
+	if _, ok := w.at["old"]; ok {
+		t.Errorf("Expected the old write to be forgotten")
+	}
+	if w.since("new") > time.Minute {
+		t.Errorf("Expected the new write to be kept, got %v", w.since("new"))
+	}
+}
+
+func TestReplicaServesReads(t *testing.T) {
+	_, conn := newTestRepository(t)
+	// the primary doubles as its own replica, which is never behind
+	r, err := NewRepository(conn, WithReplicas([]*sql.DB{conn}, ReplicaConfig{CheckInterval: 10 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer r.Close()
+
+	acme, _ := tenants()
+	writes := service.NewWrites(time.Time{})
+	todo, err := r.Create(service.WithWrites(acme, writes), &model.Todo{Title: "Read me back", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !r.replicas.replicas[0].healthy.Load() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if r.reader(acme) != nil {
+		t.Errorf("Expected the writer to read from the primary")
+	}
+	r.replicas.writes.prune(time.Now().Add(time.Hour))
+	// the next request of the writer may reach another server
+	if r.reader(service.WithWrites(acme, service.NewWrites(writes.Last()))) != nil {
+		t.Errorf("Expected the time of the write to keep reads on the primary")
+	}
+	if r.reader(acme) == nil {
+		t.Fatalf("Expected the replica to serve reads")
+	}
+	if got, err := r.Get(acme, todo.Id); err != nil || got.Title != "Read me back" {
+		t.Errorf("Expected the todo from the replica, got %v %v", got, err)
+	}
+}
@@ -3,95 +3,820 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 
 	"log"
 
 	"github.com/google/uuid"
 	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/lib/pq"
 )
 
+// todoColumns is the column list scanned by scanTodo.
+const todoColumns = `id, title, completed, version, priority, position,
+	due_at, rrule, timezone, recurrence_mode, series_id, occurrence, updated_at,
+	COALESCE(list_id::text, ''), created_by, description, description_html, links, checklist`
+
+// listFilter restricts a list query to one list if $1 is set, otherwise to
+// todos outside any list, created by $5 if set, and those of the lists in $2.
+const listFilter = `CASE WHEN $1 = ''
+	THEN (list_id IS NULL AND ($5 = '' OR created_by = $5)) OR list_id::text = ANY($2)
+	ELSE list_id::text = $1 END`
+
+// searchFilter restricts a list query to todos matching the web search
+// syntax query in $4, if set. It matches the expression of todos_search_idx.
+const searchFilter = `$4 = ''
+	OR to_tsvector('simple', title || ' ' || description) @@ websearch_to_tsquery('simple', $4)`
+
 type Repository struct {
 	db *sql.DB
+	// tx is the unit of work of a Repository passed to WithinTx, which all
+	// its methods run in.
+	tx *sql.Tx
+	// replicas serve some reads if configured.
+	replicas *replicaSet
+
+	setTenantStmt        *sql.Stmt
+	credentialLookupStmt *sql.Stmt
+	webhookDispatchStmt  *sql.Stmt
+	outboxRelayStmt      *sql.Stmt
+	attachmentPurgeStmt  *sql.Stmt
 
 	createStmt *sql.Stmt
 	getStmt    *sql.Stmt
 	listStmt   *sql.Stmt
 	updateStmt *sql.Stmt
 	deleteStmt *sql.Stmt
+	beforeStmt *sql.Stmt
+
+	listByPriorityStmt *sql.Stmt
+	listByPositionStmt *sql.Stmt
+	moveStmt           *sql.Stmt
+	nextPositionStmt   *sql.Stmt
+	prevPositionStmt   *sql.Stmt
+	rankedIdsStmt      *sql.Stmt
+	setPositionStmt    *sql.Stmt
+	lockRanksStmt      *sql.Stmt
+	shareRanksStmt     *sql.Stmt
+
+	changesStmt *sql.Stmt
+	lookupStmt  *sql.Stmt
+	insertStmt  *sql.Stmt
+	putStmt     *sql.Stmt
+	latestStmt  *sql.Stmt
+
+	countCreatedByStmt *sql.Stmt
+
+	createFeedStmt *sql.Stmt
+	listFeedsStmt  *sql.Stmt
+	revokeFeedStmt *sql.Stmt
+	feedByHashStmt *sql.Stmt
+
+	createKeyStmt   *sql.Stmt
+	listKeysStmt    *sql.Stmt
+	rotateKeyStmt   *sql.Stmt
+	revokeKeyStmt   *sql.Stmt
+	keyByPrefixStmt *sql.Stmt
+	touchKeyStmt    *sql.Stmt
+
+	createListStmt    *sql.Stmt
+	addMemberStmt     *sql.Stmt
+	listsOfStmt       *sql.Stmt
+	roleStmt          *sql.Stmt
+	membersStmt       *sql.Stmt
+	setRoleStmt       *sql.Stmt
+	removeMemberStmt  *sql.Stmt
+	createInviteStmt  *sql.Stmt
+	invitationsStmt   *sql.Stmt
+	invitationStmt    *sql.Stmt
+	respondInviteStmt *sql.Stmt
+
+	createWebhookStmt *sql.Stmt
+	listWebhooksStmt  *sql.Stmt
+	deleteWebhookStmt *sql.Stmt
+	enqueueStmt       *sql.Stmt
+	deliveriesStmt    *sql.Stmt
+	redeliverStmt     *sql.Stmt
+	claimStmt         *sql.Stmt
+	recordAttemptStmt *sql.Stmt
+
+	appendOutboxStmt  *sql.Stmt
+	lockOutboxStmt    *sql.Stmt
+	pendingOutboxStmt *sql.Stmt
+	deleteOutboxStmt  *sql.Stmt
+
+	createAttachmentStmt  *sql.Stmt
+	listAttachmentsStmt   *sql.Stmt
+	getAttachmentStmt     *sql.Stmt
+	deleteAttachmentStmt  *sql.Stmt
+	attachmentBytesStmt   *sql.Stmt
+	purgeableStmt         *sql.Stmt
+	removeAttachmentsStmt *sql.Stmt
+
+	createCommentStmt *sql.Stmt
+	getCommentStmt    *sql.Stmt
+	listCommentsStmt  *sql.Stmt
+	updateCommentStmt *sql.Stmt
+	deleteCommentStmt *sql.Stmt
+	commentCountsStmt *sql.Stmt
+
+	addDependencyStmt    *sql.Stmt
+	getDependencyStmt    *sql.Stmt
+	removeDependencyStmt *sql.Stmt
+	dependsOnStmt        *sql.Stmt
+	dependenciesStmt     *sql.Stmt
+	blockedTodosStmt     *sql.Stmt
 }
 
-func NewRepository(db *sql.DB) (*Repository, error) {
-	r := &Repository{db: db}
+// statements pairs every prepared statement of the repository with its query.
+func (r *Repository) statements() []struct {
+	stmt  **sql.Stmt
+	query string
+} {
+	return []struct {
+		stmt  **sql.Stmt
+		query string
+	}{
+		// SET LOCAL takes no parameters, set_config with is_local is the same
+		{&r.setTenantStmt, `SELECT set_config('app.tenant_id', $1, true)`},
+		{&r.credentialLookupStmt, `SELECT set_config('app.credential_lookup', 'on', true)`},
+		{&r.webhookDispatchStmt, `SELECT set_config('app.webhook_dispatch', 'on', true)`},
+		{&r.outboxRelayStmt, `SELECT set_config('app.outbox_relay', 'on', true)`},
+		{&r.attachmentPurgeStmt, `SELECT set_config('app.attachment_purge', 'on', true)`},
 
-	var err error
+		{&r.createStmt, `
+			INSERT INTO todos (id, title, completed, priority, position,
+				due_at, rrule, timezone, recurrence_mode, series_id, occurrence, created_by, list_id, tenant_id,
+				description, description_html, links, checklist)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, '')::uuid, $14, $15, $16, $17, $18)
+			ON CONFLICT DO NOTHING
+			RETURNING version, updated_at
+		`},
+		{&r.getStmt, `
+			SELECT ` + todoColumns + `
+			FROM todos
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`},
+		{&r.listStmt, `
+			SELECT ` + todoColumns + `
+			FROM todos
+			WHERE tenant_id = $3 AND deleted_at IS NULL AND (` + listFilter + `) AND (` + searchFilter + `)
+			ORDER BY created_at DESC
+		`},
+		{&r.updateStmt, `
+			UPDATE todos
+			SET title = $1, completed = $2, priority = $3,
+				due_at = $4, rrule = $5, timezone = $6, recurrence_mode = $7,
+				description = $10, description_html = $11, links = $12, checklist = $13,
+				version = nextval('todos_version_seq'), updated_at = NOW()
+			WHERE id = $8 AND tenant_id = $9 AND deleted_at IS NULL
+			RETURNING ` + todoColumns,
+		},
+		{&r.deleteStmt, `
+			UPDATE todos
+			SET deleted_at = NOW(), version = nextval('todos_version_seq'), updated_at = NOW()
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+			RETURNING ` + todoColumns,
+		},
+		// locks the todo until the write that follows commits, so its events
+		// are recorded in the order the writes happen
+		{&r.beforeStmt, `
+			SELECT completed, deleted_at IS NOT NULL
+			FROM todos
+			WHERE id = $1 AND tenant_id = $2
+			FOR UPDATE
+		`},
 
-	r.createStmt, err = db.Prepare(`
-		INSERT INTO todos (id, title, completed)
-		VALUES ($1, $2, false)
-	`)
-	if err != nil {
-		return nil, err
+		// positions are compared byte-wise, see package rank
+		{&r.listByPriorityStmt, `
+			SELECT ` + todoColumns + `
+			FROM todos
+			WHERE tenant_id = $3 AND deleted_at IS NULL AND (` + listFilter + `) AND (` + searchFilter + `)
+			ORDER BY priority DESC, position COLLATE "C", created_at DESC
+		`},
+		{&r.listByPositionStmt, `
+			SELECT ` + todoColumns + `
+			FROM todos
+			WHERE tenant_id = $3 AND deleted_at IS NULL AND (` + listFilter + `) AND (` + searchFilter + `)
+			ORDER BY position COLLATE "C", created_at DESC
+		`},
+		{&r.moveStmt, `
+			UPDATE todos
+			SET position = $1, version = nextval('todos_version_seq'), updated_at = NOW()
+			WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL
+			RETURNING ` + todoColumns,
+		},
+		{&r.nextPositionStmt, `
+			SELECT position
+			FROM todos
+			WHERE tenant_id = $3 AND deleted_at IS NULL AND position COLLATE "C" >= $1 AND id::text <> $2
+			ORDER BY position COLLATE "C"
+			LIMIT 1
+		`},
+		{&r.prevPositionStmt, `
+			SELECT position
+			FROM todos
+			WHERE tenant_id = $3 AND deleted_at IS NULL AND position COLLATE "C" <= $1 AND id::text <> $2
+			ORDER BY position COLLATE "C" DESC
+			LIMIT 1
+		`},
+		{&r.rankedIdsStmt, `
+			SELECT id
+			FROM todos
+			WHERE tenant_id = $1 AND deleted_at IS NULL
+			ORDER BY position COLLATE "C", created_at DESC
+			FOR UPDATE
+		`},
+		// a todo already at its new position keeps its version, so sync
+		// clients are not sent every todo after each rebalance
+		{&r.setPositionStmt, `
+			UPDATE todos
+			SET position = $1, version = nextval('todos_version_seq'), updated_at = NOW()
+			WHERE id = $2 AND tenant_id = $3 AND position <> $1
+		`},
+		// positions are written shared and rebalanced exclusively, in every
+		// process at once
+		{&r.lockRanksStmt, `SELECT pg_advisory_xact_lock(hashtext('todos.ranking'), hashtext($1))`},
+		{&r.shareRanksStmt, `SELECT pg_advisory_xact_lock_shared(hashtext('todos.ranking'), hashtext($1))`},
+
+		// Sequence values are handed out before commit, so a reader can see
+		// version N+1 before N commits. Clients re-reading from their last
+		// token tolerate this because every page is ordered by version.
+		// transactions older than the oldest one still running have all
+		// ended, so none can commit a change that sorts before these
+		{&r.changesStmt, `
+			SELECT ` + todoColumns + `, deleted_at IS NOT NULL, txid
+			FROM todos
+			WHERE (txid, version) > ($1, $2) AND tenant_id = $4
+				AND txid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+			ORDER BY txid, version
+			LIMIT $3
+		`},
+		{&r.lookupStmt, `
+			SELECT ` + todoColumns + `, deleted_at IS NOT NULL
+			FROM todos
+			WHERE id = $1 AND tenant_id = $2
+		`},
+		{&r.insertStmt, `
+			INSERT INTO todos (id, title, completed, priority, position, series_id, created_by, tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $1, $6, $7)
+			ON CONFLICT (id) DO NOTHING
+			RETURNING version, updated_at
+		`},
+		{&r.putStmt, `
+			UPDATE todos
+			SET title = $1,
+				completed = $2,
+				priority = $3,
+				deleted_at = CASE WHEN $4::boolean THEN COALESCE(deleted_at, NOW()) END,
+				version = nextval('todos_version_seq'),
+				updated_at = NOW()
+			WHERE id = $5 AND version = $6 AND tenant_id = $7
+			RETURNING ` + todoColumns,
+		},
+		{&r.latestStmt, `
+			SELECT COALESCE(MAX(version), 0), COALESCE(MAX(updated_at), 'epoch')
+			FROM todos
+			WHERE tenant_id = $1
+		`},
+		{&r.countCreatedByStmt, `
+			SELECT COUNT(*)
+			FROM todos
+			WHERE created_by = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`},
+
+		{&r.createFeedStmt, `
+			INSERT INTO calendar_feeds (id, name, token_hash, tenant_id, created_by)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING created_at
+		`},
+		{&r.listFeedsStmt, `
+			SELECT id, name, created_at
+			FROM calendar_feeds
+			WHERE tenant_id = $1 AND revoked_at IS NULL
+			ORDER BY created_at DESC
+		`},
+		{&r.revokeFeedStmt, `
+			UPDATE calendar_feeds
+			SET revoked_at = NOW()
+			WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL
+		`},
+		{&r.feedByHashStmt, `
+			SELECT id, name, created_at, tenant_id, created_by
+			FROM calendar_feeds
+			WHERE token_hash = $1 AND revoked_at IS NULL
+		`},
+
+		{&r.createKeyStmt, `
+			INSERT INTO api_keys (id, name, prefix, scopes, expires_at, salt, secret_hash, tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING created_at
+		`},
+		{&r.listKeysStmt, `
+			SELECT ` + apiKeyColumns + `
+			FROM api_keys
+			WHERE tenant_id = $1 AND revoked_at IS NULL
+			ORDER BY created_at DESC
+		`},
+		{&r.rotateKeyStmt, `
+			UPDATE api_keys
+			SET prefix = $2, salt = $3, secret_hash = $4
+			WHERE id = $1 AND tenant_id = $5 AND revoked_at IS NULL
+			RETURNING ` + apiKeyColumns,
+		},
+		{&r.revokeKeyStmt, `
+			UPDATE api_keys
+			SET revoked_at = NOW()
+			WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL
+		`},
+		{&r.keyByPrefixStmt, `
+			SELECT ` + apiKeyColumns + `, tenant_id, salt, secret_hash
+			FROM api_keys
+			WHERE prefix = $1 AND revoked_at IS NULL
+		`},
+		{&r.touchKeyStmt, `
+			UPDATE api_keys
+			SET last_used_at = NOW()
+			WHERE id = $1 AND tenant_id = $2 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+		`},
+
+		{&r.createListStmt, `
+			INSERT INTO lists (id, name, tenant_id)
+			VALUES ($1, $2, $3)
+			RETURNING created_at
+		`},
+		// accepting an invitation never lowers an existing role
+		{&r.addMemberStmt, `
+			INSERT INTO list_members (list_id, user_id, role, tenant_id)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (list_id, user_id) DO UPDATE SET role = GREATEST(list_members.role, EXCLUDED.role)
+			RETURNING ` + memberColumns,
+		},
+		{&r.listsOfStmt, `
+			SELECT l.id, l.name, l.created_at, m.role
+			FROM lists l
+			JOIN list_members m ON m.list_id = l.id
+			WHERE m.user_id = $1 AND m.tenant_id = $2 AND l.tenant_id = $2
+			ORDER BY l.created_at
+		`},
+		{&r.roleStmt, `
+			SELECT role
+			FROM list_members
+			WHERE list_id = $1 AND user_id = $2 AND tenant_id = $3
+		`},
+		{&r.membersStmt, `
+			SELECT ` + memberColumns + `
+			FROM list_members
+			WHERE list_id = $1 AND tenant_id = $2
+			ORDER BY created_at
+		`},
+		{&r.setRoleStmt, `
+			UPDATE list_members
+			SET role = $3
+			WHERE list_id = $1 AND user_id = $2 AND tenant_id = $4
+			RETURNING ` + memberColumns,
+		},
+		{&r.removeMemberStmt, `
+			DELETE FROM list_members
+			WHERE list_id = $1 AND user_id = $2 AND tenant_id = $3
+		`},
+		{&r.createInviteStmt, `
+			INSERT INTO list_invitations (id, list_id, user_id, email, role, invited_by, status, tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING created_at
+		`},
+		{&r.invitationsStmt, `
+			SELECT ` + invitationColumns + `
+			FROM list_invitations i
+			JOIN lists l ON l.id = i.list_id
+			WHERE i.status = 1 -- pending
+				AND i.tenant_id = $3
+				AND (i.user_id = $1 OR ($2 <> '' AND lower(i.email) = lower($2)))
+			ORDER BY i.created_at
+		`},
+		{&r.invitationStmt, `
+			SELECT ` + invitationColumns + `
+			FROM list_invitations i
+			JOIN lists l ON l.id = i.list_id
+			WHERE i.id = $1 AND i.tenant_id = $2
+		`},
+		{&r.respondInviteStmt, `
+			UPDATE list_invitations
+			SET status = $2
+			WHERE id = $1 AND tenant_id = $3 AND status = 1 -- pending
+			RETURNING list_id, role
+		`},
+
+		{&r.createWebhookStmt, `
+			INSERT INTO webhooks (id, url, events, secret, tenant_id, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING created_at
+		`},
+		{&r.listWebhooksStmt, `
+			SELECT id, url, events, created_at
+			FROM webhooks
+			WHERE tenant_id = $1
+			ORDER BY created_at
+		`},
+		{&r.deleteWebhookStmt, `
+			DELETE FROM webhooks
+			WHERE id = $1 AND tenant_id = $2
+		`},
+		// a webhook only hears about the todos its owner can see: those of
+		// the lists they are a member of and those they created outside any
+		// list
+		{&r.enqueueStmt, `
+			INSERT INTO webhook_deliveries (id, webhook_id, event, payload, status, next_attempt_at, tenant_id)
+			SELECT gen_random_uuid(), w.id, $1, $2, 1, NOW(), $3 -- pending
+			FROM webhooks w
+			JOIN todos t ON t.id::text = $4 AND t.tenant_id = $3
+			WHERE w.tenant_id = $3 AND $1 = ANY(w.events) AND w.created_by <> '' AND CASE
+				WHEN t.list_id IS NULL THEN t.created_by = w.created_by
+				ELSE EXISTS (
+					SELECT 1
+					FROM list_members m
+					WHERE m.list_id = t.list_id AND m.tenant_id = $3 AND 'user:' || m.user_id = w.created_by
+				)
+			END
+		`},
+		{&r.deliveriesStmt, `
+			SELECT ` + deliveryColumns + `
+			FROM webhook_deliveries
+			WHERE webhook_id = $1 AND tenant_id = $2 AND ($3 = 0 OR status = $3)
+			ORDER BY created_at DESC
+			LIMIT $4
+		`},
+		{&r.redeliverStmt, `
+			UPDATE webhook_deliveries
+			SET status = 1, attempts = 0, next_attempt_at = NOW(), delivered_at = NULL -- pending
+			WHERE id = $1 AND webhook_id = $2 AND tenant_id = $3
+			RETURNING ` + deliveryColumns,
+		},
+		// SKIP LOCKED lets several replicas claim deliveries side by side, and
+		// pushing next_attempt_at out by the lease keeps a delivery from being
+		// claimed again while it is being sent
+		{&r.claimStmt, `
+			UPDATE webhook_deliveries d
+			SET next_attempt_at = $1::timestamptz + $2::double precision * INTERVAL '1 millisecond'
+			FROM webhooks w
+			WHERE w.id = d.webhook_id AND d.id IN (
+				SELECT id
+				FROM webhook_deliveries
+				WHERE status = 1 AND next_attempt_at <= $1 -- pending
+				ORDER BY next_attempt_at
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + claimedColumns + `
+		`},
+		{&r.recordAttemptStmt, `
+			UPDATE webhook_deliveries
+			SET status = $2, attempts = $3, last_status_code = $4, last_error = $5,
+				next_attempt_at = $6, delivered_at = $7
+			WHERE id = $1
+		`},
+
+		{&r.appendOutboxStmt, `
+			INSERT INTO outbox (event_id, aggregate_id, event, payload, tenant_id)
+			VALUES ($1, $2, $3, $4, $5)
+		`},
+		// one relay at a time, or two could publish the events of a todo out
+		// of order
+		{&r.lockOutboxStmt, `SELECT pg_try_advisory_xact_lock(hashtext('todos.outbox'))`},
+		{&r.pendingOutboxStmt, `
+			SELECT id, event_id, tenant_id, aggregate_id, event, payload, created_at
+			FROM outbox
+			ORDER BY id
+			LIMIT $1
+		`},
+		{&r.deleteOutboxStmt, `
+			DELETE FROM outbox
+			WHERE id = ANY($1)
+		`},
+
+		// a todo deleted meanwhile takes its attachments with it
+		{&r.createAttachmentStmt, `
+			INSERT INTO attachments (id, todo_id, filename, content_type, size, sha256, blob_key, created_by, tenant_id)
+			SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
+			WHERE EXISTS (SELECT 1 FROM todos WHERE id = $2 AND tenant_id = $9 AND deleted_at IS NULL)
+			RETURNING created_at
+		`},
+		{&r.listAttachmentsStmt, `
+			SELECT ` + attachmentColumns + `
+			FROM attachments
+			WHERE todo_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+			ORDER BY created_at, id
+		`},
+		{&r.getAttachmentStmt, `
+			SELECT ` + attachmentColumns + `
+			FROM attachments
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`},
+		{&r.deleteAttachmentStmt, `
+			UPDATE attachments
+			SET deleted_at = NOW()
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`},
+		{&r.attachmentBytesStmt, `
+			SELECT COALESCE(SUM(size), 0)
+			FROM attachments
+			WHERE created_by = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`},
+		{&r.purgeableStmt, `
+			SELECT ` + purgeableColumns + `
+			FROM attachments a
+			JOIN todos t ON t.id = a.todo_id
+			WHERE a.deleted_at IS NOT NULL OR t.deleted_at < $1
+			ORDER BY a.id
+			LIMIT $2
+		`},
+		{&r.removeAttachmentsStmt, `
+			DELETE FROM attachments
+			WHERE id = ANY($1)
+		`},
+
+		// a new or deleted comment changes the comment count, so it bumps the
+		// version of its todo like any other change
+		{&r.createCommentStmt, `
+			WITH todo AS (
+				UPDATE todos
+				SET version = nextval('todos_version_seq'), updated_at = NOW()
+				WHERE id = $2 AND tenant_id = $6 AND deleted_at IS NULL
+				RETURNING id
+			)
+			INSERT INTO comments (id, todo_id, author, body, mentions, tenant_id)
+			SELECT $1, todo.id, $3, $4, $5, $6
+			FROM todo
+			RETURNING created_at
+		`},
+		{&r.getCommentStmt, `
+			SELECT ` + commentColumns + `
+			FROM comments
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`},
+		{&r.listCommentsStmt, `
+			SELECT ` + commentColumns + `
+			FROM comments
+			WHERE todo_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+				AND (created_at, id) > ($3, $4)
+			ORDER BY created_at, id
+			LIMIT $5
+		`},
+		{&r.updateCommentStmt, `
+			UPDATE comments
+			SET body = $2, mentions = $3, edited_at = NOW()
+			WHERE id = $1 AND tenant_id = $4 AND deleted_at IS NULL
+			RETURNING ` + commentColumns + `
+		`},
+		{&r.deleteCommentStmt, `
+			WITH comment AS (
+				UPDATE comments
+				SET deleted_at = NOW()
+				WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+				RETURNING todo_id
+			)
+			UPDATE todos
+			SET version = nextval('todos_version_seq'), updated_at = NOW()
+			FROM comment
+			WHERE todos.id = comment.todo_id AND todos.tenant_id = $2
+			RETURNING todos.id
+		`},
+		{&r.commentCountsStmt, `
+			SELECT todo_id, COUNT(*)
+			FROM comments
+			WHERE todo_id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL
+			GROUP BY todo_id
+		`},
+
+		// a new or removed blocker changes whether the todo is blocked, so it
+		// bumps the version of the todo too
+		{&r.addDependencyStmt, `
+			WITH todo AS (
+				UPDATE todos
+				SET version = nextval('todos_version_seq'), updated_at = NOW()
+				WHERE id = $1 AND tenant_id = $3 AND deleted_at IS NULL
+					AND EXISTS (SELECT 1 FROM todos WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL)
+					AND NOT EXISTS (SELECT 1 FROM dependencies WHERE todo_id = $1 AND blocked_by_id = $2)
+				RETURNING id
+			)
+			INSERT INTO dependencies (todo_id, blocked_by_id, tenant_id)
+			SELECT todo.id, $2, $3
+			FROM todo
+			RETURNING created_at
+		`},
+		{&r.getDependencyStmt, `
+			SELECT created_at
+			FROM dependencies
+			WHERE todo_id = $1 AND blocked_by_id = $2 AND tenant_id = $3
+		`},
+		{&r.removeDependencyStmt, `
+			WITH dependency AS (
+				DELETE FROM dependencies
+				WHERE todo_id = $1 AND blocked_by_id = $2 AND tenant_id = $3
+				RETURNING todo_id
+			)
+			UPDATE todos
+			SET version = nextval('todos_version_seq'), updated_at = NOW()
+			FROM dependency
+			WHERE todos.id = dependency.todo_id AND todos.tenant_id = $3
+			RETURNING todos.id
+		`},
+		// UNION rather than UNION ALL stops at todos already visited, should
+		// the graph hold a cycle after all
+		{&r.dependsOnStmt, `
+			WITH RECURSIVE blockers (id) AS (
+				SELECT blocked_by_id
+				FROM dependencies
+				WHERE todo_id = $1 AND tenant_id = $3
+				UNION
+				SELECT d.blocked_by_id
+				FROM dependencies d
+				JOIN blockers b ON d.todo_id = b.id
+				WHERE d.tenant_id = $3
+			)
+			SELECT EXISTS (SELECT 1 FROM blockers WHERE id = $2)
+		`},
+		{&r.dependenciesStmt, `
+			SELECT todo_id, blocked_by_id, created_at
+			FROM dependencies
+			WHERE todo_id = ANY($1) AND blocked_by_id = ANY($1) AND tenant_id = $2
+			ORDER BY created_at, todo_id, blocked_by_id
+		`},
+		{&r.blockedTodosStmt, `
+			SELECT DISTINCT d.todo_id
+			FROM dependencies d
+			JOIN todos b ON b.id = d.blocked_by_id
+			WHERE d.todo_id = ANY($1) AND d.tenant_id = $2 AND NOT b.completed AND b.deleted_at IS NULL
+		`},
 	}
+}
 
-	r.getStmt, err = db.Prepare(`
-		SELECT id, title, completed
-		FROM todos
-		WHERE id = $1
-	`)
-	if err != nil {
-		return nil, err
+func NewRepository(db *sql.DB, opts ...Option) (*Repository, error) {
+	r := &Repository{db: db}
+
+	for _, s := range r.statements() {
+		stmt, err := db.Prepare(s.query)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		*s.stmt = stmt
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+// scanTodo scans a row selected with todoColumns, optionally followed by
+// extra destinations.
+func scanTodo(row interface{ Scan(...any) error }, t *model.Todo, extra ...any) error {
+	dest := append([]any{
+		&t.Id, &t.Title, &t.Completed, &t.Version, &t.Priority, &t.Position,
+		&t.DueAt, &t.Rrule, &t.Timezone, &t.RecurrenceMode, &t.SeriesId, &t.Occurrence,
+		&t.UpdatedAt, &t.ListId, &t.CreatedBy,
+		&t.Description, &t.DescriptionHtml, jsonColumn[[]model.Link]{&t.Links}, jsonColumn[[]model.ChecklistItem]{&t.Checklist},
+	}, extra...)
+	return row.Scan(dest...)
+}
+
+// jsonColumn reads and writes a JSON column through the value it points to.
+// An empty value is written as an empty array rather than null.
+type jsonColumn[T any] struct {
+	v *T
+}
+
+func (c jsonColumn[T]) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		var zero T
+		*c.v = zero
+		return nil
+	case []byte:
+		return json.Unmarshal(src, c.v)
+	case string:
+		return json.Unmarshal([]byte(src), c.v)
 	}
+	return fmt.Errorf("cannot scan %T into a JSON column", src)
+}
 
-	r.listStmt, err = db.Prepare(`
-		SELECT id, title, completed
-		FROM todos
-		ORDER BY created_at DESC
-	`)
+func (c jsonColumn[T]) Value() (driver.Value, error) {
+	data, err := json.Marshal(*c.v)
 	if err != nil {
 		return nil, err
 	}
+	if string(data) == "null" {
+		return "[]", nil
+	}
+	return string(data), nil
+}
 
-	r.updateStmt, err = db.Prepare(`
-		UPDATE todos
-		SET title = $1, completed = $2
-		WHERE id = $3
-	`)
+// Create inserts a new todo. A todo without a series starts its own; a todo
+// continuing a series fails with service.ErrAlreadyExists if that occurrence
+// was already created.
+func (r *Repository) Create(ctx context.Context, t *model.Todo) (model.Todo, error) {
+	var todo model.Todo
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var err error
+		todo, err = r.insert(ctx, tx, tx.StmtContext(ctx, r.createStmt), tenant, t)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return model.Todo{}, err
 	}
 
-	r.deleteStmt, err = db.Prepare(`
-		DELETE FROM todos WHERE id = $1
-	`)
+	r.wrote(ctx)
+	log.Default().Println("repository: Created todo successfully:", todo.Id)
+	return todo, nil
+}
+
+// CreateBatch inserts all todos in a single transaction, so either all of
+// them are created or none is.
+func (r *Repository) CreateBatch(ctx context.Context, todos []*model.Todo) ([]model.Todo, error) {
+	result := make([]model.Todo, 0, len(todos))
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		stmt := tx.StmtContext(ctx, r.createStmt)
+		for _, t := range todos {
+			todo, err := r.insert(ctx, tx, stmt, tenant, t)
+			if err != nil {
+				return err
+			}
+			result = append(result, todo)
+		}
+		return nil
+	})
 	if err != nil {
+		log.Default().Println("repository: failed to create batch:", err)
 		return nil, err
 	}
 
-	return r, nil
+	r.wrote(ctx)
+	log.Default().Println("repository: Created todos successfully, count:", len(result))
+	return result, nil
 }
 
-func (r *Repository) Create(ctx context.Context, title string) (model.Todo, error) {
-	t := model.Todo{
-		Id:    uuid.NewString(),
-		Title: title,
+func (r *Repository) insert(ctx context.Context, tx *sql.Tx, stmt *sql.Stmt, tenant string, t *model.Todo) (model.Todo, error) {
+	t.Id = uuid.NewString()
+	if t.SeriesId == "" {
+		t.SeriesId, t.Occurrence = t.Id, 1
 	}
 
-	_, err := r.createStmt.ExecContext(ctx, t.Id, t.Title)
+	err := stmt.QueryRowContext(
+		ctx,
+		t.Id,
+		t.Title,
+		t.Completed,
+		t.Priority,
+		t.Position,
+		t.DueAt,
+		t.Rrule,
+		t.Timezone,
+		t.RecurrenceMode,
+		t.SeriesId,
+		t.Occurrence,
+		t.CreatedBy,
+		t.ListId,
+		tenant,
+		t.Description,
+		t.DescriptionHtml,
+		jsonColumn[[]model.Link]{&t.Links},
+		jsonColumn[[]model.ChecklistItem]{&t.Checklist},
+	).Scan(&t.Version, &t.UpdatedAt)
+	if err == sql.ErrNoRows {
+		log.Default().Println("repository: todo occurrence already exists:", t.SeriesId, t.Occurrence)
+		return model.Todo{}, service.ErrAlreadyExists
+	}
 	if err != nil {
 		log.Default().Println("repository: failed to create todo:", err)
 		return model.Todo{}, err
 	}
-
-	log.Default().Println("repository: Created todo successfully:", t.Id)
-	return t, nil
+	if err := r.recordEvents(ctx, tx, tenant, nil, *t); err != nil {
+		return model.Todo{}, err
+	}
+	return *t, nil
 }
 
 func (r *Repository) Get(ctx context.Context, id string) (model.Todo, error) {
+	if rep := r.reader(ctx); rep != nil {
+		// a todo missing on the replica may just not have arrived yet
+		t, err := rep.repo.Get(ctx, id)
+		if err == nil && t.Id != "" {
+			return t, nil
+		}
+		if err != nil {
+			r.replicaFailed(rep, err)
+		}
+	}
+
 	var t model.Todo
 
-	err := r.getStmt.
-		QueryRowContext(ctx, id).
-		Scan(&t.Id, &t.Title, &t.Completed)
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return scanTodo(tx.StmtContext(ctx, r.getStmt).QueryRowContext(ctx, id, tenant), &t)
+	})
 
 	if err == sql.ErrNoRows {
 		log.Default().Println("repository: todo not found:", id)
@@ -107,64 +832,133 @@ func (r *Repository) Get(ctx context.Context, id string) (model.Todo, error) {
 }
 
 func (r *Repository) Update(ctx context.Context, t *model.Todo) (model.Todo, error) {
-	_, err := r.updateStmt.ExecContext(
-		ctx,
-		t.Title,
-		t.Completed,
-		t.Id,
-	)
+	var updated model.Todo
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		before, err := r.before(ctx, tx, tenant, t.Id)
+		if err != nil {
+			return err
+		}
+		err = scanTodo(tx.StmtContext(ctx, r.updateStmt).QueryRowContext(
+			ctx,
+			t.Title,
+			t.Completed,
+			t.Priority,
+			t.DueAt,
+			t.Rrule,
+			t.Timezone,
+			t.RecurrenceMode,
+			t.Id,
+			tenant,
+			t.Description,
+			t.DescriptionHtml,
+			jsonColumn[[]model.Link]{&t.Links},
+			jsonColumn[[]model.ChecklistItem]{&t.Checklist},
+		), &updated)
+		if err != nil {
+			return err
+		}
+		return r.recordEvents(ctx, tx, tenant, before, updated)
+	})
+	if err == sql.ErrNoRows {
+		log.Default().Println("repository: todo not found:", t.Id)
+		return model.Todo{}, service.ErrNotFound
+	}
 	if err != nil {
 		log.Default().Println("repository: failed to update todo:", err)
 		return model.Todo{}, err
 	}
 
+	r.wrote(ctx)
 	log.Default().Println("repository: Updated todo successfully:", t.Id)
-	return *t, nil
+	return updated, nil
 }
 
 func (r *Repository) Delete(ctx context.Context, id string) error {
-	_, err := r.deleteStmt.ExecContext(ctx, id)
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var deleted model.Todo
+		err := scanTodo(tx.StmtContext(ctx, r.deleteStmt).QueryRowContext(ctx, id, tenant), &deleted)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		deleted.Deleted = true
+		return r.recordEvents(ctx, tx, tenant, &todoState{completed: deleted.Completed}, deleted)
+	})
 	if err != nil {
 		log.Default().Println("repository: failed to delete todo:", err)
 		return err
 	}
+	r.wrote(ctx)
 	return nil
 }
 
-func (r *Repository) List(ctx context.Context) ([]model.Todo, error) {
-	rows, err := r.listStmt.QueryContext(ctx)
-	if err != nil {
-		log.Default().Println("repository: failed to list todos:", err)
-		return nil, err
+func (r *Repository) List(ctx context.Context, opts model.ListOptions) ([]model.Todo, error) {
+	if rep := r.reader(ctx); rep != nil {
+		result, err := rep.repo.List(ctx, opts)
+		if err == nil {
+			return result, nil
+		}
+		r.replicaFailed(rep, err)
+	}
+
+	stmt := r.listStmt
+	switch opts.Order {
+	case model.ListOrderPriority:
+		stmt = r.listByPriorityStmt
+	case model.ListOrderPosition:
+		stmt = r.listByPositionStmt
 	}
-	defer rows.Close()
 
 	var result []model.Todo
-	for rows.Next() {
-		var t model.Todo
-		if err := rows.Scan(&t.Id, &t.Title, &t.Completed); err != nil {
-			log.Default().Println("repository: scan failed:", err)
-			continue
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, stmt).QueryContext(ctx, opts.ListId, pq.Array(opts.VisibleLists), tenant, opts.Query, opts.CreatedBy)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t model.Todo
+			if err := scanTodo(rows, &t); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				continue
+			}
+			result = append(result, t)
 		}
-		result = append(result, t)
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list todos:", err)
+		return nil, err
 	}
 
 	log.Default().Println("repository: Listed todos successfully, count:", len(result))
 	return result, nil
 }
 
-func (r *Repository) Close() {
-	stmts := []*sql.Stmt{
-		r.createStmt,
-		r.getStmt,
-		r.listStmt,
-		r.updateStmt,
-		r.deleteStmt,
+// CountCreatedBy returns the number of live todos created by subject.
+func (r *Repository) CountCreatedBy(ctx context.Context, subject string) (int, error) {
+	var count int
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, r.countCreatedByStmt).QueryRowContext(ctx, subject, tenant).Scan(&count)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to count todos:", err)
+		return 0, err
 	}
+	return count, nil
+}
 
-	for _, stmt := range stmts {
-		if stmt != nil {
-			stmt.Close()
+func (r *Repository) Close() {
+	for _, s := range r.statements() {
+		if *s.stmt != nil {
+			(*s.stmt).Close()
 		}
 	}
+	if r.replicas != nil {
+		r.replicas.close()
+	}
 }
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestDescriptionAndSearch(t *testing.T) {
+	r, _ := newTestRepository(t)
+	ctx, _ := tenants()
+
+	checklist := []model.ChecklistItem{{Text: "Draft the notes"}, {Text: "Tag it", Checked: true}}
+	release, err := r.Create(ctx, &model.Todo{
+		Title:           "Release 1.2",
+		Position:        "V",
+		Description:     "- [ ] Draft the notes\n- [x] Tag it",
+		DescriptionHtml: "<ul>…</ul>",
+		Links:           []model.Link{{Url: "https://example.com", Text: "example"}},
+		Checklist:       checklist,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := r.Create(ctx, &model.Todo{Title: "Water plants", Position: "W"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got, err := r.Get(ctx, release.Id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Description != release.Description || got.DescriptionHtml != release.DescriptionHtml ||
+		!reflect.DeepEqual(got.Checklist, checklist) || len(got.Links) != 1 {
+		t.Errorf("Expected the description to be stored, got %+v", got)
+	}
+
+	tests := map[string][]string{
+		"":                  {"Water plants", "Release 1.2"},
+		"notes":             {"Release 1.2"},
+		"release -draft":    {},
+		`"water plants"`:    {"Water plants"},
+		"plants or release": {"Water plants", "Release 1.2"},
+	}
+	for query, want := range tests {
+		todos, err := r.List(ctx, model.ListOptions{Query: query})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var titles []string
+		for _, todo := range todos {
+			titles = append(titles, todo.Title)
+		}
+		if len(titles) != len(want) || len(want) > 0 && !reflect.DeepEqual(titles, want) {
+			t.Errorf("%q: Expected %v, got %v", query, want, titles)
+		}
+	}
+}
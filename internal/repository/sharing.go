@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// memberColumns is the column list scanned by scanMember.
+const memberColumns = `list_id, user_id, role, created_at`
+
+// invitationColumns is the column list scanned by scanInvitation, selected
+// from list_invitations i joined with lists l.
+const invitationColumns = `i.id, i.list_id, l.name, i.user_id, i.email, i.role, i.invited_by, i.status, i.created_at`
+
+func scanMember(row interface{ Scan(...any) error }, m *model.Member) error {
+	return row.Scan(&m.ListId, &m.UserId, &m.Role, &m.CreatedAt)
+}
+
+func scanInvitation(row interface{ Scan(...any) error }, inv *model.Invitation) error {
+	return row.Scan(&inv.Id, &inv.ListId, &inv.ListName, &inv.UserId, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.Status, &inv.CreatedAt)
+}
+
+// CreateList creates a list and makes owner its first member in a single
+// transaction.
+func (r *Repository) CreateList(ctx context.Context, list *model.TodoList, owner string) (model.TodoList, error) {
+	list.Id = uuid.NewString()
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		err := tx.StmtContext(ctx, r.createListStmt).QueryRowContext(ctx, list.Id, list.Name, tenant).Scan(&list.CreatedAt)
+		if err != nil {
+			log.Default().Println("repository: failed to create list:", err)
+			return err
+		}
+
+		var m model.Member
+		err = scanMember(tx.StmtContext(ctx, r.addMemberStmt).QueryRowContext(ctx, list.Id, owner, model.RoleOwner, tenant), &m)
+		if err != nil {
+			log.Default().Println("repository: failed to add list owner:", err)
+			return err
+		}
+		list.Role = m.Role
+		return nil
+	})
+	if err != nil {
+		return model.TodoList{}, err
+	}
+
+	log.Default().Println("repository: Created list successfully:", list.Id)
+	return *list, nil
+}
+
+func (r *Repository) ListsOf(ctx context.Context, userID string) ([]model.TodoList, error) {
+	var result []model.TodoList
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.listsOfStmt).QueryContext(ctx, userID, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var l model.TodoList
+			if err := rows.Scan(&l.Id, &l.Name, &l.CreatedAt, &l.Role); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, l)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list lists:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Role returns the role of userID in a list, or model.RoleNone.
+func (r *Repository) Role(ctx context.Context, listID, userID string) (model.Role, error) {
+	var role model.Role
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, r.roleStmt).QueryRowContext(ctx, listID, userID, tenant).Scan(&role)
+	})
+	if err == sql.ErrNoRows {
+		return model.RoleNone, nil
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to read role:", err)
+		return model.RoleNone, err
+	}
+	return role, nil
+}
+
+func (r *Repository) Members(ctx context.Context, listID string) ([]model.Member, error) {
+	var result []model.Member
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.membersStmt).QueryContext(ctx, listID, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var m model.Member
+			if err := scanMember(rows, &m); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, m)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list members:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) SetRole(ctx context.Context, listID, userID string, role model.Role) (model.Member, error) {
+	var m model.Member
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return scanMember(tx.StmtContext(ctx, r.setRoleStmt).QueryRowContext(ctx, listID, userID, role, tenant), &m)
+	})
+	if err == sql.ErrNoRows {
+		return model.Member{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to set role:", err)
+		return model.Member{}, err
+	}
+
+	log.Default().Println("repository: Updated member successfully:", listID, userID)
+	return m, nil
+}
+
+func (r *Repository) RemoveMember(ctx context.Context, listID, userID string) error {
+	var res sql.Result
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var err error
+		res, err = tx.StmtContext(ctx, r.removeMemberStmt).ExecContext(ctx, listID, userID, tenant)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to remove member:", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrNotFound
+	}
+
+	log.Default().Println("repository: Removed member successfully:", listID, userID)
+	return nil
+}
+
+func (r *Repository) CreateInvitation(ctx context.Context, inv *model.Invitation) (model.Invitation, error) {
+	inv.Id = uuid.NewString()
+
+	var created model.Invitation
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		err := tx.StmtContext(ctx, r.createInviteStmt).QueryRowContext(
+			ctx,
+			inv.Id,
+			inv.ListId,
+			inv.UserId,
+			inv.Email,
+			inv.Role,
+			inv.InvitedBy,
+			inv.Status,
+			tenant,
+		).Scan(&inv.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return scanInvitation(tx.StmtContext(ctx, r.invitationStmt).QueryRowContext(ctx, inv.Id, tenant), &created)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to create invitation:", err)
+		return model.Invitation{}, err
+	}
+
+	log.Default().Println("repository: Created invitation successfully:", inv.Id)
+	return created, nil
+}
+
+func (r *Repository) Invitations(ctx context.Context, userID, email string) ([]model.Invitation, error) {
+	var result []model.Invitation
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.invitationsStmt).QueryContext(ctx, userID, email, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var inv model.Invitation
+			if err := scanInvitation(rows, &inv); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, inv)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list invitations:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) Invitation(ctx context.Context, id string) (model.Invitation, error) {
+	var inv model.Invitation
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return scanInvitation(tx.StmtContext(ctx, r.invitationStmt).QueryRowContext(ctx, id, tenant), &inv)
+	})
+	if err == sql.ErrNoRows {
+		return model.Invitation{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to get invitation:", err)
+		return model.Invitation{}, err
+	}
+	return inv, nil
+}
+
+// RespondInvitation settles a pending invitation and, if it is accepted,
+// adds the member in the same transaction.
+func (r *Repository) RespondInvitation(ctx context.Context, id, userID string, accept bool) (model.Member, error) {
+	status := model.InvitationStatusDeclined
+	if accept {
+		status = model.InvitationStatusAccepted
+	}
+
+	var m model.Member
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var (
+			listID string
+			role   model.Role
+		)
+		err := tx.StmtContext(ctx, r.respondInviteStmt).QueryRowContext(ctx, id, status, tenant).Scan(&listID, &role)
+		if err != nil {
+			return err
+		}
+		if !accept {
+			return nil
+		}
+		return scanMember(tx.StmtContext(ctx, r.addMemberStmt).QueryRowContext(ctx, listID, userID, role, tenant), &m)
+	})
+	if err == sql.ErrNoRows {
+		return model.Member{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to respond to invitation:", err)
+		return model.Member{}, err
+	}
+
+	log.Default().Println("repository: Responded to invitation successfully:", id, accept)
+	return m, nil
+}
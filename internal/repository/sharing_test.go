@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+func TestPersonalTodosThroughTheService(t *testing.T) {
+	r, _ := newTestRepository(t)
+	for _, repo := range []service.Repository{r, newTestPgxRepository(t)} {
+		s := service.NewTodosService(repo)
+		acme, _ := tenants()
+		alice := auth.WithPrincipal(acme, auth.Principal{UserID: "alice"})
+		bob := auth.WithPrincipal(acme, auth.Principal{UserID: "bob"})
+
+		todo, err := s.Create(alice, &model.Todo{Title: "Taxes"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got, err := repo.Get(alice, todo.Id); err != nil || got.CreatedBy != "user:alice" {
+			t.Errorf("Expected the creator to be loaded, got %q %v", got.CreatedBy, err)
+		}
+		if got, err := s.Get(alice, todo.Id); err != nil || got.Title != "Taxes" {
+			t.Errorf("Expected the creator to read the todo, got %v %v", got, err)
+		}
+		todo.Title = "Taxes 2025"
+		if _, err := s.Update(alice, &todo); err != nil {
+			t.Errorf("Expected the creator to update the todo, got %v", err)
+		}
+		if todos, err := s.List(alice, model.ListOptions{}); err != nil || len(todos) != 1 {
+			t.Errorf("Expected the todo in the creator's list, got %v %v", todos, err)
+		}
+		if changes, err := s.Sync(alice, &model.SyncRequest{}); err != nil || len(changes.Todos) != 1 {
+			t.Errorf("Expected the todo to sync to the creator, got %+v %v", changes, err)
+		}
+
+		if _, err := s.Get(bob, todo.Id); !errors.Is(err, service.ErrPermissionDenied) {
+			t.Errorf("Expected ErrPermissionDenied for another user, got %v", err)
+		}
+		if err := s.Delete(alice, todo.Id); err != nil {
+			t.Errorf("Expected the creator to delete the todo, got %v", err)
+		}
+	}
+}
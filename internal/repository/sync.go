@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+)
+
+// Changes returns up to limit todos, tombstones included, that come after
+// since in the order of the transactions that wrote them. Versions are drawn
+// before commit, so a version order would skip a transaction that commits
+// after one with a higher version has been read.
+func (r *Repository) Changes(ctx context.Context, since model.ChangeCursor, limit int) ([]model.Todo, error) {
+	if rep := r.reader(ctx); rep != nil {
+		result, err := rep.repo.Changes(ctx, since, limit)
+		if err == nil {
+			return result, nil
+		}
+		r.replicaFailed(rep, err)
+	}
+
+	var result []model.Todo
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.changesStmt).QueryContext(ctx, since.Txid, since.Version, limit, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t model.Todo
+			if err := scanTodo(rows, &t, &t.Deleted, &t.Txid); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, t)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list changes:", err)
+		return nil, err
+	}
+
+	log.Default().Println("repository: Listed changes successfully, count:", len(result))
+	return result, nil
+}
+
+// Lookup fetches a todo by id, returning its tombstone if it was deleted.
+func (r *Repository) Lookup(ctx context.Context, id string) (model.Todo, error) {
+	var t model.Todo
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return scanTodo(tx.StmtContext(ctx, r.lookupStmt).QueryRowContext(ctx, id, tenant), &t, &t.Deleted)
+	})
+	if err == sql.ErrNoRows {
+		return model.Todo{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to look up todo:", err)
+		return model.Todo{}, err
+	}
+	return t, nil
+}
+
+// Put writes t only if the stored version still equals expected. An expected
+// version of zero inserts t and requires that the id is not taken yet.
+func (r *Repository) Put(ctx context.Context, t *model.Todo, expected int64) (model.Todo, error) {
+	written := *t
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		if expected == 0 {
+			written.SeriesId, written.Occurrence = t.Id, 1
+			err := tx.StmtContext(ctx, r.insertStmt).
+				QueryRowContext(ctx, t.Id, t.Title, t.Completed, t.Priority, t.Position, t.CreatedBy, tenant).
+				Scan(&written.Version, &written.UpdatedAt)
+			if err != nil {
+				return err
+			}
+			return r.recordEvents(ctx, tx, tenant, nil, written)
+		}
+
+		before, err := r.before(ctx, tx, tenant, t.Id)
+		if err != nil {
+			return err
+		}
+		err = scanTodo(tx.StmtContext(ctx, r.putStmt).
+			QueryRowContext(ctx, t.Title, t.Completed, t.Priority, t.Deleted, t.Id, expected, tenant), &written)
+		if err != nil {
+			return err
+		}
+		written.Deleted = t.Deleted
+		return r.recordEvents(ctx, tx, tenant, before, written)
+	})
+	if err == sql.ErrNoRows {
+		if expected != 0 {
+			if _, lerr := r.Lookup(ctx, t.Id); lerr != nil {
+				return model.Todo{}, lerr
+			}
+		}
+		return model.Todo{}, service.ErrVersionMismatch
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to put todo:", err)
+		return model.Todo{}, err
+	}
+
+	written.Deleted = t.Deleted
+	r.wrote(ctx)
+	log.Default().Println("repository: Put todo successfully:", t.Id, written.Version)
+	return written, nil
+}
+
+// LatestChange returns the highest version handed out so far and the time
+// of the last write, which change whenever any todo is written or deleted.
+func (r *Repository) LatestChange(ctx context.Context) (model.Watermark, error) {
+	if rep := r.reader(ctx); rep != nil {
+		w, err := rep.repo.LatestChange(ctx)
+		if err == nil {
+			return w, nil
+		}
+		r.replicaFailed(rep, err)
+	}
+
+	var w model.Watermark
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, r.latestStmt).QueryRowContext(ctx, tenant).Scan(&w.Version, &w.UpdatedAt)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to read latest change:", err)
+		return model.Watermark{}, err
+	}
+	return w, nil
+}
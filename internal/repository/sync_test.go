@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestChangesWaitForRunningTransactions(t *testing.T) {
+	r, conn := newTestRepository(t)
+	acme, _ := tenants()
+
+	// a transaction that started before the write below may still commit a
+	// change that sorts before it
+	running, err := conn.Begin()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer running.Rollback()
+	if _, err := running.Exec(`SELECT pg_current_xact_id()`); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	todo, err := r.Create(acme, &model.Todo{Title: "Quarterly report", Position: "V"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if changes, err := r.Changes(acme, model.ChangeCursor{}, 100); err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes while an older transaction runs, got %v %v", changes, err)
+	}
+
+	if err := running.Rollback(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	changes, err := r.Changes(acme, model.ChangeCursor{}, 100)
+	if err != nil || len(changes) != 1 || changes[0].Id != todo.Id {
+		t.Fatalf("Expected the todo, got %v %v", changes, err)
+	}
+	if changes[0].Txid == 0 {
+		t.Errorf("Expected the todo to be stamped with its transaction")
+	}
+
+	cursor := model.ChangeCursor{Txid: changes[0].Txid, Version: changes[0].Version}
+	if changes, err := r.Changes(acme, cursor, 100); err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes after the cursor, got %v %v", changes, err)
+	}
+}
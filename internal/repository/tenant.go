@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+)
+
+// inTenant runs fn in a transaction scoped to the tenant of ctx and commits
+// it if fn succeeds. Every query filters on the tenant itself; the row-level
+// security policies, which compare tenant_id with app.tenant_id, catch any
+// query that does not.
+func (r *Repository) inTenant(ctx context.Context, fn func(tx *sql.Tx, tenant string) error) error {
+	tenant := auth.Tenant(ctx)
+	return r.inTx(ctx, r.setTenantStmt, []any{tenant}, func(tx *sql.Tx) error {
+		return fn(tx, tenant)
+	})
+}
+
+// inCredentialLookup runs fn in a transaction allowed to read the API keys
+// and calendar feeds of every tenant, which is how a token is resolved to
+// its tenant in the first place.
+func (r *Repository) inCredentialLookup(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return r.inTx(ctx, r.credentialLookupStmt, nil, fn)
+}
+
+// inTx runs fn in a new transaction set up by setup, or in the unit of
+// work of the repository if it has one. Settings made by setup in a unit of
+// work last until it ends. A transaction that fails on a stale connection
+// before fn runs starts over once on a fresh one.
+func (r *Repository) inTx(ctx context.Context, setup *sql.Stmt, args []any, fn func(tx *sql.Tx) error) error {
+	if r.tx != nil {
+		if _, err := r.tx.StmtContext(ctx, setup).ExecContext(ctx, args...); err != nil {
+			log.Default().Println("repository: failed to scope transaction:", err)
+			return err
+		}
+		return fn(r.tx)
+	}
+
+	return retryStale(func(work *bool) (err error) {
+		conn, err := r.db.Conn(ctx)
+		if err != nil {
+			log.Default().Println("repository: failed to get connection:", err)
+			return err
+		}
+		defer func() { release(conn, err) }()
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			log.Default().Println("repository: failed to begin transaction:", err)
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.StmtContext(ctx, setup).ExecContext(ctx, args...); err != nil {
+			log.Default().Println("repository: failed to scope transaction:", err)
+			return err
+		}
+		*work = true
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
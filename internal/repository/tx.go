@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+const (
+	// maxTxAttempts is how often WithinTx runs a unit of work that keeps
+	// failing to serialize.
+	maxTxAttempts = 5
+	// txRetryDelay is the base of the randomized wait between attempts.
+	txRetryDelay = 10 * time.Millisecond
+)
+
+// isolationLevels maps service isolation levels to database/sql ones.
+var isolationLevels = map[service.IsolationLevel]sql.IsolationLevel{
+	service.IsolationDefault:        sql.LevelDefault,
+	service.IsolationReadCommitted:  sql.LevelReadCommitted,
+	service.IsolationRepeatableRead: sql.LevelRepeatableRead,
+	service.IsolationSerializable:   sql.LevelSerializable,
+}
+
+// WithinTx runs fn with a copy of the repository bound to a single
+// transaction. Its methods reuse the prepared statements through
+// tx.StmtContext. Serialization failures and deadlocks roll back and run fn
+// again, up to maxTxAttempts times, and so does a transaction on a stale
+// connection, once, on a fresh one. Inside a unit of work fn joins it.
+func (r *Repository) WithinTx(ctx context.Context, opts service.TxOptions, fn func(service.Repository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+	return retryTx(ctx, func() error {
+		return r.runTx(ctx, opts, func(unit *Repository, _ *sql.Conn) error {
+			return fn(unit)
+		})
+	})
+}
+
+// retryTx runs a transaction with run, again after a serialization failure
+// or a deadlock, up to maxTxAttempts times, and once more on a fresh
+// connection after a stale one.
+func retryTx(ctx context.Context, run func() error) error {
+	for attempt, fresh := 1, false; ; attempt++ {
+		err := run()
+		if stale(err) && !fresh {
+			// release discarded the connection, the next one is fresh
+			log.Default().Println("repository: retrying on a fresh connection after:", err)
+			fresh = true
+			continue
+		}
+		if !retryable(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		log.Default().Println("repository: retrying transaction after:", err)
+		delay := txRetryDelay * time.Duration(1<<(attempt-1))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay/2 + rand.N(delay)):
+		}
+	}
+}
+
+// runTx begins a transaction on a connection of its own and calls fn with
+// a copy of the repository bound to it and the connection, then commits.
+func (r *Repository) runTx(ctx context.Context, opts service.TxOptions, fn func(unit *Repository, conn *sql.Conn) error) (err error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		log.Default().Println("repository: failed to get connection:", err)
+		return err
+	}
+	defer func() { release(conn, err) }()
+
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{Isolation: isolationLevels[opts.Isolation], ReadOnly: opts.ReadOnly})
+	if err != nil {
+		log.Default().Println("repository: failed to begin transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	unit := *r
+	unit.tx = tx
+	if err := fn(&unit, conn); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// retryable reports whether err is a serialization failure or a deadlock,
+// after which the whole transaction can simply run again.
+func retryable(err error) bool {
+	code, _ := sqlState(err)
+	return code == "40001" || code == "40P01"
+}
+
+// sqlState returns the SQLSTATE code and message of a Postgres error from
+// either driver, or empty strings for any other error.
+func sqlState(err error) (code, message string) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code), pqErr.Message
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code, pgErr.Message
+	}
+	return "", ""
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/lib/pq"
+)
+
+func TestRetryable(t *testing.T) {
+	if !retryable(fmt.Errorf("commit: %w", &pq.Error{Code: "40001"})) {
+		t.Errorf("Expected a serialization failure to be retried")
+	}
+	if !retryable(&pq.Error{Code: "40P01"}) {
+		t.Errorf("Expected a deadlock to be retried")
+	}
+	if retryable(&pq.Error{Code: "23505"}) || retryable(errors.New("boom")) || retryable(nil) {
+		t.Errorf("Expected other errors not to be retried")
+	}
+}
+
+func TestWithinTxRetriesAndRollsBack(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, _ := tenants()
+
+	attempts := 0
+	var created model.Todo
+	err := r.WithinTx(acme, service.TxOptions{Isolation: service.IsolationSerializable}, func(repo service.Repository) error {
+		attempts++
+		var err error
+		created, err = repo.Create(acme, &model.Todo{Title: fmt.Sprintf("attempt %d", attempts), Position: "V"})
+		if err != nil {
+			return err
+		}
+		// nested units of work join the outer one
+		return repo.WithinTx(acme, service.TxOptions{}, func(repo service.Repository) error {
+			if attempts == 1 {
+				return &pq.Error{Code: "40001", Message: "could not serialize access"}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+
+	todos, err := r.List(acme, model.ListOptions{})
+	if err != nil || len(todos) != 1 || todos[0].Id != created.Id {
+		t.Errorf("Expected only the todo of the second attempt, got %v %v", todos, err)
+	}
+}
+
+func TestWithinTxRollsBackOnError(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, _ := tenants()
+
+	failed := errors.New("audit failed")
+	err := r.WithinTx(acme, service.TxOptions{}, func(repo service.Repository) error {
+		if _, err := repo.Create(acme, &model.Todo{Title: "Rolled back", Position: "V"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("Expected the error of fn, got %v", err)
+	}
+	if todos, err := r.List(acme, model.ListOptions{}); err != nil || len(todos) != 0 {
+		t.Errorf("Expected no todos, got %v %v", todos, err)
+	}
+}
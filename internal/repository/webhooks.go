@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/service"
+	"github.com/lib/pq"
+)
+
+// deliveryColumns is the column list scanned by scanDelivery.
+const deliveryColumns = `id, webhook_id, event, payload, status, attempts, last_status_code, last_error,
+	next_attempt_at, delivered_at, created_at`
+
+// claimedColumns is deliveryColumns of webhook_deliveries d followed by the
+// URL and secret of webhooks w.
+const claimedColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.last_status_code, d.last_error,
+	d.next_attempt_at, d.delivered_at, d.created_at, w.url, w.secret`
+
+func scanDelivery(row interface{ Scan(...any) error }, d *model.WebhookDelivery, extra ...any) error {
+	dest := append([]any{
+		&d.Id, &d.WebhookId, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.LastStatusCode, &d.LastError,
+		&d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt,
+	}, extra...)
+	return row.Scan(dest...)
+}
+
+// inDispatch runs fn in a transaction allowed to read and update the webhooks
+// and deliveries of every tenant, which is how the dispatcher sends them.
+func (r *Repository) inDispatch(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return r.inTx(ctx, r.webhookDispatchStmt, nil, fn)
+}
+
+func (r *Repository) CreateWebhook(ctx context.Context, webhook *model.Webhook) (model.Webhook, error) {
+	webhook.Id = uuid.NewString()
+
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return tx.StmtContext(ctx, r.createWebhookStmt).QueryRowContext(
+			ctx,
+			webhook.Id,
+			webhook.Url,
+			pq.Array(webhook.Events),
+			webhook.Secret,
+			tenant,
+			webhook.CreatedBy,
+		).Scan(&webhook.CreatedAt)
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to create webhook:", err)
+		return model.Webhook{}, err
+	}
+
+	log.Default().Println("repository: Created webhook successfully:", webhook.Id)
+	return *webhook, nil
+}
+
+func (r *Repository) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	var result []model.Webhook
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.listWebhooksStmt).QueryContext(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var w model.Webhook
+			if err := rows.Scan(&w.Id, &w.Url, pq.Array(&w.Events), &w.CreatedAt); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, w)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list webhooks:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, id string) error {
+	var res sql.Result
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		var err error
+		res, err = tx.StmtContext(ctx, r.deleteWebhookStmt).ExecContext(ctx, id, tenant)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to delete webhook:", err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrNotFound
+	}
+
+	log.Default().Println("repository: Deleted webhook successfully:", id)
+	return nil
+}
+
+// EnqueueEvent adds a pending delivery of payload to every webhook of the
+// tenant subscribed to event whose owner can see the todo.
+func (r *Repository) EnqueueEvent(ctx context.Context, event, todoID string, payload []byte) error {
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		_, err := tx.StmtContext(ctx, r.enqueueStmt).ExecContext(ctx, event, string(payload), tenant, todoID)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to enqueue event:", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) ListDeliveries(ctx context.Context, opts model.DeliveryOptions) ([]model.WebhookDelivery, error) {
+	var result []model.WebhookDelivery
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		rows, err := tx.StmtContext(ctx, r.deliveriesStmt).QueryContext(ctx, opts.WebhookId, tenant, opts.Status, opts.PageSize)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var d model.WebhookDelivery
+			if err := scanDelivery(rows, &d); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to list deliveries:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Repository) RedeliverDelivery(ctx context.Context, webhookID, id string) (model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	err := r.inTenant(ctx, func(tx *sql.Tx, tenant string) error {
+		return scanDelivery(tx.StmtContext(ctx, r.redeliverStmt).QueryRowContext(ctx, id, webhookID, tenant), &d)
+	})
+	if err == sql.ErrNoRows {
+		return model.WebhookDelivery{}, service.ErrNotFound
+	}
+	if err != nil {
+		log.Default().Println("repository: failed to redeliver delivery:", err)
+		return model.WebhookDelivery{}, err
+	}
+
+	log.Default().Println("repository: Queued redelivery successfully:", id)
+	return d, nil
+}
+
+// ClaimDeliveries returns up to limit pending deliveries of every tenant that
+// are due at now, and moves their next attempt to now+lease so no other
+// dispatcher sends them meanwhile.
+func (r *Repository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	var result []model.WebhookDelivery
+	err := r.inDispatch(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.StmtContext(ctx, r.claimStmt).QueryContext(ctx, now, lease.Milliseconds(), limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var d model.WebhookDelivery
+			if err := scanDelivery(rows, &d, &d.Url, &d.Secret); err != nil {
+				log.Default().Println("repository: scan failed:", err)
+				return err
+			}
+			result = append(result, d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to claim deliveries:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// RecordAttempt stores the outcome of sending a delivery.
+func (r *Repository) RecordAttempt(ctx context.Context, d *model.WebhookDelivery) error {
+	err := r.inDispatch(ctx, func(tx *sql.Tx) error {
+		_, err := tx.StmtContext(ctx, r.recordAttemptStmt).ExecContext(
+			ctx,
+			d.Id,
+			d.Status,
+			d.Attempts,
+			d.LastStatusCode,
+			d.LastError,
+			d.NextAttemptAt,
+			d.DeliveredAt,
+		)
+		return err
+	})
+	if err != nil {
+		log.Default().Println("repository: failed to record delivery attempt:", err)
+		return err
+	}
+	return nil
+}
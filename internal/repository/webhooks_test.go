@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestEnqueueEventOnlyForTodosTheOwnerCanSee(t *testing.T) {
+	r, _ := newTestRepository(t)
+	acme, _ := tenants()
+
+	list, err := r.CreateList(acme, &model.TodoList{Name: "Groceries"}, "alice")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	hooks := map[string]model.Webhook{}
+	for _, owner := range []string{"user:alice", "user:bob"} {
+		hook, err := r.CreateWebhook(acme, &model.Webhook{Url: "https://example.com/hook", Events: []string{model.EventTodoCreated}, Secret: "secret", CreatedBy: owner})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		hooks[owner] = hook
+	}
+
+	milk, err := r.Create(acme, &model.Todo{Title: "Milk", Position: "V", ListId: list.Id, CreatedBy: "user:alice"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	taxes, err := r.Create(acme, &model.Todo{Title: "Taxes", Position: "W", CreatedBy: "user:alice"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, todo := range []model.Todo{milk, taxes} {
+		if err := r.EnqueueEvent(acme, model.EventTodoCreated, todo.Id, []byte(`{}`)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	deliveries := func(owner string) int {
+		got, err := r.ListDeliveries(acme, model.DeliveryOptions{WebhookId: hooks[owner].Id, PageSize: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return len(got)
+	}
+	if got := deliveries("user:alice"); got != 2 {
+		t.Errorf("Expected 2 deliveries for the owner of both todos, got %d", got)
+	}
+	if got := deliveries("user:bob"); got != 0 {
+		t.Errorf("Expected no deliveries for a user outside the list, got %d", got)
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+var (
+	ErrAPIKeysUnsupported = errors.New("repository does not store API keys")
+	ErrInvalidExpiry      = errors.New("API key expiry must be in the future")
+)
+
+// keyPrefixLen is the number of hex digits after auth.KeyPrefix that make up
+// the public, indexed part of a token.
+const keyPrefixLen = 12
+
+// APIKeySecret is the stored form of the secret part of an API key token.
+type APIKeySecret struct {
+	Salt []byte
+	// Hash is the SHA-256 of the salt followed by the secret.
+	Hash []byte
+}
+
+// APIKeyStore is implemented by repositories that persist API keys. Keys are
+// looked up by their unique prefix; only a salted hash of the secret is kept.
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey, secret APIKeySecret) (model.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]model.APIKey, error)
+	// RotateAPIKey replaces the prefix and secret of an active key, or
+	// returns ErrNotFound.
+	RotateAPIKey(ctx context.Context, id, prefix string, secret APIKeySecret) (model.APIKey, error)
+	// RevokeAPIKey returns ErrNotFound for unknown or revoked keys.
+	RevokeAPIKey(ctx context.Context, id string) error
+	// APIKeyByPrefix returns the active key with the given prefix and its
+	// secret, or ErrNotFound.
+	APIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, APIKeySecret, error)
+	// TouchAPIKey records that the key was used just now.
+	TouchAPIKey(ctx context.Context, id string) error
+}
+
+func (s *Service) apiKeys() (APIKeyStore, error) {
+	store, ok := lookup[APIKeyStore](s.repo)
+	if !ok {
+		return nil, ErrAPIKeysUnsupported
+	}
+	return store, nil
+}
+
+// newAPIKeyToken returns a token of the form todos_<prefix>_<secret>, its
+// prefix and the secret to store.
+func newAPIKeyToken() (token, prefix string, secret APIKeySecret, err error) {
+	raw := make([]byte, keyPrefixLen/2+32+16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", APIKeySecret{}, err
+	}
+	id, key, salt := raw[:keyPrefixLen/2], raw[keyPrefixLen/2:keyPrefixLen/2+32], raw[keyPrefixLen/2+32:]
+
+	prefix = auth.KeyPrefix + hex.EncodeToString(id)
+	plain := base64.RawURLEncoding.EncodeToString(key)
+	return prefix + "_" + plain, prefix, APIKeySecret{Salt: salt, Hash: hashAPIKey(salt, plain)}, nil
+}
+
+func hashAPIKey(salt []byte, plain string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(plain))
+	return h.Sum(nil)
+}
+
+// CreateAPIKey creates a key and returns its token, which is not stored and
+// cannot be shown again.
+func (s *Service) CreateAPIKey(ctx context.Context, key *model.APIKey) (model.CreateAPIKeyResponse, error) {
+	store, err := s.apiKeys()
+	if err != nil {
+		return model.CreateAPIKeyResponse{}, err
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(s.now()) {
+		return model.CreateAPIKeyResponse{}, ErrInvalidExpiry
+	}
+
+	token, prefix, secret, err := newAPIKeyToken()
+	if err != nil {
+		return model.CreateAPIKeyResponse{}, err
+	}
+	key.Prefix = prefix
+
+	created, err := store.CreateAPIKey(ctx, key, secret)
+	if err != nil {
+		return model.CreateAPIKeyResponse{}, err
+	}
+	return model.CreateAPIKeyResponse{APIKey: &created, Token: token}, nil
+}
+
+func (s *Service) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	store, err := s.apiKeys()
+	if err != nil {
+		return nil, err
+	}
+	return store.ListAPIKeys(ctx)
+}
+
+// RotateAPIKey gives a key a new token, keeping its name, scopes and expiry.
+func (s *Service) RotateAPIKey(ctx context.Context, id string) (model.CreateAPIKeyResponse, error) {
+	store, err := s.apiKeys()
+	if err != nil {
+		return model.CreateAPIKeyResponse{}, err
+	}
+
+	token, prefix, secret, err := newAPIKeyToken()
+	if err != nil {
+		return model.CreateAPIKeyResponse{}, err
+	}
+
+	rotated, err := store.RotateAPIKey(ctx, id, prefix, secret)
+	if err != nil {
+		return model.CreateAPIKeyResponse{}, err
+	}
+	return model.CreateAPIKeyResponse{APIKey: &rotated, Token: token}, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, id string) error {
+	store, err := s.apiKeys()
+	if err != nil {
+		return err
+	}
+	return store.RevokeAPIKey(ctx, id)
+}
+
+// VerifyAPIKey implements auth.KeyVerifier.
+func (s *Service) VerifyAPIKey(ctx context.Context, token string) (auth.Principal, error) {
+	store, err := s.apiKeys()
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	n := len(auth.KeyPrefix) + keyPrefixLen
+	if len(token) <= n+1 || token[n] != '_' {
+		return auth.Principal{}, auth.ErrInvalidKey
+	}
+	prefix, plain := token[:n], token[n+1:]
+
+	key, secret, err := store.APIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, ErrNotFound) {
+		return auth.Principal{}, auth.ErrInvalidKey
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if subtle.ConstantTimeCompare(hashAPIKey(secret.Salt, plain), secret.Hash) != 1 {
+		return auth.Principal{}, auth.ErrInvalidKey
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(s.now()) {
+		return auth.Principal{}, auth.ErrInvalidKey
+	}
+
+	if err := store.TouchAPIKey(auth.WithTenant(ctx, key.TenantId), key.Id); err != nil {
+		return auth.Principal{}, err
+	}
+	return auth.Principal{APIKeyID: key.Id, Scopes: key.Scopes, TenantID: key.TenantId}, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// keyRepo adds API key storage to memoryRepo.
+type keyRepo struct {
+	*memoryRepo
+	keys    map[string]model.APIKey
+	secrets map[string]APIKeySecret
+	touched int
+}
+
+func newKeyRepo() *keyRepo {
+	return &keyRepo{memoryRepo: newMemoryRepo(), keys: map[string]model.APIKey{}, secrets: map[string]APIKeySecret{}}
+}
+
+func (k *keyRepo) CreateAPIKey(ctx context.Context, key *model.APIKey, secret APIKeySecret) (model.APIKey, error) {
+	key.Id = fmt.Sprintf("key-%d", len(k.keys)+1)
+	key.TenantId = auth.Tenant(ctx)
+	k.keys[key.Id] = *key
+	k.secrets[key.Id] = secret
+	return *key, nil
+}
+
+func (k *keyRepo) ListAPIKeys(context.Context) ([]model.APIKey, error) {
+	var result []model.APIKey
+	for _, key := range k.keys {
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func (k *keyRepo) RotateAPIKey(_ context.Context, id, prefix string, secret APIKeySecret) (model.APIKey, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return model.APIKey{}, ErrNotFound
+	}
+	key.Prefix = prefix
+	k.keys[id] = key
+	k.secrets[id] = secret
+	return key, nil
+}
+
+func (k *keyRepo) RevokeAPIKey(_ context.Context, id string) error {
+	if _, ok := k.keys[id]; !ok {
+		return ErrNotFound
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+func (k *keyRepo) APIKeyByPrefix(_ context.Context, prefix string) (model.APIKey, APIKeySecret, error) {
+	for id, key := range k.keys {
+		if key.Prefix == prefix {
+			return key, k.secrets[id], nil
+		}
+	}
+	return model.APIKey{}, APIKeySecret{}, ErrNotFound
+}
+
+func (k *keyRepo) TouchAPIKey(ctx context.Context, id string) error {
+	if auth.Tenant(ctx) != k.keys[id].TenantId {
+		return ErrNotFound
+	}
+	k.touched++
+	return nil
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	ctx := auth.WithTenant(context.Background(), "acme")
+	repo := newKeyRepo()
+	svc := NewTodosService(repo)
+
+	created, err := svc.CreateAPIKey(ctx, &model.APIKey{Name: "ci", Scopes: []string{auth.ScopeTodosRead}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if !strings.HasPrefix(created.Token, created.APIKey.Prefix+"_") {
+		t.Errorf("Expected token to start with prefix %s, got %s", created.APIKey.Prefix, created.Token)
+	}
+
+	p, err := svc.VerifyAPIKey(context.Background(), created.Token)
+	if err != nil {
+		t.Fatalf("VerifyAPIKey failed: %v", err)
+	}
+	if p.APIKeyID != created.APIKey.Id || !p.HasScope(auth.ScopeTodosRead) || p.HasScope(auth.ScopeTodosWrite) {
+		t.Errorf("Expected a read-only principal for %s, got %+v", created.APIKey.Id, p)
+	}
+	if p.TenantID != "acme" {
+		t.Errorf("Expected the key to act in tenant 'acme', got '%s'", p.TenantID)
+	}
+	if repo.touched != 1 {
+		t.Errorf("Expected last use to be recorded once, got %d", repo.touched)
+	}
+
+	if _, err := svc.VerifyAPIKey(ctx, created.Token+"x"); !errors.Is(err, auth.ErrInvalidKey) {
+		t.Errorf("Expected ErrInvalidKey for a wrong secret, got %v", err)
+	}
+
+	rotated, err := svc.RotateAPIKey(ctx, created.APIKey.Id)
+	if err != nil {
+		t.Fatalf("RotateAPIKey failed: %v", err)
+	}
+	if _, err := svc.VerifyAPIKey(ctx, created.Token); !errors.Is(err, auth.ErrInvalidKey) {
+		t.Errorf("Expected the old token to stop working, got %v", err)
+	}
+	if _, err := svc.VerifyAPIKey(ctx, rotated.Token); err != nil {
+		t.Errorf("Expected the rotated token to work, got %v", err)
+	}
+
+	if err := svc.RevokeAPIKey(ctx, created.APIKey.Id); err != nil {
+		t.Fatalf("RevokeAPIKey failed: %v", err)
+	}
+	if _, err := svc.VerifyAPIKey(ctx, rotated.Token); !errors.Is(err, auth.ErrInvalidKey) {
+		t.Errorf("Expected a revoked key to be rejected, got %v", err)
+	}
+}
+
+func TestAPIKeyExpiry(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTodosService(newKeyRepo())
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	past := now.Add(-time.Hour)
+	if _, err := svc.CreateAPIKey(ctx, &model.APIKey{Name: "old", ExpiresAt: &past}); !errors.Is(err, ErrInvalidExpiry) {
+		t.Errorf("Expected ErrInvalidExpiry, got %v", err)
+	}
+
+	expiry := now.Add(time.Hour)
+	created, err := svc.CreateAPIKey(ctx, &model.APIKey{Name: "cron", ExpiresAt: &expiry})
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if _, err := svc.VerifyAPIKey(ctx, created.Token); err != nil {
+		t.Errorf("Expected the key to work before it expires, got %v", err)
+	}
+
+	now = expiry
+	if _, err := svc.VerifyAPIKey(ctx, created.Token); !errors.Is(err, auth.ErrInvalidKey) {
+		t.Errorf("Expected an expired key to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+var (
+	ErrAttachmentsUnsupported = errors.New("attachments are not configured")
+	ErrAttachmentTooLarge     = errors.New("attachment too large")
+	ErrAttachmentMissing      = errors.New("attachment content is missing")
+)
+
+// Attachment limits and retention used unless configured.
+const (
+	defaultMaxAttachmentBytes  = 25 << 20
+	defaultAttachmentRetention = 30 * 24 * time.Hour
+	// purgeBatchSize is the number of attachments purged per query.
+	purgeBatchSize = 100
+)
+
+// BlobStore stores the content of attachments under keys chosen by the
+// service.
+type BlobStore interface {
+	// Put stores the size bytes read from r under key.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens the blob stored under key. A missing blob fails with an
+	// fs.ErrNotExist error.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key, if there is one.
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentStore is implemented by repositories that persist the metadata
+// of attachments.
+type AttachmentStore interface {
+	CreateAttachment(ctx context.Context, a *model.Attachment) (model.Attachment, error)
+	// ListAttachments returns the attachments of a todo, oldest first.
+	ListAttachments(ctx context.Context, todoID string) ([]model.Attachment, error)
+	// GetAttachment returns ErrNotFound for unknown and deleted attachments.
+	GetAttachment(ctx context.Context, id string) (model.Attachment, error)
+	// DeleteAttachment marks an attachment deleted, or returns ErrNotFound.
+	DeleteAttachment(ctx context.Context, id string) error
+	// AttachmentBytesBy returns the size of the attachments created by
+	// subject that are not deleted.
+	AttachmentBytesBy(ctx context.Context, subject string) (int64, error)
+	// PurgeableAttachments returns up to limit attachments of any tenant
+	// that were deleted, or whose todo was deleted before deletedBefore.
+	PurgeableAttachments(ctx context.Context, deletedBefore time.Time, limit int) ([]model.Attachment, error)
+	// RemoveAttachments deletes the records of purged attachments.
+	RemoveAttachments(ctx context.Context, ids []string) error
+}
+
+// AttachmentLimits bounds the attachments callers may upload.
+type AttachmentLimits struct {
+	// MaxBytes caps a single attachment; 0 means 25 MiB.
+	MaxBytes int64
+	// MaxBytesPerUser caps the attachments each authenticated caller keeps;
+	// 0 means no cap.
+	MaxBytesPerUser int64
+	// Retention is how long the attachments of a deleted todo are kept,
+	// since a todo can be restored by pushing it again; 0 means 30 days.
+	Retention time.Duration
+}
+
+// WithAttachments stores the content of attachments in blobs.
+func WithAttachments(blobs BlobStore, limits AttachmentLimits) Option {
+	return func(s *Service) {
+		s.blobs = blobs
+		s.attachmentLimits = limits
+		if s.attachmentLimits.MaxBytes <= 0 {
+			s.attachmentLimits.MaxBytes = defaultMaxAttachmentBytes
+		}
+		if s.attachmentLimits.Retention <= 0 {
+			s.attachmentLimits.Retention = defaultAttachmentRetention
+		}
+	}
+}
+
+func (s *Service) attachments() (AttachmentStore, error) {
+	store, ok := lookup[AttachmentStore](s.repo)
+	if !ok || s.blobs == nil {
+		return nil, ErrAttachmentsUnsupported
+	}
+	return store, nil
+}
+
+// UploadAttachment attaches the content read from r to the todo a.TodoId.
+// The content is spooled to a temporary file to learn its size and digest
+// before it is stored, and refused with ErrAttachmentTooLarge as soon as it
+// exceeds a limit.
+func (s *Service) UploadAttachment(ctx context.Context, a *model.Attachment, r io.Reader) (model.Attachment, error) {
+	store, err := s.attachments()
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	subject, err := author(ctx)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if _, err := s.existingTodo(ctx, a.TodoId, model.RoleEditor); err != nil {
+		return model.Attachment{}, err
+	}
+	a.CreatedBy = subject
+
+	limit := s.attachmentLimits.MaxBytes
+	if quota, limited, err := s.attachmentQuota(ctx, store, subject); err != nil {
+		return model.Attachment{}, err
+	} else if limited {
+		limit = min(limit, quota)
+	}
+
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	var head headBuffer
+	n, err := io.Copy(io.MultiWriter(tmp, h, &head), io.LimitReader(r, limit+1))
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if n > limit {
+		return model.Attachment{}, s.tooLarge(limit)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return model.Attachment{}, err
+	}
+
+	a.Id = uuid.NewString()
+	a.Filename = cleanFilename(a.Filename)
+	a.ContentType = contentType(a.ContentType, a.Filename, head.b)
+	a.Size = n
+	a.Sha256 = hex.EncodeToString(h.Sum(nil))
+	a.BlobKey = path.Join("attachments", url.PathEscape(auth.Tenant(ctx)), a.Id)
+
+	if err := s.blobs.Put(ctx, a.BlobKey, tmp, n); err != nil {
+		return model.Attachment{}, err
+	}
+
+	var created model.Attachment
+	err = s.withinAttachmentQuota(ctx, func(repo Repository) error {
+		store, ok := lookup[AttachmentStore](repo)
+		if !ok {
+			return ErrAttachmentsUnsupported
+		}
+		if quota, limited, err := s.attachmentQuota(ctx, store, subject); err != nil {
+			return err
+		} else if limited && n > quota {
+			return s.tooLarge(quota)
+		}
+		created, err = store.CreateAttachment(ctx, a)
+		return err
+	})
+	if err != nil {
+		if err := s.blobs.Delete(context.WithoutCancel(ctx), a.BlobKey); err != nil {
+			log.Default().Println("service: failed to delete blob of failed upload:", err)
+		}
+		return model.Attachment{}, err
+	}
+	return created, nil
+}
+
+// attachmentQuota returns the bytes subject may still upload, and whether a
+// per-user cap applies at all.
+func (s *Service) attachmentQuota(ctx context.Context, store AttachmentStore, subject string) (int64, bool, error) {
+	if s.attachmentLimits.MaxBytesPerUser <= 0 {
+		return 0, false, nil
+	}
+	used, err := store.AttachmentBytesBy(ctx, subject)
+	if err != nil {
+		return 0, false, err
+	}
+	return max(s.attachmentLimits.MaxBytesPerUser-used, 0), true, nil
+}
+
+// withinAttachmentQuota runs fn in a serializable transaction if a per-user
+// cap applies, like withinQuota.
+func (s *Service) withinAttachmentQuota(ctx context.Context, fn func(Repository) error) error {
+	if s.attachmentLimits.MaxBytesPerUser <= 0 {
+		return fn(s.repo)
+	}
+	return s.repo.WithinTx(ctx, TxOptions{Isolation: IsolationSerializable}, fn)
+}
+
+func (s *Service) tooLarge(limit int64) error {
+	if limit < s.attachmentLimits.MaxBytes {
+		return fmt.Errorf("%w: %d bytes left of the %d bytes each user may store", ErrAttachmentTooLarge, limit, s.attachmentLimits.MaxBytesPerUser)
+	}
+	return fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, limit)
+}
+
+// headBuffer keeps the first bytes written to it, for content sniffing.
+type headBuffer struct {
+	b []byte
+}
+
+func (h *headBuffer) Write(p []byte) (int, error) {
+	if n := 512 - len(h.b); n > 0 {
+		h.b = append(h.b, p[:min(n, len(p))]...)
+	}
+	return len(p), nil
+}
+
+// cleanFilename keeps the last element of a client supplied path, without
+// control characters.
+func cleanFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	return name
+}
+
+// contentType returns the declared type if it is valid, otherwise the type
+// of the file extension or of the content.
+func contentType(declared, filename string, head []byte) string {
+	if declared != "" && declared != "application/octet-stream" {
+		if _, _, err := mime.ParseMediaType(declared); err == nil {
+			return declared
+		}
+	}
+	if t := mime.TypeByExtension(path.Ext(filename)); t != "" {
+		return t
+	}
+	return http.DetectContentType(head)
+}
+
+func (s *Service) ListAttachments(ctx context.Context, todoID string) ([]model.Attachment, error) {
+	store, err := s.attachments()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.existingTodo(ctx, todoID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return store.ListAttachments(ctx, todoID)
+}
+
+// OpenAttachment returns an attachment and its content, which the caller
+// must close.
+func (s *Service) OpenAttachment(ctx context.Context, id string) (model.Attachment, io.ReadCloser, error) {
+	store, err := s.attachments()
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+	a, err := store.GetAttachment(ctx, id)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+	if _, err := s.existingTodo(ctx, a.TodoId, model.RoleViewer); err != nil {
+		return model.Attachment{}, nil, err
+	}
+	content, err := s.blobs.Get(ctx, a.BlobKey)
+	if errors.Is(err, fs.ErrNotExist) {
+		return model.Attachment{}, nil, fmt.Errorf("%w: %s", ErrAttachmentMissing, a.Id)
+	}
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+	return a, content, nil
+}
+
+// DeleteAttachment deletes an attachment. Its content is removed by
+// PurgeAttachments.
+func (s *Service) DeleteAttachment(ctx context.Context, id string) error {
+	store, err := s.attachments()
+	if err != nil {
+		return err
+	}
+	a, err := store.GetAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.existingTodo(ctx, a.TodoId, model.RoleEditor); err != nil {
+		return err
+	}
+	return store.DeleteAttachment(ctx, id)
+}
+
+// PurgeAttachments removes the content and records of deleted attachments
+// and of the attachments of todos deleted longer than the retention ago, in
+// every tenant. It returns the number of attachments purged. A record is
+// only removed once its blob is, so an interrupted purge is picked up by
+// the next one.
+func (s *Service) PurgeAttachments(ctx context.Context) (int, error) {
+	store, err := s.attachments()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for {
+		batch, err := store.PurgeableAttachments(ctx, s.now().Add(-s.attachmentLimits.Retention), purgeBatchSize)
+		if err != nil || len(batch) == 0 {
+			return purged, err
+		}
+		ids := make([]string, len(batch))
+		for i, a := range batch {
+			if err := s.blobs.Delete(ctx, a.BlobKey); err != nil {
+				return purged, err
+			}
+			ids[i] = a.Id
+		}
+		if err := store.RemoveAttachments(ctx, ids); err != nil {
+			return purged, err
+		}
+		purged += len(batch)
+		if len(batch) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// RunAttachmentPurger calls PurgeAttachments every interval until ctx is
+// done.
+func (s *Service) RunAttachmentPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.PurgeAttachments(ctx)
+		if err != nil {
+			log.Default().Println("service: failed to purge attachments:", err)
+		} else if n > 0 {
+			log.Default().Println("service: purged attachments:", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// attachmentRepo adds attachments to memberRepo.
+type attachmentRepo struct {
+	*memberRepo
+	attachments map[string]model.Attachment
+	deleted     map[string]bool
+}
+
+func newAttachmentRepo() *attachmentRepo {
+	return &attachmentRepo{memberRepo: newMemberRepo(), attachments: map[string]model.Attachment{}, deleted: map[string]bool{}}
+}
+
+func (m *attachmentRepo) WithinTx(_ context.Context, _ TxOptions, fn func(Repository) error) error {
+	return fn(m)
+}
+
+func (m *attachmentRepo) CreateAttachment(_ context.Context, a *model.Attachment) (model.Attachment, error) {
+	if t, ok := m.todos[a.TodoId]; !ok || t.Deleted {
+		return model.Attachment{}, ErrNotFound
+	}
+	a.CreatedAt = time.Now()
+	m.attachments[a.Id] = *a
+	return *a, nil
+}
+
+func (m *attachmentRepo) ListAttachments(_ context.Context, todoID string) ([]model.Attachment, error) {
+	var result []model.Attachment
+	for _, a := range m.attachments {
+		if a.TodoId == todoID && !m.deleted[a.Id] {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *attachmentRepo) GetAttachment(_ context.Context, id string) (model.Attachment, error) {
+	a, ok := m.attachments[id]
+	if !ok || m.deleted[id] {
+		return model.Attachment{}, ErrNotFound
+	}
+	return a, nil
+}
+
+func (m *attachmentRepo) DeleteAttachment(_ context.Context, id string) error {
+	if _, ok := m.attachments[id]; !ok || m.deleted[id] {
+		return ErrNotFound
+	}
+	m.deleted[id] = true
+	return nil
+}
+
+func (m *attachmentRepo) AttachmentBytesBy(_ context.Context, subject string) (int64, error) {
+	var size int64
+	for _, a := range m.attachments {
+		if a.CreatedBy == subject && !m.deleted[a.Id] {
+			size += a.Size
+		}
+	}
+	return size, nil
+}
+
+// PurgeableAttachments takes the last change of a deleted todo as the time
+// it was deleted.
+func (m *attachmentRepo) PurgeableAttachments(_ context.Context, deletedBefore time.Time, limit int) ([]model.Attachment, error) {
+	var result []model.Attachment
+	for _, a := range m.attachments {
+		t := m.todos[a.TodoId]
+		if m.deleted[a.Id] || (t.Deleted && t.UpdatedAt.Before(deletedBefore)) {
+			result = append(result, a)
+		}
+	}
+	return result[:min(limit, len(result))], nil
+}
+
+func (m *attachmentRepo) RemoveAttachments(_ context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(m.attachments, id)
+		delete(m.deleted, id)
+	}
+	return nil
+}
+
+// memoryBlobs is an in-memory BlobStore.
+type memoryBlobs map[string][]byte
+
+func (b memoryBlobs) Put(_ context.Context, key string, r io.Reader, size int64) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	b[key] = data
+	return nil
+}
+
+func (b memoryBlobs) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := b[key]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (b memoryBlobs) Delete(_ context.Context, key string) error {
+	delete(b, key)
+	return nil
+}
+
+func TestUploadAndDownloadAttachment(t *testing.T) {
+	repo := newAttachmentRepo()
+	blobs := memoryBlobs{}
+	s := NewTodosService(repo, WithAttachments(blobs, AttachmentLimits{}))
+	listID := sharedList(t, s)
+	repo.roles[listID]["bob"] = model.RoleViewer
+	content := "2 litres, semi-skimmed\n"
+
+	a, err := s.UploadAttachment(as("alice", ""), &model.Attachment{TodoId: "Milk", Filename: `C:\Users\alice\notes.txt`}, strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("UploadAttachment failed: %v", err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	if a.Filename != "notes.txt" || a.ContentType != "text/plain; charset=utf-8" || a.Size != int64(len(content)) || a.Sha256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected notes.txt as text/plain with its size and digest, got %+v", a)
+	}
+	if a.CreatedBy != "user:alice" {
+		t.Errorf("Expected the uploader to be recorded, got %q", a.CreatedBy)
+	}
+
+	attachments, err := s.ListAttachments(as("bob", ""), "Milk")
+	if err != nil || len(attachments) != 1 {
+		t.Fatalf("Expected a viewer to list one attachment, got %v %v", attachments, err)
+	}
+	got, r, err := s.OpenAttachment(as("bob", ""), a.Id)
+	if err != nil {
+		t.Fatalf("OpenAttachment failed: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if got.Id != a.Id || string(data) != content {
+		t.Errorf("Expected the uploaded content, got %q", data)
+	}
+
+	if _, err := s.UploadAttachment(as("bob", ""), &model.Attachment{TodoId: "Milk"}, strings.NewReader("x")); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a viewer, got %v", err)
+	}
+	if err := s.DeleteAttachment(as("bob", ""), a.Id); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a viewer, got %v", err)
+	}
+	if _, _, err := s.OpenAttachment(as("carol", ""), a.Id); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a non-member, got %v", err)
+	}
+	if _, err := s.UploadAttachment(as("alice", ""), &model.Attachment{TodoId: "Bread"}, strings.NewReader("x")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing todo, got %v", err)
+	}
+	if _, err := s.UploadAttachment(context.Background(), &model.Attachment{TodoId: "Milk"}, strings.NewReader("x")); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+
+	delete(blobs, a.BlobKey)
+	if _, _, err := s.OpenAttachment(as("alice", ""), a.Id); !errors.Is(err, ErrAttachmentMissing) {
+		t.Errorf("Expected ErrAttachmentMissing for lost content, got %v", err)
+	}
+}
+
+func TestAttachmentLimits(t *testing.T) {
+	repo := newAttachmentRepo()
+	blobs := memoryBlobs{}
+	s := NewTodosService(repo, WithAttachments(blobs, AttachmentLimits{MaxBytes: 10, MaxBytesPerUser: 16}))
+	alice := as("alice", "")
+	if _, err := s.Create(alice, &model.Todo{Title: "Taxes"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := s.UploadAttachment(alice, &model.Attachment{TodoId: "Taxes"}, strings.NewReader("12345678901")); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("Expected ErrAttachmentTooLarge over the size limit, got %v", err)
+	}
+	a, err := s.UploadAttachment(alice, &model.Attachment{TodoId: "Taxes"}, strings.NewReader("1234567890"))
+	if err != nil {
+		t.Fatalf("Expected an upload at the size limit to succeed, got %v", err)
+	}
+	if _, err := s.UploadAttachment(alice, &model.Attachment{TodoId: "Taxes"}, strings.NewReader("1234567")); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("Expected ErrAttachmentTooLarge over the per-user limit, got %v", err)
+	}
+	if _, err := s.Create(as("bob", ""), &model.Todo{Title: "Receipts"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := s.UploadAttachment(as("bob", ""), &model.Attachment{TodoId: "Receipts"}, strings.NewReader("1234567")); err != nil {
+		t.Errorf("Expected another user to be unaffected, got %v", err)
+	}
+	if len(blobs) != 2 {
+		t.Errorf("Expected refused uploads to store nothing, got %d blobs", len(blobs))
+	}
+
+	if err := s.DeleteAttachment(alice, a.Id); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if _, err := s.UploadAttachment(alice, &model.Attachment{TodoId: "Taxes"}, strings.NewReader("1234567")); err != nil {
+		t.Errorf("Expected deleting an attachment to free quota, got %v", err)
+	}
+}
+
+func TestPurgeAttachments(t *testing.T) {
+	repo := newAttachmentRepo()
+	blobs := memoryBlobs{}
+	s := NewTodosService(repo, WithAttachments(blobs, AttachmentLimits{Retention: time.Hour}))
+	for _, title := range []string{"Kept", "Removed", "Trashed"} {
+		if _, err := s.Create(as("alice", ""), &model.Todo{Title: title}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	upload := func(todoID string) model.Attachment {
+		a, err := s.UploadAttachment(as("alice", ""), &model.Attachment{TodoId: todoID, Filename: todoID + ".pdf"}, strings.NewReader("%PDF-"))
+		if err != nil {
+			t.Fatalf("UploadAttachment failed: %v", err)
+		}
+		return a
+	}
+	kept, removed, trashed := upload("Kept"), upload("Removed"), upload("Trashed")
+
+	if err := s.DeleteAttachment(as("alice", ""), removed.Id); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if err := s.Delete(as("alice", ""), "Trashed"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if n, err := s.PurgeAttachments(as("alice", "")); err != nil || n != 1 {
+		t.Fatalf("Expected the deleted attachment to be purged, got %d %v", n, err)
+	}
+	if _, ok := blobs[removed.BlobKey]; ok {
+		t.Errorf("Expected the content of the deleted attachment to be removed")
+	}
+	if _, ok := blobs[trashed.BlobKey]; !ok {
+		t.Errorf("Expected the attachment of a deleted todo to be kept during the retention")
+	}
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if n, err := s.PurgeAttachments(as("alice", "")); err != nil || n != 1 {
+		t.Fatalf("Expected the attachment of the deleted todo to be purged, got %d %v", n, err)
+	}
+	var left []string
+	for key := range blobs {
+		left = append(left, key)
+	}
+	if !slices.Equal(left, []string{kept.BlobKey}) {
+		t.Errorf("Expected only %s to be left, got %v", kept.BlobKey, left)
+	}
+}
+
+func TestAttachmentsUnsupported(t *testing.T) {
+	s := NewTodosService(newAttachmentRepo())
+	if _, err := s.ListAttachments(context.Background(), "Milk"); !errors.Is(err, ErrAttachmentsUnsupported) {
+		t.Errorf("Expected ErrAttachmentsUnsupported without a blob store, got %v", err)
+	}
+	s = NewTodosService(newMemoryRepo(), WithAttachments(memoryBlobs{}, AttachmentLimits{}))
+	if _, err := s.ListAttachments(context.Background(), "Milk"); !errors.Is(err, ErrAttachmentsUnsupported) {
+		t.Errorf("Expected ErrAttachmentsUnsupported without an attachment store, got %v", err)
+	}
+}
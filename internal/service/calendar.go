@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/transfer"
+)
+
+var ErrCalendarUnsupported = errors.New("repository does not store calendar feeds")
+
+// CalendarFeedStore is implemented by repositories that persist the tokens
+// of subscribable calendar feeds. Only a hash of each token is stored.
+type CalendarFeedStore interface {
+	CreateCalendarFeed(ctx context.Context, feed *model.CalendarFeed, tokenHash []byte) (model.CalendarFeed, error)
+	ListCalendarFeeds(ctx context.Context) ([]model.CalendarFeed, error)
+	// RevokeCalendarFeed returns ErrNotFound for unknown or revoked feeds.
+	RevokeCalendarFeed(ctx context.Context, id string) error
+	// CalendarFeedByTokenHash returns the active feed with the given token
+	// hash, or ErrNotFound.
+	CalendarFeedByTokenHash(ctx context.Context, tokenHash []byte) (model.CalendarFeed, error)
+}
+
+func (s *Service) calendarFeeds() (CalendarFeedStore, error) {
+	store, ok := lookup[CalendarFeedStore](s.repo)
+	if !ok {
+		return nil, ErrCalendarUnsupported
+	}
+	return store, nil
+}
+
+func hashFeedToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// CreateCalendarFeed creates a feed of the todos the caller can see and
+// returns its secret token, which is not stored and cannot be shown again.
+func (s *Service) CreateCalendarFeed(ctx context.Context, name string) (model.CreateCalendarFeedResponse, error) {
+	store, err := s.calendarFeeds()
+	if err != nil {
+		return model.CreateCalendarFeedResponse{}, err
+	}
+	subject, err := author(ctx)
+	if err != nil {
+		return model.CreateCalendarFeedResponse{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return model.CreateCalendarFeedResponse{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	feed, err := store.CreateCalendarFeed(ctx, &model.CalendarFeed{Name: name, CreatedBy: subject}, hashFeedToken(token))
+	if err != nil {
+		return model.CreateCalendarFeedResponse{}, err
+	}
+	return model.CreateCalendarFeedResponse{Feed: &feed, Token: token}, nil
+}
+
+func (s *Service) ListCalendarFeeds(ctx context.Context) ([]model.CalendarFeed, error) {
+	store, err := s.calendarFeeds()
+	if err != nil {
+		return nil, err
+	}
+	return store.ListCalendarFeeds(ctx)
+}
+
+func (s *Service) RevokeCalendarFeed(ctx context.Context, id string) error {
+	store, err := s.calendarFeeds()
+	if err != nil {
+		return err
+	}
+	return store.RevokeCalendarFeed(ctx, id)
+}
+
+// CalendarFeed resolves a feed token, returning ErrNotFound for unknown or
+// revoked tokens.
+func (s *Service) CalendarFeed(ctx context.Context, token string) (model.CalendarFeed, error) {
+	store, err := s.calendarFeeds()
+	if err != nil {
+		return model.CalendarFeed{}, err
+	}
+	if token == "" {
+		return model.CalendarFeed{}, ErrNotFound
+	}
+	return store.CalendarFeedByTokenHash(ctx, hashFeedToken(token))
+}
+
+// FeedContext returns ctx acting for the owner of feed in its tenant, since
+// calendar clients present nothing but the token of the feed.
+func FeedContext(ctx context.Context, feed model.CalendarFeed) context.Context {
+	var owner auth.Principal
+	if id, ok := strings.CutPrefix(feed.CreatedBy, "user:"); ok {
+		owner.UserID = id
+	} else if id, ok := strings.CutPrefix(feed.CreatedBy, "key:"); ok {
+		owner.APIKeyID = id
+	}
+	return auth.WithPrincipal(auth.WithTenant(ctx, feed.TenantId), owner)
+}
+
+// WriteCalendar writes the todos the owner of the feed can see, those of
+// their lists and those they created outside any list, in manual order, as
+// an iCalendar feed.
+func (s *Service) WriteCalendar(ctx context.Context, w io.Writer, feed model.CalendarFeed, component transfer.CalendarComponent) error {
+	var todos []model.Todo
+	// feeds created before their owner was recorded show nothing
+	if feed.CreatedBy != "" {
+		ctx = FeedContext(ctx, feed)
+		opts, err := s.scopeList(ctx, model.ListOptions{Order: model.ListOrderPosition})
+		if err != nil {
+			return err
+		}
+		if todos, err = s.repo.List(ctx, opts); err != nil {
+			return err
+		}
+	}
+	return transfer.EncodeICalendar(w, todos, transfer.CalendarOptions{Name: feed.Name, Component: component})
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/transfer"
+)
+
+// feedRepo adds calendar feed storage to memberRepo.
+type feedRepo struct {
+	*memberRepo
+	feeds  map[string]model.CalendarFeed
+	hashes map[string]string
+}
+
+func newFeedRepo() *feedRepo {
+	return &feedRepo{memberRepo: newMemberRepo(), feeds: map[string]model.CalendarFeed{}, hashes: map[string]string{}}
+}
+
+func (f *feedRepo) CreateCalendarFeed(_ context.Context, feed *model.CalendarFeed, tokenHash []byte) (model.CalendarFeed, error) {
+	feed.Id = fmt.Sprintf("feed-%d", len(f.feeds)+1)
+	f.feeds[feed.Id] = *feed
+	f.hashes[string(tokenHash)] = feed.Id
+	return *feed, nil
+}
+
+func (f *feedRepo) ListCalendarFeeds(context.Context) ([]model.CalendarFeed, error) {
+	var result []model.CalendarFeed
+	for _, feed := range f.feeds {
+		result = append(result, feed)
+	}
+	return result, nil
+}
+
+func (f *feedRepo) RevokeCalendarFeed(_ context.Context, id string) error {
+	if _, ok := f.feeds[id]; !ok {
+		return ErrNotFound
+	}
+	delete(f.feeds, id)
+	return nil
+}
+
+func (f *feedRepo) CalendarFeedByTokenHash(_ context.Context, tokenHash []byte) (model.CalendarFeed, error) {
+	feed, ok := f.feeds[f.hashes[string(tokenHash)]]
+	if !ok {
+		return model.CalendarFeed{}, ErrNotFound
+	}
+	return feed, nil
+}
+
+func TestCalendarFeedToken(t *testing.T) {
+	ctx := as("alice", "")
+	svc := NewTodosService(newFeedRepo())
+
+	if _, err := svc.CreateCalendarFeed(context.Background(), "Work"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+	created, err := svc.CreateCalendarFeed(ctx, "Work")
+	if err != nil {
+		t.Fatalf("CreateCalendarFeed failed: %v", err)
+	}
+	if len(created.Token) < 40 {
+		t.Errorf("Expected a long random token, got %q", created.Token)
+	}
+
+	feed, err := svc.CalendarFeed(ctx, created.Token)
+	if err != nil {
+		t.Fatalf("CalendarFeed failed: %v", err)
+	}
+	if feed.Id != created.Feed.Id || feed.CreatedBy != "user:alice" {
+		t.Errorf("Expected feed %s of alice, got %+v", created.Feed.Id, feed)
+	}
+
+	if _, err := svc.CalendarFeed(ctx, created.Token+"x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a wrong token, got %v", err)
+	}
+
+	if err := svc.RevokeCalendarFeed(ctx, feed.Id); err != nil {
+		t.Fatalf("RevokeCalendarFeed failed: %v", err)
+	}
+	if _, err := svc.CalendarFeed(ctx, created.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after revocation, got %v", err)
+	}
+}
+
+func TestCalendarFeedUnsupported(t *testing.T) {
+	svc := NewTodosService(newMemoryRepo())
+
+	if _, err := svc.CreateCalendarFeed(context.Background(), "Work"); !errors.Is(err, ErrCalendarUnsupported) {
+		t.Errorf("Expected ErrCalendarUnsupported, got %v", err)
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newFeedRepo()
+	svc := NewTodosService(repo)
+
+	if _, err := svc.Create(ctx, &model.Todo{Title: "Water plants"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	before, _ := svc.Watermark(ctx)
+
+	var buf bytes.Buffer
+	err := svc.WriteCalendar(ctx, &buf, model.CalendarFeed{Name: "Home", CreatedBy: "user:alice"}, transfer.ComponentTodo)
+	if err != nil {
+		t.Fatalf("WriteCalendar failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "SUMMARY:Water plants") {
+		t.Errorf("Expected the todo in the calendar, got %q", buf.String())
+	}
+
+	if _, err := svc.Create(ctx, &model.Todo{Title: "Pay rent"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if after, _ := svc.Watermark(ctx); after.Version <= before.Version {
+		t.Errorf("Expected the watermark to grow, got %d after %d", after.Version, before.Version)
+	}
+}
+
+func TestWriteCalendarShowsTheTodosOfItsOwner(t *testing.T) {
+	svc := NewTodosService(newFeedRepo())
+	sharedList(t, svc)
+
+	if _, err := svc.Create(as("alice", ""), &model.Todo{Title: "Call mum"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := svc.Create(as("bob", ""), &model.Todo{Title: "Book dentist"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	write := func(createdBy string) string {
+		var buf bytes.Buffer
+		err := svc.WriteCalendar(context.Background(), &buf, model.CalendarFeed{Name: "Home", CreatedBy: createdBy}, transfer.ComponentTodo)
+		if err != nil {
+			t.Fatalf("WriteCalendar failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	alice := write("user:alice")
+	if !strings.Contains(alice, "SUMMARY:Milk") || !strings.Contains(alice, "SUMMARY:Call mum") || strings.Contains(alice, "SUMMARY:Book dentist") {
+		t.Errorf("Expected the list and own todos of alice, got %q", alice)
+	}
+	bob := write("user:bob")
+	if strings.Contains(bob, "SUMMARY:Milk") || strings.Contains(bob, "SUMMARY:Call mum") || !strings.Contains(bob, "SUMMARY:Book dentist") {
+		t.Errorf("Expected only the own todo of bob, got %q", bob)
+	}
+	if nobody := write(""); strings.Contains(nobody, "SUMMARY:") {
+		t.Errorf("Expected a feed without owner to show nothing, got %q", nobody)
+	}
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+const (
+	defaultCommentPageSize = 50
+	maxCommentPageSize     = 200
+	maxCommentBytes        = 8192
+	pageTokenPrefix        = "c1:"
+)
+
+var (
+	ErrCommentsUnsupported = errors.New("repository does not store comments")
+	ErrInvalidComment      = errors.New("invalid comment")
+	ErrInvalidPageToken    = errors.New("invalid page token")
+)
+
+// CommentStore is implemented by repositories that store comments on todos.
+type CommentStore interface {
+	// CreateComment stores a comment on a live todo and bumps the version
+	// of the todo, so that its comment count is picked up like any other
+	// change. It returns ErrNotFound if the todo does not exist.
+	CreateComment(ctx context.Context, c *model.Comment) (model.Comment, error)
+	// GetComment returns ErrNotFound for unknown and deleted comments.
+	GetComment(ctx context.Context, id string) (model.Comment, error)
+	// ListComments returns up to opts.PageSize comments of a todo that come
+	// after opts.AfterTime and opts.AfterId, oldest first.
+	ListComments(ctx context.Context, opts model.CommentOptions) ([]model.Comment, error)
+	// UpdateComment replaces the body and mentions of a comment and stamps
+	// it as edited, or returns ErrNotFound.
+	UpdateComment(ctx context.Context, c *model.Comment) (model.Comment, error)
+	// DeleteComment marks a comment deleted and bumps the version of its
+	// todo, or returns ErrNotFound.
+	DeleteComment(ctx context.Context, id string) error
+	CommentCounter
+}
+
+// CommentCounter is the part of CommentStore that reads of todos use, which
+// a wrapper such as a cache may serve on its own.
+type CommentCounter interface {
+	// CommentCounts returns the number of comments of each of todoIDs that
+	// has any, in one query.
+	CommentCounts(ctx context.Context, todoIDs []string) (map[string]int32, error)
+}
+
+func (s *Service) comments() (CommentStore, error) {
+	store, ok := lookup[CommentStore](s.repo)
+	if !ok {
+		return nil, ErrCommentsUnsupported
+	}
+	return store, nil
+}
+
+// withinComments runs fn on the comment store of a unit of work, so that
+// cached reads of the todos are dropped once fn changed a comment count.
+func (s *Service) withinComments(ctx context.Context, fn func(CommentStore) error) error {
+	return s.repo.WithinTx(ctx, TxOptions{}, func(repo Repository) error {
+		store, ok := lookup[CommentStore](repo)
+		if !ok {
+			return ErrCommentsUnsupported
+		}
+		return fn(store)
+	})
+}
+
+// mentionPattern matches "@user" at the start of the body or after a
+// character that cannot be part of an email address. User ids end in a
+// letter or digit, so trailing punctuation is not part of the mention.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.+-])@([\w](?:[\w.+-]*\w)?)`)
+
+// parseMentions returns the distinct user ids mentioned in body, in order of
+// appearance. Code spans and blocks are skipped.
+func parseMentions(body string) []string {
+	var mentions []string
+	for i, part := range strings.Split(body, "`") {
+		// odd parts are between backticks
+		if i%2 == 1 {
+			continue
+		}
+		for _, m := range mentionPattern.FindAllStringSubmatch(part, -1) {
+			if !slices.Contains(mentions, m[1]) {
+				mentions = append(mentions, m[1])
+			}
+		}
+	}
+	return mentions
+}
+
+// mentionsIn returns the users mentioned in body. In a shared list only its
+// members can be mentioned, so a comment cannot reach anyone else.
+func (s *Service) mentionsIn(ctx context.Context, t model.Todo, body string) ([]string, error) {
+	mentions := parseMentions(body)
+	if t.ListId == "" || len(mentions) == 0 {
+		return mentions, nil
+	}
+	store, err := s.memberships()
+	if err != nil {
+		return nil, err
+	}
+	members, err := store.Members(ctx, t.ListId)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(mentions, func(userID string) bool {
+		return !slices.ContainsFunc(members, func(m model.Member) bool { return m.UserId == userID })
+	}), nil
+}
+
+func validateComment(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("%w: body is empty", ErrInvalidComment)
+	}
+	if len(body) > maxCommentBytes {
+		return fmt.Errorf("%w: body is longer than %d bytes", ErrInvalidComment, maxCommentBytes)
+	}
+	return nil
+}
+
+// author returns the subject comments of the caller are written as. Only
+// authenticated callers can comment.
+func author(ctx context.Context) (string, error) {
+	p, _ := auth.FromContext(ctx)
+	if p.Subject() == "" {
+		return "", ErrUnauthenticated
+	}
+	return p.Subject(), nil
+}
+
+// CreateComment adds a comment to the todo c.TodoId, which the caller must
+// be allowed to edit.
+func (s *Service) CreateComment(ctx context.Context, c *model.Comment) (model.Comment, error) {
+	if _, err := s.comments(); err != nil {
+		return model.Comment{}, err
+	}
+	if err := validateComment(c.Body); err != nil {
+		return model.Comment{}, err
+	}
+	subject, err := author(ctx)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	t, err := s.existingTodo(ctx, c.TodoId, model.RoleEditor)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	c.Id = uuid.NewString()
+	c.Author = subject
+	if c.Mentions, err = s.mentionsIn(ctx, t, c.Body); err != nil {
+		return model.Comment{}, err
+	}
+
+	var created model.Comment
+	err = s.withinComments(ctx, func(store CommentStore) error {
+		created, err = store.CreateComment(ctx, c)
+		return err
+	})
+	if err != nil {
+		return model.Comment{}, err
+	}
+	return created, nil
+}
+
+// GetComment returns a comment on a todo the caller can read.
+func (s *Service) GetComment(ctx context.Context, id string) (model.Comment, error) {
+	store, err := s.comments()
+	if err != nil {
+		return model.Comment{}, err
+	}
+	c, err := store.GetComment(ctx, id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if _, err := s.existingTodo(ctx, c.TodoId, model.RoleViewer); err != nil {
+		return model.Comment{}, err
+	}
+	return c, nil
+}
+
+// ListComments returns a page of the comments of a todo, oldest first.
+func (s *Service) ListComments(ctx context.Context, opts model.CommentOptions) (model.CommentPage, error) {
+	store, err := s.comments()
+	if err != nil {
+		return model.CommentPage{}, err
+	}
+	if opts.AfterTime, opts.AfterId, err = decodePageToken(opts.PageToken); err != nil {
+		return model.CommentPage{}, err
+	}
+	if _, err := s.existingTodo(ctx, opts.TodoId, model.RoleViewer); err != nil {
+		return model.CommentPage{}, err
+	}
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultCommentPageSize
+	}
+	pageSize = min(pageSize, maxCommentPageSize)
+	// one more tells whether there is another page
+	opts.PageSize = pageSize + 1
+	comments, err := store.ListComments(ctx, opts)
+	if err != nil {
+		return model.CommentPage{}, err
+	}
+
+	page := model.CommentPage{Comments: comments}
+	if len(comments) > int(pageSize) {
+		page.Comments = comments[:pageSize]
+		last := page.Comments[pageSize-1]
+		page.NextPageToken = encodePageToken(last.CreatedAt, last.Id)
+	}
+	return page, nil
+}
+
+// UpdateComment replaces the body of a comment. Only its author may edit
+// it, as long as they may still edit the todo.
+func (s *Service) UpdateComment(ctx context.Context, c *model.Comment) (model.Comment, error) {
+	store, err := s.comments()
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if err := validateComment(c.Body); err != nil {
+		return model.Comment{}, err
+	}
+	subject, err := author(ctx)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	current, err := store.GetComment(ctx, c.Id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	t, err := s.existingTodo(ctx, current.TodoId, model.RoleEditor)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if current.Author != subject {
+		return model.Comment{}, ErrPermissionDenied
+	}
+
+	current.Body = c.Body
+	if current.Mentions, err = s.mentionsIn(ctx, t, c.Body); err != nil {
+		return model.Comment{}, err
+	}
+	return store.UpdateComment(ctx, &current)
+}
+
+// DeleteComment deletes a comment. Its author and the owners of the list
+// of the todo may delete it.
+func (s *Service) DeleteComment(ctx context.Context, id string) error {
+	store, err := s.comments()
+	if err != nil {
+		return err
+	}
+	subject, err := author(ctx)
+	if err != nil {
+		return err
+	}
+	c, err := store.GetComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	t, err := s.existingTodo(ctx, c.TodoId, model.RoleViewer)
+	if err != nil {
+		return err
+	}
+	if c.Author != subject {
+		if t.ListId == "" {
+			return ErrPermissionDenied
+		}
+		if err := s.authorize(ctx, t.ListId, model.RoleOwner); err != nil {
+			return err
+		}
+	}
+	return s.withinComments(ctx, func(store CommentStore) error {
+		return store.DeleteComment(ctx, id)
+	})
+}
+
+// countComments fills in the comment counts of todos with one query, if the
+// repository stores comments.
+func (s *Service) countComments(ctx context.Context, todos []model.Todo) error {
+	store, ok := lookup[CommentCounter](s.repo)
+	if !ok {
+		return nil
+	}
+	ids := todoIDs(todos)
+	if len(ids) == 0 {
+		return nil
+	}
+	counts, err := store.CommentCounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range todos {
+		todos[i].CommentCount = counts[todos[i].Id]
+	}
+	return nil
+}
+
+// encodePageToken returns the token of the page after the comment created
+// at createdAt with id.
+func encodePageToken(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + createdAt.UTC().Format(time.RFC3339Nano) + "," + id))
+}
+
+func decodePageToken(token string) (time.Time, string, error) {
+	if token == "" {
+		return time.Time{}, "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(raw), pageTokenPrefix) {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+	at, id, ok := strings.Cut(strings.TrimPrefix(string(raw), pageTokenPrefix), ",")
+	if !ok {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+	// the id is compared with a UUID column, where anything else fails
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+	return createdAt, id, nil
+}
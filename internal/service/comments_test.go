@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// commentRepo adds comments to memberRepo.
+type commentRepo struct {
+	*memberRepo
+	comments []model.Comment
+	deleted  map[string]bool
+	counted  int
+}
+
+func newCommentRepo() *commentRepo {
+	return &commentRepo{memberRepo: newMemberRepo(), deleted: map[string]bool{}}
+}
+
+func (m *commentRepo) WithinTx(_ context.Context, _ TxOptions, fn func(Repository) error) error {
+	return fn(m)
+}
+
+func (m *commentRepo) CreateComment(_ context.Context, c *model.Comment) (model.Comment, error) {
+	if t, ok := m.todos[c.TodoId]; !ok || t.Deleted {
+		return model.Comment{}, ErrNotFound
+	}
+	// distinct times keep the order of the comments
+	c.CreatedAt = time.Unix(int64(len(m.comments)), 0)
+	m.comments = append(m.comments, *c)
+	return *c, nil
+}
+
+func (m *commentRepo) GetComment(_ context.Context, id string) (model.Comment, error) {
+	i := slices.IndexFunc(m.comments, func(c model.Comment) bool { return c.Id == id })
+	if i < 0 || m.deleted[id] {
+		return model.Comment{}, ErrNotFound
+	}
+	return m.comments[i], nil
+}
+
+func (m *commentRepo) ListComments(_ context.Context, opts model.CommentOptions) ([]model.Comment, error) {
+	var result []model.Comment
+	for _, c := range m.comments {
+		if c.TodoId != opts.TodoId || m.deleted[c.Id] || c.CreatedAt.Before(opts.AfterTime) ||
+			(c.CreatedAt.Equal(opts.AfterTime) && c.Id <= opts.AfterId) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result[:min(int(opts.PageSize), len(result))], nil
+}
+
+func (m *commentRepo) UpdateComment(_ context.Context, c *model.Comment) (model.Comment, error) {
+	i := slices.IndexFunc(m.comments, func(stored model.Comment) bool { return stored.Id == c.Id })
+	if i < 0 || m.deleted[c.Id] {
+		return model.Comment{}, ErrNotFound
+	}
+	now := time.Now()
+	m.comments[i].Body, m.comments[i].Mentions, m.comments[i].EditedAt = c.Body, c.Mentions, &now
+	return m.comments[i], nil
+}
+
+func (m *commentRepo) DeleteComment(_ context.Context, id string) error {
+	if _, err := m.GetComment(context.Background(), id); err != nil {
+		return err
+	}
+	m.deleted[id] = true
+	return nil
+}
+
+func (m *commentRepo) CommentCounts(_ context.Context, todoIDs []string) (map[string]int32, error) {
+	m.counted++
+	counts := map[string]int32{}
+	for _, c := range m.comments {
+		if slices.Contains(todoIDs, c.TodoId) && !m.deleted[c.Id] {
+			counts[c.TodoId]++
+		}
+	}
+	return counts, nil
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"@bob can you pick this up?", []string{"bob"}},
+		{"Thanks @bob, and @carol.", []string{"bob", "carol"}},
+		{"@bob @bob", []string{"bob"}},
+		{"mail alice@example.com", nil},
+		{"run `@bob` in the shell", nil},
+		{"(@first.last)", []string{"first.last"}},
+		{"just @", nil},
+	}
+	for _, test := range tests {
+		if got := parseMentions(test.body); !slices.Equal(got, test.want) {
+			t.Errorf("Expected %v mentioned in %q, got %v", test.want, test.body, got)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	repo := newCommentRepo()
+	s := NewTodosService(repo)
+	listID := sharedList(t, s)
+	repo.roles[listID]["bob"] = model.RoleEditor
+	repo.roles[listID]["dave"] = model.RoleViewer
+	alice, bob := as("alice", ""), as("bob", "")
+
+	c, err := s.CreateComment(bob, &model.Comment{TodoId: "Milk", Body: "@alice semi-skimmed? cc @carol"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+	if c.Author != "user:bob" || c.Id == "" {
+		t.Errorf("Expected a comment by user:bob, got %+v", c)
+	}
+	if !slices.Equal(c.Mentions, []string{"alice"}) {
+		t.Errorf("Expected only members of the list to be mentioned, got %v", c.Mentions)
+	}
+
+	if _, err := s.CreateComment(as("dave", ""), &model.Comment{TodoId: "Milk", Body: "me too"}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a viewer, got %v", err)
+	}
+	if _, err := s.CreateComment(context.Background(), &model.Comment{TodoId: "Milk", Body: "anyone?"}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+	if _, err := s.CreateComment(bob, &model.Comment{TodoId: "Bread", Body: "hi"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing todo, got %v", err)
+	}
+	for _, body := range []string{" \n", strings.Repeat("x", maxCommentBytes+1)} {
+		if _, err := s.CreateComment(bob, &model.Comment{TodoId: "Milk", Body: body}); !errors.Is(err, ErrInvalidComment) {
+			t.Errorf("Expected ErrInvalidComment for a body of %d bytes, got %v", len(body), err)
+		}
+	}
+
+	if _, err := s.UpdateComment(alice, &model.Comment{Id: c.Id, Body: "edited"}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for editing someone else's comment, got %v", err)
+	}
+	edited, err := s.UpdateComment(bob, &model.Comment{Id: c.Id, Body: "2 litres, @alice"})
+	if err != nil {
+		t.Fatalf("UpdateComment failed: %v", err)
+	}
+	if edited.Body != "2 litres, @alice" || edited.EditedAt == nil || !slices.Equal(edited.Mentions, []string{"alice"}) {
+		t.Errorf("Expected the new body stamped as edited, got %+v", edited)
+	}
+
+	got, err := s.GetComment(as("dave", ""), c.Id)
+	if err != nil || got.Body != edited.Body {
+		t.Errorf("Expected a viewer to read the edited comment, got %+v %v", got, err)
+	}
+	if _, err := s.GetComment(as("carol", ""), c.Id); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a non-member, got %v", err)
+	}
+
+	if err := s.DeleteComment(as("dave", ""), c.Id); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for deleting someone else's comment, got %v", err)
+	}
+	// alice owns the list
+	if err := s.DeleteComment(alice, c.Id); err != nil {
+		t.Fatalf("Expected the list owner to delete the comment, got %v", err)
+	}
+	if _, err := s.GetComment(bob, c.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a deleted comment, got %v", err)
+	}
+}
+
+func TestListCommentsPages(t *testing.T) {
+	repo := newCommentRepo()
+	s := NewTodosService(repo)
+	alice := as("alice", "")
+	if _, err := s.Create(alice, &model.Todo{Title: "Taxes"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	for _, body := range []string{"one", "two", "three", "four", "five"} {
+		if _, err := s.CreateComment(alice, &model.Comment{TodoId: "Taxes", Body: body}); err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+	}
+
+	var bodies []string
+	opts := model.CommentOptions{TodoId: "Taxes", PageSize: 2}
+	for pages := 1; ; pages++ {
+		page, err := s.ListComments(alice, opts)
+		if err != nil {
+			t.Fatalf("ListComments failed: %v", err)
+		}
+		for _, c := range page.Comments {
+			bodies = append(bodies, c.Body)
+		}
+		if page.NextPageToken == "" {
+			if pages != 3 {
+				t.Errorf("Expected 3 pages, got %d", pages)
+			}
+			break
+		}
+		opts.PageToken = page.NextPageToken
+	}
+	if want := []string{"one", "two", "three", "four", "five"}; !slices.Equal(bodies, want) {
+		t.Errorf("Expected %v, got %v", want, bodies)
+	}
+
+	opts.PageToken = "bm9wZQ"
+	if _, err := s.ListComments(alice, opts); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("Expected ErrInvalidPageToken, got %v", err)
+	}
+	opts.PageToken = encodePageToken(time.Now(), "not-a-uuid")
+	if _, err := s.ListComments(alice, opts); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("Expected ErrInvalidPageToken for an id that is no UUID, got %v", err)
+	}
+}
+
+func TestCommentCounts(t *testing.T) {
+	repo := newCommentRepo()
+	s := NewTodosService(repo)
+	alice := as("alice", "")
+	for _, title := range []string{"Taxes", "Laundry", "Dentist"} {
+		if _, err := s.Create(alice, &model.Todo{Title: title}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	for _, todoID := range []string{"Taxes", "Taxes", "Dentist"} {
+		if _, err := s.CreateComment(alice, &model.Comment{TodoId: todoID, Body: "noted"}); err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+	}
+
+	todos, err := s.List(alice, model.ListOptions{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	counts := map[string]int32{}
+	for _, todo := range todos {
+		counts[todo.Id] = todo.CommentCount
+	}
+	if counts["Taxes"] != 2 || counts["Laundry"] != 0 || counts["Dentist"] != 1 {
+		t.Errorf("Expected 2, 0 and 1 comments, got %v", counts)
+	}
+	if repo.counted != 1 {
+		t.Errorf("Expected the counts of all todos in one query, got %d", repo.counted)
+	}
+
+	todo, err := s.Get(alice, "Taxes")
+	if err != nil || todo.CommentCount != 2 {
+		t.Errorf("Expected Get to count 2 comments, got %d %v", todo.CommentCount, err)
+	}
+}
+
+func TestCommentsUnsupported(t *testing.T) {
+	s := NewTodosService(newMemoryRepo())
+	if _, err := s.ListComments(context.Background(), model.CommentOptions{TodoId: "Milk"}); !errors.Is(err, ErrCommentsUnsupported) {
+		t.Errorf("Expected ErrCommentsUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+var (
+	ErrDependenciesUnsupported = errors.New("repository does not store dependencies")
+	ErrInvalidDependency       = errors.New("invalid dependency")
+	ErrDependencyCycle         = errors.New("dependency would create a cycle")
+	ErrBlocked                 = errors.New("todo is blocked by open todos")
+)
+
+// DependencyStore is implemented by repositories that store which todos
+// block which.
+type DependencyStore interface {
+	// AddDependency records that d.TodoId is blocked by d.BlockedById and
+	// bumps the version of d.TodoId. Adding a recorded dependency again
+	// returns it unchanged. It returns ErrNotFound unless both todos exist.
+	AddDependency(ctx context.Context, d *model.Dependency) (model.Dependency, error)
+	// RemoveDependency removes a dependency and bumps the version of its
+	// todo, or returns ErrNotFound.
+	RemoveDependency(ctx context.Context, todoID, blockedByID string) error
+	// DependsOn reports whether todoID is blocked by blockedByID, directly
+	// or through other todos.
+	DependsOn(ctx context.Context, todoID, blockedByID string) (bool, error)
+	// Dependencies returns the dependencies between todos of todoIDs.
+	Dependencies(ctx context.Context, todoIDs []string) ([]model.Dependency, error)
+	BlockedChecker
+}
+
+// BlockedChecker is the part of DependencyStore that reads of todos use,
+// which a wrapper such as a cache may serve on its own.
+type BlockedChecker interface {
+	// BlockedTodos returns those of todoIDs with a blocker that is neither
+	// completed nor deleted, in one query.
+	BlockedTodos(ctx context.Context, todoIDs []string) (map[string]bool, error)
+}
+
+func (s *Service) dependencies() (DependencyStore, error) {
+	store, ok := lookup[DependencyStore](s.repo)
+	if !ok {
+		return nil, ErrDependenciesUnsupported
+	}
+	return store, nil
+}
+
+// AddDependency records that the todo d.TodoId, which the caller must be
+// allowed to edit, is blocked by the todo d.BlockedById. Both todos must be
+// in the same list, or outside any, so the graph of a list is complete.
+func (s *Service) AddDependency(ctx context.Context, d *model.Dependency) (model.Dependency, error) {
+	if _, err := s.dependencies(); err != nil {
+		return model.Dependency{}, err
+	}
+	if d.TodoId == d.BlockedById {
+		return model.Dependency{}, fmt.Errorf("%w: a todo cannot block itself", ErrDependencyCycle)
+	}
+	todo, err := s.existingTodo(ctx, d.TodoId, model.RoleEditor)
+	if err != nil {
+		return model.Dependency{}, err
+	}
+	blocker, err := s.existingTodo(ctx, d.BlockedById, model.RoleViewer)
+	if err != nil {
+		return model.Dependency{}, err
+	}
+	if todo.ListId != blocker.ListId {
+		return model.Dependency{}, fmt.Errorf("%w: the todos are in different lists", ErrInvalidDependency)
+	}
+
+	// serializable, so that two dependencies added at once cannot close a
+	// cycle neither of them sees
+	var created model.Dependency
+	err = s.repo.WithinTx(ctx, TxOptions{Isolation: IsolationSerializable}, func(repo Repository) error {
+		store, ok := lookup[DependencyStore](repo)
+		if !ok {
+			return ErrDependenciesUnsupported
+		}
+		cycle, err := store.DependsOn(ctx, d.BlockedById, d.TodoId)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return fmt.Errorf("%w: %s already depends on %s", ErrDependencyCycle, d.BlockedById, d.TodoId)
+		}
+		created, err = store.AddDependency(ctx, d)
+		return err
+	})
+	if err != nil {
+		return model.Dependency{}, err
+	}
+	return created, nil
+}
+
+// RemoveDependency removes the dependency of the todo todoID, which the
+// caller must be allowed to edit, on the todo blockedByID.
+func (s *Service) RemoveDependency(ctx context.Context, todoID, blockedByID string) error {
+	if _, err := s.dependencies(); err != nil {
+		return err
+	}
+	if _, err := s.existingTodo(ctx, todoID, model.RoleEditor); err != nil {
+		return err
+	}
+	return s.repo.WithinTx(ctx, TxOptions{}, func(repo Repository) error {
+		store, ok := lookup[DependencyStore](repo)
+		if !ok {
+			return ErrDependenciesUnsupported
+		}
+		return store.RemoveDependency(ctx, todoID, blockedByID)
+	})
+}
+
+// GetDependencyGraph returns the todos of the list listID, or of every list
+// the caller can see when empty, that block or are blocked by others, and
+// the dependencies between them. A todoID limits the graph to the todos
+// connected to it.
+func (s *Service) GetDependencyGraph(ctx context.Context, listID, todoID string) (model.DependencyGraph, error) {
+	store, err := s.dependencies()
+	if err != nil {
+		return model.DependencyGraph{}, err
+	}
+	todos, err := s.List(ctx, model.ListOptions{ListId: listID})
+	if err != nil {
+		return model.DependencyGraph{}, err
+	}
+	edges, err := store.Dependencies(ctx, todoIDs(todos))
+	if err != nil {
+		return model.DependencyGraph{}, err
+	}
+
+	linked := map[string]bool{}
+	if todoID == "" {
+		for _, e := range edges {
+			linked[e.TodoId], linked[e.BlockedById] = true, true
+		}
+	} else {
+		if !slices.ContainsFunc(todos, func(t model.Todo) bool { return t.Id == todoID }) {
+			return model.DependencyGraph{}, ErrNotFound
+		}
+		// the todos reachable from todoID along edges in either direction
+		linked[todoID] = true
+		for grown := true; grown; {
+			grown = false
+			for _, e := range edges {
+				if linked[e.TodoId] != linked[e.BlockedById] {
+					linked[e.TodoId], linked[e.BlockedById] = true, true
+					grown = true
+				}
+			}
+		}
+		edges = slices.DeleteFunc(edges, func(e model.Dependency) bool { return !linked[e.TodoId] })
+	}
+
+	graph := model.DependencyGraph{Edges: edges}
+	for _, t := range todos {
+		if linked[t.Id] {
+			graph.Nodes = append(graph.Nodes, t)
+		}
+	}
+	return graph, nil
+}
+
+// ListNext returns the open todos of the list listID, or of every list the
+// caller can see when empty, in an order they can be done in: every todo
+// comes after its open blockers, and otherwise keeps its place in List.
+// With readyOnly, only the todos that are not blocked are returned.
+func (s *Service) ListNext(ctx context.Context, listID string, readyOnly bool) ([]model.Todo, error) {
+	store, err := s.dependencies()
+	if err != nil {
+		return nil, err
+	}
+	todos, err := s.List(ctx, model.ListOptions{ListId: listID})
+	if err != nil {
+		return nil, err
+	}
+	todos = slices.DeleteFunc(todos, func(t model.Todo) bool { return t.Completed })
+	if readyOnly {
+		return slices.DeleteFunc(todos, func(t model.Todo) bool { return t.Blocked }), nil
+	}
+	edges, err := store.Dependencies(ctx, todoIDs(todos))
+	if err != nil {
+		return nil, err
+	}
+
+	// Kahn's algorithm, taking the first ready todo in List order each time
+	index := make(map[string]int, len(todos))
+	for i, t := range todos {
+		index[t.Id] = i
+	}
+	blockers := make([]int, len(todos))
+	dependents := make([][]int, len(todos))
+	for _, e := range edges {
+		todo, blocker := index[e.TodoId], index[e.BlockedById]
+		blockers[todo]++
+		dependents[blocker] = append(dependents[blocker], todo)
+	}
+	var ready []int
+	for i := range todos {
+		if blockers[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	next := make([]model.Todo, 0, len(todos))
+	done := make([]bool, len(todos))
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		next = append(next, todos[i])
+		done[i] = true
+		for _, j := range dependents[i] {
+			if blockers[j]--; blockers[j] == 0 {
+				at, _ := slices.BinarySearch(ready, j)
+				ready = slices.Insert(ready, at, j)
+			}
+		}
+	}
+	// todos in a cycle, which AddDependency prevents, come last
+	for i, t := range todos {
+		if !done[i] {
+			next = append(next, t)
+		}
+	}
+	return next, nil
+}
+
+// checkCompletable returns ErrBlocked if t completes a todo that has open
+// blockers. Todos that are already completed can still be written. Run it
+// through withinCompletable, in the unit of work that writes t.
+func (s *Service) checkCompletable(ctx context.Context, repo Repository, t *model.Todo) error {
+	store, ok := lookup[DependencyStore](repo)
+	if !ok || !t.Completed || t.Id == "" {
+		return nil
+	}
+	blocked, err := store.BlockedTodos(ctx, []string{t.Id})
+	if err != nil || !blocked[t.Id] {
+		return err
+	}
+	current, err := repo.Get(WithPrimary(ctx), t.Id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if current.Completed {
+		return nil
+	}
+	return ErrBlocked
+}
+
+// withinCompletable runs fn, which checks t with checkCompletable and then
+// writes it, in a serializable transaction if t is completed and the
+// repository stores dependencies, so that a blocker added meanwhile, which
+// AddDependency also does serializably, cannot slip past the check.
+// Otherwise fn runs on the repository as is.
+func (s *Service) withinCompletable(ctx context.Context, t *model.Todo, fn func(Repository) error) error {
+	if _, ok := lookup[DependencyStore](s.repo); !ok || !t.Completed {
+		return fn(s.repo)
+	}
+	return s.repo.WithinTx(ctx, TxOptions{Isolation: IsolationSerializable}, fn)
+}
+
+// markBlocked sets the Blocked flag of todos with one query, if the
+// repository stores dependencies.
+func (s *Service) markBlocked(ctx context.Context, todos []model.Todo) error {
+	store, ok := lookup[BlockedChecker](s.repo)
+	if !ok {
+		return nil
+	}
+	ids := todoIDs(todos)
+	if len(ids) == 0 {
+		return nil
+	}
+	blocked, err := store.BlockedTodos(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range todos {
+		todos[i].Blocked = blocked[todos[i].Id]
+	}
+	return nil
+}
+
+// todoIDs returns the ids of todos, skipping the empty todos Get returns
+// for missing ones.
+func todoIDs(todos []model.Todo) []string {
+	ids := make([]string, 0, len(todos))
+	for _, t := range todos {
+		if t.Id != "" {
+			ids = append(ids, t.Id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// dependencyRepo adds dependencies to memberRepo.
+type dependencyRepo struct {
+	*memberRepo
+	dependencies []model.Dependency
+	opts         []TxOptions
+}
+
+func newDependencyRepo() *dependencyRepo {
+	return &dependencyRepo{memberRepo: newMemberRepo()}
+}
+
+func (m *dependencyRepo) WithinTx(_ context.Context, opts TxOptions, fn func(Repository) error) error {
+	m.opts = append(m.opts, opts)
+	return fn(m)
+}
+
+func (m *dependencyRepo) AddDependency(_ context.Context, d *model.Dependency) (model.Dependency, error) {
+	for _, id := range []string{d.TodoId, d.BlockedById} {
+		if t, ok := m.todos[id]; !ok || t.Deleted {
+			return model.Dependency{}, ErrNotFound
+		}
+	}
+	for _, existing := range m.dependencies {
+		if existing.TodoId == d.TodoId && existing.BlockedById == d.BlockedById {
+			return existing, nil
+		}
+	}
+	d.CreatedAt = time.Now()
+	m.dependencies = append(m.dependencies, *d)
+	return *d, nil
+}
+
+func (m *dependencyRepo) RemoveDependency(_ context.Context, todoID, blockedByID string) error {
+	i := slices.IndexFunc(m.dependencies, func(d model.Dependency) bool { return d.TodoId == todoID && d.BlockedById == blockedByID })
+	if i < 0 {
+		return ErrNotFound
+	}
+	m.dependencies = slices.Delete(m.dependencies, i, i+1)
+	return nil
+}
+
+func (m *dependencyRepo) DependsOn(_ context.Context, todoID, blockedByID string) (bool, error) {
+	seen := map[string]bool{}
+	pending := []string{todoID}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		for _, d := range m.dependencies {
+			if d.TodoId == id && !seen[d.BlockedById] {
+				if d.BlockedById == blockedByID {
+					return true, nil
+				}
+				seen[d.BlockedById] = true
+				pending = append(pending, d.BlockedById)
+			}
+		}
+	}
+	return false, nil
+}
+
+func (m *dependencyRepo) Dependencies(_ context.Context, todoIDs []string) ([]model.Dependency, error) {
+	var result []model.Dependency
+	for _, d := range m.dependencies {
+		if slices.Contains(todoIDs, d.TodoId) && slices.Contains(todoIDs, d.BlockedById) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (m *dependencyRepo) BlockedTodos(_ context.Context, todoIDs []string) (map[string]bool, error) {
+	blocked := map[string]bool{}
+	for _, d := range m.dependencies {
+		if blocker := m.todos[d.BlockedById]; slices.Contains(todoIDs, d.TodoId) && !blocker.Completed && !blocker.Deleted {
+			blocked[d.TodoId] = true
+		}
+	}
+	return blocked, nil
+}
+
+// dependOn records that the first todo of each pair is blocked by the
+// second.
+func dependOn(t *testing.T, s *Service, pairs ...string) {
+	t.Helper()
+	for i := 0; i < len(pairs); i += 2 {
+		if _, err := s.AddDependency(as("alice", ""), &model.Dependency{TodoId: pairs[i], BlockedById: pairs[i+1]}); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+}
+
+func createTodos(t *testing.T, s *Service, titles ...string) {
+	t.Helper()
+	for _, title := range titles {
+		if _, err := s.Create(as("alice", ""), &model.Todo{Title: title}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+}
+
+func TestDependencyCycles(t *testing.T) {
+	repo := newDependencyRepo()
+	s := NewTodosService(repo)
+	createTodos(t, s, "Deploy", "Test", "Build")
+	dependOn(t, s, "Deploy", "Test", "Test", "Build")
+
+	if _, err := s.AddDependency(as("alice", ""), &model.Dependency{TodoId: "Build", BlockedById: "Deploy"}); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Expected ErrDependencyCycle through Test, got %v", err)
+	}
+	if _, err := s.AddDependency(as("alice", ""), &model.Dependency{TodoId: "Build", BlockedById: "Build"}); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Expected ErrDependencyCycle for a todo blocking itself, got %v", err)
+	}
+	if _, err := s.AddDependency(as("alice", ""), &model.Dependency{TodoId: "Deploy", BlockedById: "Build"}); err != nil {
+		t.Errorf("Expected a redundant dependency to be allowed, got %v", err)
+	}
+	if !slices.Contains(repo.opts, TxOptions{Isolation: IsolationSerializable}) {
+		t.Errorf("Expected dependencies to be added in a serializable transaction, got %v", repo.opts)
+	}
+	if _, err := s.AddDependency(as("alice", ""), &model.Dependency{TodoId: "Deploy", BlockedById: "Release"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing blocker, got %v", err)
+	}
+}
+
+func TestDependenciesStayInTheirList(t *testing.T) {
+	repo := newDependencyRepo()
+	s := NewTodosService(repo)
+	listID := sharedList(t, s)
+	repo.roles[listID]["bob"] = model.RoleViewer
+	createTodos(t, s, "Taxes")
+
+	if _, err := s.AddDependency(as("alice", ""), &model.Dependency{TodoId: "Milk", BlockedById: "Taxes"}); !errors.Is(err, ErrInvalidDependency) {
+		t.Errorf("Expected ErrInvalidDependency across lists, got %v", err)
+	}
+	if _, err := s.Create(as("alice", ""), &model.Todo{Title: "Eggs", ListId: listID}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := s.AddDependency(as("bob", ""), &model.Dependency{TodoId: "Milk", BlockedById: "Eggs"}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a viewer, got %v", err)
+	}
+}
+
+func TestCompletingBlockedTodo(t *testing.T) {
+	repo := newDependencyRepo()
+	s := NewTodosService(repo)
+	createTodos(t, s, "Deploy", "Test")
+	dependOn(t, s, "Deploy", "Test")
+
+	deploy, err := s.Get(as("alice", ""), "Deploy")
+	if err != nil || !deploy.Blocked {
+		t.Fatalf("Expected Deploy to be blocked, got %+v %v", deploy, err)
+	}
+	repo.opts = nil
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Deploy", Title: "Deploy", Completed: true}); !errors.Is(err, ErrBlocked) {
+		t.Errorf("Expected ErrBlocked for completing a blocked todo, got %v", err)
+	}
+	if !slices.Equal(repo.opts, []TxOptions{{Isolation: IsolationSerializable}}) {
+		t.Errorf("Expected the check and the write in one serializable transaction, got %v", repo.opts)
+	}
+	deploy, _ = repo.Get(as("alice", ""), "Deploy")
+	results, err := s.Push(as("alice", ""), []*model.Mutation{
+		{Op: model.MutationOpUpdate, Id: "Deploy", Title: "Deploy", Completed: true, BaseVersion: deploy.Version},
+	})
+	if err != nil || results[0].Status != model.MutationStatusRejected {
+		t.Errorf("Expected pushing the completion of a blocked todo to be rejected, got %+v %v", results, err)
+	}
+	if len(repo.opts) != 2 || repo.opts[1] != (TxOptions{Isolation: IsolationSerializable}) {
+		t.Errorf("Expected the pushed completion in a serializable transaction, got %v", repo.opts)
+	}
+	if _, err := s.Patch(as("alice", ""), &model.Todo{Id: "Deploy", Completed: true}, []string{"completed"}); !errors.Is(err, ErrBlocked) {
+		t.Errorf("Expected ErrBlocked for patching a blocked todo, got %v", err)
+	}
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Deploy", Title: "Deploy to prod"}); err != nil {
+		t.Errorf("Expected other changes to a blocked todo to pass, got %v", err)
+	}
+
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Test", Title: "Test", Completed: true}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Deploy", Title: "Deploy", Completed: true}); err != nil {
+		t.Fatalf("Expected Deploy to be completable once Test is done, got %v", err)
+	}
+
+	// reopening the blocker leaves the completed todo editable
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Test", Title: "Test"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Deploy", Title: "Deployed", Completed: true}); err != nil {
+		t.Errorf("Expected a completed todo to stay editable, got %v", err)
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	repo := newDependencyRepo()
+	s := NewTodosService(repo)
+	createTodos(t, s, "Deploy", "Test", "Build", "Lunch", "Invite", "Book")
+	dependOn(t, s, "Deploy", "Test", "Test", "Build", "Invite", "Book")
+
+	graph, err := s.GetDependencyGraph(as("alice", ""), "", "")
+	if err != nil {
+		t.Fatalf("GetDependencyGraph failed: %v", err)
+	}
+	var nodes []string
+	for _, n := range graph.Nodes {
+		nodes = append(nodes, n.Id)
+	}
+	if want := []string{"Deploy", "Test", "Build", "Invite", "Book"}; !slices.Equal(nodes, want) || len(graph.Edges) != 3 {
+		t.Errorf("Expected nodes %v and 3 edges, got %v and %v", want, nodes, graph.Edges)
+	}
+	if !graph.Nodes[0].Blocked || graph.Nodes[2].Blocked {
+		t.Errorf("Expected Deploy to be blocked and Build not, got %+v", graph.Nodes)
+	}
+
+	graph, err = s.GetDependencyGraph(as("alice", ""), "", "Build")
+	if err != nil {
+		t.Fatalf("GetDependencyGraph failed: %v", err)
+	}
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Errorf("Expected the 3 todos connected to Build, got %v and %v", graph.Nodes, graph.Edges)
+	}
+	if _, err := s.GetDependencyGraph(as("alice", ""), "", "Release"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing todo, got %v", err)
+	}
+}
+
+func TestListNext(t *testing.T) {
+	repo := newDependencyRepo()
+	s := NewTodosService(repo)
+	createTodos(t, s, "Deploy", "Lunch", "Test", "Build", "Lint")
+	dependOn(t, s, "Deploy", "Test", "Test", "Build", "Test", "Lint")
+
+	ids := func(todos []model.Todo) []string {
+		var ids []string
+		for _, t := range todos {
+			ids = append(ids, t.Id)
+		}
+		return ids
+	}
+	next, err := s.ListNext(as("alice", ""), "", false)
+	if err != nil {
+		t.Fatalf("ListNext failed: %v", err)
+	}
+	if want := []string{"Lunch", "Build", "Lint", "Test", "Deploy"}; !slices.Equal(ids(next), want) {
+		t.Errorf("Expected %v, got %v", want, ids(next))
+	}
+
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Build", Title: "Build", Completed: true}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	ready, err := s.ListNext(as("alice", ""), "", true)
+	if err != nil {
+		t.Fatalf("ListNext failed: %v", err)
+	}
+	if want := []string{"Lunch", "Lint"}; !slices.Equal(ids(ready), want) {
+		t.Errorf("Expected %v ready, got %v", want, ids(ready))
+	}
+}
+
+func TestDependenciesUnsupported(t *testing.T) {
+	s := NewTodosService(newMemoryRepo())
+	if _, err := s.ListNext(as("alice", ""), "", false); !errors.Is(err, ErrDependenciesUnsupported) {
+		t.Errorf("Expected ErrDependenciesUnsupported, got %v", err)
+	}
+	createTodos(t, s, "Deploy")
+	if _, err := s.Update(as("alice", ""), &model.Todo{Id: "Deploy", Title: "Deploy", Completed: true}); err != nil {
+		t.Errorf("Expected todos to be completable without dependencies, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/haakaashs/todos-backend/internal/markdown"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// maxDescriptionBytes matches the description rule of CreateRequest.
+const maxDescriptionBytes = 16384
+
+var ErrInvalidDescription = errors.New("invalid description")
+
+// describe checks the description of t and derives its HTML, links and
+// checklist, which are stored with it so that reads do not render Markdown.
+func describe(t *model.Todo) error {
+	t.DescriptionHtml, t.Links, t.Checklist = "", nil, nil
+	if t.Description == "" {
+		return nil
+	}
+	if len(t.Description) > maxDescriptionBytes {
+		return fmt.Errorf("%w: description must be at most %d bytes", ErrInvalidDescription, maxDescriptionBytes)
+	}
+	r, err := markdown.Render(t.Description)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDescription, err)
+	}
+	t.DescriptionHtml, t.Links, t.Checklist = r.HTML, r.Links, r.Checklist
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+)
+
+var ErrQuotaExceeded = errors.New("todo quota exceeded")
+
+// QuotaCounter is implemented by repositories that record who created a todo.
+type QuotaCounter interface {
+	// CountCreatedBy returns the number of live todos created by subject.
+	CountCreatedBy(ctx context.Context, subject string) (int, error)
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithMaxTodosPerUser caps the number of live todos each authenticated
+// caller may have created. Zero, the default, means no cap.
+func WithMaxTodosPerUser(n int) Option {
+	return func(s *Service) {
+		s.maxTodosPerUser = n
+	}
+}
+
+// checkQuota fails with ErrQuotaExceeded if the caller may not create n more
+// todos. Anonymous callers and repositories without a QuotaCounter are not
+// limited. Run it through withinQuota, so that concurrent creates cannot
+// overshoot the cap.
+func (s *Service) checkQuota(ctx context.Context, repo Repository, n int) error {
+	if s.maxTodosPerUser <= 0 {
+		return nil
+	}
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	counter, ok := lookup[QuotaCounter](repo)
+	if !ok {
+		return nil
+	}
+
+	count, err := counter.CountCreatedBy(ctx, p.Subject())
+	if err != nil {
+		return err
+	}
+	if count+n > s.maxTodosPerUser {
+		return fmt.Errorf("%w: limit is %d todos", ErrQuotaExceeded, s.maxTodosPerUser)
+	}
+	return nil
+}
+
+// withinQuota runs fn, which counts and then creates todos, in a
+// serializable transaction if a quota applies, so that of two creates
+// racing for the last free slot one is retried and sees the other.
+// Otherwise fn runs on the repository as is.
+func (s *Service) withinQuota(ctx context.Context, limited bool, fn func(Repository) error) error {
+	if _, ok := auth.FromContext(ctx); !ok || !limited || s.maxTodosPerUser <= 0 {
+		return s.withinRanks(ctx, TxOptions{}, fn)
+	}
+	return s.withinRanks(ctx, TxOptions{Isolation: IsolationSerializable}, fn)
+}
+
+// creator returns the subject recorded as the creator of new todos.
+func creator(ctx context.Context) string {
+	p, _ := auth.FromContext(ctx)
+	return p.Subject()
+}
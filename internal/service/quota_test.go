@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestCreateEnforcesQuotaPerUser(t *testing.T) {
+	alice := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "bob"})
+	s := NewTodosService(newMemoryRepo(), WithMaxTodosPerUser(2))
+
+	for _, title := range []string{"one", "two"} {
+		if _, err := s.Create(alice, &model.Todo{Title: title}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if _, err := s.Create(alice, &model.Todo{Title: "three"}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := s.Create(bob, &model.Todo{Title: "bob's"}); err != nil {
+		t.Errorf("Expected another user to be unaffected, got %v", err)
+	}
+	if _, err := s.Create(context.Background(), &model.Todo{Title: "anonymous"}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected anonymous callers to be refused, got %v", err)
+	}
+
+	if err := s.Delete(alice, "one"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := s.Create(alice, &model.Todo{Title: "three"}); err != nil {
+		t.Errorf("Expected deleting a todo to free quota, got %v", err)
+	}
+}
+
+func TestImportAndPushEnforceQuota(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{APIKeyID: "k1"})
+	s := NewTodosService(newMemoryRepo(), WithMaxTodosPerUser(2))
+
+	_, err := s.Import(ctx, model.ImportOptions{Format: model.DataFormatMarkdown}, strings.NewReader("- [ ] a\n- [ ] b\n- [ ] c\n"))
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded for import, got %v", err)
+	}
+
+	results, err := s.Push(ctx, []*model.Mutation{
+		{Op: model.MutationOpCreate, Id: "p1", Title: "first"},
+		{Op: model.MutationOpCreate, Id: "p2", Title: "second"},
+		{Op: model.MutationOpCreate, Id: "p3", Title: "third"},
+	})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if results[1].Status != model.MutationStatusApplied {
+		t.Errorf("Expected second create to be applied, got %v", results[1].Status)
+	}
+	if results[2].Status != model.MutationStatusRejected {
+		t.Errorf("Expected third create to be rejected, got %v", results[2].Status)
+	}
+}
+
+// retryingRepo runs every unit of work twice, throwing away the first run
+// like a serialization failure would, and records the options used.
+type retryingRepo struct {
+	*memoryRepo
+	opts []TxOptions
+}
+
+func (r *retryingRepo) WithinTx(ctx context.Context, opts TxOptions, fn func(Repository) error) error {
+	r.opts = append(r.opts, opts)
+	discarded := newMemoryRepo()
+	for id, t := range r.todos {
+		discarded.todos[id] = t
+	}
+	if err := fn(discarded); err != nil {
+		return err
+	}
+	return fn(r.memoryRepo)
+}
+
+func TestQuotaCheckedInSerializableTransaction(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "alice"})
+	repo := &retryingRepo{memoryRepo: newMemoryRepo()}
+	s := NewTodosService(repo, WithMaxTodosPerUser(1))
+
+	todo, err := s.Create(ctx, &model.Todo{Title: "only one"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if len(repo.opts) != 1 || repo.opts[0].Isolation != IsolationSerializable {
+		t.Errorf("Expected one serializable transaction, got %v", repo.opts)
+	}
+	if len(repo.todos) != 1 || todo.SeriesId != todo.Id || todo.Occurrence != 1 {
+		t.Errorf("Expected the retry to start over from the todo passed in, got %+v", todo)
+	}
+
+	if _, err := s.Create(ctx, &model.Todo{Title: "second"}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := s.Create(context.Background(), &model.Todo{Title: "anonymous"}); !errors.Is(err, ErrUnauthenticated) || len(repo.opts) != 2 {
+		t.Errorf("Expected anonymous creates to be refused before any transaction, got %v %v", repo.opts, err)
+	}
+}
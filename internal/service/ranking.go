@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/rank"
+)
+
+// maxPositionLength is the position length above which ranks are rebalanced
+// in the background.
+const maxPositionLength = 12
+
+const rebalanceTimeout = time.Minute
+
+var ErrInvalidMove = errors.New("todo cannot be moved relative to itself")
+
+// RankLocker is implemented by repositories shared by several processes. The
+// lock keeps Rebalance, which takes it exclusively, from rewriting positions
+// between a position being computed and written.
+type RankLocker interface {
+	// LockRanks holds the positions of the tenant shared until the unit of
+	// work it is called in ends.
+	LockRanks(ctx context.Context) error
+}
+
+// Move places a todo directly before or after a sibling, rewriting only the
+// moved todo.
+func (s *Service) Move(ctx context.Context, req *model.MoveRequest) (model.Todo, error) {
+	anchorID, after := req.BeforeId, false
+	if req.AfterId != "" {
+		anchorID, after = req.AfterId, true
+	}
+	if anchorID == req.Id {
+		return model.Todo{}, ErrInvalidMove
+	}
+	if err := s.authorizeTodo(ctx, req.Id, model.RoleEditor); err != nil {
+		return model.Todo{}, err
+	}
+
+	var todo model.Todo
+	move := func(repo Repository) error {
+		position, err := s.positionNextTo(ctx, repo, anchorID, after)
+		if err != nil {
+			return err
+		}
+		todo, err = repo.Move(ctx, req.Id, position)
+		return err
+	}
+	err := s.withinRanks(ctx, TxOptions{}, move)
+	if errors.Is(err, rank.ErrInvalidRange) {
+		// the anchor shares its position with a neighbour, which happens
+		// when todos are created concurrently; spread them out and retry
+		if err := s.repo.Rebalance(ctx); err != nil {
+			return model.Todo{}, err
+		}
+		err = s.withinRanks(ctx, TxOptions{}, move)
+	}
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	s.rebalanceIfLong(todo.Position)
+	return todo, nil
+}
+
+func (s *Service) positionNextTo(ctx context.Context, repo Repository, anchorID string, after bool) (string, error) {
+	anchor, err := repo.Get(WithPrimary(ctx), anchorID)
+	if err != nil {
+		return "", err
+	}
+	if anchor.Id == "" {
+		return "", ErrNotFound
+	}
+
+	adjacent, err := repo.AdjacentPosition(ctx, anchor.Position, anchor.Id, after)
+	if err != nil {
+		return "", err
+	}
+	if after {
+		return rank.Between(anchor.Position, adjacent)
+	}
+	return rank.Between(adjacent, anchor.Position)
+}
+
+// topPosition returns a position above every existing todo, so new todos
+// show up first like they do when listing by creation time.
+func (s *Service) topPosition(ctx context.Context, repo Repository) (string, error) {
+	first, err := repo.AdjacentPosition(ctx, "", "", true)
+	if err != nil {
+		return "", err
+	}
+	return rank.Before(first), nil
+}
+
+// withinRanks runs fn in a unit of work holding the ranks shared, so that
+// the positions fn computes are still in place when it writes them. A
+// repository without a RankLocker runs fn outside any unit of work unless
+// opts ask for one.
+func (s *Service) withinRanks(ctx context.Context, opts TxOptions, fn func(Repository) error) error {
+	if _, ok := lookup[RankLocker](s.repo); !ok && opts == (TxOptions{}) {
+		return fn(s.repo)
+	}
+	return s.repo.WithinTx(ctx, opts, func(repo Repository) error {
+		if locker, ok := lookup[RankLocker](repo); ok {
+			if err := locker.LockRanks(ctx); err != nil {
+				return err
+			}
+		}
+		return fn(repo)
+	})
+}
+
+// rebalanceIfLong starts a background rebalance once positions grow past
+// maxPositionLength. At most one rebalance runs at a time in a process, and
+// the ranks lock of the repository serializes those of several.
+func (s *Service) rebalanceIfLong(position string) {
+	if len(position) <= maxPositionLength || !s.rebalancing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer s.rebalancing.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), rebalanceTimeout)
+		defer cancel()
+
+		if err := s.repo.Rebalance(ctx); err != nil {
+			log.Default().Println("service: failed to rebalance positions:", err)
+		}
+	}()
+}
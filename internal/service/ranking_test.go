@@ -0,0 +1,69 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestMoveReordersSingleTodo(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newMemoryRepo()
+	s := NewTodosService(repo)
+
+	for _, title := range []string{"c", "b", "a"} {
+		if _, err := s.Create(ctx, &model.Todo{Title: title}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	list := func() []string {
+		todos, _ := s.List(ctx, model.ListOptions{Order: model.ListOrderPosition})
+		return titles(todos)
+	}
+	if got := list(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("Expected newest first, got %v", got)
+	}
+
+	before := repo.version
+	if _, err := s.Move(ctx, &model.MoveRequest{Id: "a", AfterId: "b"}); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if repo.version != before+1 {
+		t.Errorf("Expected a single write, got %d", repo.version-before)
+	}
+	if got := list(); !reflect.DeepEqual(got, []string{"b", "a", "c"}) {
+		t.Errorf("Expected [b a c], got %v", got)
+	}
+
+	if _, err := s.Move(ctx, &model.MoveRequest{Id: "c", BeforeId: "b"}); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if got := list(); !reflect.DeepEqual(got, []string{"c", "b", "a"}) {
+		t.Errorf("Expected [c b a], got %v", got)
+	}
+
+	if _, err := s.Move(ctx, &model.MoveRequest{Id: "c", BeforeId: "c"}); err != ErrInvalidMove {
+		t.Errorf("Expected ErrInvalidMove, got %v", err)
+	}
+}
+
+func TestMoveRebalancesTiedPositions(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newMemoryRepo()
+	s := NewTodosService(repo)
+
+	// concurrent creates can hand out the same position
+	repo.Create(ctx, &model.Todo{Title: "a", CreatedBy: "user:alice", Position: "V"})
+	repo.Create(ctx, &model.Todo{Title: "b", CreatedBy: "user:alice", Position: "V"})
+	repo.Create(ctx, &model.Todo{Title: "c", CreatedBy: "user:alice", Position: "W"})
+
+	if _, err := s.Move(ctx, &model.MoveRequest{Id: "c", BeforeId: "b"}); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	todos, _ := s.List(ctx, model.ListOptions{Order: model.ListOrderPosition})
+	if got := titles(todos); !reflect.DeepEqual(got, []string{"a", "c", "b"}) {
+		t.Errorf("Expected [a c b], got %v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/recurrence"
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+func validateRecurrence(t *model.Todo) error {
+	if t.Rrule == "" {
+		return nil
+	}
+	if t.DueAt == nil {
+		return fmt.Errorf("%w: a recurring todo needs a due date", ErrInvalidRecurrence)
+	}
+	if _, err := recurrence.Parse(t.Rrule, t.Timezone); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return nil
+}
+
+// scheduleNext creates the next occurrence of a completed recurring todo.
+// It is safe to call more than once for the same completion: the repository
+// refuses to create an occurrence of a series twice.
+func (s *Service) scheduleNext(ctx context.Context, done model.Todo) error {
+	if !done.Completed || done.Rrule == "" || done.DueAt == nil {
+		return nil
+	}
+
+	rule, err := recurrence.Parse(done.Rrule, done.Timezone)
+	if err != nil {
+		// stored before validation existed; leave the series alone
+		return nil
+	}
+
+	start := *done.DueAt
+	if done.RecurrenceMode == model.RecurrenceModeAfterCompletion {
+		start = recurrence.AtTimeOfDay(s.now(), *done.DueAt, rule.Location())
+	}
+
+	next, ok := rule.Next(start, start, int(done.Occurrence))
+	if !ok {
+		return nil
+	}
+	next = next.UTC()
+
+	_, err = s.Create(ctx, &model.Todo{
+		Title:          done.Title,
+		Priority:       done.Priority,
+		DueAt:          &next,
+		Rrule:          done.Rrule,
+		Timezone:       done.Timezone,
+		RecurrenceMode: done.RecurrenceMode,
+		SeriesId:       done.SeriesId,
+		Occurrence:     done.Occurrence + 1,
+		ListId:         done.ListId,
+		Description:    done.Description,
+	})
+	if errors.Is(err, ErrAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+// PreviewOccurrences returns the first dates a rule produces for a series
+// starting at the given due date.
+func (s *Service) PreviewOccurrences(ctx context.Context, req *model.PreviewOccurrencesRequest) ([]time.Time, error) {
+	if req.StartAt.IsZero() {
+		return nil, fmt.Errorf("%w: start_at is required", ErrInvalidRecurrence)
+	}
+
+	rule, err := recurrence.Parse(req.Rrule, req.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return rule.Preview(req.StartAt, int(req.Count)), nil
+}
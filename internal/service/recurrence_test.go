@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestCompletingRecurringTodoSchedulesNextOccurrence(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newMemoryRepo()
+	s := NewTodosService(repo)
+
+	due := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) // a Monday
+	todo, err := s.Create(ctx, &model.Todo{Title: "standup", DueAt: &due, Rrule: "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=2"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	todo.Completed = true
+	for range 2 {
+		// completing twice must not create two successors
+		if _, err := s.Update(ctx, &todo); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+
+	todos, _ := s.List(ctx, model.ListOptions{})
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+	next := todos[1]
+	if next.Completed || next.Occurrence != 2 || !next.DueAt.Equal(due.AddDate(0, 0, 3)) {
+		t.Errorf("Expected open occurrence 2 due on Thursday, got %+v (due %v)", next, next.DueAt)
+	}
+
+	// COUNT=2 ends the series
+	next.Completed = true
+	s.Update(ctx, &next)
+	if todos, _ := s.List(ctx, model.ListOptions{}); len(todos) != 2 {
+		t.Errorf("Expected the series to end, got %d todos", len(todos))
+	}
+}
+
+func TestAfterCompletionModeSchedulesFromCompletionDate(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newMemoryRepo()
+	s := NewTodosService(repo)
+	s.now = func() time.Time { return time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC) }
+
+	due := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	todo, _ := s.Create(ctx, &model.Todo{
+		Title:          "water plants",
+		DueAt:          &due,
+		Rrule:          "FREQ=DAILY;INTERVAL=3",
+		Timezone:       "America/New_York",
+		RecurrenceMode: model.RecurrenceModeAfterCompletion,
+	})
+
+	todo.Completed = true
+	s.Update(ctx, &todo)
+
+	todos, _ := s.List(ctx, model.ListOptions{})
+	// 08:00 UTC is 03:00 in New York; completed on March 10th local time,
+	// three days later is after the DST switch on March 8th
+	want := time.Date(2026, 3, 13, 3, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	if len(todos) != 2 || !todos[1].DueAt.Equal(want) {
+		t.Errorf("Expected next occurrence at %v, got %+v", want, todos)
+	}
+}
+
+func TestRecurrenceIsValidated(t *testing.T) {
+	s := NewTodosService(newMemoryRepo())
+
+	_, err := s.Create(as("alice", ""), &model.Todo{Title: "no due date", Rrule: "FREQ=DAILY"})
+	if !errors.Is(err, ErrInvalidRecurrence) {
+		t.Errorf("Expected ErrInvalidRecurrence, got %v", err)
+	}
+
+	due := time.Now()
+	_, err = s.Create(as("alice", ""), &model.Todo{Title: "bad rule", DueAt: &due, Rrule: "FREQ=FORTNIGHTLY"})
+	if !errors.Is(err, ErrInvalidRecurrence) {
+		t.Errorf("Expected ErrInvalidRecurrence, got %v", err)
+	}
+}
@@ -3,49 +3,264 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/haakaashs/todos-backend/internal/model"
 )
 
-var ErrNotFound = errors.New("todo not found")
+var (
+	ErrNotFound        = errors.New("todo not found")
+	ErrVersionMismatch = errors.New("todo version mismatch")
+	ErrAlreadyExists   = errors.New("todo already exists")
+	ErrInvalidMask     = errors.New("invalid update mask")
+)
 
 type Repository interface {
-	Create(context.Context, string) (model.Todo, error)
+	Create(context.Context, *model.Todo) (model.Todo, error)
+	// CreateBatch creates all todos atomically.
+	CreateBatch(context.Context, []*model.Todo) ([]model.Todo, error)
 	Get(context.Context, string) (model.Todo, error)
 	Update(context.Context, *model.Todo) (model.Todo, error)
 	Delete(context.Context, string) error
-	List(context.Context) ([]model.Todo, error)
+	List(context.Context, model.ListOptions) ([]model.Todo, error)
+
+	// Move stores a new manual position for a todo.
+	Move(ctx context.Context, id, position string) (model.Todo, error)
+	// AdjacentPosition returns the closest position at or after (or before)
+	// the given one held by a todo other than excludeID, or an empty string
+	// when there is none. A result equal to position reveals a tie.
+	AdjacentPosition(ctx context.Context, position, excludeID string, after bool) (string, error)
+	// Rebalance rewrites all positions to short, evenly spaced ranks while
+	// keeping the current order. A RankLocker holds the ranks exclusively
+	// meanwhile.
+	Rebalance(context.Context) error
+
+	// WithinTx runs fn with a Repository whose methods share a single
+	// transaction, committed if fn returns nil and rolled back otherwise.
+	// If the transaction fails to serialize it is retried, running fn
+	// again, so fn must not have effects outside the repository. Calls
+	// made inside fn join its transaction.
+	WithinTx(ctx context.Context, opts TxOptions, fn func(Repository) error) error
+}
+
+// Wrapper is implemented by a Repository that decorates another, such as a
+// cache. The optional stores a wrapper does not implement itself are looked
+// up on the repository it wraps.
+type Wrapper interface {
+	Unwrap() Repository
+}
+
+// Lookup returns repo, or the first repository it wraps, as a T, for
+// wrappers that pass a store on to the repository they wrap.
+func Lookup[T any](repo Repository) (T, bool) {
+	return lookup[T](repo)
+}
+
+// lookup returns repo, or the first repository it wraps, as a T.
+func lookup[T any](repo Repository) (T, bool) {
+	for {
+		if t, ok := repo.(T); ok {
+			return t, true
+		}
+		w, ok := repo.(Wrapper)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		repo = w.Unwrap()
+	}
 }
 
 type Service struct {
 	repo Repository
+
+	rebalancing atomic.Bool
+
+	maxTodosPerUser int
+
+	blobs            BlobStore
+	attachmentLimits AttachmentLimits
+
+	now func() time.Time
 }
 
-func NewTodosService(repo Repository) *Service {
-	return &Service{repo: repo}
+func NewTodosService(repo Repository, opts ...Option) *Service {
+	s := &Service{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *Service) Create(ctx context.Context, title string) (model.Todo, error) {
-	// business logic here
-	return s.repo.Create(ctx, title)
+func (s *Service) Create(ctx context.Context, t *model.Todo) (model.Todo, error) {
+	if err := validateRecurrence(t); err != nil {
+		return model.Todo{}, err
+	}
+	if err := describe(t); err != nil {
+		return model.Todo{}, err
+	}
+	if err := s.authorize(ctx, t.ListId, model.RoleEditor); err != nil {
+		return model.Todo{}, err
+	}
+	t.CreatedBy = creator(ctx)
+
+	var todo model.Todo
+	// the next occurrence of a series replaces the completed one
+	err := s.withinQuota(ctx, t.SeriesId == "", func(repo Repository) error {
+		if t.SeriesId == "" {
+			if err := s.checkQuota(ctx, repo, 1); err != nil {
+				return err
+			}
+		}
+		// a retry starts over from the todo as it was passed in
+		attempt := *t
+		position, err := s.topPosition(ctx, repo)
+		if err != nil {
+			return err
+		}
+		attempt.Position = position
+		todo, err = repo.Create(ctx, &attempt)
+		return err
+	})
+	if err != nil {
+		return model.Todo{}, err
+	}
+	*t = todo
+	s.rebalanceIfLong(todo.Position)
+	return todo, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (model.Todo, error) {
-	// business logic here
-	return s.repo.Get(ctx, id)
+	todo, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if err := s.authorizeOn(ctx, todo, model.RoleViewer); err != nil {
+		return model.Todo{}, err
+	}
+	todos := []model.Todo{todo}
+	if err := s.annotate(ctx, todos); err != nil {
+		return model.Todo{}, err
+	}
+	return todos[0], nil
 }
 
-func (s *Service) List(ctx context.Context) ([]model.Todo, error) {
-	// business logic here
-	return s.repo.List(ctx)
+// List returns the todos the caller created outside any list and those of
+// the lists they are a member of, or only those of opts.ListId.
+func (s *Service) List(ctx context.Context, opts model.ListOptions) ([]model.Todo, error) {
+	opts, err := s.scopeList(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	todos, err := s.repo.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.annotate(ctx, todos); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// annotate fills in the fields of todos that reads compute rather than
+// store.
+func (s *Service) annotate(ctx context.Context, todos []model.Todo) error {
+	if err := s.countComments(ctx, todos); err != nil {
+		return err
+	}
+	return s.markBlocked(ctx, todos)
+}
+
+// Watermark returns the latest change to any todo, or a zero Watermark if
+// the repository does not record changes. Its Scope changes when the caller
+// joins or leaves a list.
+func (s *Service) Watermark(ctx context.Context) (model.Watermark, error) {
+	rec, ok := lookup[ChangeRecorder](s.repo)
+	if !ok {
+		return model.Watermark{}, nil
+	}
+	w, err := rec.LatestChange(ctx)
+	if err != nil {
+		return model.Watermark{}, err
+	}
+	lists, err := s.visibleLists(ctx)
+	if err != nil {
+		return model.Watermark{}, err
+	}
+	w.Scope = listScope(lists)
+	return w, nil
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
-	// business logic here
+	if err := s.authorizeTodo(ctx, id, model.RoleEditor); err != nil {
+		return err
+	}
 	return s.repo.Delete(ctx, id)
 }
 
 func (s *Service) Update(ctx context.Context, t *model.Todo) (model.Todo, error) {
-	// business logic here
-	return s.repo.Update(ctx, t)
+	if err := validateRecurrence(t); err != nil {
+		return model.Todo{}, err
+	}
+	if err := describe(t); err != nil {
+		return model.Todo{}, err
+	}
+	if err := s.authorizeTodo(ctx, t.Id, model.RoleEditor); err != nil {
+		return model.Todo{}, err
+	}
+	var todo model.Todo
+	err := s.withinCompletable(ctx, t, func(repo Repository) error {
+		if err := s.checkCompletable(ctx, repo, t); err != nil {
+			return err
+		}
+		var err error
+		todo, err = repo.Update(ctx, t)
+		return err
+	})
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := s.scheduleNext(ctx, todo); err != nil {
+		return model.Todo{}, err
+	}
+	return todo, nil
+}
+
+// Patch updates only the fields of the todo named in paths, which use the
+// proto field names, and keeps the stored value of every other field.
+func (s *Service) Patch(ctx context.Context, t *model.Todo, paths []string) (model.Todo, error) {
+	current, err := s.Get(WithPrimary(ctx), t.Id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if current.Id == "" {
+		return model.Todo{}, ErrNotFound
+	}
+
+	for _, path := range paths {
+		switch path {
+		case "title":
+			current.Title = t.Title
+		case "completed":
+			current.Completed = t.Completed
+		case "priority":
+			current.Priority = t.Priority
+		case "due_at":
+			current.DueAt = t.DueAt
+		case "rrule":
+			current.Rrule = t.Rrule
+		case "timezone":
+			current.Timezone = t.Timezone
+		case "recurrence_mode":
+			current.RecurrenceMode = t.RecurrenceMode
+		case "description":
+			current.Description = t.Description
+		default:
+			return model.Todo{}, fmt.Errorf("%w: unknown field %q", ErrInvalidMask, path)
+		}
+	}
+	return s.Update(ctx, &current)
 }
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/rank"
+)
+
+// memoryRepo is an in-memory Repository and ChangeRecorder used by the tests.
+type memoryRepo struct {
+	version int64
+	todos   map[string]model.Todo
+	order   []string
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{todos: map[string]model.Todo{}}
+}
+
+func (m *memoryRepo) write(t model.Todo) model.Todo {
+	m.version++
+	now := time.Now()
+	t.Version, t.UpdatedAt = m.version, &now
+	if _, ok := m.todos[t.Id]; !ok {
+		m.order = append(m.order, t.Id)
+	}
+	m.todos[t.Id] = t
+	return t
+}
+
+// Create uses the title as id unless one is set, to keep tests readable.
+func (m *memoryRepo) Create(_ context.Context, t *model.Todo) (model.Todo, error) {
+	if t.Id == "" {
+		t.Id = t.Title
+	}
+	if t.SeriesId == "" {
+		t.SeriesId, t.Occurrence = t.Id, 1
+	}
+	for _, existing := range m.todos {
+		if existing.SeriesId == t.SeriesId && existing.Occurrence == t.Occurrence {
+			return model.Todo{}, ErrAlreadyExists
+		}
+	}
+	if _, ok := m.todos[t.Id]; ok {
+		// later occurrences reuse the title, keep ids unique
+		t.Id = fmt.Sprintf("%s#%d", t.Id, t.Occurrence)
+	}
+	return m.write(*t), nil
+}
+
+func (m *memoryRepo) CreateBatch(ctx context.Context, todos []*model.Todo) ([]model.Todo, error) {
+	var result []model.Todo
+	for _, t := range todos {
+		created, err := m.Create(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, created)
+	}
+	return result, nil
+}
+
+func (m *memoryRepo) Get(_ context.Context, id string) (model.Todo, error) {
+	t, ok := m.todos[id]
+	if !ok || t.Deleted {
+		return model.Todo{}, ErrNotFound
+	}
+	return t, nil
+}
+
+func (m *memoryRepo) Update(_ context.Context, t *model.Todo) (model.Todo, error) {
+	current, ok := m.todos[t.Id]
+	if !ok {
+		return model.Todo{}, ErrNotFound
+	}
+	updated := *t
+	updated.Position, updated.SeriesId, updated.Occurrence = current.Position, current.SeriesId, current.Occurrence
+	updated.ListId, updated.CreatedBy = current.ListId, current.CreatedBy
+	return m.write(updated), nil
+}
+
+func (m *memoryRepo) Delete(_ context.Context, id string) error {
+	t, ok := m.todos[id]
+	if !ok {
+		return ErrNotFound
+	}
+	t.Deleted = true
+	m.write(t)
+	return nil
+}
+
+func (m *memoryRepo) List(_ context.Context, opts model.ListOptions) ([]model.Todo, error) {
+	var result []model.Todo
+	for _, id := range m.order {
+		t := m.todos[id]
+		if t.Deleted {
+			continue
+		}
+		if opts.ListId != "" && t.ListId != opts.ListId ||
+			opts.ListId == "" && t.ListId != "" && !slices.Contains(opts.VisibleLists, t.ListId) ||
+			opts.ListId == "" && t.ListId == "" && opts.CreatedBy != "" && t.CreatedBy != opts.CreatedBy {
+			continue
+		}
+		result = append(result, t)
+	}
+	if opts.Order == model.ListOrderPosition {
+		sort.SliceStable(result, func(i, j int) bool { return result[i].Position < result[j].Position })
+	}
+	return result, nil
+}
+
+func (m *memoryRepo) Move(_ context.Context, id, position string) (model.Todo, error) {
+	t, ok := m.todos[id]
+	if !ok || t.Deleted {
+		return model.Todo{}, ErrNotFound
+	}
+	t.Position = position
+	return m.write(t), nil
+}
+
+func (m *memoryRepo) AdjacentPosition(_ context.Context, position, excludeID string, after bool) (string, error) {
+	adjacent, found := "", false
+	for _, t := range m.todos {
+		if t.Deleted || t.Id == excludeID {
+			continue
+		}
+		if after && t.Position >= position && (!found || t.Position < adjacent) {
+			adjacent, found = t.Position, true
+		}
+		if !after && t.Position <= position && (!found || t.Position > adjacent) {
+			adjacent, found = t.Position, true
+		}
+	}
+	return adjacent, nil
+}
+
+func (m *memoryRepo) Rebalance(ctx context.Context) error {
+	todos, _ := m.List(ctx, model.ListOptions{Order: model.ListOrderPosition})
+	for i, position := range rank.Spread(len(todos)) {
+		todos[i].Position = position
+		m.write(todos[i])
+	}
+	return nil
+}
+
+// WithinTx runs fn on the repository itself; there is nothing to roll back
+// in the tests.
+func (m *memoryRepo) WithinTx(_ context.Context, _ TxOptions, fn func(Repository) error) error {
+	return fn(m)
+}
+
+// Changes treats every write as a transaction of its own, numbered by its
+// version.
+func (m *memoryRepo) Changes(_ context.Context, since model.ChangeCursor, limit int) ([]model.Todo, error) {
+	var result []model.Todo
+	for v := since.Version + 1; v <= m.version && len(result) < limit; v++ {
+		for _, t := range m.todos {
+			if t.Version == v {
+				t.Txid = v
+				result = append(result, t)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryRepo) Lookup(_ context.Context, id string) (model.Todo, error) {
+	t, ok := m.todos[id]
+	if !ok {
+		return model.Todo{}, ErrNotFound
+	}
+	return t, nil
+}
+
+func (m *memoryRepo) LatestChange(context.Context) (model.Watermark, error) {
+	w := model.Watermark{Version: m.version}
+	for _, t := range m.todos {
+		if t.UpdatedAt != nil && t.UpdatedAt.After(w.UpdatedAt) {
+			w.UpdatedAt = *t.UpdatedAt
+		}
+	}
+	return w, nil
+}
+
+func (m *memoryRepo) Put(_ context.Context, t *model.Todo, expected int64) (model.Todo, error) {
+	current, ok := m.todos[t.Id]
+	switch {
+	case expected == 0 && ok:
+		return model.Todo{}, ErrVersionMismatch
+	case expected != 0 && !ok:
+		return model.Todo{}, ErrNotFound
+	case expected != 0 && current.Version != expected:
+		return model.Todo{}, ErrVersionMismatch
+	}
+	if ok {
+		t.ListId, t.CreatedBy = current.ListId, current.CreatedBy
+	}
+	return m.write(*t), nil
+}
+
+func (m *memoryRepo) CountCreatedBy(_ context.Context, subject string) (int, error) {
+	count := 0
+	for _, t := range m.todos {
+		if !t.Deleted && t.CreatedBy == subject {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func titles(todos []model.Todo) []string {
+	var result []string
+	for _, t := range todos {
+		result = append(result, t.Title)
+	}
+	return result
+}
+
+func TestPatchKeepsUnmaskedFields(t *testing.T) {
+	ctx := as("alice", "")
+	s := NewTodosService(newMemoryRepo())
+
+	created, err := s.Create(ctx, &model.Todo{Title: "Water plants", Priority: model.PriorityHigh})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	patched, err := s.Patch(ctx, &model.Todo{Id: created.Id, Completed: true}, []string{"completed"})
+	if err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	if !patched.Completed || patched.Title != "Water plants" || patched.Priority != model.PriorityHigh {
+		t.Errorf("Expected only completed to change, got %+v", patched)
+	}
+
+	if _, err := s.Patch(ctx, &model.Todo{Id: created.Id}, []string{"position"}); !errors.Is(err, ErrInvalidMask) {
+		t.Errorf("Expected ErrInvalidMask, got %v", err)
+	}
+	if _, err := s.Patch(ctx, &model.Todo{Id: "missing"}, []string{"title"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDescriptionIsRenderedOnWrite(t *testing.T) {
+	ctx := as("alice", "")
+	s := NewTodosService(newMemoryRepo())
+
+	created, err := s.Create(ctx, &model.Todo{Title: "Release", Description: "- [ ] Tag it, see [notes](https://example.com/notes)"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !strings.Contains(created.DescriptionHtml, `type="checkbox"`) || len(created.Links) != 1 || len(created.Checklist) != 1 {
+		t.Errorf("Expected the description to be rendered, got %+v", created)
+	}
+
+	// a client cannot store HTML or checklists of its own
+	patched, err := s.Patch(ctx, &model.Todo{Id: created.Id, Description: "- [x] Tag it", DescriptionHtml: "<script>"}, []string{"description"})
+	if err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	if strings.Contains(patched.DescriptionHtml, "<script>") || len(patched.Links) != 0 || !patched.Checklist[0].Checked {
+		t.Errorf("Expected the new description to be rendered, got %+v", patched)
+	}
+
+	cleared, err := s.Patch(ctx, &model.Todo{Id: created.Id}, []string{"description"})
+	if err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	if cleared.DescriptionHtml != "" || cleared.Checklist != nil {
+		t.Errorf("Expected no rendering without a description, got %+v", cleared)
+	}
+
+	if _, err := s.Create(ctx, &model.Todo{Title: "Too long", Description: strings.Repeat("x", maxDescriptionBytes+1)}); !errors.Is(err, ErrInvalidDescription) {
+		t.Errorf("Expected ErrInvalidDescription, got %v", err)
+	}
+}
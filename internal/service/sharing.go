@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+var (
+	ErrSharingUnsupported = errors.New("repository does not store shared lists")
+	ErrUnauthenticated    = errors.New("a signed-in user is required")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrLastOwner          = errors.New("a list must keep at least one owner")
+)
+
+// MembershipStore is implemented by repositories that persist shared lists,
+// their members and invitations.
+type MembershipStore interface {
+	// CreateList creates a list with owner as its only member.
+	CreateList(ctx context.Context, list *model.TodoList, owner string) (model.TodoList, error)
+	// ListsOf returns the lists userID is a member of, with its role.
+	ListsOf(ctx context.Context, userID string) ([]model.TodoList, error)
+	// Role returns the role of userID in a list, RoleNone for non-members.
+	Role(ctx context.Context, listID, userID string) (model.Role, error)
+	Members(ctx context.Context, listID string) ([]model.Member, error)
+	// SetRole changes the role of a member, or returns ErrNotFound.
+	SetRole(ctx context.Context, listID, userID string, role model.Role) (model.Member, error)
+	// RemoveMember returns ErrNotFound for non-members.
+	RemoveMember(ctx context.Context, listID, userID string) error
+
+	CreateInvitation(ctx context.Context, inv *model.Invitation) (model.Invitation, error)
+	// Invitations returns the pending invitations addressed to userID or,
+	// if not empty, to email.
+	Invitations(ctx context.Context, userID, email string) ([]model.Invitation, error)
+	Invitation(ctx context.Context, id string) (model.Invitation, error)
+	// RespondInvitation marks a pending invitation accepted or declined and,
+	// when accepted, makes userID a member with the invited role unless it
+	// already has a higher one. It returns ErrNotFound unless the invitation
+	// is pending.
+	RespondInvitation(ctx context.Context, id, userID string, accept bool) (model.Member, error)
+}
+
+func (s *Service) memberships() (MembershipStore, error) {
+	store, ok := lookup[MembershipStore](s.repo)
+	if !ok {
+		return nil, ErrSharingUnsupported
+	}
+	return store, nil
+}
+
+// user returns the signed-in user of the request.
+func user(ctx context.Context) (auth.Principal, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.UserID == "" {
+		return auth.Principal{}, ErrUnauthenticated
+	}
+	return p, nil
+}
+
+// authorize fails with ErrPermissionDenied unless the caller has at least
+// role in the list. Outside any list the caller only has to be
+// authenticated.
+func (s *Service) authorize(ctx context.Context, listID string, role model.Role) error {
+	if listID == "" {
+		_, err := author(ctx)
+		return err
+	}
+	store, err := s.memberships()
+	if err != nil {
+		return err
+	}
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.UserID == "" {
+		return ErrPermissionDenied
+	}
+
+	have, err := store.Role(ctx, listID, p.UserID)
+	if err != nil {
+		return err
+	}
+	if have < role {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// authorizeOn checks the caller's role in the list of t. A todo outside any
+// list belongs to whoever created it. Anonymous callers fail with
+// ErrUnauthenticated. A missing todo, which Get returns empty, passes, so
+// that callers report it the way they always have.
+func (s *Service) authorizeOn(ctx context.Context, t model.Todo, role model.Role) error {
+	subject, err := author(ctx)
+	if err != nil {
+		return err
+	}
+	if t.Id == "" {
+		return nil
+	}
+	if t.ListId == "" {
+		if t.CreatedBy != subject {
+			return ErrPermissionDenied
+		}
+		return nil
+	}
+	return s.authorize(ctx, t.ListId, role)
+}
+
+// authorizeTodo checks the caller's access to a stored todo with
+// authorizeOn. It reads from the primary, where a todo is never missing that
+// exists.
+func (s *Service) authorizeTodo(ctx context.Context, id string, role model.Role) error {
+	t, err := s.repo.Get(WithPrimary(ctx), id)
+	if errors.Is(err, ErrNotFound) {
+		t, err = model.Todo{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.authorizeOn(ctx, t, role)
+}
+
+// existingTodo returns the todo id if the caller has at least role in it.
+// Unlike authorizeTodo, a missing todo fails with ErrNotFound.
+func (s *Service) existingTodo(ctx context.Context, id string, role model.Role) (model.Todo, error) {
+	t, err := s.repo.Get(WithPrimary(ctx), id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if err := s.authorizeOn(ctx, t, role); err != nil {
+		return model.Todo{}, err
+	}
+	if t.Id == "" {
+		return model.Todo{}, ErrNotFound
+	}
+	return t, nil
+}
+
+// visibleTo reports whether the caller with subject and the lists may read
+// t, the way List decides it.
+func visibleTo(t model.Todo, subject string, lists []string) bool {
+	if t.ListId == "" {
+		return t.CreatedBy == subject
+	}
+	return slices.Contains(lists, t.ListId)
+}
+
+// visibleLists returns the ids of the lists the caller is a member of.
+func (s *Service) visibleLists(ctx context.Context) ([]string, error) {
+	store, ok := lookup[MembershipStore](s.repo)
+	if !ok {
+		return nil, nil
+	}
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.UserID == "" {
+		return nil, nil
+	}
+
+	lists, err := store.ListsOf(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(lists))
+	for i, l := range lists {
+		ids[i] = l.Id
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// scopeList restricts opts to what the caller may read: the todos of their
+// lists and those they created outside any list.
+func (s *Service) scopeList(ctx context.Context, opts model.ListOptions) (model.ListOptions, error) {
+	subject, err := author(ctx)
+	if err != nil {
+		return opts, err
+	}
+	if opts.ListId != "" {
+		return opts, s.authorize(ctx, opts.ListId, model.RoleViewer)
+	}
+	opts.CreatedBy = subject
+	opts.VisibleLists, err = s.visibleLists(ctx)
+	return opts, err
+}
+
+// listScope identifies the set of lists visible to the caller, so cached
+// responses are not reused after the caller joins or leaves a list.
+func listScope(lists []string) string {
+	if len(lists) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join(lists, ",")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *Service) CreateList(ctx context.Context, name string) (model.TodoList, error) {
+	store, err := s.memberships()
+	if err != nil {
+		return model.TodoList{}, err
+	}
+	p, err := user(ctx)
+	if err != nil {
+		return model.TodoList{}, err
+	}
+	return store.CreateList(ctx, &model.TodoList{Name: name}, p.UserID)
+}
+
+func (s *Service) ListLists(ctx context.Context) ([]model.TodoList, error) {
+	store, err := s.memberships()
+	if err != nil {
+		return nil, err
+	}
+	p, err := user(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.ListsOf(ctx, p.UserID)
+}
+
+// InviteMember invites a user to a list; only owners may invite.
+func (s *Service) InviteMember(ctx context.Context, inv *model.Invitation) (model.Invitation, error) {
+	store, err := s.memberships()
+	if err != nil {
+		return model.Invitation{}, err
+	}
+	p, err := user(ctx)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+	if err := s.authorize(ctx, inv.ListId, model.RoleOwner); err != nil {
+		return model.Invitation{}, err
+	}
+
+	inv.InvitedBy = p.UserID
+	inv.Status = model.InvitationStatusPending
+	return store.CreateInvitation(ctx, inv)
+}
+
+func (s *Service) ListInvitations(ctx context.Context) ([]model.Invitation, error) {
+	store, err := s.memberships()
+	if err != nil {
+		return nil, err
+	}
+	p, err := user(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.Invitations(ctx, p.UserID, p.Email)
+}
+
+// RespondInvitation accepts or declines an invitation addressed to the
+// caller. Invitations addressed to someone else are reported as not found.
+func (s *Service) RespondInvitation(ctx context.Context, id string, accept bool) (model.Member, error) {
+	store, err := s.memberships()
+	if err != nil {
+		return model.Member{}, err
+	}
+	p, err := user(ctx)
+	if err != nil {
+		return model.Member{}, err
+	}
+
+	inv, err := store.Invitation(ctx, id)
+	if err != nil {
+		return model.Member{}, err
+	}
+	addressed := inv.UserId != "" && inv.UserId == p.UserID ||
+		inv.Email != "" && p.Email != "" && strings.EqualFold(inv.Email, p.Email)
+	if !addressed {
+		return model.Member{}, ErrNotFound
+	}
+	return store.RespondInvitation(ctx, id, p.UserID, accept)
+}
+
+func (s *Service) ListMembers(ctx context.Context, listID string) ([]model.Member, error) {
+	store, err := s.memberships()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := user(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, listID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return store.Members(ctx, listID)
+}
+
+// UpdateMember changes the role of a member; only owners may do so.
+func (s *Service) UpdateMember(ctx context.Context, listID, userID string, role model.Role) (model.Member, error) {
+	store, err := s.memberships()
+	if err != nil {
+		return model.Member{}, err
+	}
+	if _, err := user(ctx); err != nil {
+		return model.Member{}, err
+	}
+	if err := s.authorize(ctx, listID, model.RoleOwner); err != nil {
+		return model.Member{}, err
+	}
+	if role == model.RoleOwner {
+		return store.SetRole(ctx, listID, userID, role)
+	}
+	var member model.Member
+	err = s.keepingOwner(ctx, listID, userID, func(store MembershipStore) error {
+		member, err = store.SetRole(ctx, listID, userID, role)
+		return err
+	})
+	if err != nil {
+		return model.Member{}, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a member from a list. Owners may remove anyone,
+// other members only themselves.
+func (s *Service) RemoveMember(ctx context.Context, listID, userID string) error {
+	if _, err := s.memberships(); err != nil {
+		return err
+	}
+	p, err := user(ctx)
+	if err != nil {
+		return err
+	}
+	required := model.RoleOwner
+	if userID == p.UserID {
+		required = model.RoleViewer
+	}
+	if err := s.authorize(ctx, listID, required); err != nil {
+		return err
+	}
+	return s.keepingOwner(ctx, listID, userID, func(store MembershipStore) error {
+		return store.RemoveMember(ctx, listID, userID)
+	})
+}
+
+// keepingOwner runs write, which demotes or removes userID, after checking
+// with keepOwner that the list keeps another owner. Both run in one
+// serializable transaction, so that two owners demoting or removing each
+// other at once cannot leave the list without one.
+func (s *Service) keepingOwner(ctx context.Context, listID, userID string, write func(MembershipStore) error) error {
+	return s.repo.WithinTx(ctx, TxOptions{Isolation: IsolationSerializable}, func(repo Repository) error {
+		store, ok := lookup[MembershipStore](repo)
+		if !ok {
+			return ErrSharingUnsupported
+		}
+		if err := s.keepOwner(ctx, store, listID, userID); err != nil {
+			return err
+		}
+		return write(store)
+	})
+}
+
+// keepOwner fails with ErrLastOwner if userID is the only owner of a list.
+func (s *Service) keepOwner(ctx context.Context, store MembershipStore, listID, userID string) error {
+	members, err := store.Members(ctx, listID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.Role == model.RoleOwner && m.UserId != userID {
+			return nil
+		}
+	}
+	for _, m := range members {
+		if m.UserId == userID && m.Role == model.RoleOwner {
+			return ErrLastOwner
+		}
+	}
+	return nil
+}
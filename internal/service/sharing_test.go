@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// memberRepo adds shared lists to memoryRepo.
+type memberRepo struct {
+	*memoryRepo
+	lists       map[string]model.TodoList
+	roles       map[string]map[string]model.Role
+	invitations map[string]model.Invitation
+	opts        []TxOptions
+}
+
+func newMemberRepo() *memberRepo {
+	return &memberRepo{
+		memoryRepo:  newMemoryRepo(),
+		lists:       map[string]model.TodoList{},
+		roles:       map[string]map[string]model.Role{},
+		invitations: map[string]model.Invitation{},
+	}
+}
+
+// WithinTx records opts and runs fn on the repository itself.
+func (m *memberRepo) WithinTx(_ context.Context, opts TxOptions, fn func(Repository) error) error {
+	m.opts = append(m.opts, opts)
+	return fn(m)
+}
+
+func (m *memberRepo) CreateList(_ context.Context, list *model.TodoList, owner string) (model.TodoList, error) {
+	list.Id = fmt.Sprintf("list-%d", len(m.lists)+1)
+	list.Role = model.RoleOwner
+	m.lists[list.Id] = *list
+	m.roles[list.Id] = map[string]model.Role{owner: model.RoleOwner}
+	return *list, nil
+}
+
+func (m *memberRepo) ListsOf(_ context.Context, userID string) ([]model.TodoList, error) {
+	var result []model.TodoList
+	for id, roles := range m.roles {
+		if role, ok := roles[userID]; ok {
+			list := m.lists[id]
+			list.Role = role
+			result = append(result, list)
+		}
+	}
+	return result, nil
+}
+
+func (m *memberRepo) Role(_ context.Context, listID, userID string) (model.Role, error) {
+	return m.roles[listID][userID], nil
+}
+
+func (m *memberRepo) Members(_ context.Context, listID string) ([]model.Member, error) {
+	var result []model.Member
+	for userID, role := range m.roles[listID] {
+		result = append(result, model.Member{ListId: listID, UserId: userID, Role: role})
+	}
+	return result, nil
+}
+
+func (m *memberRepo) SetRole(_ context.Context, listID, userID string, role model.Role) (model.Member, error) {
+	if _, ok := m.roles[listID][userID]; !ok {
+		return model.Member{}, ErrNotFound
+	}
+	m.roles[listID][userID] = role
+	return model.Member{ListId: listID, UserId: userID, Role: role}, nil
+}
+
+func (m *memberRepo) RemoveMember(_ context.Context, listID, userID string) error {
+	if _, ok := m.roles[listID][userID]; !ok {
+		return ErrNotFound
+	}
+	delete(m.roles[listID], userID)
+	return nil
+}
+
+func (m *memberRepo) CreateInvitation(_ context.Context, inv *model.Invitation) (model.Invitation, error) {
+	inv.Id = fmt.Sprintf("inv-%d", len(m.invitations)+1)
+	inv.ListName = m.lists[inv.ListId].Name
+	m.invitations[inv.Id] = *inv
+	return *inv, nil
+}
+
+func (m *memberRepo) Invitations(_ context.Context, userID, email string) ([]model.Invitation, error) {
+	var result []model.Invitation
+	for _, inv := range m.invitations {
+		if inv.Status == model.InvitationStatusPending && (inv.UserId == userID || email != "" && inv.Email == email) {
+			result = append(result, inv)
+		}
+	}
+	return result, nil
+}
+
+func (m *memberRepo) Invitation(_ context.Context, id string) (model.Invitation, error) {
+	inv, ok := m.invitations[id]
+	if !ok {
+		return model.Invitation{}, ErrNotFound
+	}
+	return inv, nil
+}
+
+func (m *memberRepo) RespondInvitation(_ context.Context, id, userID string, accept bool) (model.Member, error) {
+	inv, ok := m.invitations[id]
+	if !ok || inv.Status != model.InvitationStatusPending {
+		return model.Member{}, ErrNotFound
+	}
+	if !accept {
+		inv.Status = model.InvitationStatusDeclined
+		m.invitations[id] = inv
+		return model.Member{}, nil
+	}
+	inv.Status = model.InvitationStatusAccepted
+	m.invitations[id] = inv
+	role := max(m.roles[inv.ListId][userID], inv.Role)
+	m.roles[inv.ListId][userID] = role
+	return model.Member{ListId: inv.ListId, UserId: userID, Role: role}, nil
+}
+
+func as(userID, email string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Email: email})
+}
+
+// sharedList creates a list owned by alice holding one todo and returns
+// the list id.
+func sharedList(t *testing.T, s *Service) string {
+	t.Helper()
+	list, err := s.CreateList(as("alice", ""), "Groceries")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := s.Create(as("alice", ""), &model.Todo{Title: "Milk", ListId: list.Id}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return list.Id
+}
+
+func TestInvitedMemberSeesListTodos(t *testing.T) {
+	s := NewTodosService(newMemberRepo())
+	listID := sharedList(t, s)
+	bob := as("bob", "bob@example.com")
+
+	todos, err := s.List(bob, model.ListOptions{})
+	if err != nil || len(todos) != 0 {
+		t.Fatalf("Expected no todos before joining, got %v %v", titles(todos), err)
+	}
+	if _, err := s.List(bob, model.ListOptions{ListId: listID}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied, got %v", err)
+	}
+
+	inv, err := s.InviteMember(as("alice", ""), &model.Invitation{ListId: listID, Email: "Bob@example.com", Role: model.RoleViewer})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := s.RespondInvitation(as("mallory", "mallory@example.com"), inv.Id, true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected an invitation for someone else to be not found, got %v", err)
+	}
+	member, err := s.RespondInvitation(bob, inv.Id, true)
+	if err != nil || member.Role != model.RoleViewer {
+		t.Fatalf("Expected bob to join as viewer, got %v %v", member, err)
+	}
+
+	todos, err = s.List(bob, model.ListOptions{})
+	if err != nil || len(todos) != 1 || todos[0].Title != "Milk" {
+		t.Errorf("Expected [Milk], got %v %v", titles(todos), err)
+	}
+	watermark, _ := s.Watermark(bob)
+	if watermark.Scope == "" {
+		t.Errorf("Expected the watermark to be scoped to bob's lists")
+	}
+}
+
+func TestViewerCannotEdit(t *testing.T) {
+	repo := newMemberRepo()
+	s := NewTodosService(repo)
+	listID := sharedList(t, s)
+	repo.roles[listID]["bob"] = model.RoleViewer
+
+	if _, err := s.Get(as("bob", ""), "Milk"); err != nil {
+		t.Errorf("Expected a viewer to read, got %v", err)
+	}
+	if _, err := s.Update(as("bob", ""), &model.Todo{Id: "Milk", Title: "Oat milk", ListId: listID}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied, got %v", err)
+	}
+	if err := s.Delete(as("bob", ""), "Milk"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied, got %v", err)
+	}
+	if _, err := s.Create(as("bob", ""), &model.Todo{Title: "Eggs", ListId: listID}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied, got %v", err)
+	}
+	if _, err := s.Get(as("carol", ""), "Milk"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a non-member, got %v", err)
+	}
+	if _, err := s.Get(context.Background(), "Milk"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+}
+
+func TestUnlistedTodosStayWithTheirCreator(t *testing.T) {
+	s := NewTodosService(newMemberRepo())
+	if _, err := s.Create(as("alice", ""), &model.Todo{Title: "Taxes"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := s.Get(as("alice", ""), "Taxes"); err != nil {
+		t.Errorf("Expected the creator to read, got %v", err)
+	}
+	if _, err := s.Get(as("bob", ""), "Taxes"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for another user, got %v", err)
+	}
+	if _, err := s.Update(as("bob", ""), &model.Todo{Id: "Taxes", Title: "Paid"}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for another user, got %v", err)
+	}
+	if err := s.Delete(as("bob", ""), "Taxes"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for another user, got %v", err)
+	}
+	if todos, err := s.List(as("bob", ""), model.ListOptions{}); err != nil || len(todos) != 0 {
+		t.Errorf("Expected another user to list nothing, got %v %v", titles(todos), err)
+	}
+	if changes, err := s.Sync(as("bob", ""), &model.SyncRequest{}); err != nil || len(changes.Todos) != 0 {
+		t.Errorf("Expected another user to sync nothing, got %+v %v", changes, err)
+	}
+
+	anonymous := context.Background()
+	if _, err := s.Get(anonymous, "Taxes"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+	if _, err := s.Update(anonymous, &model.Todo{Id: "Taxes", Title: "Paid"}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+	if err := s.Delete(anonymous, "Taxes"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+	if _, err := s.List(anonymous, model.ListOptions{}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+}
+
+func TestLastOwnerCannotLeave(t *testing.T) {
+	repo := newMemberRepo()
+	s := NewTodosService(repo)
+	listID := sharedList(t, s)
+	repo.roles[listID]["bob"] = model.RoleEditor
+
+	if err := s.RemoveMember(as("alice", ""), listID, "alice"); !errors.Is(err, ErrLastOwner) {
+		t.Errorf("Expected ErrLastOwner, got %v", err)
+	}
+	if _, err := s.UpdateMember(as("alice", ""), listID, "alice", model.RoleEditor); !errors.Is(err, ErrLastOwner) {
+		t.Errorf("Expected ErrLastOwner, got %v", err)
+	}
+	if len(repo.opts) != 2 || repo.opts[0].Isolation != IsolationSerializable || repo.opts[1].Isolation != IsolationSerializable {
+		t.Errorf("Expected the owner checks to run serializably with their writes, got %v", repo.opts)
+	}
+	if _, err := s.UpdateMember(as("bob", ""), listID, "bob", model.RoleOwner); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected an editor not to promote itself, got %v", err)
+	}
+
+	if _, err := s.UpdateMember(as("alice", ""), listID, "bob", model.RoleOwner); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := s.RemoveMember(as("alice", ""), listID, "alice"); err != nil {
+		t.Errorf("Expected alice to leave once bob owns the list, got %v", err)
+	}
+}
+
+func TestDeclineInvitation(t *testing.T) {
+	repo := newMemberRepo()
+	s := NewTodosService(repo)
+	listID := sharedList(t, s)
+
+	inv, err := s.InviteMember(as("alice", ""), &model.Invitation{ListId: listID, UserId: "bob", Role: model.RoleEditor})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pending, _ := s.ListInvitations(as("bob", ""))
+	if len(pending) != 1 || pending[0].ListName != "Groceries" {
+		t.Fatalf("Expected one invitation to Groceries, got %v", pending)
+	}
+
+	if _, err := s.RespondInvitation(as("bob", ""), inv.Id, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if role := repo.roles[listID]["bob"]; role != model.RoleNone {
+		t.Errorf("Expected bob not to join, got role %v", role)
+	}
+	if _, err := s.RespondInvitation(as("bob", ""), inv.Id, true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a declined invitation to be not found, got %v", err)
+	}
+}
+
+func TestSharingRequiresUser(t *testing.T) {
+	s := NewTodosService(newMemberRepo())
+	if _, err := s.CreateList(context.Background(), "Groceries"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated, got %v", err)
+	}
+	if _, err := NewTodosService(newMemoryRepo()).ListLists(as("alice", "")); !errors.Is(err, ErrSharingUnsupported) {
+		t.Errorf("Expected ErrSharingUnsupported, got %v", err)
+	}
+}
+
+// wrapped hides the optional stores of the repository it wraps, like a
+// cache does.
+type wrapped struct {
+	Repository
+}
+
+func (w wrapped) Unwrap() Repository {
+	return w.Repository
+}
+
+func TestSharingThroughWrapper(t *testing.T) {
+	s := NewTodosService(wrapped{newMemberRepo()})
+	if _, err := s.CreateList(as("alice", ""), "Groceries"); err != nil {
+		t.Errorf("Expected the store of the wrapped repository to be found, got %v", err)
+	}
+}
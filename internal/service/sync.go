@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+const (
+	defaultSyncPageSize = 500
+	syncTokenPrefix     = "v3:"
+	// tokens of the second release hold a cursor only
+	cursorTokenPrefix = "v2:"
+	// tokens of the first release hold a version only
+	versionTokenPrefix = "v1:"
+)
+
+var (
+	ErrSyncUnsupported  = errors.New("repository does not record change sequences")
+	ErrInvalidSyncToken = errors.New("invalid sync token")
+)
+
+// ChangeRecorder is implemented by repositories that stamp every write with a
+// monotonically increasing version and keep tombstones for deleted todos.
+type ChangeRecorder interface {
+	// Changes returns up to limit todos, tombstones included, that come
+	// after since, ordered by the transaction that wrote them and then by
+	// version. Only transactions that ended before every transaction still
+	// running are returned, so that no change can later commit before the
+	// cursor of a client.
+	Changes(ctx context.Context, since model.ChangeCursor, limit int) ([]model.Todo, error)
+	// Lookup returns a todo or its tombstone, or ErrNotFound.
+	Lookup(ctx context.Context, id string) (model.Todo, error)
+	// Put writes t only if the stored version equals expected, returning
+	// ErrVersionMismatch otherwise. An expected version of zero creates t.
+	Put(ctx context.Context, t *model.Todo, expected int64) (model.Todo, error)
+	// LatestChange returns the highest version handed out so far and the
+	// time it was written.
+	LatestChange(ctx context.Context) (model.Watermark, error)
+}
+
+// syncToken is where a client got to in the changes and the lists whose
+// todos it holds, so that the todos of lists the caller joined or left
+// since can be sent or removed.
+type syncToken struct {
+	cursor model.ChangeCursor
+	lists  []string
+	// scoped is unset for tokens of earlier releases, whose clients are
+	// taken to hold the lists the caller is a member of now.
+	scoped bool
+}
+
+func encodeSyncToken(token syncToken) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncTokenPrefix +
+		strconv.FormatInt(token.cursor.Txid, 10) + "." + strconv.FormatInt(token.cursor.Version, 10) + "." +
+		strings.Join(token.lists, ",")))
+}
+
+// decodeSyncToken returns the state in token. A version token of the first
+// release restarts at the first transaction, which sends the client every
+// todo once more rather than skip any.
+func decodeSyncToken(token string) (syncToken, error) {
+	if token == "" {
+		return syncToken{scoped: true}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return syncToken{}, ErrInvalidSyncToken
+	}
+	var st syncToken
+	switch {
+	case strings.HasPrefix(string(raw), versionTokenPrefix):
+		st.cursor.Version, err = strconv.ParseInt(strings.TrimPrefix(string(raw), versionTokenPrefix), 10, 64)
+	case strings.HasPrefix(string(raw), cursorTokenPrefix):
+		txid, version, ok := strings.Cut(strings.TrimPrefix(string(raw), cursorTokenPrefix), ".")
+		if !ok {
+			return syncToken{}, ErrInvalidSyncToken
+		}
+		st.cursor, err = parseCursor(txid, version)
+	case strings.HasPrefix(string(raw), syncTokenPrefix):
+		parts := strings.SplitN(strings.TrimPrefix(string(raw), syncTokenPrefix), ".", 3)
+		if len(parts) != 3 {
+			return syncToken{}, ErrInvalidSyncToken
+		}
+		st.cursor, err = parseCursor(parts[0], parts[1])
+		if parts[2] != "" {
+			st.lists = strings.Split(parts[2], ",")
+		}
+		st.scoped = true
+	default:
+		return syncToken{}, ErrInvalidSyncToken
+	}
+	if err != nil || st.cursor.Txid < 0 || st.cursor.Version < 0 {
+		return syncToken{}, ErrInvalidSyncToken
+	}
+	return st, nil
+}
+
+func parseCursor(txid, version string) (model.ChangeCursor, error) {
+	var cursor model.ChangeCursor
+	var err error
+	cursor.Txid, err = strconv.ParseInt(txid, 10, 64)
+	if err == nil {
+		cursor.Version, err = strconv.ParseInt(version, 10, 64)
+	}
+	return cursor, err
+}
+
+func (s *Service) changeRecorder() (ChangeRecorder, error) {
+	rec, ok := lookup[ChangeRecorder](s.repo)
+	if !ok {
+		return nil, ErrSyncUnsupported
+	}
+	return rec, nil
+}
+
+// Sync returns the todos changed or deleted since the given sync token along
+// with the token to pass on the next call. After the caller joined a list,
+// the todos it already holds are returned too, and after the caller left a
+// list, its todos are returned as deleted.
+func (s *Service) Sync(ctx context.Context, req *model.SyncRequest) (model.SyncResponse, error) {
+	rec, err := s.changeRecorder()
+	if err != nil {
+		return model.SyncResponse{}, err
+	}
+	subject, err := author(ctx)
+	if err != nil {
+		return model.SyncResponse{}, err
+	}
+
+	since, err := decodeSyncToken(req.SyncToken)
+	if err != nil {
+		return model.SyncResponse{}, err
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultSyncPageSize
+	}
+
+	// fetch one extra row to learn whether another page follows
+	changes, err := rec.Changes(ctx, since.cursor, pageSize+1)
+	if err != nil {
+		return model.SyncResponse{}, err
+	}
+
+	res := model.SyncResponse{HasMore: len(changes) > pageSize}
+	if res.HasMore {
+		changes = changes[:pageSize]
+	}
+
+	lists, err := s.visibleLists(ctx)
+	if err != nil {
+		return model.SyncResponse{}, err
+	}
+	// a client doing its first sync holds nothing yet, and one of an
+	// earlier release is taken to hold the lists of now
+	var joined, left []string
+	if since.scoped && since.cursor != (model.ChangeCursor{}) {
+		joined, left = missing(lists, since.lists), missing(since.lists, lists)
+	}
+
+	cursor := since.cursor
+	for i := range changes {
+		t := changes[i]
+		cursor = model.ChangeCursor{Txid: t.Txid, Version: t.Version}
+		switch {
+		case slices.Contains(joined, t.ListId):
+			// sent below as the todo is now
+			continue
+		case t.Deleted && slices.Contains(left, t.ListId):
+			res.DeletedIds = append(res.DeletedIds, t.Id)
+			continue
+		case !visibleTo(t, subject, lists):
+			continue
+		}
+		if t.Deleted {
+			// a client doing its first sync never saw this todo
+			if since.cursor != (model.ChangeCursor{}) {
+				res.DeletedIds = append(res.DeletedIds, t.Id)
+			}
+			continue
+		}
+		res.Todos = append(res.Todos, &t)
+	}
+
+	for _, listID := range joined {
+		todos, err := s.repo.List(ctx, model.ListOptions{ListId: listID})
+		if err != nil {
+			return model.SyncResponse{}, err
+		}
+		for i := range todos {
+			res.Todos = append(res.Todos, &todos[i])
+		}
+	}
+	// a forged token can at most learn the ids, not the content, of the
+	// todos of a list whose id it knows
+	for _, listID := range left {
+		todos, err := s.repo.List(ctx, model.ListOptions{ListId: listID})
+		if err != nil {
+			return model.SyncResponse{}, err
+		}
+		for _, t := range todos {
+			res.DeletedIds = append(res.DeletedIds, t.Id)
+		}
+	}
+
+	next := syncToken{cursor: cursor, lists: lists, scoped: true}
+	if res.HasMore && (len(joined) > 0 || len(left) > 0) {
+		// todos the client still holds may be deleted in the pages to come,
+		// so every page of the catch up repeats the lists it joined and left
+		next.lists = since.lists
+	}
+	res.SyncToken = encodeSyncToken(next)
+
+	return res, nil
+}
+
+// missing returns the lists of a that are not in b.
+func missing(a, b []string) []string {
+	var result []string
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// Push applies client mutations one by one. A mutation whose base version no
+// longer matches the server is not applied and reports the server state so the
+// client can resolve the conflict.
+func (s *Service) Push(ctx context.Context, mutations []*model.Mutation) ([]*model.MutationResult, error) {
+	rec, err := s.changeRecorder()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := author(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.MutationResult, 0, len(mutations))
+	for _, m := range mutations {
+		res, err := s.apply(ctx, rec, m)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *Service) apply(ctx context.Context, rec ChangeRecorder, m *model.Mutation) (*model.MutationResult, error) {
+	res := &model.MutationResult{Id: m.Id}
+
+	var err error
+
+	t := &model.Todo{Id: m.Id, Title: m.Title, Completed: m.Completed, Priority: m.Priority}
+	expected := m.BaseVersion
+	switch m.Op {
+	case model.MutationOpCreate:
+		expected = 0
+		t.CreatedBy = creator(ctx)
+	case model.MutationOpUpdate:
+	case model.MutationOpDelete:
+		current, err := rec.Lookup(ctx, m.Id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		t.Title, t.Completed, t.Priority, t.Deleted = current.Title, current.Completed, current.Priority, true
+	default:
+		res.Status = model.MutationStatusRejected
+		res.Error = "unknown mutation op"
+		return res, nil
+	}
+	if m.Op != model.MutationOpCreate {
+		if err := s.authorizeTodo(ctx, m.Id, model.RoleEditor); errors.Is(err, ErrPermissionDenied) {
+			res.Status = model.MutationStatusRejected
+			res.Error = err.Error()
+			return res, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if !t.Deleted && strings.TrimSpace(t.Title) == "" {
+		res.Status = model.MutationStatusRejected
+		res.Error = "title must not be empty"
+		return res, nil
+	}
+	// clients pick the id of the todos they create, so that a retried push
+	// cannot create a todo twice
+	if m.Id == "" || (m.Op != model.MutationOpCreate && expected <= 0) {
+		res.Status = model.MutationStatusRejected
+		res.Error = "id and base_version are required"
+		return res, nil
+	}
+	var written model.Todo
+	write := func(repo Repository) error {
+		if m.Op == model.MutationOpUpdate {
+			if err := s.checkCompletable(ctx, repo, t); err != nil {
+				return err
+			}
+		}
+		if expected == 0 {
+			if err := s.checkQuota(ctx, repo, 1); err != nil {
+				return err
+			}
+			position, err := s.topPosition(ctx, repo)
+			if err != nil {
+				return err
+			}
+			t.Position = position
+		}
+		txRec, ok := lookup[ChangeRecorder](repo)
+		if !ok {
+			txRec = rec
+		}
+		var err error
+		written, err = txRec.Put(ctx, t, expected)
+		return err
+	}
+	if m.Op == model.MutationOpUpdate {
+		err = s.withinCompletable(ctx, t, write)
+	} else {
+		err = s.withinQuota(ctx, expected == 0, write)
+	}
+	switch {
+	case errors.Is(err, ErrQuotaExceeded), errors.Is(err, ErrBlocked):
+		res.Status = model.MutationStatusRejected
+		res.Error = err.Error()
+		return res, nil
+	case err == nil:
+		if err := s.scheduleNext(ctx, written); err != nil {
+			return nil, err
+		}
+		res.Status = model.MutationStatusApplied
+		res.Deleted = written.Deleted
+		if !written.Deleted {
+			res.Todo = &written
+		}
+		return res, nil
+	case errors.Is(err, ErrNotFound):
+		res.Status = model.MutationStatusNotFound
+		return res, nil
+	case !errors.Is(err, ErrVersionMismatch):
+		return nil, err
+	}
+
+	current, err := rec.Lookup(ctx, t.Id)
+	if errors.Is(err, ErrNotFound) {
+		res.Status = model.MutationStatusNotFound
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// a create may clash with the id of a todo the caller cannot see
+	if err := s.authorizeOn(ctx, current, model.RoleViewer); errors.Is(err, ErrPermissionDenied) {
+		res.Status = model.MutationStatusRejected
+		res.Error = err.Error()
+		return res, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	res.Status = model.MutationStatusConflict
+	res.Deleted = current.Deleted
+	if !current.Deleted {
+		res.Todo = &current
+	}
+	return res, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"encoding/base64"
+	"slices"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestSyncTokenRoundTrip(t *testing.T) {
+	want := syncToken{cursor: model.ChangeCursor{Txid: 7, Version: 42}, lists: []string{"list-1", "list-2"}, scoped: true}
+	token, err := decodeSyncToken(encodeSyncToken(want))
+	if err != nil || token.cursor != want.cursor || !slices.Equal(token.lists, want.lists) || !token.scoped {
+		t.Errorf("Expected %+v, got %+v (%v)", want, token, err)
+	}
+	token, err = decodeSyncToken(encodeSyncToken(syncToken{cursor: want.cursor}))
+	if err != nil || token.lists != nil || !token.scoped {
+		t.Errorf("Expected a token without lists, got %+v (%v)", token, err)
+	}
+
+	// a cursor token of the second release holds no lists
+	token, err = decodeSyncToken(base64.RawURLEncoding.EncodeToString([]byte("v2:7.42")))
+	if err != nil || token.cursor != want.cursor || token.scoped {
+		t.Errorf("Expected an unscoped cursor, got %+v (%v)", token, err)
+	}
+
+	// a version token of the first release starts over
+	token, err = decodeSyncToken(base64.RawURLEncoding.EncodeToString([]byte("v1:42")))
+	if err != nil || token.cursor.Txid != 0 {
+		t.Errorf("Expected a version token to restart at the first transaction, got %+v (%v)", token, err)
+	}
+
+	if _, err := decodeSyncToken("not-a-token"); err != ErrInvalidSyncToken {
+		t.Errorf("Expected ErrInvalidSyncToken, got %v", err)
+	}
+}
+
+func TestSyncReturnsChangesAndTombstones(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newMemoryRepo()
+	s := NewTodosService(repo)
+
+	repo.Create(ctx, &model.Todo{Title: "a", CreatedBy: "user:alice"})
+	repo.Create(ctx, &model.Todo{Title: "b", CreatedBy: "user:alice"})
+
+	first, err := s.Sync(ctx, &model.SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(first.Todos) != 2 || first.HasMore {
+		t.Fatalf("Expected 2 todos and no more pages, got %d (has_more=%v)", len(first.Todos), first.HasMore)
+	}
+
+	repo.Delete(ctx, "a")
+	repo.Update(ctx, &model.Todo{Id: "b", Title: "b", Completed: true})
+
+	second, err := s.Sync(ctx, &model.SyncRequest{SyncToken: first.SyncToken})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(second.DeletedIds) != 1 || second.DeletedIds[0] != "a" {
+		t.Errorf("Expected tombstone for 'a', got %v", second.DeletedIds)
+	}
+	if len(second.Todos) != 1 || !second.Todos[0].Completed {
+		t.Errorf("Expected completed 'b', got %+v", second.Todos)
+	}
+
+	third, err := s.Sync(ctx, &model.SyncRequest{SyncToken: first.SyncToken, PageSize: 1})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !third.HasMore || len(third.DeletedIds) != 1 {
+		t.Errorf("Expected a single change with more pending, got %+v", third)
+	}
+}
+
+func TestSyncAfterJoiningAList(t *testing.T) {
+	repo := newMemberRepo()
+	s := NewTodosService(repo)
+	listID := sharedList(t, s)
+	bob := as("bob", "")
+
+	first, err := s.Sync(bob, &model.SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(first.Todos) != 0 {
+		t.Fatalf("Expected no todos outside bob's lists, got %+v", first.Todos)
+	}
+
+	repo.roles[listID]["bob"] = model.RoleViewer
+	second, err := s.Sync(bob, &model.SyncRequest{SyncToken: first.SyncToken})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(second.Todos) != 1 || second.Todos[0].Title != "Milk" {
+		t.Errorf("Expected the existing todo of the joined list, got %+v", second.Todos)
+	}
+
+	third, err := s.Sync(bob, &model.SyncRequest{SyncToken: second.SyncToken})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(third.Todos) != 0 || len(third.DeletedIds) != 0 {
+		t.Errorf("Expected nothing new, got %+v", third)
+	}
+}
+
+func TestSyncAfterLeavingAList(t *testing.T) {
+	repo := newMemberRepo()
+	s := NewTodosService(repo)
+	listID := sharedList(t, s)
+	alice, bob := as("alice", ""), as("bob", "")
+	repo.roles[listID]["bob"] = model.RoleEditor
+
+	eggs, err := s.Create(alice, &model.Todo{Title: "Eggs", ListId: listID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	first, err := s.Sync(bob, &model.SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(first.Todos) != 2 {
+		t.Fatalf("Expected the todos of the list, got %+v", first.Todos)
+	}
+
+	if err := s.Delete(alice, eggs.Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := s.RemoveMember(bob, listID, "bob"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := s.Sync(bob, &model.SyncRequest{SyncToken: first.SyncToken})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	slices.Sort(second.DeletedIds)
+	want := []string{first.Todos[0].Id, first.Todos[1].Id}
+	slices.Sort(want)
+	if !slices.Equal(second.DeletedIds, want) || len(second.Todos) != 0 {
+		t.Errorf("Expected the todos of the left list to be deleted, got %+v", second)
+	}
+
+	third, err := s.Sync(bob, &model.SyncRequest{SyncToken: second.SyncToken})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(third.Todos) != 0 || len(third.DeletedIds) != 0 {
+		t.Errorf("Expected nothing new, got %+v", third)
+	}
+}
+
+func TestPushDetectsConflicts(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newMemoryRepo()
+	s := NewTodosService(repo)
+
+	created, _ := repo.Create(ctx, &model.Todo{Title: "a", CreatedBy: "user:alice"})
+	repo.Update(ctx, &model.Todo{Id: "a", Title: "changed on server"})
+
+	results, err := s.Push(ctx, []*model.Mutation{
+		{Op: model.MutationOpUpdate, Id: "a", Title: "changed offline", BaseVersion: created.Version},
+		{Op: model.MutationOpCreate, Id: "c", Title: "new"},
+		{Op: model.MutationOpDelete, Id: "missing", BaseVersion: 1},
+		{Op: model.MutationOpCreate, Title: "no id"},
+	})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if results[0].Status != model.MutationStatusConflict || results[0].Todo.Title != "changed on server" {
+		t.Errorf("Expected conflict with server state, got %+v", results[0])
+	}
+	if results[1].Status != model.MutationStatusApplied || results[1].Todo.Version == 0 {
+		t.Errorf("Expected create to be applied, got %+v", results[1])
+	}
+	if results[2].Status != model.MutationStatusNotFound {
+		t.Errorf("Expected not found, got %+v", results[2])
+	}
+	if results[3].Status != model.MutationStatusRejected {
+		t.Errorf("Expected a create without id to be rejected, got %+v", results[3])
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+	"github.com/haakaashs/todos-backend/internal/rank"
+	"github.com/haakaashs/todos-backend/internal/transfer"
+)
+
+// maxTitleLength matches the title rule of CreateRequest.
+const maxTitleLength = 255
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// Export writes every todo visible to the caller, in manual order, to w in the given format.
+func (s *Service) Export(ctx context.Context, format model.DataFormat, w io.Writer) error {
+	todos, err := s.List(ctx, model.ListOptions{Order: model.ListOrderPosition})
+	if err != nil {
+		return err
+	}
+	return transfer.Encode(w, format, todos)
+}
+
+// Import creates the todos read from r. The whole file is decoded and
+// validated before anything is written, and the todos are created in one
+// transaction, so a bad file never leaves partial data behind. Todos whose
+// title already exists are skipped unless duplicates are allowed.
+func (s *Service) Import(ctx context.Context, opts model.ImportOptions, r io.Reader) (model.ImportResponse, error) {
+	subject, err := author(ctx)
+	if err != nil {
+		return model.ImportResponse{}, err
+	}
+	decoded, err := transfer.Decode(r, opts.Format)
+	if err != nil {
+		if errors.Is(err, transfer.ErrMalformed) || errors.Is(err, transfer.ErrUnsupportedFormat) {
+			return model.ImportResponse{}, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		return model.ImportResponse{}, err
+	}
+
+	for i := range decoded {
+		t := &decoded[i]
+		t.Title = strings.TrimSpace(t.Title)
+		if t.Title == "" || utf8.RuneCountInString(t.Title) > maxTitleLength {
+			return model.ImportResponse{}, fmt.Errorf("%w: todo %d: title must be 1 to %d characters", ErrInvalidImport, i+1, maxTitleLength)
+		}
+		if err := validateRecurrence(t); err != nil {
+			return model.ImportResponse{}, fmt.Errorf("%w: todo %d: %v", ErrInvalidImport, i+1, err)
+		}
+		if err := describe(t); err != nil {
+			return model.ImportResponse{}, fmt.Errorf("%w: todo %d: %v", ErrInvalidImport, i+1, err)
+		}
+	}
+
+	res := model.ImportResponse{DryRun: opts.DryRun}
+
+	seen := map[string]bool{}
+	if !opts.AllowDuplicates {
+		// imported todos are outside any list, among those of the caller
+		existing, err := s.repo.List(ctx, model.ListOptions{CreatedBy: subject})
+		if err != nil {
+			return model.ImportResponse{}, err
+		}
+		for _, t := range existing {
+			seen[titleKey(t.Title)] = true
+		}
+	}
+
+	var todos []*model.Todo
+	for i := range decoded {
+		t := &decoded[i]
+		key := titleKey(t.Title)
+		if seen[key] {
+			res.Duplicates = append(res.Duplicates, t.Title)
+			continue
+		}
+		if !opts.AllowDuplicates {
+			seen[key] = true
+		}
+		todos = append(todos, t)
+	}
+
+	if opts.DryRun || len(todos) == 0 {
+		res.Todos = todos
+		return res, nil
+	}
+
+	created, err := s.createImported(ctx, todos)
+	if err != nil {
+		return model.ImportResponse{}, err
+	}
+
+	for i := range created {
+		res.Todos = append(res.Todos, &created[i])
+	}
+	s.rebalanceIfLong(created[len(created)-1].Position)
+	return res, nil
+}
+
+// createImported places the imported todos above the existing ones, in file
+// order, and creates them.
+func (s *Service) createImported(ctx context.Context, todos []*model.Todo) ([]model.Todo, error) {
+	for _, t := range todos {
+		t.CreatedBy = creator(ctx)
+	}
+
+	var created []model.Todo
+	err := s.withinQuota(ctx, true, func(repo Repository) error {
+		if err := s.checkQuota(ctx, repo, len(todos)); err != nil {
+			return err
+		}
+		first, err := repo.AdjacentPosition(ctx, "", "", true)
+		if err != nil {
+			return err
+		}
+		positions, err := rank.BetweenN("", first, len(todos))
+		if err != nil {
+			return err
+		}
+		// a retry starts over from the todos as they were passed in
+		attempt := make([]*model.Todo, len(todos))
+		for i, t := range todos {
+			copied := *t
+			copied.Position = positions[i]
+			attempt[i] = &copied
+		}
+		created, err = repo.CreateBatch(ctx, attempt)
+		return err
+	})
+	return created, err
+}
+
+func titleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
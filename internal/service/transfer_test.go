@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestImportSkipsDuplicatesAndSupportsDryRun(t *testing.T) {
+	ctx := as("alice", "")
+	repo := newMemoryRepo()
+	s := NewTodosService(repo)
+	s.Create(ctx, &model.Todo{Title: "Buy milk"})
+
+	file := "- [ ] buy  MILK\n- [ ] walk dog\n- [x] walk dog\n- [ ] file taxes\n"
+	opts := model.ImportOptions{Format: model.DataFormatMarkdown, DryRun: true}
+
+	dry, err := s.Import(ctx, opts, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(dry.Todos) != 2 || !reflect.DeepEqual(dry.Duplicates, []string{"buy  MILK", "walk dog"}) {
+		t.Errorf("Unexpected dry run result: %+v", dry)
+	}
+	if todos, _ := s.List(ctx, model.ListOptions{}); len(todos) != 1 {
+		t.Fatalf("Dry run created %d todos", len(todos)-1)
+	}
+
+	opts.DryRun = false
+	if _, err := s.Import(ctx, opts, strings.NewReader(file)); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	todos, _ := s.List(ctx, model.ListOptions{Order: model.ListOrderPosition})
+	if got := titles(todos); !reflect.DeepEqual(got, []string{"walk dog", "file taxes", "Buy milk"}) {
+		t.Errorf("Expected imported todos on top in file order, got %v", got)
+	}
+}
+
+func TestImportRejectsMalformedFileWithoutWriting(t *testing.T) {
+	ctx := as("alice", "")
+	s := NewTodosService(newMemoryRepo())
+
+	file := "{\"title\":\"fine\"}\n{\"title\":\"\"}\n"
+	_, err := s.Import(ctx, model.ImportOptions{Format: model.DataFormatNDJSON}, strings.NewReader(file))
+	if !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("Expected ErrInvalidImport, got %v", err)
+	}
+	if todos, _ := s.List(ctx, model.ListOptions{}); len(todos) != 0 {
+		t.Errorf("Expected nothing imported, got %d todos", len(todos))
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// IsolationLevel is the isolation level of a unit of work.
+type IsolationLevel int
+
+const (
+	// IsolationDefault uses the database default, read committed for
+	// Postgres.
+	IsolationDefault IsolationLevel = iota
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+// TxOptions configures a unit of work started with Repository.WithinTx.
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+}
+
+type primaryKey struct{}
+
+// WithPrimary marks reads made with the returned context as deciding a
+// write, so a repository with read replicas serves them from the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// ReadsPrimary reports whether ctx was marked with WithPrimary.
+func ReadsPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+type writesKey struct{}
+
+// Writes carries the last write of a client across the requests it sends,
+// possibly to different servers: the client sends back the time of its last
+// write, and a repository with read replicas reads from the primary for a
+// while after it and records the writes of the request. The time only routes
+// reads, so a client can at most keep its own reads on the primary.
+type Writes struct {
+	last atomic.Int64 // unix milliseconds
+}
+
+// NewWrites returns the writes of a client that last wrote at last, or
+// never for the zero time.
+func NewWrites(last time.Time) *Writes {
+	w := &Writes{}
+	if !last.IsZero() {
+		w.last.Store(last.UnixMilli())
+	}
+	return w
+}
+
+// WithWrites returns a context carrying w.
+func WithWrites(ctx context.Context, w *Writes) context.Context {
+	return context.WithValue(ctx, writesKey{}, w)
+}
+
+// WritesFrom returns the writes carried by ctx, or nil.
+func WritesFrom(ctx context.Context) *Writes {
+	w, _ := ctx.Value(writesKey{}).(*Writes)
+	return w
+}
+
+// Mark records a write at.
+func (w *Writes) Mark(at time.Time) {
+	ms := at.UnixMilli()
+	for {
+		last := w.last.Load()
+		if last >= ms || w.last.CompareAndSwap(last, ms) {
+			return
+		}
+	}
+}
+
+// Last returns the time of the last write, or the zero time.
+func (w *Writes) Last() time.Time {
+	ms := w.last.Load()
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
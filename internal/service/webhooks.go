@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+var ErrWebhooksUnsupported = errors.New("repository does not store webhooks")
+
+// defaultDeliveryPageSize is the number of deliveries listed when the
+// request does not say.
+const defaultDeliveryPageSize = 50
+
+// WebhookStore is implemented by repositories that persist webhooks and the
+// log of their deliveries.
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) (model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]model.Webhook, error)
+	// DeleteWebhook returns ErrNotFound for unknown webhooks.
+	DeleteWebhook(ctx context.Context, id string) error
+	// EnqueueEvent adds a pending delivery of payload to every webhook
+	// subscribed to event whose owner can see the todo with todoID.
+	EnqueueEvent(ctx context.Context, event, todoID string, payload []byte) error
+	// ListDeliveries returns the most recent deliveries first.
+	ListDeliveries(ctx context.Context, opts model.DeliveryOptions) ([]model.WebhookDelivery, error)
+	// RedeliverDelivery makes a delivery pending again with no attempts
+	// made, or returns ErrNotFound.
+	RedeliverDelivery(ctx context.Context, webhookID, id string) (model.WebhookDelivery, error)
+}
+
+func (s *Service) webhooks() (WebhookStore, error) {
+	store, ok := lookup[WebhookStore](s.repo)
+	if !ok {
+		return nil, ErrWebhooksUnsupported
+	}
+	return store, nil
+}
+
+// CreateWebhook subscribes a URL to events on the todos the caller can see
+// and returns the secret that signs its deliveries, generating one unless it
+// is set.
+func (s *Service) CreateWebhook(ctx context.Context, webhook *model.Webhook) (model.CreateWebhookResponse, error) {
+	store, err := s.webhooks()
+	if err != nil {
+		return model.CreateWebhookResponse{}, err
+	}
+	if webhook.CreatedBy, err = author(ctx); err != nil {
+		return model.CreateWebhookResponse{}, err
+	}
+
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return model.CreateWebhookResponse{}, err
+		}
+		webhook.Secret = "whsec_" + base64.RawURLEncoding.EncodeToString(secret)
+	}
+
+	created, err := store.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return model.CreateWebhookResponse{}, err
+	}
+	return model.CreateWebhookResponse{Webhook: &created, Secret: created.Secret}, nil
+}
+
+func (s *Service) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	store, err := s.webhooks()
+	if err != nil {
+		return nil, err
+	}
+	return store.ListWebhooks(ctx)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, id string) error {
+	store, err := s.webhooks()
+	if err != nil {
+		return err
+	}
+	return store.DeleteWebhook(ctx, id)
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, opts model.DeliveryOptions) ([]model.WebhookDelivery, error) {
+	store, err := s.webhooks()
+	if err != nil {
+		return nil, err
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultDeliveryPageSize
+	}
+	return store.ListDeliveries(ctx, opts)
+}
+
+func (s *Service) RedeliverDelivery(ctx context.Context, webhookID, id string) (model.WebhookDelivery, error) {
+	store, err := s.webhooks()
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	return store.RedeliverDelivery(ctx, webhookID, id)
+}
+
+// QueueDeliveries adds a delivery of an outbox message to every webhook of
+// its tenant subscribed to it. It is subscribed to the outbox bus; since the
+// outbox delivers at least once, a message may be queued twice, and
+// receivers tell the copies apart by the event id in the payload.
+func (s *Service) QueueDeliveries(ctx context.Context, m model.OutboxMessage) error {
+	store, ok := lookup[WebhookStore](s.repo)
+	if !ok {
+		return nil
+	}
+	return store.EnqueueEvent(auth.WithTenant(ctx, m.TenantId), m.Type, m.AggregateId, m.Payload)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/haakaashs/todos-backend/internal/auth"
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// webhookRepo adds a webhook store to memoryRepo that records the events
+// queued and their tenant, whether or not anything is subscribed.
+type webhookRepo struct {
+	*memoryRepo
+	webhooks []model.Webhook
+	events   []model.Event
+	tenants  []string
+}
+
+func (m *webhookRepo) CreateWebhook(_ context.Context, webhook *model.Webhook) (model.Webhook, error) {
+	webhook.Id = "hook"
+	m.webhooks = append(m.webhooks, *webhook)
+	return *webhook, nil
+}
+
+func (m *webhookRepo) ListWebhooks(context.Context) ([]model.Webhook, error) {
+	return m.webhooks, nil
+}
+
+func (m *webhookRepo) DeleteWebhook(context.Context, string) error {
+	return ErrNotFound
+}
+
+func (m *webhookRepo) EnqueueEvent(ctx context.Context, event, _ string, payload []byte) error {
+	var e model.Event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return err
+	}
+	m.events = append(m.events, e)
+	m.tenants = append(m.tenants, auth.Tenant(ctx))
+	return nil
+}
+
+func (m *webhookRepo) ListDeliveries(context.Context, model.DeliveryOptions) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *webhookRepo) RedeliverDelivery(context.Context, string, string) (model.WebhookDelivery, error) {
+	return model.WebhookDelivery{}, ErrNotFound
+}
+
+func TestQueueDeliveriesInMessageTenant(t *testing.T) {
+	repo := &webhookRepo{memoryRepo: newMemoryRepo()}
+	s := NewTodosService(repo)
+
+	payload, _ := json.Marshal(model.Event{Id: "e1", Type: model.EventTodoCompleted, Data: model.EventData{Todo: model.Todo{Id: "t1"}}})
+	err := s.QueueDeliveries(context.Background(), model.OutboxMessage{
+		Id:          7,
+		EventId:     "e1",
+		TenantId:    "acme",
+		AggregateId: "t1",
+		Type:        model.EventTodoCompleted,
+		Payload:     payload,
+	})
+	if err != nil {
+		t.Fatalf("QueueDeliveries failed: %v", err)
+	}
+	if len(repo.events) != 1 || repo.events[0].Id != "e1" || repo.tenants[0] != "acme" {
+		t.Errorf("Expected the event queued in tenant acme, got %+v in %v", repo.events, repo.tenants)
+	}
+}
+
+func TestCreateWebhookGeneratesSecret(t *testing.T) {
+	repo := &webhookRepo{memoryRepo: newMemoryRepo()}
+	s := NewTodosService(repo)
+
+	if _, err := s.CreateWebhook(context.Background(), &model.Webhook{Url: "https://example.com/hook"}); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an anonymous caller, got %v", err)
+	}
+	res, err := s.CreateWebhook(as("alice", ""), &model.Webhook{Url: "https://example.com/hook", Events: []string{model.EventTodoCreated}})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if !strings.HasPrefix(res.Secret, "whsec_") || res.Webhook.Id != "hook" {
+		t.Errorf("Expected a generated secret and the webhook, got %+v", res)
+	}
+	if repo.webhooks[0].CreatedBy != "user:alice" {
+		t.Errorf("Expected the webhook to be owned by alice, got %q", repo.webhooks[0].CreatedBy)
+	}
+
+	res, err = s.CreateWebhook(as("alice", ""), &model.Webhook{Url: "https://example.com/hook", Secret: "my-own-secret-value"})
+	if err != nil || res.Secret != "my-own-secret-value" {
+		t.Errorf("Expected the given secret to be kept, got %q, %v", res.Secret, err)
+	}
+}
+
+func TestWebhooksUnsupported(t *testing.T) {
+	s := NewTodosService(newMemoryRepo())
+
+	if _, err := s.ListWebhooks(context.Background()); err != ErrWebhooksUnsupported {
+		t.Errorf("Expected ErrWebhooksUnsupported, got %v", err)
+	}
+	if _, err := s.Create(as("alice", ""), &model.Todo{Title: "no webhooks"}); err != nil {
+		t.Errorf("Expected todos to work without webhooks, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+var csvHeader = []string{"title", "completed", "priority", "due_at", "rrule", "timezone"}
+
+func encodeCSV(w io.Writer, todos []model.Todo) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, t := range todos {
+		due := ""
+		if t.DueAt != nil {
+			due = t.DueAt.Format(time.RFC3339)
+		}
+		record := []string{t.Title, strconv.FormatBool(t.Completed), priorityName(t.Priority), due, t.Rrule, t.Timezone}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// decodeCSV requires a header row naming at least the title column; the other
+// columns of csvHeader are optional and may appear in any order.
+func decodeCSV(r io.Reader) ([]model.Todo, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, &SyntaxError{Line: 1, Msg: "header has no title column"}
+	}
+
+	var todos []model.Todo
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return todos, nil
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		t := model.Todo{Title: field("title"), Rrule: field("rrule"), Timezone: field("timezone")}
+		if v := field("completed"); v != "" {
+			if t.Completed, err = strconv.ParseBool(v); err != nil {
+				return nil, &SyntaxError{Line: line, Msg: fmt.Sprintf("invalid completed value %q", v)}
+			}
+		}
+		var ok bool
+		if t.Priority, ok = parsePriority(field("priority")); !ok {
+			return nil, &SyntaxError{Line: line, Msg: fmt.Sprintf("invalid priority %q", field("priority"))}
+		}
+		if v := field("due_at"); v != "" {
+			due, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, &SyntaxError{Line: line, Msg: fmt.Sprintf("invalid due_at %q", v)}
+			}
+			t.DueAt = &due
+		}
+		todos = append(todos, t)
+	}
+}
+
+func csvError(err error) error {
+	var perr *csv.ParseError
+	if errors.As(err, &perr) {
+		return &SyntaxError{Line: perr.Line, Msg: perr.Err.Error()}
+	}
+	return err
+}
@@ -0,0 +1,379 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+const (
+	icalDateTime    = "20060102T150405"
+	icalDateTimeUTC = "20060102T150405Z"
+	icalDate        = "20060102"
+	// icalLineLimit is the folding limit of RFC 5545 in octets, excluding CRLF.
+	icalLineLimit = 75
+)
+
+// CalendarComponent selects how todos are represented in an iCalendar file.
+type CalendarComponent int
+
+const (
+	// ComponentTodo writes every todo as a VTODO.
+	ComponentTodo CalendarComponent = iota
+	// ComponentEvent writes todos with a due date as 30 minute VEVENTs, for
+	// calendar apps that do not show VTODOs.
+	ComponentEvent
+)
+
+// CalendarOptions controls EncodeICalendar.
+type CalendarOptions struct {
+	Name      string
+	Component CalendarComponent
+	// Now is written as DTSTAMP, defaults to the current time.
+	Now time.Time
+}
+
+// iCalendar PRIORITY is 1 (highest) to 9 (lowest), 0 meaning undefined.
+var icalPriorities = map[model.Priority]int{
+	model.PriorityUrgent: 1,
+	model.PriorityHigh:   3,
+	model.PriorityMedium: 5,
+	model.PriorityLow:    7,
+}
+
+// EncodeICalendar writes todos as an RFC 5545 calendar.
+//
+// Due dates of todos with a time zone are written with a TZID parameter
+// naming the IANA zone, without a VTIMEZONE definition; mainstream calendar
+// clients resolve IANA names themselves.
+func EncodeICalendar(w io.Writer, todos []model.Todo, opts CalendarOptions) error {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	stamp := opts.Now.UTC().Format(icalDateTimeUTC)
+
+	cw := &icalWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//haakaashs//todos-backend//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	if opts.Name != "" {
+		cw.line("X-WR-CALNAME:" + icalEscape(opts.Name))
+	}
+
+	for _, t := range todos {
+		if opts.Component == ComponentEvent {
+			if t.DueAt == nil {
+				continue
+			}
+			cw.line("BEGIN:VEVENT")
+		} else {
+			cw.line("BEGIN:VTODO")
+		}
+
+		cw.line("UID:" + t.Id + "@todos")
+		cw.line("DTSTAMP:" + stamp)
+
+		summary := t.Title
+		if opts.Component == ComponentEvent && t.Completed {
+			summary = "✓ " + summary
+		}
+		cw.line("SUMMARY:" + icalEscape(summary))
+
+		if p, ok := icalPriorities[t.Priority]; ok {
+			cw.line("PRIORITY:" + strconv.Itoa(p))
+		}
+
+		if t.DueAt != nil {
+			due := icalTime(*t.DueAt, t.Timezone)
+			if opts.Component == ComponentEvent {
+				cw.line("DTSTART" + due)
+				cw.line("DURATION:PT30M")
+			} else {
+				cw.line("DUE" + due)
+			}
+			if t.Rrule != "" {
+				cw.line("RRULE:" + strings.TrimPrefix(t.Rrule, "RRULE:"))
+			}
+		}
+
+		if opts.Component == ComponentEvent {
+			cw.line("STATUS:CONFIRMED")
+			cw.line("END:VEVENT")
+			continue
+		}
+		if t.Completed {
+			cw.line("STATUS:COMPLETED")
+			cw.line("PERCENT-COMPLETE:100")
+		} else {
+			cw.line("STATUS:NEEDS-ACTION")
+		}
+		cw.line("END:VTODO")
+	}
+
+	cw.line("END:VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// icalTime formats a date-time value with its leading ":" or ";TZID=...:".
+func icalTime(t time.Time, timezone string) string {
+	if timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err == nil && loc != time.UTC {
+			return ";TZID=" + timezone + ":" + t.In(loc).Format(icalDateTime)
+		}
+	}
+	return ":" + t.UTC().Format(icalDateTimeUTC)
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icalEscape(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func icalUnescape(s string) string {
+	return icalUnescaper.Replace(s)
+}
+
+// icalWriter writes content lines, folding them at icalLineLimit octets
+// without splitting UTF-8 sequences.
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *icalWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		_, cw.err = cw.w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// continuation lines start with a space that counts against the limit
+		limit = icalLineLimit - 1
+	}
+	if cw.err == nil {
+		_, cw.err = cw.w.WriteString(s + "\r\n")
+	}
+}
+
+func encodeICalendar(w io.Writer, todos []model.Todo) error {
+	return EncodeICalendar(w, todos, CalendarOptions{Name: "Todos"})
+}
+
+// icalProperty is an unfolded content line.
+type icalProperty struct {
+	line   int
+	name   string
+	params map[string]string
+	value  string
+}
+
+// decodeICalendar imports the VTODO components of a calendar and ignores all
+// other components.
+func decodeICalendar(r io.Reader) ([]model.Todo, error) {
+	props, err := readICalendar(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(props) == 0 || props[0].name != "BEGIN" || !strings.EqualFold(props[0].value, "VCALENDAR") {
+		return nil, &SyntaxError{Line: 1, Msg: "expected BEGIN:VCALENDAR"}
+	}
+
+	var (
+		todos []model.Todo
+		todo  *model.Todo
+		start int
+		depth int // nesting inside the VTODO, e.g. VALARM
+	)
+	for _, p := range props {
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VTODO") && todo == nil:
+			todo, start, depth = &model.Todo{}, p.line, 0
+		case todo == nil:
+			continue
+		case p.name == "BEGIN":
+			depth++
+		case p.name == "END" && depth > 0:
+			depth--
+		case p.name == "END":
+			if todo.Title == "" {
+				return nil, &SyntaxError{Line: start, Msg: "VTODO has no SUMMARY"}
+			}
+			todos = append(todos, *todo)
+			todo = nil
+		case depth > 0:
+			continue
+		default:
+			if err := applyICalProperty(todo, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if todo != nil {
+		return nil, &SyntaxError{Line: start, Msg: "VTODO is not terminated"}
+	}
+	return todos, nil
+}
+
+func applyICalProperty(t *model.Todo, p icalProperty) error {
+	switch p.name {
+	case "SUMMARY":
+		t.Title = strings.TrimSpace(icalUnescape(p.value))
+	case "STATUS":
+		t.Completed = t.Completed || strings.EqualFold(p.value, "COMPLETED")
+	case "COMPLETED":
+		t.Completed = true
+	case "PERCENT-COMPLETE":
+		t.Completed = t.Completed || p.value == "100"
+	case "PRIORITY":
+		n, err := strconv.Atoi(p.value)
+		if err != nil || n < 0 || n > 9 {
+			return &SyntaxError{Line: p.line, Msg: fmt.Sprintf("invalid PRIORITY %q", p.value)}
+		}
+		switch {
+		case n == 1:
+			t.Priority = model.PriorityUrgent
+		case n >= 2 && n <= 4:
+			t.Priority = model.PriorityHigh
+		case n == 5:
+			t.Priority = model.PriorityMedium
+		case n >= 6:
+			t.Priority = model.PriorityLow
+		}
+	case "DUE", "DTSTART":
+		// DUE wins over DTSTART whatever their order
+		if p.name == "DTSTART" && t.DueAt != nil {
+			return nil
+		}
+		due, err := parseICalTime(p)
+		if err != nil {
+			return &SyntaxError{Line: p.line, Msg: err.Error()}
+		}
+		t.DueAt = &due
+		t.Timezone = p.params["TZID"]
+	case "RRULE":
+		t.Rrule = p.value
+	}
+	return nil
+}
+
+func parseICalTime(p icalProperty) (time.Time, error) {
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+
+	var (
+		t   time.Time
+		err error
+	)
+	switch {
+	case p.params["VALUE"] == "DATE" || len(p.value) == len(icalDate):
+		t, err = time.ParseInLocation(icalDate, p.value, loc)
+	case strings.HasSuffix(p.value, "Z"):
+		t, err = time.Parse(icalDateTimeUTC, p.value)
+	default:
+		t, err = time.ParseInLocation(icalDateTime, p.value, loc)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", p.name, p.value)
+	}
+	return t.UTC(), nil
+}
+
+// readICalendar unfolds and parses all content lines.
+func readICalendar(r io.Reader) ([]icalProperty, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var (
+		props   []icalProperty
+		current strings.Builder
+		start   int
+	)
+	flush := func() error {
+		if current.Len() == 0 {
+			return nil
+		}
+		p, err := parseICalLine(start, current.String())
+		if err != nil {
+			return err
+		}
+		props = append(props, p)
+		current.Reset()
+		return nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			current.WriteString(text[1:])
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		if text != "" {
+			current.WriteString(text)
+			start = line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// parseICalLine splits "NAME;PARAM=VALUE;PARAM="QUOTED":VALUE".
+func parseICalLine(line int, text string) (icalProperty, error) {
+	p := icalProperty{line: line, params: map[string]string{}}
+
+	// find the colon that ends the name and parameters, skipping quoted
+	// parameter values
+	quoted, end := false, -1
+	for i := 0; i < len(text) && end < 0; i++ {
+		switch text[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return p, &SyntaxError{Line: line, Msg: "content line has no value"}
+	}
+
+	head := strings.Split(text[:end], ";")
+	p.name = strings.ToUpper(head[0])
+	for _, param := range head[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	p.value = text[end+1:]
+	return p, nil
+}
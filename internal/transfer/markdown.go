@@ -0,0 +1,55 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// checklistItem matches "- [ ] title" and "- [x] title" list items, also with
+// "*" or "+" bullets.
+var checklistItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+func encodeMarkdown(w io.Writer, todos []model.Todo) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range todos {
+		box := " "
+		if t.Completed {
+			box = "x"
+		}
+		// keep the title on a single list item
+		title := strings.Join(strings.Fields(t.Title), " ")
+		if _, err := fmt.Fprintf(bw, "- [%s] %s\n", box, title); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeMarkdown imports every checklist item and ignores everything else, so
+// headings and notes around the list do not get in the way.
+func decodeMarkdown(r io.Reader) ([]model.Todo, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var todos []model.Todo
+	for line := 1; scanner.Scan(); line++ {
+		m := checklistItem.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		title := strings.TrimSpace(m[2])
+		if title == "" {
+			return nil, &SyntaxError{Line: line, Msg: "checklist item has no title"}
+		}
+		todos = append(todos, model.Todo{Title: title, Completed: m[1] != " "})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
@@ -0,0 +1,57 @@
+package transfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// maxLineSize bounds a single line of any line-oriented format.
+const maxLineSize = 1 << 20
+
+func encodeNDJSON(w io.Writer, todos []model.Todo) error {
+	enc := json.NewEncoder(w)
+	for _, t := range todos {
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeNDJSON(r io.Reader) ([]model.Todo, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var todos []model.Todo
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var t model.Todo
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&t); err != nil {
+			return nil, &SyntaxError{Line: line, Msg: err.Error()}
+		}
+		todos = append(todos, model.Todo{
+			Title:          t.Title,
+			Completed:      t.Completed,
+			Priority:       t.Priority,
+			DueAt:          t.DueAt,
+			Rrule:          t.Rrule,
+			Timezone:       t.Timezone,
+			RecurrenceMode: t.RecurrenceMode,
+			Description:    t.Description,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
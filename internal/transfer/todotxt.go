@@ -0,0 +1,130 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+// todo.txt priorities are letters; A is the most urgent.
+var todoTxtPriorities = map[model.Priority]string{
+	model.PriorityUrgent: "A",
+	model.PriorityHigh:   "B",
+	model.PriorityMedium: "C",
+	model.PriorityLow:    "D",
+}
+
+const todoTxtDate = "2006-01-02"
+
+func encodeTodoTxt(w io.Writer, todos []model.Todo) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range todos {
+		title := strings.Join(strings.Fields(t.Title), " ")
+		letter, hasPriority := todoTxtPriorities[t.Priority]
+
+		var parts []string
+		switch {
+		case t.Completed && hasPriority:
+			// completed tasks drop the priority prefix, keep it as a tag
+			parts = append(parts, "x", title, "pri:"+letter)
+		case t.Completed:
+			parts = append(parts, "x", title)
+		case hasPriority:
+			parts = append(parts, "("+letter+")", title)
+		default:
+			parts = append(parts, title)
+		}
+		if t.DueAt != nil {
+			parts = append(parts, "due:"+t.DueAt.Format(todoTxtDate))
+		}
+		if _, err := fmt.Fprintln(bw, strings.Join(parts, " ")); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeTodoTxt follows the todo.txt format: an optional "x" completion mark
+// and dates, an optional "(A)" priority, then the description. Projects and
+// contexts stay part of the title; due: and pri: tags are extracted.
+func decodeTodoTxt(r io.Reader) ([]model.Todo, error) {
+	letters := map[string]model.Priority{}
+	for p, letter := range todoTxtPriorities {
+		letters[letter] = p
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var todos []model.Todo
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var t model.Todo
+		if fields[0] == "x" {
+			t.Completed = true
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && len(fields[0]) == 3 && fields[0][0] == '(' && fields[0][2] == ')' {
+			letter := fields[0][1:2]
+			if letter < "A" || letter > "Z" {
+				return nil, &SyntaxError{Line: line, Msg: fmt.Sprintf("invalid priority %q", fields[0])}
+			}
+			t.Priority = letterPriority(letters, letter)
+			fields = fields[1:]
+		}
+		// completion and creation dates
+		for range 2 {
+			if len(fields) > 0 && isTodoTxtDate(fields[0]) {
+				fields = fields[1:]
+			}
+		}
+
+		var words []string
+		for _, f := range fields {
+			key, value, ok := strings.Cut(f, ":")
+			switch {
+			case ok && key == "due":
+				due, err := time.Parse(todoTxtDate, value)
+				if err != nil {
+					return nil, &SyntaxError{Line: line, Msg: fmt.Sprintf("invalid due date %q", value)}
+				}
+				t.DueAt = &due
+			case ok && key == "pri" && len(value) == 1:
+				t.Priority = letterPriority(letters, strings.ToUpper(value))
+			default:
+				words = append(words, f)
+			}
+		}
+
+		t.Title = strings.Join(words, " ")
+		if t.Title == "" {
+			return nil, &SyntaxError{Line: line, Msg: "task has no description"}
+		}
+		todos = append(todos, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// letterPriority maps A-D to our levels; letters past D are still low.
+func letterPriority(letters map[string]model.Priority, letter string) model.Priority {
+	if p, ok := letters[letter]; ok {
+		return p
+	}
+	return model.PriorityLow
+}
+
+func isTodoTxtDate(s string) bool {
+	_, err := time.Parse(todoTxtDate, s)
+	return err == nil
+}
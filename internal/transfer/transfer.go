@@ -0,0 +1,92 @@
+// Package transfer encodes and decodes todos in the file formats supported
+// by import and export: NDJSON, CSV, Markdown checklists, todo.txt and
+// iCalendar.
+//
+// Decoders only carry over user-facing fields; ids, versions and positions
+// are assigned by the service when the todos are imported.
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+var (
+	ErrMalformed         = errors.New("malformed import file")
+	ErrUnsupportedFormat = errors.New("unsupported format")
+)
+
+// SyntaxError reports the line of the input that could not be decoded.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v: line %d: %s", ErrMalformed, e.Line, e.Msg)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrMalformed
+}
+
+// Encode writes todos to w in the given format.
+func Encode(w io.Writer, format model.DataFormat, todos []model.Todo) error {
+	switch format {
+	case model.DataFormatUnspecified, model.DataFormatNDJSON:
+		return encodeNDJSON(w, todos)
+	case model.DataFormatCSV:
+		return encodeCSV(w, todos)
+	case model.DataFormatMarkdown:
+		return encodeMarkdown(w, todos)
+	case model.DataFormatTodoTxt:
+		return encodeTodoTxt(w, todos)
+	case model.DataFormatICalendar:
+		return encodeICalendar(w, todos)
+	}
+	return ErrUnsupportedFormat
+}
+
+// Decode reads todos from r in the given format. Any malformed line fails the
+// whole decode with a *SyntaxError.
+func Decode(r io.Reader, format model.DataFormat) ([]model.Todo, error) {
+	switch format {
+	case model.DataFormatUnspecified, model.DataFormatNDJSON:
+		return decodeNDJSON(r)
+	case model.DataFormatCSV:
+		return decodeCSV(r)
+	case model.DataFormatMarkdown:
+		return decodeMarkdown(r)
+	case model.DataFormatTodoTxt:
+		return decodeTodoTxt(r)
+	case model.DataFormatICalendar:
+		return decodeICalendar(r)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func priorityName(p model.Priority) string {
+	if int(p) < 0 || int(p) >= len(priorityNames) {
+		return priorityNames[0]
+	}
+	return priorityNames[p]
+}
+
+func parsePriority(name string) (model.Priority, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return model.PriorityNone, true
+	}
+	for i, n := range priorityNames {
+		if n == name {
+			return model.Priority(i), true
+		}
+	}
+	return model.PriorityNone, false
+}
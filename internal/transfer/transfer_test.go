@@ -0,0 +1,147 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+func TestRoundTrip(t *testing.T) {
+	due := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	todos := []model.Todo{
+		{Title: "write report", Priority: model.PriorityHigh, DueAt: &due},
+		{Title: "buy milk, eggs", Completed: true, Priority: model.PriorityLow},
+		{Title: "call mom"},
+	}
+
+	for _, format := range []model.DataFormat{model.DataFormatNDJSON, model.DataFormatCSV, model.DataFormatTodoTxt} {
+		var buf bytes.Buffer
+		if err := Encode(&buf, format, todos); err != nil {
+			t.Fatalf("Encode(%d) failed: %v", format, err)
+		}
+		got, err := Decode(&buf, format)
+		if err != nil {
+			t.Fatalf("Decode(%d) failed: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, todos) {
+			t.Errorf("Format %d did not round-trip:\n got %+v\nwant %+v", format, got, todos)
+		}
+	}
+}
+
+func TestDecodeMarkdownChecklist(t *testing.T) {
+	input := "# Groceries\n\nSome notes.\n- [ ] milk\n* [x] bread\n- plain bullet\n"
+	got, err := Decode(strings.NewReader(input), model.DataFormatMarkdown)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want := []model.Todo{{Title: "milk"}, {Title: "bread", Completed: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDecodeTodoTxt(t *testing.T) {
+	input := "x 2026-10-19 2026-10-01 file taxes +home pri:A\n(B) 2026-10-01 review PR @work due:2026-10-21\n"
+	got, err := Decode(strings.NewReader(input), model.DataFormatTodoTxt)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	due := time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)
+	want := []model.Todo{
+		{Title: "file taxes +home", Completed: true, Priority: model.PriorityUrgent},
+		{Title: "review PR @work", Priority: model.PriorityHigh, DueAt: &due},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDecodeReportsMalformedLine(t *testing.T) {
+	inputs := map[model.DataFormat]string{
+		model.DataFormatNDJSON:  "{\"title\":\"ok\"}\n{\"title\":\n",
+		model.DataFormatCSV:     "title,priority\nok,low\nbad,sometimes\n",
+		model.DataFormatTodoTxt: "ok\n\n(B) due:tomorrow\n",
+	}
+	lines := map[model.DataFormat]int{
+		model.DataFormatNDJSON:  2,
+		model.DataFormatCSV:     3,
+		model.DataFormatTodoTxt: 3,
+	}
+	for format, input := range inputs {
+		_, err := Decode(strings.NewReader(input), format)
+		var serr *SyntaxError
+		if !errors.As(err, &serr) || !errors.Is(err, ErrMalformed) {
+			t.Errorf("Format %d: expected a SyntaxError, got %v", format, err)
+			continue
+		}
+		if serr.Line != lines[format] {
+			t.Errorf("Format %d: expected line %d, got %d", format, lines[format], serr.Line)
+		}
+	}
+}
+
+func TestICalendarRoundTrip(t *testing.T) {
+	due := time.Date(2026, 10, 26, 8, 0, 0, 0, time.UTC)
+	todos := []model.Todo{
+		{Id: "1", Title: "standup; daily," + strings.Repeat(" long", 20), Priority: model.PriorityUrgent, DueAt: &due, Rrule: "FREQ=WEEKLY;BYDAY=MO", Timezone: "Europe/Berlin"},
+		{Id: "2", Title: "done", Completed: true},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeICalendar(&buf, todos, CalendarOptions{Name: "Todos"}); err != nil {
+		t.Fatalf("EncodeICalendar failed: %v", err)
+	}
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > icalLineLimit {
+			t.Errorf("Line longer than %d octets: %q", icalLineLimit, line)
+		}
+	}
+	if !strings.Contains(buf.String(), "DUE;TZID=Europe/Berlin:20261026T090000\r\n") {
+		t.Errorf("Expected a local due date with TZID, got:\n%s", buf.String())
+	}
+
+	got, err := Decode(&buf, model.DataFormatICalendar)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	for i := range todos {
+		todos[i].Id = ""
+	}
+	if !reflect.DeepEqual(got, todos) {
+		t.Errorf("iCalendar did not round-trip:\n got %+v\nwant %+v", got, todos)
+	}
+}
+
+func TestDecodeICalendarSkipsOtherComponents(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:meeting",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"SUMMARY:pay ",
+		" rent",
+		"DUE;VALUE=DATE:20261101",
+		"BEGIN:VALARM",
+		"SUMMARY:reminder",
+		"END:VALARM",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := Decode(strings.NewReader(input), model.DataFormatICalendar)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	want := []model.Todo{{Title: "pay rent", DueAt: &due}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,300 @@
+// Package webhook signs webhook deliveries and sends the due ones, retrying
+// failures with exponential backoff until they succeed or run out of
+// attempts.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>", where
+	// the HMAC of the webhook secret is taken over "<t>.<body>".
+	SignatureHeader = "Todos-Signature"
+	// EventHeader carries the event type, e.g. "todo.created".
+	EventHeader = "Todos-Event"
+	// DeliveryHeader carries the delivery id, which stays the same across
+	// retries so receivers can drop duplicates.
+	DeliveryHeader = "Todos-Delivery"
+
+	// maxErrorLen caps the error stored with a failed attempt.
+	maxErrorLen = 500
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the SignatureHeader value of body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks a SignatureHeader value against body, rejecting signatures
+// older than tolerance to stop replays. Receivers written in Go can use it
+// as is.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	want := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Store is the delivery queue of the dispatcher.
+type Store interface {
+	// ClaimDeliveries returns up to limit pending deliveries of every tenant
+	// that are due at now, with the URL and secret of their webhook, and
+	// holds them for lease.
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	// RecordAttempt stores the status, attempts, last result and next
+	// attempt of a delivery.
+	RecordAttempt(ctx context.Context, d *model.WebhookDelivery) error
+}
+
+// Config configures a Dispatcher. Zero values take the defaults below.
+type Config struct {
+	Store Store
+	// Client sends the deliveries. The default times out after 10 seconds
+	// and refuses to connect to private networks.
+	Client *http.Client
+	// MaxAttempts is the number of attempts before a delivery is dead;
+	// 8 by default.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry, doubled on every retry
+	// up to MaxDelay; 30 seconds and an hour by default.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// PollInterval is how often Run looks for due deliveries; 5 seconds by
+	// default.
+	PollInterval time.Duration
+	// BatchSize is the number of deliveries claimed at once; 20 by default.
+	BatchSize int
+	// AllowPrivateNetworks lets the default client reach loopback, private
+	// and link-local addresses, which is only wanted in development.
+	AllowPrivateNetworks bool
+}
+
+// Dispatcher sends due deliveries.
+type Dispatcher struct {
+	config Config
+	now    func() time.Time
+}
+
+// NewDispatcher creates a Dispatcher.
+func NewDispatcher(config Config) *Dispatcher {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 8
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = 30 * time.Second
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = time.Hour
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 20
+	}
+	if config.Client == nil {
+		config.Client = newClient(config.AllowPrivateNetworks)
+	}
+	return &Dispatcher{config: config, now: time.Now}
+}
+
+// newClient returns a client that does not follow redirects, since a
+// redirect could point anywhere, and unless allowPrivate is set only
+// connects to public addresses. The check runs on the resolved address so
+// DNS names pointing inside the cluster are caught too.
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !public(addrPort.Addr()) {
+				return fmt.Errorf("refusing to connect to non-public address %s", addrPort.Addr())
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        20,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// Run sends due deliveries every PollInterval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// drain the queue before waiting again
+		for {
+			n, err := d.DispatchDue(ctx)
+			if err != nil {
+				log.Default().Println("webhook: failed to dispatch deliveries:", err)
+			}
+			if err != nil || n < d.config.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends one batch of due deliveries and returns its size.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	// the lease outlasts an attempt, so a crashed dispatcher's claims are
+	// picked up again shortly after
+	lease := 2 * d.config.Client.Timeout
+	if lease <= 0 {
+		lease = time.Minute
+	}
+
+	deliveries, err := d.config.Store.ClaimDeliveries(ctx, d.now(), lease, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range deliveries {
+		d.attempt(ctx, &deliveries[i])
+		if err := d.config.Store.RecordAttempt(ctx, &deliveries[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery and updates it with the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	delivery.Attempts++
+	code, err := d.send(ctx, delivery)
+	delivery.LastStatusCode = int32(code)
+	now := d.now()
+
+	if err == nil {
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > maxErrorLen {
+		delivery.LastError = delivery.LastError[:maxErrorLen]
+	}
+	if int(delivery.Attempts) >= d.config.MaxAttempts {
+		log.Default().Println("webhook: delivery", delivery.Id, "is dead after", delivery.Attempts, "attempts:", err)
+		delivery.Status = model.DeliveryStatusDead
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(d.backoff(int(delivery.Attempts)))
+	delivery.Status = model.DeliveryStatusPending
+	delivery.NextAttemptAt = &next
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.BaseDelay
+	for i := 1; i < attempts && delay < d.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, d.config.MaxDelay)
+}
+
+// send posts a delivery and returns the status code of the response, if
+// any. Any status other than 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todos-webhooks/1")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.Id)
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, d.now(), body))
+
+	res, err := d.config.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver responded %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/haakaashs/todos-backend/internal/model"
+)
+
+const secret = "whsec_test_secret_value"
+
+// queue is an in-memory Store.
+type queue struct {
+	mu         sync.Mutex
+	deliveries []model.WebhookDelivery
+}
+
+func (q *queue) ClaimDeliveries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var due []model.WebhookDelivery
+	for i := range q.deliveries {
+		d := &q.deliveries[i]
+		if d.Status == model.DeliveryStatusPending && !d.NextAttemptAt.After(now) && len(due) < limit {
+			next := now.Add(lease)
+			d.NextAttemptAt = &next
+			due = append(due, *d)
+		}
+	}
+	return due, nil
+}
+
+func (q *queue) RecordAttempt(_ context.Context, d *model.WebhookDelivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i := range q.deliveries {
+		if q.deliveries[i].Id == d.Id {
+			q.deliveries[i] = *d
+			return nil
+		}
+	}
+	return errors.New("unknown delivery")
+}
+
+func (q *queue) get(id string) model.WebhookDelivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, d := range q.deliveries {
+		if d.Id == id {
+			return d
+		}
+	}
+	return model.WebhookDelivery{}
+}
+
+func newQueue(url string, now time.Time) *queue {
+	return &queue{deliveries: []model.WebhookDelivery{{
+		Id:            "d1",
+		WebhookId:     "w1",
+		Event:         model.EventTodoCreated,
+		Payload:       `{"type":"todo.created"}`,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &now,
+		Url:           url,
+		Secret:        secret,
+	}}}
+}
+
+func newTestDispatcher(store Store, now *time.Time) *Dispatcher {
+	d := NewDispatcher(Config{Store: store, MaxAttempts: 3, BaseDelay: time.Minute, AllowPrivateNetworks: true})
+	d.now = func() time.Time { return *now }
+	return d
+}
+
+func TestDeliverySigned(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	var header http.Header
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer receiver.Close()
+
+	store := newQueue(receiver.URL, now)
+	n, err := newTestDispatcher(store, &now).DispatchDue(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Expected one delivery, got %d: %v", n, err)
+	}
+
+	if err := Verify(secret, header.Get(SignatureHeader), body, 5*time.Minute, now); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+	if header.Get(EventHeader) != model.EventTodoCreated || header.Get(DeliveryHeader) != "d1" {
+		t.Errorf("Expected the event and delivery headers, got %v", header)
+	}
+	d := store.get("d1")
+	if d.Status != model.DeliveryStatusSucceeded || d.Attempts != 1 || d.LastStatusCode != 200 || d.DeliveredAt == nil {
+		t.Errorf("Expected a succeeded delivery, got %+v", d)
+	}
+}
+
+func TestRetryWithBackoffThenDead(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	store := newQueue(receiver.URL, now)
+	dispatcher := newTestDispatcher(store, &now)
+	ctx := context.Background()
+
+	for attempt, delay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		dispatcher.DispatchDue(ctx)
+		d := store.get("d1")
+		if d.Status != model.DeliveryStatusPending || d.LastStatusCode != 500 {
+			t.Fatalf("Expected a pending retry after attempt %d, got %+v", attempt+1, d)
+		}
+		if want := now.Add(delay); !d.NextAttemptAt.Equal(want) {
+			t.Errorf("Expected the next attempt at %v, got %v", want, d.NextAttemptAt)
+		}
+
+		if n, _ := dispatcher.DispatchDue(ctx); n != 0 {
+			t.Errorf("Expected no delivery before the retry is due, got %d", n)
+		}
+		now = now.Add(delay)
+	}
+
+	dispatcher.DispatchDue(ctx)
+	d := store.get("d1")
+	if d.Status != model.DeliveryStatusDead || d.Attempts != 3 || d.NextAttemptAt != nil {
+		t.Errorf("Expected a dead delivery after 3 attempts, got %+v", d)
+	}
+	if n, _ := dispatcher.DispatchDue(ctx); n != 0 {
+		t.Errorf("Expected a dead delivery not to be retried, got %d", n)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	d := NewDispatcher(Config{BaseDelay: time.Minute, MaxDelay: 10 * time.Minute})
+	if got := d.backoff(3); got != 4*time.Minute {
+		t.Errorf("Expected 4m, got %v", got)
+	}
+	if got := d.backoff(30); got != 10*time.Minute {
+		t.Errorf("Expected 10m, got %v", got)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"todo.deleted"}`)
+	header := Sign(secret, now, body)
+
+	if err := Verify(secret, header, []byte(`{"type":"todo.created"}`), time.Minute, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected a tampered body to be rejected, got %v", err)
+	}
+	if err := Verify("another secret", header, body, time.Minute, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected the wrong secret to be rejected, got %v", err)
+	}
+	if err := Verify(secret, header, body, time.Minute, now.Add(10*time.Minute)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected an old signature to be rejected, got %v", err)
+	}
+}
+
+func TestPrivateNetworksRefused(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	store := newQueue(receiver.URL, now)
+	dispatcher := NewDispatcher(Config{Store: store})
+	dispatcher.now = func() time.Time { return now }
+	dispatcher.DispatchDue(context.Background())
+
+	if d := store.get("d1"); called || d.Status != model.DeliveryStatusPending || d.LastError == "" {
+		t.Errorf("Expected the loopback receiver to be refused, got %+v", d)
+	}
+}